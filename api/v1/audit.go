@@ -0,0 +1,29 @@
+package v1
+
+type SearchAuditLogRequest struct {
+	Page
+
+	EntityType string `json:"entity_type" form:"entity_type" binding:"omitempty,max=100" example:"products"`
+	EntityID   string `json:"entity_id" form:"entity_id" binding:"omitempty,max=255" example:"1d5e5e0e-7d1c-4f5e-9d2a-3b0c6f7a8e9b"`
+	ActorID    string `json:"actor_id" form:"actor_id" binding:"omitempty,max=100" example:"1sdWdNiYj"`
+	Action     string `json:"action" form:"action" binding:"omitempty,oneof=create update delete" example:"update"`
+	DateFrom   string `json:"date_from" form:"date_from" binding:"omitempty,datetime=2006-01-02" example:"2024-01-28"`
+	DateTo     string `json:"date_to" form:"date_to" binding:"omitempty,datetime=2006-01-02" example:"2024-01-28"`
+}
+
+type SearchAuditLogResponse struct {
+	Response
+	Pagination
+}
+
+type GetAuditLogData struct {
+	ID         uint64      `json:"id" example:"1"`
+	ActorID    string      `json:"actor_id" example:"1sdWdNiYj"`
+	Action     string      `json:"action" example:"update"`
+	EntityType string      `json:"entity_type" example:"products"`
+	EntityID   string      `json:"entity_id" example:"1d5e5e0e-7d1c-4f5e-9d2a-3b0c6f7a8e9b"`
+	Changes    interface{} `json:"changes"`
+	TraceID    string      `json:"trace_id" example:"5d41402abc4b2a76b9719d911017c592"`
+	IP         string      `json:"ip" example:"203.0.113.7"`
+	CreatedAt  string      `json:"created_at" example:"2024-01-28T10:00:00Z"`
+}
@@ -6,6 +6,8 @@ type SearchCategoryRequest struct {
 
 	Name   string `json:"name" form:"name" binding:"omitempty,min=3,max=100" example:"Crunchy Munch"`
 	Status string `json:"status" form:"status" binding:"omitempty,oneof='active' 'deactive'" example:"active"`
+	// ParentId lists the direct subcategories of a category
+	ParentId string `json:"parent_id" form:"parent_id" binding:"omitempty,uuid4" example:"67389c69-2e78-413a-9d77-6b749520b127"`
 }
 
 type SearchCategoryResponse struct {
@@ -16,14 +18,62 @@ type SearchCategoryResponse struct {
 type CreateCategoryRequest struct {
 	Name   string `json:"name" form:"name" binding:"omitempty,min=3,max=100" example:"Crunchy Munch"`
 	Status string `json:"status" form:"status" binding:"omitempty,oneof='active' 'deactive'" example:"active"`
+	// ParentId is empty for a root category
+	ParentId string `json:"parent_id" form:"parent_id" binding:"omitempty,uuid4" example:"67389c69-2e78-413a-9d77-6b749520b127"`
+	// Attributes is the schema of the attribute values of the products
+	Attributes []CategoryAttributeRequest `json:"attributes" binding:"omitempty,max=50,dive"`
+	// ReferencePrefix replaces {CAT} in the references generated for the products, the configured default is used without
+	ReferencePrefix string `json:"reference_prefix" binding:"omitempty,category_prefix" example:"FOOD"`
+}
+
+type UpdateCategoryRequest struct {
+	Name   string `json:"name" binding:"required,min=3,max=100" example:"Crunchy Munch"`
+	Status string `json:"status" binding:"required,oneof='active' 'deactive'" example:"active"`
+	// ParentId moves the category with its subtree, empty makes it a root category
+	ParentId string `json:"parent_id" binding:"omitempty,uuid4" example:"67389c69-2e78-413a-9d77-6b749520b127"`
+	// Attributes replaces the attribute schema, the values of the products are checked against it on their next write
+	Attributes []CategoryAttributeRequest `json:"attributes" binding:"omitempty,max=50,dive"`
+	// ReferencePrefix only applies to the references generated from now on
+	ReferencePrefix string `json:"reference_prefix" binding:"omitempty,category_prefix" example:"FOOD"`
+}
+
+type CategoryAttributeRequest struct {
+	Name     string `json:"name" binding:"required,attribute_name" example:"color"`
+	Type     string `json:"type" binding:"required,oneof=string number bool date enum" example:"enum"`
+	Required bool   `json:"required" example:"true"`
+	// AllowedValues are the values of an enum attribute, the other types have none
+	AllowedValues []string `json:"allowed_values" binding:"required_if=Type enum,omitempty,max=100,dive,required,max=255" example:"red,green"`
+}
+
+type MergeCategoryRequest struct {
+	// TargetId receives the products and subcategories, the merged category goes to the trash
+	TargetId string `json:"target_id" binding:"required,uuid4" example:"67389c69-2e78-413a-9d77-6b749520b127"`
+}
+
+type MergeCategoryData struct {
+	MovedProducts      int64 `json:"moved_products" example:"12"`
+	MovedSubcategories int   `json:"moved_subcategories" example:"2"`
 }
 
 type GetCategoryDetailData struct {
 	ID        string `json:"id" example:"67389c69-2e78-413a-9d77-6b749520b127"`
 	Name      string `json:"name" example:"Food"`
 	Status    string `json:"status" example:"active"`
+	ParentID  string `json:"parent_id,omitempty" example:"66ae8b26-a0cd-40d5-8b2d-5127d7b9d817"`
+	Path      string `json:"path" example:"/66ae8b26-a0cd-40d5-8b2d-5127d7b9d817/67389c69-2e78-413a-9d77-6b749520b127/"`
 	CreatedAt string `json:"created_at" example:"2023-01-01"`
 	UpdatedAt string `json:"updated_at" example:"2023-01-01"`
+	Version   int    `json:"version" example:"1"`
+	// Attributes is only set in the detail of a category
+	Attributes      []CategoryAttributeData `json:"attributes,omitempty"`
+	ReferencePrefix string                  `json:"reference_prefix,omitempty" example:"FOOD"`
+}
+
+type CategoryAttributeData struct {
+	Name          string   `json:"name" example:"color"`
+	Type          string   `json:"type" example:"enum"`
+	Required      bool     `json:"required" example:"true"`
+	AllowedValues []string `json:"allowed_values,omitempty" example:"red,green"`
 }
 
 type GetCategoryDetailResponse struct {
@@ -5,9 +5,22 @@ var (
 	ErrSuccess             = newError(0, "ok")
 	ErrBadRequest          = newError(400, "Bad Request")
 	ErrUnauthorized        = newError(401, "Unauthorized")
+	ErrForbidden           = newError(403, "Forbidden")
 	ErrNotFound            = newError(404, "Not Found")
 	ErrInternalServerError = newError(500, "Internal Server Error")
 
 	// more biz errors
-	ErrEmailAlreadyUse = newError(1001, "The email is already in use.")
+	ErrEmailAlreadyUse       = newError(1001, "The email is already in use.")
+	ErrPriceAlreadyEffective = newError(1002, "The price is already in effect.")
+	ErrVersionMismatch       = newError(1003, "The resource has been modified since it was read.")
+	ErrCategoryHasProducts   = newError(1004, "The category still has products.")
+	ErrCategoryHasChildren   = newError(1005, "The category still has subcategories.")
+	ErrPurchaseOrderStatus   = newError(1006, "The purchase order can't do this in its current status.")
+	ErrProductTransition     = newError(1007, "The product can't move from its current status to this one.")
+	ErrProductHasVariants    = newError(1008, "The product still has variants.")
+	ErrGTINInUse             = newError(1009, "The GTIN belongs to another product.")
+	ErrBulkRolledBack        = newError(1010, "The bulk operation failed on a product, nothing was changed.")
+	ErrProductInBundle       = newError(1011, "The product is a component of a bundle.")
+	ErrStocktakeStatus       = newError(1012, "The stocktake can't do this in its current status.")
+	ErrStatusNeedsTransition = newError(1013, "The status of a product changes through POST /products/{id}/transition.")
 )
@@ -0,0 +1,49 @@
+package v1
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ETag return the entity tag of a resource at the given version
+func ETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// IfMatch is the precondition of an If-Match header, the zero value (no header or "*") matches any version
+type IfMatch struct {
+	Set      bool
+	Versions []int
+}
+
+// ParseIfMatch reads the header value, tags that are weak or not ours never match
+func ParseIfMatch(header string) IfMatch {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return IfMatch{}
+	}
+
+	ifMatch := IfMatch{Set: true}
+	for _, tag := range strings.Split(header, ",") {
+		value, err := strconv.Unquote(strings.TrimSpace(tag))
+		if err != nil {
+			continue
+		}
+		if version, err := strconv.Atoi(value); err == nil {
+			ifMatch.Versions = append(ifMatch.Versions, version)
+		}
+	}
+	return ifMatch
+}
+
+func (m IfMatch) Matches(version int) bool {
+	if !m.Set {
+		return true
+	}
+	for _, v := range m.Versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
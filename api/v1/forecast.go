@@ -0,0 +1,49 @@
+package v1
+
+type ForecastFilter struct {
+	SupplierId string `json:"supplier_id" form:"supplier_id" binding:"omitempty,uuid4" example:"67389c69-2e78-413a-9d77-6b749520b127"`
+	// ReorderOnly keeps the products with a reorder to place now
+	ReorderOnly bool `json:"reorder_only" form:"reorder_only" example:"true"`
+}
+
+type SearchForecastRequest struct {
+	Page
+	ForecastFilter
+}
+
+type SearchForecastResponse struct {
+	Response
+	Pagination
+}
+
+type ExportForecastsRequest struct {
+	ForecastFilter
+}
+
+type ForecastData struct {
+	Reference    string `json:"reference" example:"PROD-202401-001"`
+	ProductName  string `json:"product_name" example:"Crunchy Munch"`
+	SupplierID   string `json:"supplier_id" example:"67389c69-2e78-413a-9d77-6b749520b127"`
+	SupplierName string `json:"supplier_name,omitempty" example:"Snack Co"`
+	Method       string `json:"method" example:"exponential_smoothing"`
+	PeriodDays   int    `json:"period_days" example:"7"`
+	Periods      int    `json:"periods" example:"12"`
+	// The demands are per day, DailyDemand is the one of the method
+	MovingAverage        float64 `json:"moving_average" example:"3.5"`
+	ExponentialSmoothing float64 `json:"exponential_smoothing" example:"3.8"`
+	DailyDemand          float64 `json:"daily_demand" example:"3.8"`
+	DemandStdDev         float64 `json:"demand_std_dev" example:"1.2"`
+	LeadTimeDays         int     `json:"lead_time_days" example:"7"`
+	SafetyStock          int     `json:"safety_stock" example:"6"`
+	Quantity             int     `json:"quantity" example:"20"`
+	OnOrder              int     `json:"on_order" example:"0"`
+	// ReorderQuantity is what to order now, 0 while the stock and the orders are above ReorderPoint
+	ReorderPoint    int    `json:"reorder_point" example:"33"`
+	ReorderQuantity int    `json:"reorder_quantity" example:"66"`
+	ComputedAt      string `json:"computed_at" example:"2024-02-03T02:30:00Z"`
+}
+
+type GetProductForecastResponse struct {
+	Response
+	Data ForecastData
+}
@@ -4,16 +4,28 @@ type SearchProductRequest struct {
 	Page
 	Sorting
 
-	Reference       string  `json:"reference" form:"reference" binding:"omitempty,product_ref" example:"PROD-202401-001"`
-	ProductName     string  `json:"product_name" form:"product_name" binding:"omitempty,min=3,max=100" example:"Crunchy Munch"`
-	CategoryId      string  `json:"category_id" form:"category_id" binding:"omitempty,gt=0" example:"1"`
-	SupplierId      string  `json:"supplier_id" form:"supplier_id" binding:"omitempty,gt=0" example:"1"`
-	StockLocationId string  `json:"stock_location_id" form:"stock_location_id" binding:"omitempty,gt=0" example:"1"`
-	MinPrice        float64 `json:"min_price" form:"min_price" binding:"omitempty,gt=0" example:"100000"`
-	MaxPrice        float64 `json:"max_price" form:"max_price" binding:"omitempty,gt=0" example:"500000"`
-	DateAddedFrom   string  `json:"date_added_from" form:"date_added_from" binding:"omitempty,datetime=2006-01-02" example:"2024-01-28"`
-	DateAddedTo     string  `json:"date_added_to" form:"date_added_to" binding:"omitempty,datetime=2006-01-02" example:"2024-01-28"`
-	Status          string  `json:"status" form:"status" binding:"omitempty,oneof='Available' 'Out of Stock' 'On Order'" example:"Available"`
+	Reference   string `json:"reference" form:"reference" binding:"omitempty,product_ref_lookup" example:"PROD-202401-001"`
+	ProductName string `json:"product_name" form:"product_name" binding:"omitempty,min=3,max=100" example:"Crunchy Munch"`
+	CategoryId  string `json:"category_id" form:"category_id" binding:"omitempty,gt=0" example:"1"`
+	// IncludeSubcategories extends the category_id filter to the whole subtree of the category
+	IncludeSubcategories bool    `json:"include_subcategories" form:"include_subcategories" example:"true"`
+	SupplierId           string  `json:"supplier_id" form:"supplier_id" binding:"omitempty,gt=0" example:"1"`
+	StockLocationId      string  `json:"stock_location_id" form:"stock_location_id" binding:"omitempty,gt=0" example:"1"`
+	MinPrice             float64 `json:"min_price" form:"min_price" binding:"omitempty,gt=0" example:"100000"`
+	MaxPrice             float64 `json:"max_price" form:"max_price" binding:"omitempty,gt=0" example:"500000"`
+	DateAddedFrom        string  `json:"date_added_from" form:"date_added_from" binding:"omitempty,datetime=2006-01-02" example:"2024-01-28"`
+	DateAddedTo          string  `json:"date_added_to" form:"date_added_to" binding:"omitempty,datetime=2006-01-02" example:"2024-01-28"`
+	Status               string  `json:"status" form:"status" binding:"omitempty,oneof='Draft' 'Available' 'Out of Stock' 'On Order' 'Discontinued' 'Archived'" example:"Available"`
+	// IncludeArchived lists the archived products too, they are hidden unless filtered by status
+	IncludeArchived bool `json:"include_archived" form:"include_archived" example:"false"`
+	// PriceAt makes the price filters, sorting and effective_price use the price in effect on that date
+	PriceAt string `json:"price_at" form:"price_at" binding:"omitempty,datetime=2006-01-02" example:"2024-02-01"`
+	// Attributes filters on attribute values given as name:value, the values are compared as the detail shows them
+	Attributes []string `json:"attribute" form:"attribute" binding:"omitempty,max=10,dive,attribute_filter" example:"color:red"`
+	// CollapseVariants lists a parent instead of its variants, when it or one of them matches, with their total quantity
+	CollapseVariants bool `json:"collapse_variants" form:"collapse_variants" example:"true"`
+	// GTIN matches the code however it is written, e.g. a UPC-A as 12 digits or as the 13 of an EAN-13
+	GTIN string `json:"gtin" form:"gtin" binding:"omitempty,gtin" example:"4006381333931"`
 }
 
 type SearchProductResponse struct {
@@ -26,25 +38,64 @@ type CreateProductRequest struct {
 	ProductName   string  `json:"product_name" binding:"required,min=3,max=100" example:"Crunchy Munch"`
 	CategoryId    string  `json:"category_id" binding:"required,uuid4" example:"67389c69-2e78-413a-9d77-6b749520b127"`
 	Price         float64 `json:"price" binding:"required,gt=0" example:"150000"`
-	Status        string  `json:"status" binding:"required,oneof='Available' 'Out of Stock' 'On Order'" example:"Available"`
+	Status        string  `json:"status" binding:"required,oneof='Draft' 'Available' 'Out of Stock' 'On Order'" example:"Available"`
 	StockLocation string  `json:"stock_location" binding:"required" example:"Brookstone"`
 	SupplierId    string  `json:"supplier_id" binding:"required,uuid4" example:"66ae8b26-a0cd-40d5-8b2d-5127d7b9d817"`
 	Quantity      int     `json:"quantity" binding:"required,gt=0" example:"10"`
+	// GTIN is the optional EAN/UPC code, it is unique among the products
+	GTIN string `json:"gtin" binding:"omitempty,gtin" example:"4006381333931"`
+	// Attributes are the values for the attribute schema of the category, by attribute name
+	Attributes map[string]interface{} `json:"attributes" binding:"omitempty,max=50"`
 }
 
+// CreateVariantRequest adds a variant to a product, it gets the category, supplier and attribute values of the product.
+// The name and stock location are those of the product when empty.
+type CreateVariantRequest struct {
+	Reference     string  `json:"reference" binding:"omitempty,product_ref" example:"PROD-202401-001-M"`
+	ProductName   string  `json:"product_name" binding:"omitempty,min=3,max=100" example:"Crunchy Munch M"`
+	Price         float64 `json:"price" binding:"required,gt=0" example:"150000"`
+	Status        string  `json:"status" binding:"required,oneof='Draft' 'Available' 'Out of Stock' 'On Order'" example:"Available"`
+	StockLocation string  `json:"stock_location" example:"Brookstone"`
+	Quantity      int     `json:"quantity" binding:"gte=0" example:"10"`
+	GTIN          string  `json:"gtin" binding:"omitempty,gtin" example:"4006381333948"`
+	// Options tell the variant from the other variants of the product
+	Options map[string]string `json:"options" binding:"required,min=1,max=10,dive,keys,attribute_name,endkeys,required,max=100" example:"size:M"`
+}
+
+// UpdateProductRequest replaces the fields of a product, its status must stay the current one and changes through a transition.
+// In a merge patch the attributes and options are merged too, null removes a value.
 type UpdateProductRequest struct {
 	ProductName   string  `json:"product_name" binding:"required,min=3,max=100" example:"Crunchy Munch"`
 	CategoryId    string  `json:"category_id" binding:"required,uuid4" example:"67389c69-2e78-413a-9d77-6b749520b127"`
 	Price         float64 `json:"price" binding:"required,gt=0" example:"150000"`
-	Status        string  `json:"status" binding:"required,oneof='Available' 'Out of Stock' 'On Order'" example:"Available"`
+	Status        string  `json:"status" binding:"required,oneof='Draft' 'Available' 'Out of Stock' 'On Order' 'Discontinued' 'Archived'" example:"Available"`
 	StockLocation string  `json:"stock_location" binding:"required" example:"Brookstone"`
 	DateAdded     string  `json:"added_date" binding:"required,datetime=2006-01-02" example:"2024-01-28"`
 	SupplierId    string  `json:"supplier_id" binding:"required,uuid4" example:"66ae8b26-a0cd-40d5-8b2d-5127d7b9d817"`
 	Quantity      int     `json:"quantity" binding:"required,gt=0" example:"10"`
+	// GTIN is removed when empty
+	GTIN string `json:"gtin" binding:"omitempty,gtin" example:"4006381333931"`
+	// Attributes are the values for the attribute schema of the category, by attribute name
+	Attributes map[string]interface{} `json:"attributes" binding:"omitempty,max=50"`
+	// Options are only set for a variant, the category and supplier of a variant are those of its parent
+	Options map[string]string `json:"options" binding:"omitempty,max=10,dive,keys,attribute_name,endkeys,max=100"`
+}
+
+// PatchProductRequest is a JSON merge patch (RFC 7396) of the fields of UpdateProductRequest
+type PatchProductRequest struct {
+	UpdateProductRequest
+	// Fields holds the JSON names of the fields present in the patch, only those are validated and applied
+	Fields map[string]bool `json:"-"`
+}
+
+type PatchProductData struct {
+	Version int                           `json:"version" example:"5"`
+	Changes map[string]ProductFieldChange `json:"changes"`
 }
 
 type GetProductDetailData struct {
 	Reference     string  `json:"reference" example:"PROD-202401-001"`
+	GTIN          string  `json:"gtin,omitempty" example:"4006381333931"`
 	ProductName   string  `json:"product_name" example:"Crunchy Munch"`
 	Category      string  `json:"category" example:"Food"`
 	Price         float64 `json:"price" example:"150000"`
@@ -53,12 +104,61 @@ type GetProductDetailData struct {
 	DateAdded     string  `json:"added_date" example:"2024-01-28"`
 	Quantity      int     `json:"quantity" example:"10"`
 	Supplier      string  `json:"supplier" example:"KFC"`
+	// Version is sent back in the If-Match header to update or delete the product, it is also the ETag header
+	Version int `json:"version,omitempty" example:"4"`
+	// EffectivePrice is the price in effect on the requested date, only set when searching with price_at
+	EffectivePrice *float64 `json:"effective_price,omitempty" example:"145000"`
+	// Attributes are the attribute values by name, numbers and bools keep their JSON type
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	// ParentReference and Options are only set for a variant
+	ParentReference string            `json:"parent_reference,omitempty" example:"PROD-202401-001"`
+	Options         map[string]string `json:"options,omitempty"`
+	// VariantCount and TotalQuantity, the quantity of the product and its variants, are only set for a parent
+	VariantCount  int  `json:"variant_count,omitempty" example:"3"`
+	TotalQuantity *int `json:"total_quantity,omitempty" example:"42"`
+	// Variants are only listed in the detail of a product
+	Variants []ProductVariantData `json:"variants,omitempty"`
+	// Images are ordered by position, the first one is the primary image
+	Images []ProductImageData `json:"images,omitempty"`
+	// Components are only set for a bundle, its quantity is how many bundles their stock can make
+	Components []BundleComponentData `json:"components,omitempty"`
+}
+
+type ProductVariantData struct {
+	Reference   string            `json:"reference" example:"PROD-202401-001-M"`
+	GTIN        string            `json:"gtin,omitempty" example:"4006381333948"`
+	ProductName string            `json:"product_name" example:"Crunchy Munch M"`
+	Price       float64           `json:"price" example:"150000"`
+	Status      string            `json:"status" example:"Available"`
+	Quantity    int               `json:"quantity" example:"10"`
+	Options     map[string]string `json:"options"`
+}
+
+type ProductImageData struct {
+	ID          string `json:"id" example:"6f1c2a0e-6a0b-4d8e-9a43-2f0b8d9e1c55"`
+	Filename    string `json:"filename" example:"front.jpg"`
+	ContentType string `json:"content_type" example:"image/jpeg"`
+	Size        int64  `json:"size" example:"482133"`
+	Width       int    `json:"width" example:"1600"`
+	Height      int    `json:"height" example:"1200"`
+	Position    int    `json:"position" example:"0"`
+	URL         string `json:"url" example:"/images/6f1c2a0e-6a0b-4d8e-9a43-2f0b8d9e1c55.jpg"`
+	// Thumbnails are the URLs of the thumbnails by their longest side in pixels
+	Thumbnails map[int]string `json:"thumbnails"`
+}
+
+// ReorderProductImagesRequest lists all the images of the product in their new order
+type ReorderProductImagesRequest struct {
+	ImageIds []string `json:"image_ids" binding:"required,min=1,dive,uuid"`
 }
 
 type ProductCategoryStatsResponse struct {
-	CategoryID   string  `json:"category_id"`
-	CategoryName string  `json:"category_name"`
-	Percentage   float64 `json:"percentage"`
+	CategoryID   string `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	ParentID     string `json:"parent_id,omitempty"`
+	// Percentage rolls up the products of the subcategories, OwnPercentage only counts the category itself
+	Percentage    float64 `json:"percentage"`
+	OwnPercentage float64 `json:"own_percentage"`
 }
 
 type ProductSupplierStatsResponse struct {
@@ -66,3 +166,68 @@ type ProductSupplierStatsResponse struct {
 	SupplierName string  `json:"supplier_name"`
 	Percentage   float64 `json:"percentage"`
 }
+
+type GetProductVersionsRequest struct {
+	Page
+}
+
+type GetProductVersionsResponse struct {
+	Response
+	Pagination
+}
+
+type GetProductVersionData struct {
+	Version   int                  `json:"version" example:"3"`
+	AuthorID  string               `json:"author_id" example:"1sdWdNiYj"`
+	CreatedAt string               `json:"created_at" example:"2024-01-28T10:00:00Z"`
+	Product   GetProductDetailData `json:"product"`
+}
+
+type GetProductAsOfRequest struct {
+	Timestamp string `json:"timestamp" form:"timestamp" binding:"required,datetime=2006-01-02T15:04:05Z07:00" example:"2024-01-28T10:00:00Z"`
+}
+
+type DiffProductVersionsRequest struct {
+	From int `json:"from" form:"from" binding:"required,gte=1" example:"1"`
+	To   int `json:"to" form:"to" binding:"required,gte=1" example:"3"`
+}
+
+type ProductFieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+type DiffProductVersionsData struct {
+	From    int                           `json:"from" example:"1"`
+	To      int                           `json:"to" example:"3"`
+	Changes map[string]ProductFieldChange `json:"changes"`
+}
+
+type ExportProductRequest struct {
+	// PriceDate prints the price in effect on that date instead of the current price
+	PriceDate string `json:"price_date" form:"price_date" binding:"omitempty,datetime=2006-01-02" example:"2024-02-01"`
+}
+
+type GetProductPricesRequest struct {
+	Page
+}
+
+type GetProductPricesResponse struct {
+	Response
+	Pagination
+}
+
+type GetProductPriceData struct {
+	ID            uint64  `json:"id" example:"1"`
+	Price         float64 `json:"price" example:"150000"`
+	EffectiveFrom string  `json:"effective_from" example:"2024-02-01T00:00:00Z"`
+	EffectiveTo   string  `json:"effective_to" example:""`
+	AuthorID      string  `json:"author_id" example:"1sdWdNiYj"`
+	// Scheduled prices are not in effect yet and can be cancelled
+	Scheduled bool `json:"scheduled" example:"false"`
+}
+
+type ScheduleProductPriceRequest struct {
+	Price         float64 `json:"price" binding:"required,gt=0" example:"145000"`
+	EffectiveFrom string  `json:"effective_from" binding:"required,datetime=2006-01-02T15:04:05Z07:00" example:"2024-02-01T00:00:00Z"`
+}
@@ -0,0 +1,66 @@
+package v1
+
+const (
+	BulkSetCategory = "set_category"
+	BulkSetSupplier = "set_supplier"
+	BulkSetStatus   = "set_status"
+	BulkAdjustPrice = "adjust_price"
+)
+
+// The outcome of each product of a bulk operation
+const (
+	BulkResultMatched    = "matched"
+	BulkResultUpdated    = "updated"
+	BulkResultUnchanged  = "unchanged"
+	BulkResultDeleted    = "deleted"
+	BulkResultFailed     = "failed"
+	BulkResultRolledBack = "rolled_back"
+)
+
+// BulkProductTarget selects the products of a bulk operation, either by their references or with the filters of
+// the product search. The page of the filter is ignored, every match is taken up to the limit of the server.
+type BulkProductTarget struct {
+	References []string              `json:"references" binding:"omitempty,dive,product_ref_lookup" example:"PROD-202401-001"`
+	Filter     *SearchProductRequest `json:"filter" binding:"omitempty,structonly"`
+	// Preview only counts and lists the products, nothing is changed
+	Preview bool `json:"preview" example:"false"`
+	// Atomic applies the operation to every product or to none, by default each product is done on its own
+	Atomic bool `json:"atomic" example:"false"`
+}
+
+type BulkUpdateProductsRequest struct {
+	BulkProductTarget
+
+	Action     string `json:"action" binding:"required,oneof=set_category set_supplier set_status adjust_price" example:"set_status"`
+	CategoryId string `json:"category_id" binding:"required_if=Action set_category,omitempty,uuid4" example:"67389c69-2e78-413a-9d77-6b749520b127"`
+	SupplierId string `json:"supplier_id" binding:"required_if=Action set_supplier,omitempty,uuid4" example:"66ae8b26-a0cd-40d5-8b2d-5127d7b9d817"`
+	Status     string `json:"status" binding:"required_if=Action set_status,omitempty,oneof='Draft' 'Available' 'Out of Stock' 'On Order' 'Discontinued' 'Archived'" example:"Discontinued"`
+	// Reason is kept in the status history of the products, it is required to set the status
+	Reason string `json:"reason" binding:"required_if=Action set_status,omitempty,min=3,max=255" example:"End of the summer range"`
+	// PriceAmount or PricePercent is added to the price of each product, the new price is rounded to 2 decimals
+	PriceAmount  float64 `json:"price_amount" example:"-5000"`
+	PricePercent float64 `json:"price_percent" binding:"omitempty,gt=-100" example:"10"`
+}
+
+type BulkDeleteProductsRequest struct {
+	BulkProductTarget
+}
+
+type BulkProductsData struct {
+	Preview bool `json:"preview" example:"false"`
+	Atomic  bool `json:"atomic" example:"false"`
+	// RolledBack is set when an atomic operation failed on a product, nothing was changed
+	RolledBack bool                `json:"rolled_back" example:"false"`
+	Matched    int                 `json:"matched" example:"12"`
+	Succeeded  int                 `json:"succeeded" example:"11"`
+	Failed     int                 `json:"failed" example:"1"`
+	Results    []BulkProductResult `json:"results"`
+}
+
+type BulkProductResult struct {
+	Reference string `json:"reference" example:"PROD-202401-001"`
+	Status    string `json:"status" example:"updated"`
+	// Version is the version of the product after the update
+	Version int    `json:"version,omitempty" example:"4"`
+	Error   string `json:"error,omitempty" example:"The product can't move from its current status to this one."`
+}
@@ -0,0 +1,45 @@
+package v1
+
+type BundleComponentRequest struct {
+	Reference string `json:"reference" binding:"required,product_ref_lookup" example:"PROD-202401-001"`
+	// Quantity is how many of the component go in one bundle
+	Quantity int `json:"quantity" binding:"required,gt=0" example:"2"`
+}
+
+// SetBundleComponentsRequest replaces the components of the bundle, none makes it an ordinary product again
+type SetBundleComponentsRequest struct {
+	Components []BundleComponentRequest `json:"components" binding:"max=20,unique=Reference,dive"`
+}
+
+type BundleComponentData struct {
+	Reference   string `json:"reference" example:"PROD-202401-001"`
+	ProductName string `json:"product_name" example:"Crunchy Munch"`
+	Status      string `json:"status" example:"Available"`
+	// Quantity is how many go in one bundle, Stock is the quantity of the component
+	Quantity int `json:"quantity" example:"2"`
+	Stock    int `json:"stock" example:"15"`
+	// Buildable is how many bundles the stock of the component is enough for, the fewest is the quantity of the bundle
+	Buildable int `json:"buildable" example:"7"`
+}
+
+// IssueBundleRequest takes the stock of the components of the bundles, as ConsumeProductLotsRequest does for each
+type IssueBundleRequest struct {
+	Quantity int `json:"quantity" binding:"required,gt=0" example:"3"`
+	// SourceID is kept in the stock movements of the components, the reference of the bundle by default
+	SourceID string `json:"source_id" binding:"omitempty,max=150" example:"SO-2024-0042"`
+}
+
+type IssueBundleData struct {
+	// Quantity is how many bundles can still be built
+	Quantity   int                   `json:"quantity" example:"4"`
+	Components []IssuedComponentData `json:"components"`
+}
+
+type IssuedComponentData struct {
+	Reference string `json:"reference" example:"PROD-202401-001"`
+	// Issued is what was taken for the bundles, Stock what is left of the component
+	Issued     int                  `json:"issued" example:"6"`
+	Stock      int                  `json:"stock" example:"9"`
+	Lots       []LotConsumptionData `json:"lots"`
+	WithoutLot int                  `json:"without_lot" example:"0"`
+}
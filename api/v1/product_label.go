@@ -0,0 +1,32 @@
+package v1
+
+type GetProductBarcodeRequest struct {
+	// Format of the code, EAN-13 encodes the GTIN of the product and the others its reference
+	Format string `json:"format" form:"format" binding:"required,oneof=code128 ean13 qr" example:"code128"`
+	// Output is the image type, png by default
+	Output string `json:"output" form:"output" binding:"omitempty,oneof=png svg" example:"svg"`
+	// Width and Height are in pixels, 300x100 for bars and 256x256 for QR codes by default
+	Width  int `json:"width" form:"width" binding:"omitempty,gte=32,lte=2000" example:"300"`
+	Height int `json:"height" form:"height" binding:"omitempty,gte=32,lte=2000" example:"100"`
+}
+
+// ExportProductLabelsRequest prints a label for each product of a search page
+type ExportProductLabelsRequest struct {
+	SearchProductRequest
+
+	// Layout is the Avery sheet the labels are printed on
+	Layout string `json:"layout" form:"layout" binding:"required,oneof=L7160 L7163 L7651 5160" example:"L7160"`
+	// Barcode is the format of the code on the labels
+	Barcode string `json:"barcode" form:"barcode" binding:"required,oneof=code128 ean13 qr" example:"code128"`
+	// StartPosition is the first free label of the first sheet, from 1, to finish a sheet already used
+	StartPosition int `json:"start_position" form:"start_position" binding:"omitempty,gte=1" example:"4"`
+}
+
+type ExportProductLabelsData struct {
+	DocumentID string `json:"document_id" example:"8f14e45f-ceea-467f-a0e6-1b2c3d4e5f60"`
+	Filename   string `json:"filename" example:"labels_L7160_20240128_100000.pdf"`
+	Labels     int    `json:"labels" example:"21"`
+	Sheets     int    `json:"sheets" example:"1"`
+	// Skipped are the references of the products that can't be encoded in the barcode format, e.g. without a GTIN for EAN-13
+	Skipped []string `json:"skipped,omitempty" example:"PROD-202401-001"`
+}
@@ -0,0 +1,81 @@
+package v1
+
+type ReceiveProductLotRequest struct {
+	LotNumber      string `json:"lot_number" binding:"required,max=50" example:"L240115-A"`
+	Quantity       int    `json:"quantity" binding:"required,gt=0" example:"24"`
+	ManufacturedAt string `json:"manufactured_at" binding:"omitempty,datetime=2006-01-02" example:"2024-01-15"`
+	ExpiresAt      string `json:"expires_at" binding:"required,datetime=2006-01-02" example:"2024-04-15"`
+	// StockLocation is where the lot is stored, the stock location of the product by default
+	StockLocation string `json:"stock_location" binding:"omitempty,max=100" example:"Ha Noi"`
+}
+
+type GetProductLotsRequest struct {
+	// IncludeEmpty lists the lots used up too
+	IncludeEmpty bool `json:"include_empty" form:"include_empty" example:"false"`
+}
+
+type ProductLotData struct {
+	ID string `json:"id" example:"3b8f0c2e-41d5-4c1e-9d7a-5e2f6a1b9c04"`
+	// Reference and ProductName are only set in the lists of lots of several products
+	Reference        string `json:"reference,omitempty" example:"PROD-202401-001"`
+	ProductName      string `json:"product_name,omitempty" example:"Crunchy Munch"`
+	LotNumber        string `json:"lot_number" example:"L240115-A"`
+	Quantity         int    `json:"quantity" example:"18"`
+	ReceivedQuantity int    `json:"received_quantity" example:"24"`
+	ManufacturedAt   string `json:"manufactured_at,omitempty" example:"2024-01-15"`
+	ExpiresAt        string `json:"expires_at" example:"2024-04-15"`
+	// DaysLeft is negative once the lot has expired
+	DaysLeft      int    `json:"days_left" example:"12"`
+	StockLocation string `json:"stock_location" example:"Ha Noi"`
+	// ExpiryFlaggedAt is when the lot expiry task flagged the lot as nearing expiry
+	ExpiryFlaggedAt string `json:"expiry_flagged_at,omitempty" example:"2024-04-01T06:00:00Z"`
+	ReceivedAt      string `json:"received_at" example:"2024-01-20T09:30:00Z"`
+}
+
+// ConsumeProductLotsRequest issues stock from the lots that have not expired, the first to expire first,
+// then from the stock without lot
+type ConsumeProductLotsRequest struct {
+	Quantity int `json:"quantity" binding:"required,gt=0" example:"6"`
+	// SourceID is kept in the stock movements, e.g. the number of the sales order
+	SourceID string `json:"source_id" binding:"omitempty,max=150" example:"SO-2024-0042"`
+}
+
+type ConsumeProductLotsData struct {
+	// Quantity is the stock of the product left
+	Quantity int                  `json:"quantity" example:"36"`
+	Lots     []LotConsumptionData `json:"lots"`
+	// WithoutLot is what was taken from the stock without lot
+	WithoutLot int `json:"without_lot" example:"0"`
+}
+
+type LotConsumptionData struct {
+	LotNumber string `json:"lot_number" example:"L240115-A"`
+	Quantity  int    `json:"quantity" example:"6"`
+	ExpiresAt string `json:"expires_at" example:"2024-04-15"`
+}
+
+// GetExpiringLotsRequest is a window of expiry dates, from today to 30 days later by default
+type GetExpiringLotsRequest struct {
+	Page
+	From          string `json:"from" form:"from" binding:"omitempty,datetime=2006-01-02" example:"2024-04-01"`
+	To            string `json:"to" form:"to" binding:"omitempty,datetime=2006-01-02" example:"2024-04-30"`
+	StockLocation string `json:"stock_location" form:"stock_location" binding:"omitempty,max=100" example:"Ha Noi"`
+}
+
+type GetExpiringLotsResponse struct {
+	Response
+	Pagination
+}
+
+type ExportExpiringLotsRequest struct {
+	From          string `json:"from" form:"from" binding:"omitempty,datetime=2006-01-02" example:"2024-04-01"`
+	To            string `json:"to" form:"to" binding:"omitempty,datetime=2006-01-02" example:"2024-04-30"`
+	StockLocation string `json:"stock_location" form:"stock_location" binding:"omitempty,max=100" example:"Ha Noi"`
+}
+
+type ExportExpiringLotsData struct {
+	DocumentID string `json:"document_id" example:"8f14e45f-ceea-467f-a0e6-1b2c3d4e5f60"`
+	Filename   string `json:"filename" example:"expiring_lots_20240401_20240430_20240401_100000.pdf"`
+	Lots       int    `json:"lots" example:"12"`
+	Quantity   int    `json:"quantity" example:"164"`
+}
@@ -0,0 +1,24 @@
+package v1
+
+type TransitionProductRequest struct {
+	Status string `json:"status" binding:"required,oneof='Draft' 'Available' 'Out of Stock' 'On Order' 'Discontinued' 'Archived'" example:"Discontinued"`
+	// Reason is kept in the status history of the product
+	Reason string `json:"reason" binding:"required,min=3,max=255" example:"Replaced by the 200g pack"`
+}
+
+type GetProductStatusHistoryRequest struct {
+	Page
+}
+
+type GetProductStatusHistoryResponse struct {
+	Response
+	Pagination
+}
+
+type ProductStatusChangeData struct {
+	FromStatus string `json:"from_status" example:"Available"`
+	ToStatus   string `json:"to_status" example:"Discontinued"`
+	Reason     string `json:"reason,omitempty" example:"Replaced by the 200g pack"`
+	ActorID    string `json:"actor_id,omitempty" example:"3Kf9sQ2"`
+	CreatedAt  string `json:"created_at" example:"2024-01-28T10:00:00Z"`
+}
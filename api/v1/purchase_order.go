@@ -0,0 +1,77 @@
+package v1
+
+type PurchaseOrderLineRequest struct {
+	Reference string  `json:"reference" binding:"required" example:"PROD-2024-01-0001"`
+	Quantity  int     `json:"quantity" binding:"required,gte=1" example:"50"`
+	UnitCost  float64 `json:"unit_cost" binding:"gte=0" example:"12000"`
+}
+
+type CreatePurchaseOrderRequest struct {
+	SupplierId string                     `json:"supplier_id" binding:"required,uuid4" example:"67389c69-2e78-413a-9d77-6b749520b127"`
+	Note       string                     `json:"note" binding:"omitempty,max=1000" example:"Deliver to the back door"`
+	Lines      []PurchaseOrderLineRequest `json:"lines" binding:"required,min=1,max=200,dive"`
+}
+
+// UpdatePurchaseOrderRequest replaces the note and the lines of a draft order
+type UpdatePurchaseOrderRequest struct {
+	Note  string                     `json:"note" binding:"omitempty,max=1000" example:"Deliver to the back door"`
+	Lines []PurchaseOrderLineRequest `json:"lines" binding:"required,min=1,max=200,dive"`
+}
+
+type ReceivePurchaseOrderLineRequest struct {
+	Reference string `json:"reference" binding:"required" example:"PROD-2024-01-0001"`
+	Quantity  int    `json:"quantity" binding:"required,gte=1" example:"20"`
+}
+
+type ReceivePurchaseOrderRequest struct {
+	// Lines lists the received quantities, everything still expected is received when empty
+	Lines []ReceivePurchaseOrderLineRequest `json:"lines" binding:"omitempty,max=200,dive"`
+}
+
+type SearchPurchaseOrderRequest struct {
+	Page
+
+	SupplierId string `json:"supplier_id" form:"supplier_id" binding:"omitempty,uuid4" example:"67389c69-2e78-413a-9d77-6b749520b127"`
+	Status     string `json:"status" form:"status" binding:"omitempty,oneof=draft sent partially_received received cancelled" example:"sent"`
+}
+
+type SearchPurchaseOrderResponse struct {
+	Response
+	Pagination
+}
+
+type PurchaseOrderLineData struct {
+	Reference        string  `json:"reference" example:"PROD-2024-01-0001"`
+	Name             string  `json:"name" example:"Potato chips"`
+	Quantity         int     `json:"quantity" example:"50"`
+	ReceivedQuantity int     `json:"received_quantity" example:"20"`
+	UnitCost         float64 `json:"unit_cost" example:"12000"`
+}
+
+type GetPurchaseOrderDetailData struct {
+	ID           string                  `json:"id" example:"0b5f7a0e-8f0e-4c55-a9f5-8c1e2b1f1d2a"`
+	Number       string                  `json:"number" example:"PO-3G7dRk2Lq"`
+	SupplierID   string                  `json:"supplier_id,omitempty" example:"67389c69-2e78-413a-9d77-6b749520b127"`
+	SupplierName string                  `json:"supplier_name" example:"Crunchy Munch"`
+	Status       string                  `json:"status" example:"sent"`
+	Currency     string                  `json:"currency" example:"VND"`
+	Note         string                  `json:"note,omitempty" example:"Deliver to the back door"`
+	Total        float64                 `json:"total" example:"600000"`
+	ExpectedAt   string                  `json:"expected_at,omitempty" example:"2024-02-04"`
+	SentAt       string                  `json:"sent_at,omitempty" example:"2024-01-28T10:00:00Z"`
+	ReceivedAt   string                  `json:"received_at,omitempty" example:"2024-02-03T15:30:00Z"`
+	CancelledAt  string                  `json:"cancelled_at,omitempty" example:"2024-01-29T09:00:00Z"`
+	CreatedAt    string                  `json:"created_at" example:"2024-01-28T09:00:00Z"`
+	Lines        []PurchaseOrderLineData `json:"lines,omitempty"`
+	Version      int                     `json:"version" example:"1"`
+}
+
+type GetPurchaseOrderDetailResponse struct {
+	Response
+	Data GetPurchaseOrderDetailData
+}
+
+type ExportPurchaseOrderData struct {
+	DocumentID string `json:"document_id" example:"8f14e45f-ceea-467f-a0e6-1b2c3d4e5f60"`
+	Filename   string `json:"filename" example:"purchase_order_PO-3G7dRk2Lq_20240128_100000.pdf"`
+}
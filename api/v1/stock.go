@@ -0,0 +1,10 @@
+package v1
+
+// StockUpdateMessage is pushed by external systems (ERP, WMS...) on the stock updates topic
+type StockUpdateMessage struct {
+	Reference string `json:"reference" example:"PROD-202401-001"`
+	// Mode "set" replaces the quantity, "adjust" adds Quantity (which may be negative) to it
+	Mode     string `json:"mode" example:"set"`
+	Quantity int    `json:"quantity" example:"10"`
+	Source   string `json:"source" example:"erp"`
+}
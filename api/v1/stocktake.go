@@ -0,0 +1,98 @@
+package v1
+
+// CreateStocktakeRequest opens a stocktake of the products of a stock location, of a category, or of both
+type CreateStocktakeRequest struct {
+	StockLocation string `json:"stock_location" binding:"required_without=CategoryId,omitempty,max=100" example:"Ha Noi"`
+	CategoryId    string `json:"category_id" binding:"required_without=StockLocation,omitempty,uuid4" example:"67389c69-2e78-413a-9d77-6b749520b127"`
+	// IncludeSubcategories extends the category to its whole subtree
+	IncludeSubcategories bool   `json:"include_subcategories" example:"true"`
+	Note                 string `json:"note" binding:"omitempty,max=1000" example:"Quarterly count of the back store"`
+}
+
+type StocktakeCountRequest struct {
+	Reference string `json:"reference" binding:"required,product_ref_lookup" example:"PROD-202401-001"`
+	Quantity  int    `json:"quantity" binding:"gte=0" example:"12"`
+}
+
+// SubmitStocktakeCountsRequest saves what a device counted. The rule is one count per product and device: a new
+// count replaces the previous one of the same device, and the counted quantity of a product is the sum over the
+// devices, which count separate places. A product is listed once per submission, a duplicate is refused.
+type SubmitStocktakeCountsRequest struct {
+	DeviceID string                  `json:"device_id" binding:"required,max=100" example:"scanner-02"`
+	Counts   []StocktakeCountRequest `json:"counts" binding:"required,min=1,max=500,unique=Reference,dive"`
+}
+
+// ApproveStocktakeRequest applies the variances to the stock of the products
+type ApproveStocktakeRequest struct {
+	// ZeroUncounted sets the stock of the products nobody counted to 0, they are left as is by default
+	ZeroUncounted bool `json:"zero_uncounted" example:"false"`
+}
+
+type SearchStocktakeRequest struct {
+	Page
+
+	Status        string `json:"status" form:"status" binding:"omitempty,oneof=open approved cancelled" example:"open"`
+	StockLocation string `json:"stock_location" form:"stock_location" binding:"omitempty,max=100" example:"Ha Noi"`
+}
+
+type SearchStocktakeResponse struct {
+	Response
+	Pagination
+}
+
+type GetStocktakeDetailData struct {
+	ID                   string `json:"id" example:"0b5f7a0e-8f0e-4c55-a9f5-8c1e2b1f1d2a"`
+	Number               string `json:"number" example:"ST-3G7dRk2Lq"`
+	Status               string `json:"status" example:"open"`
+	StockLocation        string `json:"stock_location,omitempty" example:"Ha Noi"`
+	CategoryID           string `json:"category_id,omitempty" example:"67389c69-2e78-413a-9d77-6b749520b127"`
+	IncludeSubcategories bool   `json:"include_subcategories" example:"true"`
+	Note                 string `json:"note,omitempty" example:"Quarterly count of the back store"`
+	CreatedBy            string `json:"created_by" example:"3G7dRk2Lq"`
+	ApprovedBy           string `json:"approved_by,omitempty" example:"8hTq1LmZx"`
+	ApprovedAt           string `json:"approved_at,omitempty" example:"2024-02-03T15:30:00Z"`
+	CancelledAt          string `json:"cancelled_at,omitempty" example:"2024-01-29T09:00:00Z"`
+	CreatedAt            string `json:"created_at" example:"2024-01-28T09:00:00Z"`
+	// Products is the number of lines, Counted the number of them counted so far
+	Products int                 `json:"products" example:"42"`
+	Counted  int                 `json:"counted" example:"40"`
+	Lines    []StocktakeLineData `json:"lines,omitempty"`
+	Version  int                 `json:"version" example:"3"`
+}
+
+type GetStocktakeDetailResponse struct {
+	Response
+	Data GetStocktakeDetailData
+}
+
+type StocktakeLineData struct {
+	Reference     string `json:"reference" example:"PROD-202401-001"`
+	ProductName   string `json:"product_name" example:"Crunchy Munch"`
+	StockLocation string `json:"stock_location,omitempty" example:"Ha Noi"`
+	// SystemQuantity is the stock of the product now, or when the stocktake was approved
+	SystemQuantity  int  `json:"system_quantity" example:"12"`
+	CountedQuantity *int `json:"counted_quantity,omitempty" example:"10"`
+	// Variance is the counted quantity less the system quantity, VarianceValue the variance at the price of the product
+	Variance      *int     `json:"variance,omitempty" example:"-2"`
+	VarianceValue *float64 `json:"variance_value,omitempty" example:"-300000"`
+	// Adjustment is the change of stock applied by the approval
+	Adjustment *int `json:"adjustment,omitempty" example:"-2"`
+}
+
+// GetStocktakeVarianceData lists the lines with a variance and those not counted
+type GetStocktakeVarianceData struct {
+	Number string `json:"number" example:"ST-3G7dRk2Lq"`
+	Status string `json:"status" example:"open"`
+	// Shrinkage and Surplus add up the variances under and over 0, in quantity and at the price of the products
+	Shrinkage      int                 `json:"shrinkage" example:"-5"`
+	Surplus        int                 `json:"surplus" example:"2"`
+	ShrinkageValue float64             `json:"shrinkage_value" example:"-750000"`
+	SurplusValue   float64             `json:"surplus_value" example:"300000"`
+	Lines          []StocktakeLineData `json:"lines"`
+	Uncounted      []StocktakeLineData `json:"uncounted"`
+}
+
+type ExportStocktakeData struct {
+	DocumentID string `json:"document_id" example:"8f14e45f-ceea-467f-a0e6-1b2c3d4e5f60"`
+	Filename   string `json:"filename" example:"stocktake_sheet_ST-3G7dRk2Lq_20240128_100000.pdf"`
+}
@@ -1,14 +1,66 @@
 package v1
 
+type SupplierContactRequest struct {
+	Name  string `json:"name" binding:"required,min=2,max=100" example:"Nguyen Van A"`
+	Role  string `json:"role" binding:"omitempty,max=100" example:"Sales"`
+	Email string `json:"email" binding:"omitempty,email,max=255" example:"sales@crunchymunch.com"`
+	Phone string `json:"phone" binding:"omitempty,max=50" example:"+84 28 3822 1234"`
+}
+
+type SupplierAddressRequest struct {
+	Kind       string `json:"kind" binding:"required,oneof=billing shipping office warehouse" example:"billing"`
+	Line1      string `json:"line1" binding:"required,max=255" example:"12 Nguyen Hue"`
+	Line2      string `json:"line2" binding:"omitempty,max=255" example:"Floor 3"`
+	City       string `json:"city" binding:"required,max=100" example:"Ho Chi Minh"`
+	PostalCode string `json:"postal_code" binding:"omitempty,max=20" example:"700000"`
+	Country    string `json:"country" binding:"required,iso3166_1_alpha2" example:"VN"`
+}
+
 type CreateSupplierRequest struct {
-	Name string `json:"name" form:"name" binding:"omitempty,min=3,max=100" example:"Crunchy Munch"`
+	Name  string `json:"name" form:"name" binding:"omitempty,min=3,max=100" example:"Crunchy Munch"`
+	Email string `json:"email" binding:"omitempty,email,max=255" example:"contact@crunchymunch.com"`
+	Phone string `json:"phone" binding:"omitempty,max=50" example:"+84 28 3822 1234"`
+	// Currency is an ISO 4217 code, VND when empty
+	Currency     string `json:"currency" binding:"omitempty,iso4217" example:"VND"`
+	PaymentTerms string `json:"payment_terms" binding:"omitempty,max=100" example:"Net 30"`
+	LeadTimeDays int    `json:"lead_time_days" binding:"omitempty,gte=0,lte=365" example:"7"`
+	Status       string `json:"status" binding:"omitempty,oneof='active' 'deactive'" example:"active"`
+
+	Contacts  []SupplierContactRequest `json:"contacts" binding:"omitempty,max=20,dive"`
+	Addresses []SupplierAddressRequest `json:"addresses" binding:"omitempty,max=20,dive"`
+}
+
+type UpdateSupplierRequest struct {
+	Name         string `json:"name" binding:"required,min=3,max=100" example:"Crunchy Munch"`
+	Email        string `json:"email" binding:"omitempty,email,max=255" example:"contact@crunchymunch.com"`
+	Phone        string `json:"phone" binding:"omitempty,max=50" example:"+84 28 3822 1234"`
+	Currency     string `json:"currency" binding:"required,iso4217" example:"VND"`
+	PaymentTerms string `json:"payment_terms" binding:"omitempty,max=100" example:"Net 30"`
+	LeadTimeDays int    `json:"lead_time_days" binding:"gte=0,lte=365" example:"7"`
+	Status       string `json:"status" binding:"required,oneof='active' 'deactive'" example:"active"`
+
+	// Contacts and Addresses replace the current ones
+	Contacts  []SupplierContactRequest `json:"contacts" binding:"omitempty,max=20,dive"`
+	Addresses []SupplierAddressRequest `json:"addresses" binding:"omitempty,max=20,dive"`
+}
+
+type MergeSupplierRequest struct {
+	// TargetId receives the products, contacts and addresses, the merged supplier goes to the trash
+	TargetId string `json:"target_id" binding:"required,uuid4" example:"67389c69-2e78-413a-9d77-6b749520b127"`
+}
+
+type MergeSupplierData struct {
+	MovedProducts  int64 `json:"moved_products" example:"12"`
+	MovedContacts  int   `json:"moved_contacts" example:"1"`
+	MovedAddresses int   `json:"moved_addresses" example:"0"`
 }
 
 type SearchSupplierRequest struct {
 	Page
 	Sorting
 
-	Name string `json:"name" form:"name" binding:"omitempty,min=3,max=100" example:"Crunchy Munch"`
+	Name   string `json:"name" form:"name" binding:"omitempty,min=3,max=100" example:"Crunchy Munch"`
+	Status string `json:"status" form:"status" binding:"omitempty,oneof='active' 'deactive'" example:"active"`
 }
 
 type SearchSupplierResponse struct {
@@ -16,9 +68,44 @@ type SearchSupplierResponse struct {
 	Pagination
 }
 
+type SupplierContactData struct {
+	Name  string `json:"name" example:"Nguyen Van A"`
+	Role  string `json:"role,omitempty" example:"Sales"`
+	Email string `json:"email,omitempty" example:"sales@crunchymunch.com"`
+	Phone string `json:"phone,omitempty" example:"+84 28 3822 1234"`
+}
+
+type SupplierAddressData struct {
+	Kind       string `json:"kind" example:"billing"`
+	Line1      string `json:"line1" example:"12 Nguyen Hue"`
+	Line2      string `json:"line2,omitempty" example:"Floor 3"`
+	City       string `json:"city" example:"Ho Chi Minh"`
+	PostalCode string `json:"postal_code,omitempty" example:"700000"`
+	Country    string `json:"country" example:"VN"`
+}
+
+// SupplierStockData sums up the live products of a supplier
+type SupplierStockData struct {
+	ProductCount int64 `json:"product_count" example:"12"`
+	Quantity     int64 `json:"quantity" example:"340"`
+	// StockValue is the sum of quantity times price, in the currency of the products
+	StockValue float64 `json:"stock_value" example:"1520000"`
+}
+
 type GetSupplierDetailData struct {
-	ID   string `json:"id" example:"67389c69-2e78-413a-9d77-6b749520b127"`
-	Name string `json:"name" example:"Food"`
+	ID           string                `json:"id" example:"67389c69-2e78-413a-9d77-6b749520b127"`
+	Name         string                `json:"name" example:"Food"`
+	Email        string                `json:"email,omitempty" example:"contact@crunchymunch.com"`
+	Phone        string                `json:"phone,omitempty" example:"+84 28 3822 1234"`
+	Currency     string                `json:"currency" example:"VND"`
+	PaymentTerms string                `json:"payment_terms,omitempty" example:"Net 30"`
+	LeadTimeDays int                   `json:"lead_time_days" example:"7"`
+	Status       string                `json:"status" example:"active"`
+	Contacts     []SupplierContactData `json:"contacts,omitempty"`
+	Addresses    []SupplierAddressData `json:"addresses,omitempty"`
+	// Stock is only filled in the supplier detail
+	Stock   *SupplierStockData `json:"stock,omitempty"`
+	Version int                `json:"version" example:"1"`
 }
 
 type GetSupplierDetailResponse struct {
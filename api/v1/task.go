@@ -0,0 +1,47 @@
+package v1
+
+type GetTaskData struct {
+	Name    string `json:"name" example:"check_user"`
+	Cron    string `json:"cron" example:"0 */5 * * * *"`
+	Timeout string `json:"timeout" example:"1m0s"`
+	Overlap string `json:"overlap" example:"skip"`
+	DryRun  bool   `json:"dry_run" example:"false"`
+}
+
+type TriggerTaskRequest struct {
+	DryRun bool `json:"dry_run" form:"dry_run" example:"true"`
+}
+
+type SearchTaskRunRequest struct {
+	Page
+
+	TaskName string `json:"task_name" form:"task_name" binding:"omitempty,max=100" example:"check_user"`
+	Status   string `json:"status" form:"status" binding:"omitempty,oneof=pending running success failed timeout skipped" example:"failed"`
+	DateFrom string `json:"date_from" form:"date_from" binding:"omitempty,datetime=2006-01-02" example:"2024-01-28"`
+	DateTo   string `json:"date_to" form:"date_to" binding:"omitempty,datetime=2006-01-02" example:"2024-01-28"`
+}
+
+type SearchTaskRunResponse struct {
+	Response
+	Pagination
+}
+
+type GetTaskRunData struct {
+	ID          uint64      `json:"id" example:"1"`
+	TaskName    string      `json:"task_name" example:"check_user"`
+	Trigger     string      `json:"trigger" example:"schedule"`
+	TriggeredBy string      `json:"triggered_by" example:"1sdWdNiYj"`
+	DryRun      bool        `json:"dry_run" example:"false"`
+	Status      string      `json:"status" example:"success"`
+	Error       string      `json:"error" example:""`
+	Result      interface{} `json:"result"`
+	Host        string      `json:"host" example:"task-7d9f8"`
+	ScheduledAt string      `json:"scheduled_at" example:"2024-01-28T10:00:00Z"`
+	StartedAt   string      `json:"started_at" example:"2024-01-28T10:00:00Z"`
+	FinishedAt  string      `json:"finished_at" example:"2024-01-28T10:00:01Z"`
+}
+
+type GetTaskRunResponse struct {
+	Response
+	Data GetTaskRunData
+}
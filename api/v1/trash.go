@@ -0,0 +1,17 @@
+package v1
+
+type GetTrashRequest struct {
+	Page
+}
+
+type GetTrashResponse struct {
+	Response
+	Pagination
+}
+
+type TrashItemData struct {
+	// ID is the reference for products
+	ID        string `json:"id" example:"PROD-202401-001"`
+	Name      string `json:"name" example:"Crunchy Munch"`
+	DeletedAt string `json:"deleted_at" example:"2024-01-28T10:00:00Z"`
+}
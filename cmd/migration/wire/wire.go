@@ -21,6 +21,13 @@ var repositorySet = wire.NewSet(
 	repository.NewSupplierRepository,
 	repository.NewCategoryRepository,
 	repository.NewDocumentsRepository,
+	repository.NewOutboxRepository,
+	repository.NewProcessedMessageRepository,
+	repository.NewStockMovementRepository,
+	repository.NewTaskRunRepository,
+	repository.NewAuditLogRepository,
+	repository.NewProductVersionRepository,
+	repository.NewProductPriceRepository,
 )
 var serverSet = wire.NewSet(
 	server.NewMigrateServer,
@@ -27,7 +27,7 @@ func NewWire(viperViper *viper.Viper, logger *log.Logger) (*app.App, func(), err
 
 // wire.go:
 
-var repositorySet = wire.NewSet(repository.NewDB, repository.NewRepository, repository.NewUserRepository, repository.NewProductRepository, repository.NewSupplierRepository, repository.NewCategoryRepository, repository.NewDocumentsRepository)
+var repositorySet = wire.NewSet(repository.NewDB, repository.NewRepository, repository.NewUserRepository, repository.NewProductRepository, repository.NewSupplierRepository, repository.NewCategoryRepository, repository.NewDocumentsRepository, repository.NewOutboxRepository, repository.NewProcessedMessageRepository, repository.NewStockMovementRepository, repository.NewTaskRunRepository, repository.NewAuditLogRepository, repository.NewProductVersionRepository, repository.NewProductPriceRepository)
 
 var serverSet = wire.NewSet(server.NewMigrateServer)
 
@@ -10,7 +10,9 @@ import (
 	"github.com/quydmfl/niveau-test/internal/repository"
 	"github.com/quydmfl/niveau-test/internal/server"
 	"github.com/quydmfl/niveau-test/internal/service"
+	"github.com/quydmfl/niveau-test/internal/task"
 	"github.com/quydmfl/niveau-test/pkg/app"
+	"github.com/quydmfl/niveau-test/pkg/broker"
 	"github.com/quydmfl/niveau-test/pkg/jwt"
 	"github.com/quydmfl/niveau-test/pkg/log"
 	"github.com/quydmfl/niveau-test/pkg/server/http"
@@ -23,11 +25,27 @@ var repositorySet = wire.NewSet(
 	//repository.NewRedis,
 	repository.NewRepository,
 	repository.NewTransaction,
+	repository.NewLocker,
 	repository.NewUserRepository,
 	repository.NewProductRepository,
 	repository.NewSupplierRepository,
 	repository.NewCategoryRepository,
 	repository.NewDocumentsRepository,
+	repository.NewOutboxRepository,
+	repository.NewProcessedMessageRepository,
+	repository.NewStockMovementRepository,
+	repository.NewTaskRunRepository,
+	repository.NewAuditLogRepository,
+	repository.NewProductVersionRepository,
+	repository.NewProductPriceRepository,
+	repository.NewProductStatusChangeRepository,
+	repository.NewProductImageRepository,
+	repository.NewProductLotRepository,
+	repository.NewProductForecastRepository,
+	repository.NewBundleComponentRepository,
+	repository.NewReferenceCounterRepository,
+	repository.NewPurchaseOrderRepository,
+	repository.NewStocktakeRepository,
 )
 
 var serviceSet = wire.NewSet(
@@ -36,6 +54,11 @@ var serviceSet = wire.NewSet(
 	service.NewProductService,
 	service.NewSupplierService,
 	service.NewCategoryService,
+	service.NewTaskService,
+	service.NewAuditService,
+	service.NewPurchaseOrderService,
+	service.NewStocktakeService,
+	service.NewForecastService,
 )
 
 var handlerSet = wire.NewSet(
@@ -44,11 +67,31 @@ var handlerSet = wire.NewSet(
 	handler.NewProductHandler,
 	handler.NewSupplierHandler,
 	handler.NewCategoryHandler,
+	handler.NewTaskHandler,
+	handler.NewAuditHandler,
+	handler.NewPurchaseOrderHandler,
+	handler.NewStocktakeHandler,
+	handler.NewForecastHandler,
 )
 
 var jobSet = wire.NewSet(
 	job.NewJob,
-	job.NewUserJob,
+	job.NewOutboxJob,
+	job.NewConsumer,
+	job.NewStockJob,
+)
+
+// Tasks are only triggered manually from the server, the scheduler runs in cmd/task
+var taskSet = wire.NewSet(
+	task.NewTask,
+	task.NewUserTask,
+	task.NewDocumentTask,
+	task.NewPriceTask,
+	task.NewTrashTask,
+	task.NewLotTask,
+	task.NewForecastTask,
+	task.NewTaskProviders,
+	task.NewRegistry,
 )
 var serverSet = wire.NewSet(
 	server.NewHTTPServer,
@@ -73,9 +116,11 @@ func NewWire(*viper.Viper, *log.Logger) (*app.App, func(), error) {
 		serviceSet,
 		handlerSet,
 		jobSet,
+		taskSet,
 		serverSet,
 		sid.NewSid,
 		jwt.NewJwt,
+		broker.NewBroker,
 		newApp,
 	))
 }
@@ -13,7 +13,9 @@ import (
 	"github.com/quydmfl/niveau-test/internal/repository"
 	"github.com/quydmfl/niveau-test/internal/server"
 	"github.com/quydmfl/niveau-test/internal/service"
+	"github.com/quydmfl/niveau-test/internal/task"
 	"github.com/quydmfl/niveau-test/pkg/app"
+	"github.com/quydmfl/niveau-test/pkg/broker"
 	"github.com/quydmfl/niveau-test/pkg/jwt"
 	"github.com/quydmfl/niveau-test/pkg/log"
 	"github.com/quydmfl/niveau-test/pkg/server/http"
@@ -38,30 +40,72 @@ func NewWire(viperViper *viper.Viper, logger *log.Logger) (*app.App, func(), err
 	categoryRepository := repository.NewCategoryRepository(repositoryRepository)
 	documentsRepository := repository.NewDocumentsRepository(repositoryRepository)
 	supplierRepository := repository.NewSupplierRepository(repositoryRepository)
-	productService := service.NewProductService(serviceService, productRepository, categoryRepository, documentsRepository, supplierRepository)
+	outboxRepository := repository.NewOutboxRepository(repositoryRepository)
+	productVersionRepository := repository.NewProductVersionRepository(repositoryRepository)
+	productPriceRepository := repository.NewProductPriceRepository(repositoryRepository)
+	productStatusChangeRepository := repository.NewProductStatusChangeRepository(repositoryRepository)
+	productImageRepository := repository.NewProductImageRepository(repositoryRepository)
+	productLotRepository := repository.NewProductLotRepository(repositoryRepository)
+	stockMovementRepository := repository.NewStockMovementRepository(repositoryRepository)
+	referenceCounterRepository := repository.NewReferenceCounterRepository(repositoryRepository)
+	bundleComponentRepository := repository.NewBundleComponentRepository(repositoryRepository)
+	productService := service.NewProductService(serviceService, viperViper, productRepository, categoryRepository, documentsRepository, supplierRepository, outboxRepository, productVersionRepository, productPriceRepository, productStatusChangeRepository, productImageRepository, productLotRepository, stockMovementRepository, referenceCounterRepository, bundleComponentRepository)
 	productHandler := handler.NewProductHandler(handlerHandler, productService)
-	categoryService := service.NewCategoryService(serviceService, categoryRepository)
+	categoryService := service.NewCategoryService(serviceService, categoryRepository, productRepository, productVersionRepository, outboxRepository)
 	categoryHandler := handler.NewCategoryHandler(handlerHandler, categoryService)
-	supplierService := service.NewSupplierService(serviceService, supplierRepository)
+	supplierService := service.NewSupplierService(serviceService, supplierRepository, productRepository, productVersionRepository, outboxRepository)
 	supplierHandler := handler.NewSupplierHandler(handlerHandler, supplierService)
-	httpServer := server.NewHTTPServer(logger, viperViper, jwtJWT, userHandler, productHandler, categoryHandler, supplierHandler)
+	locker := repository.NewLocker(viperViper, db)
+	taskRunRepository := repository.NewTaskRunRepository(repositoryRepository)
+	taskTask := task.NewTask(transaction, logger, sidSid)
+	userTask := task.NewUserTask(taskTask, userRepository)
+	documentTask := task.NewDocumentTask(taskTask, viperViper, documentsRepository)
+	priceTask := task.NewPriceTask(taskTask, productRepository, productPriceRepository, productVersionRepository, outboxRepository)
+	trashTask := task.NewTrashTask(taskTask, viperViper, productRepository, categoryRepository, supplierRepository, productImageRepository)
+	lotTask := task.NewLotTask(taskTask, viperViper, productLotRepository, outboxRepository)
+	productForecastRepository := repository.NewProductForecastRepository(repositoryRepository)
+	purchaseOrderRepository := repository.NewPurchaseOrderRepository(repositoryRepository)
+	forecastTask := task.NewForecastTask(taskTask, viperViper, productForecastRepository, stockMovementRepository, purchaseOrderRepository)
+	v := task.NewTaskProviders(userTask, documentTask, priceTask, trashTask, lotTask, forecastTask)
+	registry := task.NewRegistry(logger, viperViper, locker, taskRunRepository, v)
+	taskService := service.NewTaskService(serviceService, registry, taskRunRepository)
+	taskHandler := handler.NewTaskHandler(handlerHandler, taskService)
+	auditLogRepository := repository.NewAuditLogRepository(repositoryRepository)
+	auditService := service.NewAuditService(serviceService, auditLogRepository)
+	auditHandler := handler.NewAuditHandler(handlerHandler, auditService)
+	purchaseOrderService := service.NewPurchaseOrderService(serviceService, purchaseOrderRepository, supplierRepository, productRepository, stockMovementRepository, documentsRepository, productStatusChangeRepository, bundleComponentRepository)
+	purchaseOrderHandler := handler.NewPurchaseOrderHandler(handlerHandler, purchaseOrderService)
+	stocktakeRepository := repository.NewStocktakeRepository(repositoryRepository)
+	stocktakeService := service.NewStocktakeService(serviceService, viperViper, stocktakeRepository, productRepository, categoryRepository, productLotRepository, stockMovementRepository, productStatusChangeRepository, documentsRepository)
+	stocktakeHandler := handler.NewStocktakeHandler(handlerHandler, stocktakeService)
+	forecastService := service.NewForecastService(serviceService, productForecastRepository, productRepository)
+	forecastHandler := handler.NewForecastHandler(handlerHandler, forecastService)
+	httpServer := server.NewHTTPServer(logger, viperViper, jwtJWT, userHandler, productHandler, categoryHandler, supplierHandler, taskHandler, auditHandler, purchaseOrderHandler, stocktakeHandler, forecastHandler)
 	jobJob := job.NewJob(transaction, logger, sidSid)
-	userJob := job.NewUserJob(jobJob, userRepository)
-	jobServer := server.NewJobServer(logger, userJob)
+	brokerBroker, cleanup := broker.NewBroker(viperViper)
+	processedMessageRepository := repository.NewProcessedMessageRepository(repositoryRepository)
+	consumer := job.NewConsumer(jobJob, viperViper, brokerBroker, processedMessageRepository)
+	outboxJob := job.NewOutboxJob(jobJob, viperViper, outboxRepository, brokerBroker)
+	stockJob := job.NewStockJob(jobJob, productRepository, stockMovementRepository, productStatusChangeRepository, productLotRepository, bundleComponentRepository)
+	jobServer := server.NewJobServer(logger, viperViper, consumer, outboxJob, stockJob)
 	appApp := newApp(httpServer, jobServer)
 	return appApp, func() {
+		cleanup()
 	}, nil
 }
 
 // wire.go:
 
-var repositorySet = wire.NewSet(repository.NewDB, repository.NewRepository, repository.NewTransaction, repository.NewUserRepository, repository.NewProductRepository, repository.NewSupplierRepository, repository.NewCategoryRepository, repository.NewDocumentsRepository)
+var repositorySet = wire.NewSet(repository.NewDB, repository.NewRepository, repository.NewTransaction, repository.NewLocker, repository.NewUserRepository, repository.NewProductRepository, repository.NewSupplierRepository, repository.NewCategoryRepository, repository.NewDocumentsRepository, repository.NewOutboxRepository, repository.NewProcessedMessageRepository, repository.NewStockMovementRepository, repository.NewTaskRunRepository, repository.NewAuditLogRepository, repository.NewProductVersionRepository, repository.NewProductPriceRepository, repository.NewProductStatusChangeRepository, repository.NewProductImageRepository, repository.NewProductLotRepository, repository.NewProductForecastRepository, repository.NewBundleComponentRepository, repository.NewReferenceCounterRepository, repository.NewPurchaseOrderRepository, repository.NewStocktakeRepository)
 
-var serviceSet = wire.NewSet(service.NewService, service.NewUserService, service.NewProductService, service.NewSupplierService, service.NewCategoryService)
+var serviceSet = wire.NewSet(service.NewService, service.NewUserService, service.NewProductService, service.NewSupplierService, service.NewCategoryService, service.NewTaskService, service.NewAuditService, service.NewPurchaseOrderService, service.NewStocktakeService, service.NewForecastService)
 
-var handlerSet = wire.NewSet(handler.NewHandler, handler.NewUserHandler, handler.NewProductHandler, handler.NewSupplierHandler, handler.NewCategoryHandler)
+var handlerSet = wire.NewSet(handler.NewHandler, handler.NewUserHandler, handler.NewProductHandler, handler.NewSupplierHandler, handler.NewCategoryHandler, handler.NewTaskHandler, handler.NewAuditHandler, handler.NewPurchaseOrderHandler, handler.NewStocktakeHandler, handler.NewForecastHandler)
 
-var jobSet = wire.NewSet(job.NewJob, job.NewUserJob)
+var jobSet = wire.NewSet(job.NewJob, job.NewOutboxJob, job.NewConsumer, job.NewStockJob)
+
+// Tasks are only triggered manually from the server, the scheduler runs in cmd/task
+var taskSet = wire.NewSet(task.NewTask, task.NewUserTask, task.NewDocumentTask, task.NewPriceTask, task.NewTrashTask, task.NewLotTask, task.NewForecastTask, task.NewTaskProviders, task.NewRegistry)
 
 var serverSet = wire.NewSet(server.NewHTTPServer, server.NewJobServer)
 
@@ -19,16 +19,36 @@ var repositorySet = wire.NewSet(
 	//repository.NewRedis,
 	repository.NewRepository,
 	repository.NewTransaction,
+	repository.NewLocker,
 	repository.NewUserRepository,
 	repository.NewProductRepository,
 	repository.NewSupplierRepository,
 	repository.NewCategoryRepository,
 	repository.NewDocumentsRepository,
+	repository.NewOutboxRepository,
+	repository.NewProcessedMessageRepository,
+	repository.NewStockMovementRepository,
+	repository.NewTaskRunRepository,
+	repository.NewAuditLogRepository,
+	repository.NewProductVersionRepository,
+	repository.NewProductPriceRepository,
+	repository.NewProductStatusChangeRepository,
+	repository.NewProductImageRepository,
+	repository.NewProductLotRepository,
+	repository.NewProductForecastRepository,
+	repository.NewPurchaseOrderRepository,
 )
 
 var taskSet = wire.NewSet(
 	task.NewTask,
 	task.NewUserTask,
+	task.NewDocumentTask,
+	task.NewPriceTask,
+	task.NewTrashTask,
+	task.NewLotTask,
+	task.NewForecastTask,
+	task.NewTaskProviders,
+	task.NewRegistry,
 )
 var serverSet = wire.NewSet(
 	server.NewTaskServer,
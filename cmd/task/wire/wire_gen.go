@@ -21,13 +21,34 @@ import (
 
 func NewWire(viperViper *viper.Viper, logger *log.Logger) (*app.App, func(), error) {
 	db := repository.NewDB(viperViper, logger)
+	locker := repository.NewLocker(viperViper, db)
 	repositoryRepository := repository.NewRepository(logger, db)
+	taskRunRepository := repository.NewTaskRunRepository(repositoryRepository)
 	transaction := repository.NewTransaction(repositoryRepository)
 	sidSid := sid.NewSid()
 	taskTask := task.NewTask(transaction, logger, sidSid)
 	userRepository := repository.NewUserRepository(repositoryRepository)
 	userTask := task.NewUserTask(taskTask, userRepository)
-	taskServer := server.NewTaskServer(logger, userTask)
+	documentsRepository := repository.NewDocumentsRepository(repositoryRepository)
+	documentTask := task.NewDocumentTask(taskTask, viperViper, documentsRepository)
+	productRepository := repository.NewProductRepository(repositoryRepository)
+	productPriceRepository := repository.NewProductPriceRepository(repositoryRepository)
+	productVersionRepository := repository.NewProductVersionRepository(repositoryRepository)
+	outboxRepository := repository.NewOutboxRepository(repositoryRepository)
+	priceTask := task.NewPriceTask(taskTask, productRepository, productPriceRepository, productVersionRepository, outboxRepository)
+	categoryRepository := repository.NewCategoryRepository(repositoryRepository)
+	supplierRepository := repository.NewSupplierRepository(repositoryRepository)
+	productImageRepository := repository.NewProductImageRepository(repositoryRepository)
+	trashTask := task.NewTrashTask(taskTask, viperViper, productRepository, categoryRepository, supplierRepository, productImageRepository)
+	productLotRepository := repository.NewProductLotRepository(repositoryRepository)
+	lotTask := task.NewLotTask(taskTask, viperViper, productLotRepository, outboxRepository)
+	productForecastRepository := repository.NewProductForecastRepository(repositoryRepository)
+	stockMovementRepository := repository.NewStockMovementRepository(repositoryRepository)
+	purchaseOrderRepository := repository.NewPurchaseOrderRepository(repositoryRepository)
+	forecastTask := task.NewForecastTask(taskTask, viperViper, productForecastRepository, stockMovementRepository, purchaseOrderRepository)
+	v := task.NewTaskProviders(userTask, documentTask, priceTask, trashTask, lotTask, forecastTask)
+	registry := task.NewRegistry(logger, viperViper, locker, taskRunRepository, v)
+	taskServer := server.NewTaskServer(logger, registry)
 	appApp := newApp(taskServer)
 	return appApp, func() {
 	}, nil
@@ -35,9 +56,9 @@ func NewWire(viperViper *viper.Viper, logger *log.Logger) (*app.App, func(), err
 
 // wire.go:
 
-var repositorySet = wire.NewSet(repository.NewDB, repository.NewRepository, repository.NewTransaction, repository.NewUserRepository, repository.NewProductRepository, repository.NewSupplierRepository, repository.NewCategoryRepository, repository.NewDocumentsRepository)
+var repositorySet = wire.NewSet(repository.NewDB, repository.NewRepository, repository.NewTransaction, repository.NewLocker, repository.NewUserRepository, repository.NewProductRepository, repository.NewSupplierRepository, repository.NewCategoryRepository, repository.NewDocumentsRepository, repository.NewOutboxRepository, repository.NewProcessedMessageRepository, repository.NewStockMovementRepository, repository.NewTaskRunRepository, repository.NewAuditLogRepository, repository.NewProductVersionRepository, repository.NewProductPriceRepository, repository.NewProductStatusChangeRepository, repository.NewProductImageRepository, repository.NewProductLotRepository, repository.NewProductForecastRepository, repository.NewPurchaseOrderRepository)
 
-var taskSet = wire.NewSet(task.NewTask, task.NewUserTask)
+var taskSet = wire.NewSet(task.NewTask, task.NewUserTask, task.NewDocumentTask, task.NewPriceTask, task.NewTrashTask, task.NewLotTask, task.NewForecastTask, task.NewTaskProviders, task.NewRegistry)
 
 var serverSet = wire.NewSet(server.NewTaskServer)
 
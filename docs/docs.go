@@ -24,8 +24,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
-            "post": {
+        "/admin/audit-logs": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve a paginated list of write operations with their changed fields, latest first.",
                 "consumes": [
                     "application/json"
                 ],
@@ -33,33 +39,100 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth Modules"
+                    "Admin Modules"
                 ],
-                "summary": "Account Login",
+                "summary": "Get audit logs",
                 "parameters": [
                     {
-                        "description": "params",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.LoginRequest"
-                        }
+                        "type": "string",
+                        "example": "\"products\"",
+                        "description": "Filter by entity type (table name)",
+                        "name": "entity_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by entity ID",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"1sdWdNiYj\"",
+                        "description": "Filter by actor",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "create",
+                            "update",
+                            "delete"
+                        ],
+                        "type": "string",
+                        "description": "Filter by action",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "example": "\"2024-01-28\"",
+                        "description": "Filter by start date (YYYY-MM-DD)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "example": "\"2024-01-28\"",
+                        "description": "Filter by end date (YYYY-MM-DD)",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "example": 1,
+                        "description": "Page number (must be \u003e= 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "example": 10,
+                        "description": "Items per page (between 10-100)",
+                        "name": "size",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.LoginResponse"
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.SearchAuditLogResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user is not an admin",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
                         }
                     }
                 }
             }
         },
-        "/auth/register": {
-            "post": {
-                "description": "Currently only supports email login",
+        "/admin/tasks": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve the registered tasks with their cron expression, timeout and overlap policy.",
                 "consumes": [
                     "application/json"
                 ],
@@ -67,23 +140,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth Modules"
-                ],
-                "summary": "User Registration",
-                "parameters": [
-                    {
-                        "description": "params",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.RegisterRequest"
-                        }
-                    }
+                    "Admin Modules"
                 ],
+                "summary": "Get list of scheduled tasks",
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetTaskData"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "The user is not an admin",
                         "schema": {
                             "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
                         }
@@ -91,14 +162,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/categories": {
+        "/admin/tasks/runs": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve a paginated list of categories with optional search and sorting criteria.",
+                "description": "Retrieve a paginated list of task runs, latest first.",
                 "consumes": [
                     "application/json"
                 ],
@@ -106,49 +177,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Categories Modules"
+                    "Admin Modules"
                 ],
-                "summary": "Get list of categories",
+                "summary": "Get task run history",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Filter by category name",
-                        "name": "name",
+                        "example": "\"check_user\"",
+                        "description": "Filter by task name",
+                        "name": "task_name",
                         "in": "query"
                     },
                     {
                         "enum": [
-                            "active",
-                            "deactive"
+                            "pending",
+                            "running",
+                            "success",
+                            "failed",
+                            "timeout",
+                            "skipped"
                         ],
                         "type": "string",
-                        "example": "\"active\"",
-                        "description": "Filter by Status",
+                        "description": "Filter by status",
                         "name": "status",
                         "in": "query"
                     },
                     {
-                        "enum": [
-                            "name",
-                            "created_at"
-                        ],
                         "type": "string",
-                        "default": "created_at",
-                        "example": "\"name\"",
-                        "description": "Sort by field",
-                        "name": "sort_by",
+                        "format": "date",
+                        "example": "\"2024-01-28\"",
+                        "description": "Filter by start date (YYYY-MM-DD)",
+                        "name": "date_from",
                         "in": "query"
                     },
                     {
-                        "enum": [
-                            "asc",
-                            "desc"
-                        ],
                         "type": "string",
-                        "default": "desc",
-                        "example": "\"asc\"",
-                        "description": "Sort order",
-                        "name": "sort_order",
+                        "format": "date",
+                        "example": "\"2024-01-28\"",
+                        "description": "Filter by end date (YYYY-MM-DD)",
+                        "name": "date_to",
                         "in": "query"
                     },
                     {
@@ -172,23 +239,28 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successful response with a list of categories",
+                        "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.SearchCategoryResponse"
-                            }
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.SearchTaskRunResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user is not an admin",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/admin/tasks/runs/{id}": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Create a new category with the given details",
+                "description": "Retrieve a task run by its ID, including its result and error.",
                 "consumes": [
                     "application/json"
                 ],
@@ -196,23 +268,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Categories Modules"
+                    "Admin Modules"
                 ],
-                "summary": "Create a new category",
+                "summary": "Get a task run",
                 "parameters": [
                     {
-                        "description": "Category creation request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.CreateCategoryRequest"
-                        }
+                        "type": "integer",
+                        "description": "Task run ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Category created successfully",
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetTaskRunResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user is not an admin",
                         "schema": {
                             "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
                         }
@@ -220,14 +296,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/categories/{id}": {
-            "get": {
+        "/admin/tasks/{name}/trigger": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve detailed information about a category by its ID.",
+                "description": "Start a run of the task in background. The run is skipped if another run holds the task lock.",
                 "consumes": [
                     "application/json"
                 ],
@@ -235,36 +311,77 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Categories Modules"
+                    "Admin Modules"
                 ],
-                "summary": "Get category details",
+                "summary": "Trigger a task manually",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Category ID",
-                        "name": "id",
+                        "example": "\"check_user\"",
+                        "description": "Task name",
+                        "name": "name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only report what the task would change",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successful response",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetCategoryDetailResponse"
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetTaskRunResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user is not an admin",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
                         }
                     }
                 }
             }
         },
-        "/products": {
-            "get": {
-                "security": [
+        "/auth/login": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth Modules"
+                ],
+                "summary": "Account Login",
+                "parameters": [
                     {
-                        "Bearer": []
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.LoginRequest"
+                        }
                     }
                 ],
-                "description": "Retrieve a list of products with optional filters, sorting, and pagination",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.LoginResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Currently only supports email login",
                 "consumes": [
                     "application/json"
                 ],
@@ -272,96 +389,80 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product Modules"
+                    "Auth Modules"
                 ],
-                "summary": "Search and filter products",
+                "summary": "User Registration",
                 "parameters": [
                     {
-                        "type": "string",
-                        "example": "\"PROD-202401-001\"",
-                        "description": "Filter by Product Reference",
-                        "name": "reference",
-                        "in": "query"
-                    },
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories": {
+            "get": {
+                "security": [
                     {
-                        "type": "string",
-                        "example": "\"Crunchy Munch\"",
-                        "description": "Filter by Product Name (3-100 chars)",
-                        "name": "product_name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "1",
-                        "description": "Filter by Category ID (must be \u003e 0)",
-                        "name": "category_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "1",
-                        "description": "Filter by Supplier ID (must be \u003e 0)",
-                        "name": "supplier_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "1",
-                        "description": "Filter by Stock Location ID (must be \u003e 0)",
-                        "name": "stock_location_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "number",
-                        "example": 100000,
-                        "description": "Filter by Minimum Price (must be \u003e 0)",
-                        "name": "min_price",
-                        "in": "query"
-                    },
-                    {
-                        "type": "number",
-                        "example": 500000,
-                        "description": "Filter by Maximum Price (must be \u003e 0)",
-                        "name": "max_price",
-                        "in": "query"
-                    },
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve a paginated list of categories with optional search and sorting criteria.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories Modules"
+                ],
+                "summary": "Get list of categories",
+                "parameters": [
                     {
                         "type": "string",
-                        "format": "date",
-                        "example": "\"2024-01-28\"",
-                        "description": "Filter by start date (YYYY-MM-DD)",
-                        "name": "date_added_from",
+                        "description": "Filter by category name",
+                        "name": "name",
                         "in": "query"
                     },
                     {
+                        "enum": [
+                            "active",
+                            "deactive"
+                        ],
                         "type": "string",
-                        "format": "date",
-                        "example": "\"2024-01-28\"",
-                        "description": "Filter by end date (YYYY-MM-DD)",
-                        "name": "date_added_to",
+                        "example": "\"active\"",
+                        "description": "Filter by Status",
+                        "name": "status",
                         "in": "query"
                     },
                     {
-                        "enum": [
-                            "Available",
-                            "Out of Stock",
-                            "On Order"
-                        ],
                         "type": "string",
-                        "example": "\"Available\"",
-                        "description": "Filter by Product Status",
-                        "name": "status",
+                        "description": "List the direct subcategories of a category",
+                        "name": "parent_id",
                         "in": "query"
                     },
                     {
                         "enum": [
-                            "price",
                             "name",
-                            "added_date"
+                            "created_at"
                         ],
                         "type": "string",
-                        "default": "added_date",
-                        "example": "\"price\"",
+                        "default": "created_at",
+                        "example": "\"name\"",
                         "description": "Sort by field",
                         "name": "sort_by",
                         "in": "query"
@@ -399,9 +500,12 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Successful response with a list of categories",
                         "schema": {
-                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.SearchProductResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.SearchCategoryResponse"
+                            }
                         }
                     }
                 }
@@ -412,7 +516,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Create a new product with the given details",
+                "description": "Create a new category with the given details",
                 "consumes": [
                     "application/json"
                 ],
@@ -420,23 +524,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product Modules"
+                    "Categories Modules"
                 ],
-                "summary": "Create a new product",
+                "summary": "Create a new category",
                 "parameters": [
                     {
-                        "description": "Product creation request",
+                        "description": "Category creation request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.CreateProductRequest"
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.CreateCategoryRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Product created successfully",
+                        "description": "Category created successfully",
                         "schema": {
                             "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
                         }
@@ -444,14 +548,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/products/distance/ip/{city}": {
+        "/categories/trash": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Computes the distance (in km) between the user's location (determined via IP) and a specified city.",
+                "description": "Retrieve the deleted categories that can still be restored, last deleted first",
                 "consumes": [
                     "application/json"
                 ],
@@ -459,59 +563,98 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product Modules"
+                    "Categories Modules"
                 ],
-                "summary": "Calculate the distance between an IP address and a city",
+                "summary": "Get the category trash",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "City name to compare distance with",
-                        "name": "city",
-                        "in": "path",
+                        "type": "integer",
+                        "default": 1,
+                        "example": 1,
+                        "description": "Page number (must be \u003e= 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "example": 10,
+                        "description": "Items per page (between 10-100)",
+                        "name": "size",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns the calculated distance in kilometers",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetTrashResponse"
                         }
                     }
                 }
             }
         },
-        "/products/export/{format}/{id}": {
-            "get": {
+        "/categories/trash/{id}": {
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Exports product details in the specified format (currently supports PDF).",
+                "description": "Deletes a category in the trash for good with its products in the trash, refused while it has live products or subcategories",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json",
                     "application/json"
                 ],
                 "tags": [
-                    "Product Modules"
+                    "Categories Modules"
                 ],
-                "summary": "Export a single product",
+                "summary": "Purge a category from the trash",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Export format (e.g., 'pdf')",
-                        "name": "format",
+                        "description": "Category ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/trash/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Takes a deleted category out of the trash, its version changes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories Modules"
+                ],
+                "summary": "Restore a category from the trash",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "Product ID (UUID)",
+                        "description": "Category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -519,7 +662,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "File generated successfully",
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
                         }
@@ -527,14 +670,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/products/{id}": {
+        "/categories/{id}": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve detailed product information by ID",
+                "description": "Retrieve detailed information about a category by its ID.",
                 "consumes": [
                     "application/json"
                 ],
@@ -542,13 +685,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product Modules"
+                    "Categories Modules"
                 ],
-                "summary": "Get product detail information",
+                "summary": "Get category details",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Product Reference ID",
+                        "description": "Category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -556,9 +699,15 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Successful response",
                         "schema": {
-                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetProductDetailData"
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetCategoryDetailResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the resource"
+                            }
                         }
                     }
                 }
@@ -569,7 +718,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Update product details using the product reference (ID) provided in the URL",
+                "description": "Update a category, changing its parent moves it with its subcategories",
                 "consumes": [
                     "application/json"
                 ],
@@ -577,33 +726,56 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product Modules"
+                    "Categories Modules"
                 ],
-                "summary": "Update a product by ID",
+                "summary": "Update a category",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Product Reference ID",
+                        "description": "Category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Update Product Request Body",
-                        "name": "body",
+                        "description": "Category update request",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.UpdateProductRequest"
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.UpdateCategoryRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the category as last read, the update is refused if it changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success message",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetCategoryDetailData"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the category"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "The category still has live products or subcategories",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "The category has been modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
                         }
                     }
                 }
@@ -614,7 +786,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Permanently deletes a product using the provided product reference (ID) in the URL",
+                "description": "Moves the category to the trash, it can be restored until it is purged",
                 "consumes": [
                     "application/json"
                 ],
@@ -622,93 +794,209 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product Modules"
+                    "Categories Modules"
                 ],
-                "summary": "Delete a product by ID",
+                "summary": "Delete a category",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Product Reference ID",
+                        "description": "Category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the category as last read, the delete is refused if it changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Product deleted successfully",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "The category has been modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
                         }
                     }
                 }
             }
         },
-        "/statistics/products-per-category": {
-            "get": {
+        "/categories/{id}/merge": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve statistics on the distribution of products per category based on quantity",
+                "description": "Moves the products and subcategories of the category to the target in one transaction, then puts the category in the trash",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Statistics"
+                    "Categories Modules"
                 ],
-                "summary": "Get percentage of products per category",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ProductCategoryStatsResponse"
-                            }
-                        }
-                    }
+                "summary": "Merge a category into another",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Category merge request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.MergeCategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.MergeCategoryData"
+                        }
+                    },
+                    "400": {
+                        "description": "The target is in the subtree of the category or a product has attribute values outside the schema of the target",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
                 }
             }
         },
-        "/statistics/products-per-supplier": {
+        "/forecasts": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve statistics on the distribution of products per supplier based on quantity",
+                "description": "Retrieve a paginated list of the forecasts computed by the demand_forecast task, the largest reorder first",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Statistics"
+                    "Forecast Modules"
+                ],
+                "summary": "Get demand forecasts and reorder suggestions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by supplier ID",
+                        "name": "supplier_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only the products with a reorder to place now",
+                        "name": "reorder_only",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "example": 1,
+                        "description": "Page number (must be \u003e= 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "example": 10,
+                        "description": "Items per page (between 10-100)",
+                        "name": "size",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "Get percentage of products per supplier",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ProductSupplierStatsResponse"
-                            }
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.SearchForecastResponse"
                         }
                     }
                 }
             }
         },
-        "/supplier": {
+        "/forecasts/export/{format}": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve a paginated list of suppliers with optional search and sorting criteria.",
+                "description": "Download every forecast of the filter in the specified format (currently supports CSV), the largest reorder first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Forecast Modules"
+                ],
+                "summary": "Export demand forecasts and reorder suggestions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export format (e.g., 'csv')",
+                        "name": "format",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by supplier ID",
+                        "name": "supplier_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only the products with a reorder to place now",
+                        "name": "reorder_only",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Forecasts",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/products": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve a list of products with optional filters, sorting, and pagination",
                 "consumes": [
                     "application/json"
                 ],
@@ -716,24 +1004,143 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Supplier Modules"
+                    "Product Modules"
                 ],
-                "summary": "Get list of suppliers",
+                "summary": "Search and filter products",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Filter by supplier name",
-                        "name": "name",
+                        "example": "\"PROD-202401-001\"",
+                        "description": "Filter by Product Reference",
+                        "name": "reference",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"4006381333931\"",
+                        "description": "Filter by GTIN, however it is written",
+                        "name": "gtin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"Crunchy Munch\"",
+                        "description": "Filter by Product Name (3-100 chars)",
+                        "name": "product_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "1",
+                        "description": "Filter by Category ID (must be \u003e 0)",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the products of the subcategories of category_id",
+                        "name": "include_subcategories",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "1",
+                        "description": "Filter by Supplier ID (must be \u003e 0)",
+                        "name": "supplier_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "1",
+                        "description": "Filter by Stock Location ID (must be \u003e 0)",
+                        "name": "stock_location_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "example": 100000,
+                        "description": "Filter by Minimum Price (must be \u003e 0)",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "example": 500000,
+                        "description": "Filter by Maximum Price (must be \u003e 0)",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "example": "\"2024-01-28\"",
+                        "description": "Filter by start date (YYYY-MM-DD)",
+                        "name": "date_added_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "example": "\"2024-01-28\"",
+                        "description": "Filter by end date (YYYY-MM-DD)",
+                        "name": "date_added_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "Draft",
+                            "Available",
+                            "Out of Stock",
+                            "On Order",
+                            "Discontinued",
+                            "Archived"
+                        ],
+                        "type": "string",
+                        "example": "\"Available\"",
+                        "description": "Filter by Product Status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "List the archived products too, they are hidden unless filtered by status",
+                        "name": "include_archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "example": "\"2024-02-01\"",
+                        "description": "Apply the price filters and sorting to the price in effect on this date (YYYY-MM-DD)",
+                        "name": "price_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "List a parent in place of its variants, with their total quantity",
+                        "name": "collapse_variants",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "example": "color:red",
+                        "description": "Filter on an attribute value as name:value, repeat it to filter on several attributes",
+                        "name": "attribute",
                         "in": "query"
                     },
                     {
                         "enum": [
+                            "price",
                             "name",
-                            "id"
+                            "added_date"
                         ],
                         "type": "string",
-                        "default": "name",
-                        "example": "\"name\"",
+                        "default": "added_date",
+                        "example": "\"price\"",
                         "description": "Sort by field",
                         "name": "sort_by",
                         "in": "query"
@@ -771,12 +1178,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successful response with a list of suppliers",
+                        "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.SearchSupplierResponse"
-                            }
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.SearchProductResponse"
                         }
                     }
                 }
@@ -787,7 +1191,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Create a new supplier with the given details",
+                "description": "Create a new product with the given details",
                 "consumes": [
                     "application/json"
                 ],
@@ -795,23 +1199,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Supplier Modules"
+                    "Product Modules"
                 ],
-                "summary": "Create a new supplier",
+                "summary": "Create a new product",
                 "parameters": [
                     {
-                        "description": "Supplier creation request",
+                        "description": "Product creation request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.CreateSupplierRequest"
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.CreateProductRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Supplier created successfully",
+                        "description": "Product created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "The GTIN belongs to another product",
                         "schema": {
                             "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
                         }
@@ -819,14 +1229,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/supplier/{id}": {
-            "get": {
+        "/products/bulk/delete": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve detailed information about a supplier by its ID.",
+                "description": "Move the products to the trash, they are given by their references or by the filters of the product search. With preview the products are only counted and listed. A product with variants left fails. Each product is deleted on its own unless atomic is set, then a failure leaves every product as it was and is answered with 409.",
                 "consumes": [
                     "application/json"
                 ],
@@ -834,35 +1244,62 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Supplier Modules"
+                    "Product Modules"
                 ],
-                "summary": "Get supplier details",
+                "summary": "Delete products in bulk",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Supplier ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Target",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.BulkDeleteProductsRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successful response",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetSupplierDetailResponse"
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.BulkProductsData"
                         }
-                    }
-                }
+                    },
+                    "400": {
+                        "description": "The target is missing, ambiguous or over the limit of products",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "The atomic delete failed on a product and was rolled back",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.BulkProductsData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
             }
         },
-        "/user": {
-            "get": {
+        "/products/bulk/update": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
+                "description": "Set the category, supplier or status of the products, or adjust their price by an amount or a percentage. The products are given by their references or by the filters of the product search. With preview the products are only counted and listed. Each product is updated on its own unless atomic is set, then a failure leaves every product as it was and is answered with 409.",
                 "consumes": [
                     "application/json"
                 ],
@@ -870,24 +1307,62 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User Modules"
+                    "Product Modules"
+                ],
+                "summary": "Update products in bulk",
+                "parameters": [
+                    {
+                        "description": "Target and change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.BulkUpdateProductsRequest"
+                        }
+                    }
                 ],
-                "summary": "Get user information",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetProfileResponse"
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.BulkProductsData"
+                        }
+                    },
+                    "400": {
+                        "description": "The target is missing, ambiguous or over the limit of products",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "The atomic update failed on a product and was rolled back",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.BulkProductsData"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/products/distance/ip/{city}": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
+                "description": "Computes the distance (in km) between the user's location (determined via IP) and a specified city.",
                 "consumes": [
                     "application/json"
                 ],
@@ -895,164 +1370,5333 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User Modules"
+                    "Product Modules"
                 ],
-                "summary": "Modify user information",
+                "summary": "Calculate the distance between an IP address and a city",
                 "parameters": [
                     {
-                        "description": "params",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "City name to compare distance with",
+                        "name": "city",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns the calculated distance in kilometers",
                         "schema": {
-                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.UpdateProfileRequest"
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/products/export/{format}/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Exports product details in the specified format (currently supports PDF).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Export a single product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export format (e.g., 'pdf')",
+                        "name": "format",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "example": "\"2024-02-01\"",
+                        "description": "Print the price in effect on this date (YYYY-MM-DD)",
+                        "name": "price_date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "File generated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
                         }
                     }
+                }
+            }
+        },
+        "/products/gtin/{gtin}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Resolve a scanned EAN/UPC code to its product, the code matches however the GTIN was written, e.g. a UPC-A stored as 12 digits is found from the 13 digits of its EAN-13",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Look up a product by its GTIN",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"4006381333931\"",
+                        "description": "GTIN-8, GTIN-12, GTIN-13 or GTIN-14",
+                        "name": "gtin",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetProductDetailData"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The check digit of the code is wrong",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "No product has the code",
                         "schema": {
                             "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "github_com_quydmfl_niveau-test_api_v1.CreateCategoryRequest": {
+        },
+        "/products/labels": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Print a label with the name, price and barcode of each product of a search page on an Avery sheet (PDF). Products that can't be encoded in the barcode format, e.g. without a GTIN for EAN-13, are skipped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Print product labels",
+                "parameters": [
+                    {
+                        "enum": [
+                            "L7160",
+                            "L7163",
+                            "L7651",
+                            "5160"
+                        ],
+                        "type": "string",
+                        "description": "Avery sheet",
+                        "name": "layout",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "code128",
+                            "ean13",
+                            "qr"
+                        ],
+                        "type": "string",
+                        "description": "Barcode format",
+                        "name": "barcode",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 4,
+                        "description": "First free label of the first sheet, from 1",
+                        "name": "start_position",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "example": 1,
+                        "description": "Page number (must be \u003e= 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "example": 10,
+                        "description": "Items per page (between 10-100)",
+                        "name": "size",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"PROD-202401-001\"",
+                        "description": "Filter by Product Reference",
+                        "name": "reference",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"4006381333931\"",
+                        "description": "Filter by GTIN, however it is written",
+                        "name": "gtin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"Crunchy Munch\"",
+                        "description": "Filter by Product Name (3-100 chars)",
+                        "name": "product_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "1",
+                        "description": "Filter by Category ID",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the products of the subcategories of category_id",
+                        "name": "include_subcategories",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "1",
+                        "description": "Filter by Supplier ID",
+                        "name": "supplier_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "Draft",
+                            "Available",
+                            "Out of Stock",
+                            "On Order",
+                            "Discontinued",
+                            "Archived"
+                        ],
+                        "type": "string",
+                        "description": "Filter by Product Status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Filter by attribute value as name:value, repeat for several attributes",
+                        "name": "attribute",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "price",
+                            "name",
+                            "added_date"
+                        ],
+                        "type": "string",
+                        "default": "added_date",
+                        "example": "\"price\"",
+                        "description": "Sort by field",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "example": "\"desc\"",
+                        "description": "Sort order",
+                        "name": "sort_order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "File generated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ExportProductLabelsData"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/lots/expiring": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "List the lots with stock left whose expiry date is in the window, soonest first. The window is from today to 30 days later by default.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Get the expiring stock",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "example": 1,
+                        "description": "Page number (must be \u003e= 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "example": 10,
+                        "description": "Items per page (between 10-100)",
+                        "name": "size",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2024-04-01\"",
+                        "description": "First expiry date of the window",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2024-04-30\"",
+                        "description": "Last expiry date of the window",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"Ha Noi\"",
+                        "description": "Filter by the stock location of the lots",
+                        "name": "stock_location",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetExpiringLotsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/lots/expiring/export/{format}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Exports the lots with stock left whose expiry date is in the window in the specified format (currently supports PDF). The window is from today to 30 days later by default.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Export the expiring stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export format (e.g., 'pdf')",
+                        "name": "format",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2024-04-01\"",
+                        "description": "First expiry date of the window",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2024-04-30\"",
+                        "description": "Last expiry date of the window",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"Ha Noi\"",
+                        "description": "Filter by the stock location of the lots",
+                        "name": "stock_location",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "File generated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ExportExpiringLotsData"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/trash": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve the deleted products that can still be restored, last deleted first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Get the product trash",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "example": 1,
+                        "description": "Page number (must be \u003e= 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "example": 10,
+                        "description": "Items per page (between 10-100)",
+                        "name": "size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetTrashResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/trash/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Deletes a product in the trash for good, with its price and version history",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Purge a product from the trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/trash/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Takes a deleted product out of the trash, its version changes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Restore a product from the trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve detailed product information by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Get product detail information",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetProductDetailData"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the resource"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update product details using the product reference (ID) provided in the URL",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Update a product by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Product Request Body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.UpdateProductRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the product as last read, the update is refused if it changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "The status differs from the current one, it changes through the transition of the product",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "The GTIN belongs to another product",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "The product has been modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Moves the product to the trash using the provided product reference (ID) in the URL, it can be restored until it is purged",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Delete a product by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the product as last read, the delete is refused if it changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "The product still has variants or is a component of a bundle",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "The product has been modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Apply a JSON merge patch (RFC 7396) to the product, only the fields present are validated and changed",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Partially update a product by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Any subset of the fields of the update request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.UpdateProductRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the product as last read, the update is refused if it changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Fields changed and new version",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.PatchProductData"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the product"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The status differs from the current one, it changes through the transition of the product",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "The GTIN belongs to another product",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "The product has been modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/as-of": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve the version of a product that was in effect at the given time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Get a product as of a timestamp",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2024-01-28T10:00:00Z\"",
+                        "description": "Point in time (RFC 3339)",
+                        "name": "timestamp",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetProductVersionData"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/barcode": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Render the product as a Code128, EAN-13 or QR code, in PNG or SVG. EAN-13 encodes the GTIN of the product, the others its reference.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "image/png",
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Get the barcode of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "code128",
+                            "ean13",
+                            "qr"
+                        ],
+                        "type": "string",
+                        "description": "Barcode format",
+                        "name": "format",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "png",
+                            "svg"
+                        ],
+                        "type": "string",
+                        "default": "png",
+                        "description": "Image type",
+                        "name": "output",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 300,
+                        "description": "Width in pixels (32-2000)",
+                        "name": "width",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 100,
+                        "description": "Height in pixels (32-2000)",
+                        "name": "height",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Barcode image",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "The product can't be encoded in the format, e.g. it has no GTIN for EAN-13, or the size is too small for it",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/components": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Make the product a bundle of other products with their quantity per bundle, the components replace those it had. Without components it is an ordinary product again. A bundle has no stock of its own, its quantity is how many bundles the stock of its components can make. Bundles are not nested and can't have variants.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Set the components of a bundle",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Components",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.SetBundleComponentsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetProductDetailData"
+                        }
+                    },
+                    "400": {
+                        "description": "The product has stock of its own, or a component is not found or is a bundle",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/diff": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve the fields that differ between two versions of a product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Compare two versions of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Base version",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 3,
+                        "description": "Compared version",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.DiffProductVersionsData"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/forecast": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve the forecast demand of a product and the reorder it suggests, as of the last run of the demand_forecast task",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Forecast Modules"
+                ],
+                "summary": "Get the demand forecast of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetProductForecastResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "The product is not found or was not forecast yet",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/images": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "List the images of a product in their order with the URLs of their thumbnails, the first one is the primary image",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Get the images of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ProductImageData"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Add a JPEG, PNG, GIF or WebP image after the other images of the product, its thumbnails are generated on upload",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Upload an image of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ProductImageData"
+                        }
+                    },
+                    "400": {
+                        "description": "The file is not an accepted image or is too large",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/images/order": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Give the images of the product the order of the list, it must contain all of them. The first one becomes the primary image.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Reorder the images of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Image IDs in their new order",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ReorderProductImagesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ProductImageData"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/images/{imageId}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete the image and its thumbnails, the images after it move up",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Delete an image of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Image ID",
+                        "name": "imageId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/issue": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Take the stock of the components of the bundles in one transaction, from the lots of each component that have not expired first. Nothing is issued when a component is short.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Issue bundles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Quantity of bundles to issue",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.IssueBundleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.IssueBundleData"
+                        }
+                    },
+                    "400": {
+                        "description": "The product is not a bundle or a component is short",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/lots": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "List the lots of a product with stock left, the first to expire first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Get the lots of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "List the lots used up too",
+                        "name": "include_empty",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ProductLotData"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Add a lot with its expiry date to the stock of the product, the lot number must be new for the product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Receive a lot of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Lot",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ReceiveProductLotRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ProductLotData"
+                        }
+                    },
+                    "400": {
+                        "description": "The lot was already received or has expired",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/lots/consume": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Take the quantity from the lots that have not expired, the first to expire first, then from the stock without lot. Expired lots are never issued.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Issue stock of a product first-expired-first-out",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Quantity to issue",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ConsumeProductLotsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ConsumeProductLotsData"
+                        }
+                    },
+                    "400": {
+                        "description": "There is not enough stock left outside the expired lots",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/prices": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve the price ranges of a product, scheduled prices first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Get the price history of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "example": 1,
+                        "description": "Page number (must be \u003e= 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "example": 10,
+                        "description": "Items per page (between 10-100)",
+                        "name": "size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetProductPricesResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Make a price effective from the given time until the next scheduled price. The time must not be in the past.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Schedule a price change",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Price change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ScheduleProductPriceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetProductPriceData"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/prices/{priceId}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Remove a price that is not in effect yet, the previous price stays in effect instead",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Cancel a scheduled price",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Price ID",
+                        "name": "priceId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/status-history": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve the changes of status of a product with who made them and why, latest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Get the status history of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "example": 1,
+                        "description": "Page number (must be \u003e= 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "example": 10,
+                        "description": "Items per page (between 10-100)",
+                        "name": "size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetProductStatusHistoryResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/transition": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Move the product to another status of its lifecycle. Draft products become Available, On Order or Out of Stock, or are Archived. Available, On Order and Out of Stock move between each other or to Discontinued. Discontinued products are Archived or Available again, and Archived ones go back to Discontinued.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Change the status of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status and reason of the change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.TransitionProductRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the product as last read, the change is refused if it changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Fields changed and new version",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.PatchProductData"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the product"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "The status can't move to the requested one",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "The product has been modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/variants": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create a variant of the product with its own reference, price, quantity and options, it gets the category, supplier and attribute values of the product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Add a variant to a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Variant creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.CreateVariantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetProductDetailData"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the variant"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The product is a variant or another variant has the same options",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "The GTIN belongs to another product",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/versions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve the change history of a product, latest version first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Get the versions of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "example": 1,
+                        "description": "Page number (must be \u003e= 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "example": 10,
+                        "description": "Items per page (between 10-100)",
+                        "name": "size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetProductVersionsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/versions/{version}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve the snapshot of a product at the given version number",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Get a product as of a version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version number",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetProductVersionData"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/versions/{version}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Apply the name, category, supplier, price, stock location, added date and GTIN of a version as a new update. Quantity and status are kept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Modules"
+                ],
+                "summary": "Restore a version of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Reference ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version number to restore",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The new version",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetProductVersionData"
+                        }
+                    },
+                    "409": {
+                        "description": "The GTIN of the version belongs to another product",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve a paginated list of purchase orders, latest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Order Modules"
+                ],
+                "summary": "Get list of purchase orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by supplier ID",
+                        "name": "supplier_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "draft",
+                            "sent",
+                            "partially_received",
+                            "received",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "example": 1,
+                        "description": "Page number (must be \u003e= 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "example": 10,
+                        "description": "Items per page (between 10-100)",
+                        "name": "size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.SearchPurchaseOrderResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create a draft purchase order to an active supplier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Order Modules"
+                ],
+                "summary": "Create a purchase order",
+                "parameters": [
+                    {
+                        "description": "Purchase order creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.CreatePurchaseOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetPurchaseOrderDetailData"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the purchase order"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve a purchase order with its lines",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Order Modules"
+                ],
+                "summary": "Get purchase order details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetPurchaseOrderDetailResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the purchase order"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Replace the note and the lines of a purchase order that was not sent yet",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Order Modules"
+                ],
+                "summary": "Update a draft purchase order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Purchase order update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.UpdatePurchaseOrderRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the purchase order as last read, the update is refused if it changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetPurchaseOrderDetailData"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the purchase order"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "The purchase order is no longer a draft",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "The purchase order has been modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Cancel a purchase order that is not fully received, the stock already received is kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Order Modules"
+                ],
+                "summary": "Cancel a purchase order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the purchase order as last read, the order is not cancelled if it changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetPurchaseOrderDetailData"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the purchase order"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "The purchase order is already received or cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "The purchase order has been modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}/export/{format}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Exports the purchase order with its lines in the specified format (currently supports PDF).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Order Modules"
+                ],
+                "summary": "Export a purchase order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export format (e.g., 'pdf')",
+                        "name": "format",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "File generated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ExportPurchaseOrderData"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}/receive": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Add the received quantities to the stock of the products in one transaction and set them Available. The order is received once every line is, partially received before.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Order Modules"
+                ],
+                "summary": "Receive a purchase order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Received quantities, everything still expected when empty",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ReceivePurchaseOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetPurchaseOrderDetailData"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the purchase order"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "The purchase order is not sent",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}/send": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mark a draft purchase order as sent to the supplier, the delivery is expected after the lead time of the supplier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Order Modules"
+                ],
+                "summary": "Send a purchase order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the purchase order as last read, the order is not sent if it changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetPurchaseOrderDetailData"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the purchase order"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "The purchase order is not a draft",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "The purchase order has been modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/statistics/products-per-category": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve statistics on the distribution of products per category based on quantity",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Statistics"
+                ],
+                "summary": "Get percentage of products per category",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ProductCategoryStatsResponse"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/statistics/products-per-supplier": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve statistics on the distribution of products per supplier based on quantity",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Statistics"
+                ],
+                "summary": "Get percentage of products per supplier",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ProductSupplierStatsResponse"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stocktakes": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve a paginated list of stocktakes without their lines, latest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stocktake Modules"
+                ],
+                "summary": "Get list of stocktakes",
+                "parameters": [
+                    {
+                        "enum": [
+                            "open",
+                            "approved",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"Ha Noi\"",
+                        "description": "Filter by stock location",
+                        "name": "stock_location",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "example": 1,
+                        "description": "Page number (must be \u003e= 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "example": 10,
+                        "description": "Items per page (between 10-100)",
+                        "name": "size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.SearchStocktakeResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Open a stocktake of the live products of a stock location, of a category, or of both. Archived products and bundles are not counted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stocktake Modules"
+                ],
+                "summary": "Open a stocktake",
+                "parameters": [
+                    {
+                        "description": "Stocktake creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.CreateStocktakeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetStocktakeDetailData"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the stocktake"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stocktakes/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve a stocktake with its lines. While it is open the system quantity is the stock of the product now.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stocktake Modules"
+                ],
+                "summary": "Get stocktake details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stocktake ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetStocktakeDetailResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the stocktake"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stocktakes/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Set the stock of the counted products to their counted quantity in one transaction, each change is recorded as a stock movement. Only a supervisor can approve.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stocktake Modules"
+                ],
+                "summary": "Approve a stocktake",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stocktake ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Stocktake approval request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ApproveStocktakeRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the stocktake as last read, it is not approved if it changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetStocktakeDetailData"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the stocktake"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "The user is not a supervisor",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "The stocktake is no longer open",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "The stocktake has been modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/stocktakes/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Cancel an open stocktake, the stock is left as is",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stocktake Modules"
+                ],
+                "summary": "Cancel a stocktake",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stocktake ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the stocktake as last read, it is not cancelled if it changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetStocktakeDetailData"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the stocktake"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "The stocktake is no longer open",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "The stocktake has been modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/stocktakes/{id}/counts": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Save what a device counted. A new count of a product replaces the previous one of the same device, the counts of the devices are added up.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stocktake Modules"
+                ],
+                "summary": "Submit counted quantities",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stocktake ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Counted quantities",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.SubmitStocktakeCountsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetStocktakeDetailData"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the stocktake"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "The stocktake is no longer open",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/stocktakes/{id}/sheet/export/{format}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Exports the products to count with a blank counted column in the specified format (currently supports PDF). The system quantities are left out.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stocktake Modules"
+                ],
+                "summary": "Export the count sheet of a stocktake",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export format (e.g., 'pdf')",
+                        "name": "format",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stocktake ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "File generated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ExportStocktakeData"
+                        }
+                    }
+                }
+            }
+        },
+        "/stocktakes/{id}/variance": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "List the counted products whose quantity differs from the system quantity with their value, the totals and the products not counted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stocktake Modules"
+                ],
+                "summary": "Get the variance report of a stocktake",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stocktake ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetStocktakeVarianceData"
+                        }
+                    }
+                }
+            }
+        },
+        "/stocktakes/{id}/variance/export/{format}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Exports the variance report in the specified format (currently supports PDF).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stocktake Modules"
+                ],
+                "summary": "Export the variance report of a stocktake",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export format (e.g., 'pdf')",
+                        "name": "format",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stocktake ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "File generated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ExportStocktakeData"
+                        }
+                    }
+                }
+            }
+        },
+        "/supplier": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve a paginated list of suppliers with optional search and sorting criteria.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Supplier Modules"
+                ],
+                "summary": "Get list of suppliers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by supplier name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "deactive"
+                        ],
+                        "type": "string",
+                        "description": "Filter by supplier status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "name",
+                            "id"
+                        ],
+                        "type": "string",
+                        "default": "name",
+                        "example": "\"name\"",
+                        "description": "Sort by field",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "example": "\"asc\"",
+                        "description": "Sort order",
+                        "name": "sort_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "example": 1,
+                        "description": "Page number (must be \u003e= 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "example": 10,
+                        "description": "Items per page (between 10-100)",
+                        "name": "size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response with a list of suppliers",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.SearchSupplierResponse"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create a new supplier with the given details",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Supplier Modules"
+                ],
+                "summary": "Create a new supplier",
+                "parameters": [
+                    {
+                        "description": "Supplier creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.CreateSupplierRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Supplier created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/supplier/trash": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve the deleted suppliers that can still be restored, last deleted first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Supplier Modules"
+                ],
+                "summary": "Get the supplier trash",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "example": 1,
+                        "description": "Page number (must be \u003e= 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "example": 10,
+                        "description": "Items per page (between 10-100)",
+                        "name": "size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetTrashResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/supplier/trash/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Deletes a supplier in the trash for good, its products are left without supplier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Supplier Modules"
+                ],
+                "summary": "Purge a supplier from the trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/supplier/trash/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Takes a deleted supplier out of the trash, its version changes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Supplier Modules"
+                ],
+                "summary": "Restore a supplier from the trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/supplier/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve detailed information about a supplier by its ID, with the count and stock value of its products.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Supplier Modules"
+                ],
+                "summary": "Get supplier details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetSupplierDetailResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the resource"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update a supplier, its contacts and addresses are replaced by the given ones",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Supplier Modules"
+                ],
+                "summary": "Update a supplier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Supplier update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.UpdateSupplierRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the supplier as last read, the update is refused if it changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetSupplierDetailData"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the supplier"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "The supplier has been modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Moves the supplier to the trash, it can be restored until it is purged",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Supplier Modules"
+                ],
+                "summary": "Delete a supplier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the supplier as last read, the delete is refused if it changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "The supplier has been modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/supplier/{id}/merge": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Moves the products of the supplier to the target in one transaction, with the contacts and addresses the target doesn't have, then puts the supplier in the trash",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Supplier Modules"
+                ],
+                "summary": "Merge a duplicated supplier into another",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Supplier merge request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.MergeSupplierRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.MergeSupplierData"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Modules"
+                ],
+                "summary": "Get user information",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetProfileResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Modules"
+                ],
+                "summary": "Modify user information",
+                "parameters": [
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_quydmfl_niveau-test_api_v1.ApproveStocktakeRequest": {
+            "type": "object",
+            "properties": {
+                "zero_uncounted": {
+                    "description": "ZeroUncounted sets the stock of the products nobody counted to 0, they are left as is by default",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.BulkDeleteProductsRequest": {
+            "type": "object",
+            "properties": {
+                "atomic": {
+                    "description": "Atomic applies the operation to every product or to none, by default each product is done on its own",
+                    "type": "boolean",
+                    "example": false
+                },
+                "filter": {
+                    "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.SearchProductRequest"
+                },
+                "preview": {
+                    "description": "Preview only counts and lists the products, nothing is changed",
+                    "type": "boolean",
+                    "example": false
+                },
+                "references": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "PROD-202401-001"
+                    ]
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.BulkProductResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "The product can't move from its current status to this one."
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "PROD-202401-001"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "updated"
+                },
+                "version": {
+                    "description": "Version is the version of the product after the update",
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.BulkProductsData": {
+            "type": "object",
+            "properties": {
+                "atomic": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "matched": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "preview": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.BulkProductResult"
+                    }
+                },
+                "rolled_back": {
+                    "description": "RolledBack is set when an atomic operation failed on a product, nothing was changed",
+                    "type": "boolean",
+                    "example": false
+                },
+                "succeeded": {
+                    "type": "integer",
+                    "example": 11
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.BulkUpdateProductsRequest": {
+            "type": "object",
+            "required": [
+                "action"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "set_category",
+                        "set_supplier",
+                        "set_status",
+                        "adjust_price"
+                    ],
+                    "example": "set_status"
+                },
+                "atomic": {
+                    "description": "Atomic applies the operation to every product or to none, by default each product is done on its own",
+                    "type": "boolean",
+                    "example": false
+                },
+                "category_id": {
+                    "type": "string",
+                    "example": "67389c69-2e78-413a-9d77-6b749520b127"
+                },
+                "filter": {
+                    "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.SearchProductRequest"
+                },
+                "preview": {
+                    "description": "Preview only counts and lists the products, nothing is changed",
+                    "type": "boolean",
+                    "example": false
+                },
+                "price_amount": {
+                    "description": "PriceAmount or PricePercent is added to the price of each product, the new price is rounded to 2 decimals",
+                    "type": "number",
+                    "example": -5000
+                },
+                "price_percent": {
+                    "type": "number",
+                    "example": 10
+                },
+                "reason": {
+                    "description": "Reason is kept in the status history of the products, it is required to set the status",
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 3,
+                    "example": "End of the summer range"
+                },
+                "references": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "PROD-202401-001"
+                    ]
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "Draft",
+                        "Available",
+                        "Out of Stock",
+                        "On Order",
+                        "Discontinued",
+                        "Archived"
+                    ],
+                    "example": "Discontinued"
+                },
+                "supplier_id": {
+                    "type": "string",
+                    "example": "66ae8b26-a0cd-40d5-8b2d-5127d7b9d817"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.BundleComponentData": {
+            "type": "object",
+            "properties": {
+                "buildable": {
+                    "description": "Buildable is how many bundles the stock of the component is enough for, the fewest is the quantity of the bundle",
+                    "type": "integer",
+                    "example": 7
+                },
+                "product_name": {
+                    "type": "string",
+                    "example": "Crunchy Munch"
+                },
+                "quantity": {
+                    "description": "Quantity is how many go in one bundle, Stock is the quantity of the component",
+                    "type": "integer",
+                    "example": 2
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "PROD-202401-001"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "Available"
+                },
+                "stock": {
+                    "type": "integer",
+                    "example": 15
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.BundleComponentRequest": {
+            "type": "object",
+            "required": [
+                "quantity",
+                "reference"
+            ],
+            "properties": {
+                "quantity": {
+                    "description": "Quantity is how many of the component go in one bundle",
+                    "type": "integer",
+                    "example": 2
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "PROD-202401-001"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.CategoryAttributeData": {
+            "type": "object",
+            "properties": {
+                "allowed_values": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "red",
+                        "green"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "color"
+                },
+                "required": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "type": {
+                    "type": "string",
+                    "example": "enum"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.CategoryAttributeRequest": {
+            "type": "object",
+            "required": [
+                "allowed_values",
+                "name",
+                "type"
+            ],
+            "properties": {
+                "allowed_values": {
+                    "description": "AllowedValues are the values of an enum attribute, the other types have none",
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "red",
+                        "green"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "color"
+                },
+                "required": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "string",
+                        "number",
+                        "bool",
+                        "date",
+                        "enum"
+                    ],
+                    "example": "enum"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.ConsumeProductLotsData": {
+            "type": "object",
+            "properties": {
+                "lots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.LotConsumptionData"
+                    }
+                },
+                "quantity": {
+                    "description": "Quantity is the stock of the product left",
+                    "type": "integer",
+                    "example": 36
+                },
+                "without_lot": {
+                    "description": "WithoutLot is what was taken from the stock without lot",
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.ConsumeProductLotsRequest": {
+            "type": "object",
+            "required": [
+                "quantity"
+            ],
+            "properties": {
+                "quantity": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "source_id": {
+                    "description": "SourceID is kept in the stock movements, e.g. the number of the sales order",
+                    "type": "string",
+                    "maxLength": 150,
+                    "example": "SO-2024-0042"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.CreateCategoryRequest": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "description": "Attributes is the schema of the attribute values of the products",
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.CategoryAttributeRequest"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3,
+                    "example": "Crunchy Munch"
+                },
+                "parent_id": {
+                    "description": "ParentId is empty for a root category",
+                    "type": "string",
+                    "example": "67389c69-2e78-413a-9d77-6b749520b127"
+                },
+                "reference_prefix": {
+                    "description": "ReferencePrefix replaces {CAT} in the references generated for the products, the configured default is used without",
+                    "type": "string",
+                    "example": "FOOD"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "deactive"
+                    ],
+                    "example": "active"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.CreateProductRequest": {
+            "type": "object",
+            "required": [
+                "category_id",
+                "price",
+                "product_name",
+                "quantity",
+                "status",
+                "stock_location",
+                "supplier_id"
+            ],
+            "properties": {
+                "attributes": {
+                    "description": "Attributes are the values for the attribute schema of the category, by attribute name",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "category_id": {
+                    "type": "string",
+                    "example": "67389c69-2e78-413a-9d77-6b749520b127"
+                },
+                "gtin": {
+                    "description": "GTIN is the optional EAN/UPC code, it is unique among the products",
+                    "type": "string",
+                    "example": "4006381333931"
+                },
+                "price": {
+                    "type": "number",
+                    "example": 150000
+                },
+                "product_name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3,
+                    "example": "Crunchy Munch"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "PROD-202401-001"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "Draft",
+                        "Available",
+                        "Out of Stock",
+                        "On Order"
+                    ],
+                    "example": "Available"
+                },
+                "stock_location": {
+                    "type": "string",
+                    "example": "Brookstone"
+                },
+                "supplier_id": {
+                    "type": "string",
+                    "example": "66ae8b26-a0cd-40d5-8b2d-5127d7b9d817"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.CreatePurchaseOrderRequest": {
+            "type": "object",
+            "required": [
+                "lines",
+                "supplier_id"
+            ],
+            "properties": {
+                "lines": {
+                    "type": "array",
+                    "maxItems": 200,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.PurchaseOrderLineRequest"
+                    }
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Deliver to the back door"
+                },
+                "supplier_id": {
+                    "type": "string",
+                    "example": "67389c69-2e78-413a-9d77-6b749520b127"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.CreateStocktakeRequest": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "string",
+                    "example": "67389c69-2e78-413a-9d77-6b749520b127"
+                },
+                "include_subcategories": {
+                    "description": "IncludeSubcategories extends the category to its whole subtree",
+                    "type": "boolean",
+                    "example": true
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Quarterly count of the back store"
+                },
+                "stock_location": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Ha Noi"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.CreateSupplierRequest": {
+            "type": "object",
+            "properties": {
+                "addresses": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.SupplierAddressRequest"
+                    }
+                },
+                "contacts": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.SupplierContactRequest"
+                    }
+                },
+                "currency": {
+                    "description": "Currency is an ISO 4217 code, VND when empty",
+                    "type": "string",
+                    "example": "VND"
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "contact@crunchymunch.com"
+                },
+                "lead_time_days": {
+                    "type": "integer",
+                    "maximum": 365,
+                    "minimum": 0,
+                    "example": 7
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3,
+                    "example": "Crunchy Munch"
+                },
+                "payment_terms": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Net 30"
+                },
+                "phone": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "+84 28 3822 1234"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "deactive"
+                    ],
+                    "example": "active"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.CreateVariantRequest": {
+            "type": "object",
+            "required": [
+                "options",
+                "price",
+                "status"
+            ],
+            "properties": {
+                "gtin": {
+                    "type": "string",
+                    "example": "4006381333948"
+                },
+                "options": {
+                    "description": "Options tell the variant from the other variants of the product",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "size": "M"
+                    }
+                },
+                "price": {
+                    "type": "number",
+                    "example": 150000
+                },
+                "product_name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3,
+                    "example": "Crunchy Munch M"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 10
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "PROD-202401-001-M"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "Draft",
+                        "Available",
+                        "Out of Stock",
+                        "On Order"
+                    ],
+                    "example": "Available"
+                },
+                "stock_location": {
+                    "type": "string",
+                    "example": "Brookstone"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.DiffProductVersionsData": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ProductFieldChange"
+                    }
+                },
+                "from": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "to": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.ExportExpiringLotsData": {
+            "type": "object",
+            "properties": {
+                "document_id": {
+                    "type": "string",
+                    "example": "8f14e45f-ceea-467f-a0e6-1b2c3d4e5f60"
+                },
+                "filename": {
+                    "type": "string",
+                    "example": "expiring_lots_20240401_20240430_20240401_100000.pdf"
+                },
+                "lots": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 164
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.ExportProductLabelsData": {
+            "type": "object",
+            "properties": {
+                "document_id": {
+                    "type": "string",
+                    "example": "8f14e45f-ceea-467f-a0e6-1b2c3d4e5f60"
+                },
+                "filename": {
+                    "type": "string",
+                    "example": "labels_L7160_20240128_100000.pdf"
+                },
+                "labels": {
+                    "type": "integer",
+                    "example": 21
+                },
+                "sheets": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "skipped": {
+                    "description": "Skipped are the references of the products that can't be encoded in the barcode format, e.g. without a GTIN for EAN-13",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "PROD-202401-001"
+                    ]
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.ExportPurchaseOrderData": {
+            "type": "object",
+            "properties": {
+                "document_id": {
+                    "type": "string",
+                    "example": "8f14e45f-ceea-467f-a0e6-1b2c3d4e5f60"
+                },
+                "filename": {
+                    "type": "string",
+                    "example": "purchase_order_PO-3G7dRk2Lq_20240128_100000.pdf"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.ExportStocktakeData": {
+            "type": "object",
+            "properties": {
+                "document_id": {
+                    "type": "string",
+                    "example": "8f14e45f-ceea-467f-a0e6-1b2c3d4e5f60"
+                },
+                "filename": {
+                    "type": "string",
+                    "example": "stocktake_sheet_ST-3G7dRk2Lq_20240128_100000.pdf"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.ForecastData": {
+            "type": "object",
+            "properties": {
+                "computed_at": {
+                    "type": "string",
+                    "example": "2024-02-03T02:30:00Z"
+                },
+                "daily_demand": {
+                    "type": "number",
+                    "example": 3.8
+                },
+                "demand_std_dev": {
+                    "type": "number",
+                    "example": 1.2
+                },
+                "exponential_smoothing": {
+                    "type": "number",
+                    "example": 3.8
+                },
+                "lead_time_days": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "method": {
+                    "type": "string",
+                    "example": "exponential_smoothing"
+                },
+                "moving_average": {
+                    "description": "The demands are per day, DailyDemand is the one of the method",
+                    "type": "number",
+                    "example": 3.5
+                },
+                "on_order": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "period_days": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "periods": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "product_name": {
+                    "type": "string",
+                    "example": "Crunchy Munch"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "PROD-202401-001"
+                },
+                "reorder_point": {
+                    "description": "ReorderQuantity is what to order now, 0 while the stock and the orders are above ReorderPoint",
+                    "type": "integer",
+                    "example": 33
+                },
+                "reorder_quantity": {
+                    "type": "integer",
+                    "example": 66
+                },
+                "safety_stock": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "supplier_id": {
+                    "type": "string",
+                    "example": "67389c69-2e78-413a-9d77-6b749520b127"
+                },
+                "supplier_name": {
+                    "type": "string",
+                    "example": "Snack Co"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.GetCategoryDetailData": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "description": "Attributes is only set in the detail of a category",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.CategoryAttributeData"
+                    }
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "67389c69-2e78-413a-9d77-6b749520b127"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Food"
+                },
+                "parent_id": {
+                    "type": "string",
+                    "example": "66ae8b26-a0cd-40d5-8b2d-5127d7b9d817"
+                },
+                "path": {
+                    "type": "string",
+                    "example": "/66ae8b26-a0cd-40d5-8b2d-5127d7b9d817/67389c69-2e78-413a-9d77-6b749520b127/"
+                },
+                "reference_prefix": {
+                    "type": "string",
+                    "example": "FOOD"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-01-01"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.GetCategoryDetailResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetCategoryDetailData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.GetExpiringLotsResponse": {
+            "type": "object",
+            "required": [
+                "page",
+                "size"
+            ],
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {},
+                "message": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 10,
+                    "example": 10
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "total_rows": {
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.GetProductDetailData": {
+            "type": "object",
+            "properties": {
+                "added_date": {
+                    "type": "string",
+                    "example": "2024-01-28"
+                },
+                "attributes": {
+                    "description": "Attributes are the attribute values by name, numbers and bools keep their JSON type",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "category": {
+                    "type": "string",
+                    "example": "Food"
+                },
+                "components": {
+                    "description": "Components are only set for a bundle, its quantity is how many bundles their stock can make",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.BundleComponentData"
+                    }
+                },
+                "effective_price": {
+                    "description": "EffectivePrice is the price in effect on the requested date, only set when searching with price_at",
+                    "type": "number",
+                    "example": 145000
+                },
+                "gtin": {
+                    "type": "string",
+                    "example": "4006381333931"
+                },
+                "images": {
+                    "description": "Images are ordered by position, the first one is the primary image",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ProductImageData"
+                    }
+                },
+                "options": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "parent_reference": {
+                    "description": "ParentReference and Options are only set for a variant",
+                    "type": "string",
+                    "example": "PROD-202401-001"
+                },
+                "price": {
+                    "type": "number",
+                    "example": 150000
+                },
+                "product_name": {
+                    "type": "string",
+                    "example": "Crunchy Munch"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "PROD-202401-001"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "Available"
+                },
+                "stock_location": {
+                    "type": "string",
+                    "example": "Cedar Valley"
+                },
+                "supplier": {
+                    "type": "string",
+                    "example": "KFC"
+                },
+                "total_quantity": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "variant_count": {
+                    "description": "VariantCount and TotalQuantity, the quantity of the product and its variants, are only set for a parent",
+                    "type": "integer",
+                    "example": 3
+                },
+                "variants": {
+                    "description": "Variants are only listed in the detail of a product",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ProductVariantData"
+                    }
+                },
+                "version": {
+                    "description": "Version is sent back in the If-Match header to update or delete the product, it is also the ETag header",
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.GetProductForecastResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ForecastData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.GetProductPriceData": {
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "string",
+                    "example": "1sdWdNiYj"
+                },
+                "effective_from": {
+                    "type": "string",
+                    "example": "2024-02-01T00:00:00Z"
+                },
+                "effective_to": {
+                    "type": "string",
+                    "example": ""
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "price": {
+                    "type": "number",
+                    "example": 150000
+                },
+                "scheduled": {
+                    "description": "Scheduled prices are not in effect yet and can be cancelled",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.GetProductPricesResponse": {
+            "type": "object",
+            "required": [
+                "page",
+                "size"
+            ],
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {},
+                "message": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 10,
+                    "example": 10
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "total_rows": {
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.GetProductStatusHistoryResponse": {
+            "type": "object",
+            "required": [
+                "page",
+                "size"
+            ],
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {},
+                "message": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 10,
+                    "example": 10
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "total_rows": {
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.GetProductVersionData": {
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "string",
+                    "example": "1sdWdNiYj"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-28T10:00:00Z"
+                },
+                "product": {
+                    "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetProductDetailData"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.GetProductVersionsResponse": {
+            "type": "object",
+            "required": [
+                "page",
+                "size"
+            ],
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {},
+                "message": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 10,
+                    "example": 10
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "total_rows": {
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.GetProfileResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetProfileResponseData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.GetProfileResponseData": {
+            "type": "object",
+            "properties": {
+                "nickname": {
+                    "type": "string",
+                    "example": "alan"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.GetPurchaseOrderDetailData": {
+            "type": "object",
+            "properties": {
+                "cancelled_at": {
+                    "type": "string",
+                    "example": "2024-01-29T09:00:00Z"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-28T09:00:00Z"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "VND"
+                },
+                "expected_at": {
+                    "type": "string",
+                    "example": "2024-02-04"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "0b5f7a0e-8f0e-4c55-a9f5-8c1e2b1f1d2a"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.PurchaseOrderLineData"
+                    }
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Deliver to the back door"
+                },
+                "number": {
+                    "type": "string",
+                    "example": "PO-3G7dRk2Lq"
+                },
+                "received_at": {
+                    "type": "string",
+                    "example": "2024-02-03T15:30:00Z"
+                },
+                "sent_at": {
+                    "type": "string",
+                    "example": "2024-01-28T10:00:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "sent"
+                },
+                "supplier_id": {
+                    "type": "string",
+                    "example": "67389c69-2e78-413a-9d77-6b749520b127"
+                },
+                "supplier_name": {
+                    "type": "string",
+                    "example": "Crunchy Munch"
+                },
+                "total": {
+                    "type": "number",
+                    "example": 600000
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.GetPurchaseOrderDetailResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetPurchaseOrderDetailData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.GetStocktakeDetailData": {
+            "type": "object",
+            "properties": {
+                "approved_at": {
+                    "type": "string",
+                    "example": "2024-02-03T15:30:00Z"
+                },
+                "approved_by": {
+                    "type": "string",
+                    "example": "8hTq1LmZx"
+                },
+                "cancelled_at": {
+                    "type": "string",
+                    "example": "2024-01-29T09:00:00Z"
+                },
+                "category_id": {
+                    "type": "string",
+                    "example": "67389c69-2e78-413a-9d77-6b749520b127"
+                },
+                "counted": {
+                    "type": "integer",
+                    "example": 40
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-28T09:00:00Z"
+                },
+                "created_by": {
+                    "type": "string",
+                    "example": "3G7dRk2Lq"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "0b5f7a0e-8f0e-4c55-a9f5-8c1e2b1f1d2a"
+                },
+                "include_subcategories": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.StocktakeLineData"
+                    }
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Quarterly count of the back store"
+                },
+                "number": {
+                    "type": "string",
+                    "example": "ST-3G7dRk2Lq"
+                },
+                "products": {
+                    "description": "Products is the number of lines, Counted the number of them counted so far",
+                    "type": "integer",
+                    "example": 42
+                },
+                "status": {
+                    "type": "string",
+                    "example": "open"
+                },
+                "stock_location": {
+                    "type": "string",
+                    "example": "Ha Noi"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.GetStocktakeDetailResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetStocktakeDetailData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.GetStocktakeVarianceData": {
+            "type": "object",
+            "properties": {
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.StocktakeLineData"
+                    }
+                },
+                "number": {
+                    "type": "string",
+                    "example": "ST-3G7dRk2Lq"
+                },
+                "shrinkage": {
+                    "description": "Shrinkage and Surplus add up the variances under and over 0, in quantity and at the price of the products",
+                    "type": "integer",
+                    "example": -5
+                },
+                "shrinkage_value": {
+                    "type": "number",
+                    "example": -750000
+                },
+                "status": {
+                    "type": "string",
+                    "example": "open"
+                },
+                "surplus": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "surplus_value": {
+                    "type": "number",
+                    "example": 300000
+                },
+                "uncounted": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.StocktakeLineData"
+                    }
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.GetSupplierDetailData": {
+            "type": "object",
+            "properties": {
+                "addresses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.SupplierAddressData"
+                    }
+                },
+                "contacts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.SupplierContactData"
+                    }
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "VND"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "contact@crunchymunch.com"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "67389c69-2e78-413a-9d77-6b749520b127"
+                },
+                "lead_time_days": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Food"
+                },
+                "payment_terms": {
+                    "type": "string",
+                    "example": "Net 30"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+84 28 3822 1234"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                },
+                "stock": {
+                    "description": "Stock is only filled in the supplier detail",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.SupplierStockData"
+                        }
+                    ]
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.GetSupplierDetailResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetSupplierDetailData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.GetTaskData": {
+            "type": "object",
+            "properties": {
+                "cron": {
+                    "type": "string",
+                    "example": "0 */5 * * * *"
+                },
+                "dry_run": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "name": {
+                    "type": "string",
+                    "example": "check_user"
+                },
+                "overlap": {
+                    "type": "string",
+                    "example": "skip"
+                },
+                "timeout": {
+                    "type": "string",
+                    "example": "1m0s"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.GetTaskRunData": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "error": {
+                    "type": "string",
+                    "example": ""
+                },
+                "finished_at": {
+                    "type": "string",
+                    "example": "2024-01-28T10:00:01Z"
+                },
+                "host": {
+                    "type": "string",
+                    "example": "task-7d9f8"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "result": {},
+                "scheduled_at": {
+                    "type": "string",
+                    "example": "2024-01-28T10:00:00Z"
+                },
+                "started_at": {
+                    "type": "string",
+                    "example": "2024-01-28T10:00:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "task_name": {
+                    "type": "string",
+                    "example": "check_user"
+                },
+                "trigger": {
+                    "type": "string",
+                    "example": "schedule"
+                },
+                "triggered_by": {
+                    "type": "string",
+                    "example": "1sdWdNiYj"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.GetTaskRunResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetTaskRunData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.GetTrashResponse": {
+            "type": "object",
+            "required": [
+                "page",
+                "size"
+            ],
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {},
+                "message": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 10,
+                    "example": 10
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "total_rows": {
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.IssueBundleData": {
+            "type": "object",
+            "properties": {
+                "components": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.IssuedComponentData"
+                    }
+                },
+                "quantity": {
+                    "description": "Quantity is how many bundles can still be built",
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.IssueBundleRequest": {
+            "type": "object",
+            "required": [
+                "quantity"
+            ],
+            "properties": {
+                "quantity": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "source_id": {
+                    "description": "SourceID is kept in the stock movements of the components, the reference of the bundle by default",
+                    "type": "string",
+                    "maxLength": 150,
+                    "example": "SO-2024-0042"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.IssuedComponentData": {
+            "type": "object",
+            "properties": {
+                "issued": {
+                    "description": "Issued is what was taken for the bundles, Stock what is left of the component",
+                    "type": "integer",
+                    "example": 6
+                },
+                "lots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.LotConsumptionData"
+                    }
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "PROD-202401-001"
+                },
+                "stock": {
+                    "type": "integer",
+                    "example": 9
+                },
+                "without_lot": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "1234@gmail.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.LoginResponseData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.LoginResponseData": {
             "type": "object",
             "properties": {
-                "name": {
+                "accessToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.LotConsumptionData": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
                     "type": "string",
-                    "maxLength": 100,
-                    "minLength": 3,
-                    "example": "Crunchy Munch"
+                    "example": "2024-04-15"
                 },
-                "status": {
+                "lot_number": {
                     "type": "string",
-                    "enum": [
-                        "active",
-                        "deactive"
-                    ],
-                    "example": "active"
+                    "example": "L240115-A"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 6
                 }
             }
         },
-        "github_com_quydmfl_niveau-test_api_v1.CreateProductRequest": {
+        "github_com_quydmfl_niveau-test_api_v1.MergeCategoryData": {
+            "type": "object",
+            "properties": {
+                "moved_products": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "moved_subcategories": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.MergeCategoryRequest": {
             "type": "object",
             "required": [
-                "category_id",
-                "price",
-                "product_name",
-                "quantity",
-                "status",
-                "stock_location",
-                "supplier_id"
+                "target_id"
             ],
             "properties": {
-                "category_id": {
+                "target_id": {
+                    "description": "TargetId receives the products and subcategories, the merged category goes to the trash",
                     "type": "string",
                     "example": "67389c69-2e78-413a-9d77-6b749520b127"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.MergeSupplierData": {
+            "type": "object",
+            "properties": {
+                "moved_addresses": {
+                    "type": "integer",
+                    "example": 0
                 },
-                "price": {
-                    "type": "number",
-                    "example": 150000
+                "moved_contacts": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "product_name": {
+                "moved_products": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.MergeSupplierRequest": {
+            "type": "object",
+            "required": [
+                "target_id"
+            ],
+            "properties": {
+                "target_id": {
+                    "description": "TargetId receives the products, contacts and addresses, the merged supplier goes to the trash",
                     "type": "string",
-                    "maxLength": 100,
-                    "minLength": 3,
-                    "example": "Crunchy Munch"
+                    "example": "67389c69-2e78-413a-9d77-6b749520b127"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.PatchProductData": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ProductFieldChange"
+                    }
                 },
-                "quantity": {
+                "version": {
                     "type": "integer",
-                    "example": 10
+                    "example": 5
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.ProductCategoryStatsResponse": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "string"
                 },
-                "reference": {
-                    "type": "string",
-                    "example": "PROD-202401-001"
+                "category_name": {
+                    "type": "string"
                 },
-                "status": {
-                    "type": "string",
-                    "enum": [
-                        "Available",
-                        "Out of Stock",
-                        "On Order"
-                    ],
-                    "example": "Available"
+                "own_percentage": {
+                    "type": "number"
                 },
-                "stock_location": {
-                    "type": "string",
-                    "example": "Brookstone"
+                "parent_id": {
+                    "type": "string"
                 },
-                "supplier_id": {
-                    "type": "string",
-                    "example": "66ae8b26-a0cd-40d5-8b2d-5127d7b9d817"
+                "percentage": {
+                    "description": "Percentage rolls up the products of the subcategories, OwnPercentage only counts the category itself",
+                    "type": "number"
                 }
             }
         },
-        "github_com_quydmfl_niveau-test_api_v1.CreateSupplierRequest": {
+        "github_com_quydmfl_niveau-test_api_v1.ProductFieldChange": {
             "type": "object",
             "properties": {
-                "name": {
+                "new": {},
+                "old": {}
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.ProductImageData": {
+            "type": "object",
+            "properties": {
+                "content_type": {
                     "type": "string",
-                    "maxLength": 100,
-                    "minLength": 3,
-                    "example": "Crunchy Munch"
+                    "example": "image/jpeg"
+                },
+                "filename": {
+                    "type": "string",
+                    "example": "front.jpg"
+                },
+                "height": {
+                    "type": "integer",
+                    "example": 1200
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6f1c2a0e-6a0b-4d8e-9a43-2f0b8d9e1c55"
+                },
+                "position": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 482133
+                },
+                "thumbnails": {
+                    "description": "Thumbnails are the URLs of the thumbnails by their longest side in pixels",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string",
+                    "example": "/images/6f1c2a0e-6a0b-4d8e-9a43-2f0b8d9e1c55.jpg"
+                },
+                "width": {
+                    "type": "integer",
+                    "example": 1600
                 }
             }
         },
-        "github_com_quydmfl_niveau-test_api_v1.GetCategoryDetailData": {
+        "github_com_quydmfl_niveau-test_api_v1.ProductLotData": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "days_left": {
+                    "description": "DaysLeft is negative once the lot has expired",
+                    "type": "integer",
+                    "example": 12
+                },
+                "expires_at": {
                     "type": "string",
-                    "example": "2023-01-01"
+                    "example": "2024-04-15"
+                },
+                "expiry_flagged_at": {
+                    "description": "ExpiryFlaggedAt is when the lot expiry task flagged the lot as nearing expiry",
+                    "type": "string",
+                    "example": "2024-04-01T06:00:00Z"
                 },
                 "id": {
                     "type": "string",
-                    "example": "67389c69-2e78-413a-9d77-6b749520b127"
+                    "example": "3b8f0c2e-41d5-4c1e-9d7a-5e2f6a1b9c04"
                 },
-                "name": {
+                "lot_number": {
                     "type": "string",
-                    "example": "Food"
+                    "example": "L240115-A"
                 },
-                "status": {
+                "manufactured_at": {
                     "type": "string",
-                    "example": "active"
+                    "example": "2024-01-15"
                 },
-                "updated_at": {
+                "product_name": {
                     "type": "string",
-                    "example": "2023-01-01"
+                    "example": "Crunchy Munch"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 18
+                },
+                "received_at": {
+                    "type": "string",
+                    "example": "2024-01-20T09:30:00Z"
+                },
+                "received_quantity": {
+                    "type": "integer",
+                    "example": 24
+                },
+                "reference": {
+                    "description": "Reference and ProductName are only set in the lists of lots of several products",
+                    "type": "string",
+                    "example": "PROD-202401-001"
+                },
+                "stock_location": {
+                    "type": "string",
+                    "example": "Ha Noi"
                 }
             }
         },
-        "github_com_quydmfl_niveau-test_api_v1.GetCategoryDetailResponse": {
+        "github_com_quydmfl_niveau-test_api_v1.ProductSupplierStatsResponse": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "integer"
+                "percentage": {
+                    "type": "number"
                 },
-                "data": {
-                    "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetCategoryDetailData"
+                "supplier_id": {
+                    "type": "string"
                 },
-                "message": {
+                "supplier_name": {
                     "type": "string"
                 }
             }
         },
-        "github_com_quydmfl_niveau-test_api_v1.GetProductDetailData": {
+        "github_com_quydmfl_niveau-test_api_v1.ProductVariantData": {
             "type": "object",
             "properties": {
-                "added_date": {
+                "gtin": {
                     "type": "string",
-                    "example": "2024-01-28"
+                    "example": "4006381333948"
                 },
-                "category": {
-                    "type": "string",
-                    "example": "Food"
+                "options": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 },
                 "price": {
                     "type": "number",
@@ -1060,7 +6704,7 @@ const docTemplate = `{
                 },
                 "product_name": {
                     "type": "string",
-                    "example": "Crunchy Munch"
+                    "example": "Crunchy Munch M"
                 },
                 "quantity": {
                     "type": "integer",
@@ -1068,161 +6712,451 @@ const docTemplate = `{
                 },
                 "reference": {
                     "type": "string",
-                    "example": "PROD-202401-001"
+                    "example": "PROD-202401-001-M"
                 },
                 "status": {
                     "type": "string",
                     "example": "Available"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.PurchaseOrderLineData": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Potato chips"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "received_quantity": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "PROD-2024-01-0001"
+                },
+                "unit_cost": {
+                    "type": "number",
+                    "example": 12000
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.PurchaseOrderLineRequest": {
+            "type": "object",
+            "required": [
+                "quantity",
+                "reference"
+            ],
+            "properties": {
+                "quantity": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 50
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "PROD-2024-01-0001"
+                },
+                "unit_cost": {
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 12000
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.ReceiveProductLotRequest": {
+            "type": "object",
+            "required": [
+                "expires_at",
+                "lot_number",
+                "quantity"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2024-04-15"
+                },
+                "lot_number": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "L240115-A"
+                },
+                "manufactured_at": {
+                    "type": "string",
+                    "example": "2024-01-15"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 24
                 },
                 "stock_location": {
+                    "description": "StockLocation is where the lot is stored, the stock location of the product by default",
                     "type": "string",
-                    "example": "Cedar Valley"
+                    "maxLength": 100,
+                    "example": "Ha Noi"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.ReceivePurchaseOrderLineRequest": {
+            "type": "object",
+            "required": [
+                "quantity",
+                "reference"
+            ],
+            "properties": {
+                "quantity": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 20
                 },
-                "supplier": {
+                "reference": {
                     "type": "string",
-                    "example": "KFC"
+                    "example": "PROD-2024-01-0001"
                 }
             }
         },
-        "github_com_quydmfl_niveau-test_api_v1.GetProfileResponse": {
+        "github_com_quydmfl_niveau-test_api_v1.ReceivePurchaseOrderRequest": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "integer"
-                },
-                "data": {
-                    "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetProfileResponseData"
-                },
-                "message": {
-                    "type": "string"
+                "lines": {
+                    "description": "Lines lists the received quantities, everything still expected is received when empty",
+                    "type": "array",
+                    "maxItems": 200,
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.ReceivePurchaseOrderLineRequest"
+                    }
                 }
             }
         },
-        "github_com_quydmfl_niveau-test_api_v1.GetProfileResponseData": {
+        "github_com_quydmfl_niveau-test_api_v1.RegisterRequest": {
             "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
             "properties": {
-                "nickname": {
+                "email": {
                     "type": "string",
-                    "example": "alan"
+                    "example": "1234@gmail.com"
                 },
-                "userId": {
-                    "type": "string"
+                "password": {
+                    "type": "string",
+                    "example": "123456"
                 }
             }
         },
-        "github_com_quydmfl_niveau-test_api_v1.GetSupplierDetailData": {
+        "github_com_quydmfl_niveau-test_api_v1.ReorderProductImagesRequest": {
             "type": "object",
+            "required": [
+                "image_ids"
+            ],
             "properties": {
-                "id": {
-                    "type": "string",
-                    "example": "67389c69-2e78-413a-9d77-6b749520b127"
-                },
-                "name": {
-                    "type": "string",
-                    "example": "Food"
+                "image_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "github_com_quydmfl_niveau-test_api_v1.GetSupplierDetailResponse": {
+        "github_com_quydmfl_niveau-test_api_v1.Response": {
             "type": "object",
             "properties": {
                 "code": {
                     "type": "integer"
                 },
-                "data": {
-                    "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.GetSupplierDetailData"
-                },
+                "data": {},
                 "message": {
                     "type": "string"
                 }
             }
         },
-        "github_com_quydmfl_niveau-test_api_v1.LoginRequest": {
+        "github_com_quydmfl_niveau-test_api_v1.ScheduleProductPriceRequest": {
             "type": "object",
             "required": [
-                "email",
-                "password"
+                "effective_from",
+                "price"
             ],
             "properties": {
-                "email": {
+                "effective_from": {
                     "type": "string",
-                    "example": "1234@gmail.com"
+                    "example": "2024-02-01T00:00:00Z"
                 },
-                "password": {
-                    "type": "string",
-                    "example": "123456"
+                "price": {
+                    "type": "number",
+                    "example": 145000
                 }
             }
         },
-        "github_com_quydmfl_niveau-test_api_v1.LoginResponse": {
+        "github_com_quydmfl_niveau-test_api_v1.SearchAuditLogResponse": {
             "type": "object",
+            "required": [
+                "page",
+                "size"
+            ],
             "properties": {
                 "code": {
                     "type": "integer"
                 },
-                "data": {
-                    "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.LoginResponseData"
-                },
+                "data": {},
                 "message": {
                     "type": "string"
+                },
+                "page": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 10,
+                    "example": 10
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "total_rows": {
+                    "type": "integer",
+                    "example": 100
                 }
             }
         },
-        "github_com_quydmfl_niveau-test_api_v1.LoginResponseData": {
+        "github_com_quydmfl_niveau-test_api_v1.SearchCategoryResponse": {
             "type": "object",
+            "required": [
+                "page",
+                "size"
+            ],
             "properties": {
-                "accessToken": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {},
+                "message": {
                     "type": "string"
+                },
+                "page": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 10,
+                    "example": 10
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "total_rows": {
+                    "type": "integer",
+                    "example": 100
                 }
             }
         },
-        "github_com_quydmfl_niveau-test_api_v1.ProductCategoryStatsResponse": {
+        "github_com_quydmfl_niveau-test_api_v1.SearchForecastResponse": {
             "type": "object",
+            "required": [
+                "page",
+                "size"
+            ],
             "properties": {
-                "category_id": {
-                    "type": "string"
+                "code": {
+                    "type": "integer"
                 },
-                "category_name": {
+                "data": {},
+                "message": {
                     "type": "string"
                 },
-                "percentage": {
-                    "type": "number"
+                "page": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 10,
+                    "example": 10
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "total_rows": {
+                    "type": "integer",
+                    "example": 100
                 }
             }
         },
-        "github_com_quydmfl_niveau-test_api_v1.ProductSupplierStatsResponse": {
+        "github_com_quydmfl_niveau-test_api_v1.SearchProductRequest": {
             "type": "object",
+            "required": [
+                "page",
+                "size"
+            ],
             "properties": {
-                "percentage": {
-                    "type": "number"
+                "attribute": {
+                    "description": "Attributes filters on attribute values given as name:value, the values are compared as the detail shows them",
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "color:red"
+                    ]
                 },
-                "supplier_id": {
-                    "type": "string"
+                "category_id": {
+                    "type": "string",
+                    "example": "1"
                 },
-                "supplier_name": {
-                    "type": "string"
+                "collapse_variants": {
+                    "description": "CollapseVariants lists a parent instead of its variants, when it or one of them matches, with their total quantity",
+                    "type": "boolean",
+                    "example": true
+                },
+                "date_added_from": {
+                    "type": "string",
+                    "example": "2024-01-28"
+                },
+                "date_added_to": {
+                    "type": "string",
+                    "example": "2024-01-28"
+                },
+                "gtin": {
+                    "description": "GTIN matches the code however it is written, e.g. a UPC-A as 12 digits or as the 13 of an EAN-13",
+                    "type": "string",
+                    "example": "4006381333931"
+                },
+                "include_archived": {
+                    "description": "IncludeArchived lists the archived products too, they are hidden unless filtered by status",
+                    "type": "boolean",
+                    "example": false
+                },
+                "include_subcategories": {
+                    "description": "IncludeSubcategories extends the category_id filter to the whole subtree of the category",
+                    "type": "boolean",
+                    "example": true
+                },
+                "max_price": {
+                    "type": "number",
+                    "example": 500000
+                },
+                "min_price": {
+                    "type": "number",
+                    "example": 100000
+                },
+                "page": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "price_at": {
+                    "description": "PriceAt makes the price filters, sorting and effective_price use the price in effect on that date",
+                    "type": "string",
+                    "example": "2024-02-01"
+                },
+                "product_name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3,
+                    "example": "Crunchy Munch"
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "PROD-202401-001"
+                },
+                "size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 10,
+                    "example": 10
+                },
+                "sort_by": {
+                    "type": "string",
+                    "example": "price"
+                },
+                "sort_order": {
+                    "type": "string",
+                    "enum": [
+                        "asc",
+                        "desc"
+                    ],
+                    "example": "asc"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "Draft",
+                        "Available",
+                        "Out of Stock",
+                        "On Order",
+                        "Discontinued",
+                        "Archived"
+                    ],
+                    "example": "Available"
+                },
+                "stock_location_id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "supplier_id": {
+                    "type": "string",
+                    "example": "1"
                 }
             }
         },
-        "github_com_quydmfl_niveau-test_api_v1.RegisterRequest": {
+        "github_com_quydmfl_niveau-test_api_v1.SearchProductResponse": {
             "type": "object",
             "required": [
-                "email",
-                "password"
+                "page",
+                "size"
             ],
             "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "1234@gmail.com"
+                "code": {
+                    "type": "integer"
                 },
-                "password": {
-                    "type": "string",
-                    "example": "123456"
+                "data": {},
+                "message": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 10,
+                    "example": 10
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "total_rows": {
+                    "type": "integer",
+                    "example": 100
                 }
             }
         },
-        "github_com_quydmfl_niveau-test_api_v1.Response": {
+        "github_com_quydmfl_niveau-test_api_v1.SearchPurchaseOrderResponse": {
             "type": "object",
+            "required": [
+                "page",
+                "size"
+            ],
             "properties": {
                 "code": {
                     "type": "integer"
@@ -1230,10 +7164,29 @@ const docTemplate = `{
                 "data": {},
                 "message": {
                     "type": "string"
+                },
+                "page": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 10,
+                    "example": 10
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "total_rows": {
+                    "type": "integer",
+                    "example": 100
                 }
             }
         },
-        "github_com_quydmfl_niveau-test_api_v1.SearchCategoryResponse": {
+        "github_com_quydmfl_niveau-test_api_v1.SearchStocktakeResponse": {
             "type": "object",
             "required": [
                 "page",
@@ -1268,7 +7221,7 @@ const docTemplate = `{
                 }
             }
         },
-        "github_com_quydmfl_niveau-test_api_v1.SearchProductResponse": {
+        "github_com_quydmfl_niveau-test_api_v1.SearchSupplierResponse": {
             "type": "object",
             "required": [
                 "page",
@@ -1303,7 +7256,7 @@ const docTemplate = `{
                 }
             }
         },
-        "github_com_quydmfl_niveau-test_api_v1.SearchSupplierResponse": {
+        "github_com_quydmfl_niveau-test_api_v1.SearchTaskRunResponse": {
             "type": "object",
             "required": [
                 "page",
@@ -1338,6 +7291,310 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_quydmfl_niveau-test_api_v1.SetBundleComponentsRequest": {
+            "type": "object",
+            "properties": {
+                "components": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "uniqueItems": true,
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.BundleComponentRequest"
+                    }
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.StocktakeCountRequest": {
+            "type": "object",
+            "required": [
+                "reference"
+            ],
+            "properties": {
+                "quantity": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 12
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "PROD-202401-001"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.StocktakeLineData": {
+            "type": "object",
+            "properties": {
+                "adjustment": {
+                    "description": "Adjustment is the change of stock applied by the approval",
+                    "type": "integer",
+                    "example": -2
+                },
+                "counted_quantity": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "product_name": {
+                    "type": "string",
+                    "example": "Crunchy Munch"
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "PROD-202401-001"
+                },
+                "stock_location": {
+                    "type": "string",
+                    "example": "Ha Noi"
+                },
+                "system_quantity": {
+                    "description": "SystemQuantity is the stock of the product now, or when the stocktake was approved",
+                    "type": "integer",
+                    "example": 12
+                },
+                "variance": {
+                    "description": "Variance is the counted quantity less the system quantity, VarianceValue the variance at the price of the product",
+                    "type": "integer",
+                    "example": -2
+                },
+                "variance_value": {
+                    "type": "number",
+                    "example": -300000
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.SubmitStocktakeCountsRequest": {
+            "type": "object",
+            "required": [
+                "counts",
+                "device_id"
+            ],
+            "properties": {
+                "counts": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "uniqueItems": true,
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.StocktakeCountRequest"
+                    }
+                },
+                "device_id": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "scanner-02"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.SupplierAddressData": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "example": "Ho Chi Minh"
+                },
+                "country": {
+                    "type": "string",
+                    "example": "VN"
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "billing"
+                },
+                "line1": {
+                    "type": "string",
+                    "example": "12 Nguyen Hue"
+                },
+                "line2": {
+                    "type": "string",
+                    "example": "Floor 3"
+                },
+                "postal_code": {
+                    "type": "string",
+                    "example": "700000"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.SupplierAddressRequest": {
+            "type": "object",
+            "required": [
+                "city",
+                "country",
+                "kind",
+                "line1"
+            ],
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Ho Chi Minh"
+                },
+                "country": {
+                    "type": "string",
+                    "example": "VN"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "billing",
+                        "shipping",
+                        "office",
+                        "warehouse"
+                    ],
+                    "example": "billing"
+                },
+                "line1": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "12 Nguyen Hue"
+                },
+                "line2": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Floor 3"
+                },
+                "postal_code": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "example": "700000"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.SupplierContactData": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "sales@crunchymunch.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Nguyen Van A"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+84 28 3822 1234"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "Sales"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.SupplierContactRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "sales@crunchymunch.com"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 2,
+                    "example": "Nguyen Van A"
+                },
+                "phone": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "+84 28 3822 1234"
+                },
+                "role": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Sales"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.SupplierStockData": {
+            "type": "object",
+            "properties": {
+                "product_count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 340
+                },
+                "stock_value": {
+                    "description": "StockValue is the sum of quantity times price, in the currency of the products",
+                    "type": "number",
+                    "example": 1520000
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.TransitionProductRequest": {
+            "type": "object",
+            "required": [
+                "reason",
+                "status"
+            ],
+            "properties": {
+                "reason": {
+                    "description": "Reason is kept in the status history of the product",
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 3,
+                    "example": "Replaced by the 200g pack"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "Draft",
+                        "Available",
+                        "Out of Stock",
+                        "On Order",
+                        "Discontinued",
+                        "Archived"
+                    ],
+                    "example": "Discontinued"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.UpdateCategoryRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "status"
+            ],
+            "properties": {
+                "attributes": {
+                    "description": "Attributes replaces the attribute schema, the values of the products are checked against it on their next write",
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.CategoryAttributeRequest"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3,
+                    "example": "Crunchy Munch"
+                },
+                "parent_id": {
+                    "description": "ParentId moves the category with its subtree, empty makes it a root category",
+                    "type": "string",
+                    "example": "67389c69-2e78-413a-9d77-6b749520b127"
+                },
+                "reference_prefix": {
+                    "description": "ReferencePrefix only applies to the references generated from now on",
+                    "type": "string",
+                    "example": "FOOD"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "deactive"
+                    ],
+                    "example": "active"
+                }
+            }
+        },
         "github_com_quydmfl_niveau-test_api_v1.UpdateProductRequest": {
             "type": "object",
             "required": [
@@ -1355,10 +7612,27 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2024-01-28"
                 },
+                "attributes": {
+                    "description": "Attributes are the values for the attribute schema of the category, by attribute name",
+                    "type": "object",
+                    "additionalProperties": true
+                },
                 "category_id": {
                     "type": "string",
                     "example": "67389c69-2e78-413a-9d77-6b749520b127"
                 },
+                "gtin": {
+                    "description": "GTIN is removed when empty",
+                    "type": "string",
+                    "example": "4006381333931"
+                },
+                "options": {
+                    "description": "Options are only set for a variant, the category and supplier of a variant are those of its parent",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "price": {
                     "type": "number",
                     "example": 150000
@@ -1376,9 +7650,12 @@ const docTemplate = `{
                 "status": {
                     "type": "string",
                     "enum": [
+                        "Draft",
                         "Available",
                         "Out of Stock",
-                        "On Order"
+                        "On Order",
+                        "Discontinued",
+                        "Archived"
                     ],
                     "example": "Available"
                 },
@@ -1407,6 +7684,91 @@ const docTemplate = `{
                     "example": "alan"
                 }
             }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.UpdatePurchaseOrderRequest": {
+            "type": "object",
+            "required": [
+                "lines"
+            ],
+            "properties": {
+                "lines": {
+                    "type": "array",
+                    "maxItems": 200,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.PurchaseOrderLineRequest"
+                    }
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Deliver to the back door"
+                }
+            }
+        },
+        "github_com_quydmfl_niveau-test_api_v1.UpdateSupplierRequest": {
+            "type": "object",
+            "required": [
+                "currency",
+                "name",
+                "status"
+            ],
+            "properties": {
+                "addresses": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.SupplierAddressRequest"
+                    }
+                },
+                "contacts": {
+                    "description": "Contacts and Addresses replace the current ones",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "$ref": "#/definitions/github_com_quydmfl_niveau-test_api_v1.SupplierContactRequest"
+                    }
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "VND"
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "contact@crunchymunch.com"
+                },
+                "lead_time_days": {
+                    "type": "integer",
+                    "maximum": 365,
+                    "minimum": 0,
+                    "example": 7
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3,
+                    "example": "Crunchy Munch"
+                },
+                "payment_terms": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Net 30"
+                },
+                "phone": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "+84 28 3822 1234"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "deactive"
+                    ],
+                    "example": "active"
+                }
+            }
         }
     },
     "securityDefinitions": {
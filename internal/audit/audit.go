@@ -0,0 +1,41 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+const ctxActorKey = "AuditActor"
+
+// Actor is who performs the write operations recorded in the audit log
+type Actor struct {
+	// UserID is the authenticated user, or the component name for background work
+	UserID  string
+	TraceID string
+	IP      string
+}
+
+// Skipper is implemented by the models that must not be audited, e.g. the audit log itself
+type Skipper interface {
+	SkipAudit() bool
+}
+
+// WithActor return a copy of ctx carrying the actor
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, ctxActorKey, actor)
+}
+
+// SetActor stores the actor of the current request, services receive it through the gin context
+func SetActor(ctx *gin.Context, actor Actor) {
+	ctx.Set(ctxActorKey, actor)
+}
+
+// ActorFromContext return the actor of ctx, the zero Actor if none was set
+func ActorFromContext(ctx context.Context) Actor {
+	if ctx == nil {
+		return Actor{}
+	}
+	actor, _ := ctx.Value(ctxActorKey).(Actor)
+	return actor
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/service"
+)
+
+type AuditHandler struct {
+	*Handler
+	auditService service.AuditService
+}
+
+func NewAuditHandler(
+	handler *Handler,
+	auditService service.AuditService,
+) *AuditHandler {
+	return &AuditHandler{
+		Handler:      handler,
+		auditService: auditService,
+	}
+}
+
+// GetAuditLogs godoc
+// @Summary Get audit logs
+// @Description Retrieve a paginated list of write operations with their changed fields, latest first.
+// @Tags Admin Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param entity_type query string false "Filter by entity type (table name)" example("products")
+// @Param entity_id query string false "Filter by entity ID"
+// @Param actor_id query string false "Filter by actor" example("1sdWdNiYj")
+// @Param action query string false "Filter by action" Enums(create, update, delete)
+// @Param date_from query string false "Filter by start date (YYYY-MM-DD)" format(date) example("2024-01-28")
+// @Param date_to query string false "Filter by end date (YYYY-MM-DD)" format(date) example("2024-01-28")
+// @Param page query int true "Page number (must be >= 1)" default(1) example(1)
+// @Param size query int true "Items per page (between 10-100)" default(20) example(10)
+// @Success 200 {object} v1.SearchAuditLogResponse
+// @Failure 403 {object} v1.Response "The user is not an admin"
+// @Router /admin/audit-logs [get]
+func (h *AuditHandler) GetAuditLogs(ctx *gin.Context) {
+	var req v1.SearchAuditLogRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	logs, err := h.auditService.GetAuditLogs(ctx, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, logs)
+}
@@ -33,6 +33,7 @@ func NewCategoryHandler(
 // @Security Bearer
 // @Param name query string false "Filter by category name"
 // @Param status query string false "Filter by Status" Enums(active, deactive) example("active")
+// @Param parent_id query string false "List the direct subcategories of a category"
 // @Param sort_by query string false "Sort by field" Enums(name, created_at) default(created_at) example("name")
 // @Param sort_order query string false "Sort order" Enums(asc, desc) default(desc) example("asc")
 // @Param page query int true "Page number (must be >= 1)" default(1) example(1)
@@ -68,6 +69,7 @@ func (h *CategoryHandler) GetCategories(ctx *gin.Context) {
 // @Security Bearer
 // @Param id path string true "Category ID"
 // @Success 200 {object} v1.GetCategoryDetailResponse "Successful response"
+// @Header 200 {string} ETag "Version of the resource"
 // @Router /categories/{id} [get]
 func (h *CategoryHandler) GetCategoryDetail(ctx *gin.Context) {
 	categoryID := ctx.Param("id")
@@ -83,6 +85,7 @@ func (h *CategoryHandler) GetCategoryDetail(ctx *gin.Context) {
 		return
 	}
 
+	ctx.Header("ETag", v1.ETag(category.Version))
 	v1.HandleSuccess(ctx, category)
 }
 
@@ -110,3 +113,84 @@ func (h *CategoryHandler) CreateCategory(ctx *gin.Context) {
 
 	v1.HandleSuccess(ctx, nil)
 }
+
+// UpdateCategory godoc
+// @Summary Update a category
+// @Description Update a category, changing its parent moves it with its subcategories
+// @Tags Categories Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Category ID"
+// @Param request body v1.UpdateCategoryRequest true "Category update request"
+// @Param If-Match header string false "ETag of the category as last read, the update is refused if it changed since"
+// @Success 200 {object} v1.GetCategoryDetailData
+// @Header 200 {string} ETag "Version of the category"
+// @Failure 409 {object} v1.Response "The category still has live products or subcategories"
+// @Failure 412 {object} v1.Response "The category has been modified since it was read"
+// @Router /categories/{id} [put]
+func (h *CategoryHandler) UpdateCategory(ctx *gin.Context) {
+	req := new(v1.UpdateCategoryRequest)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	category, err := h.categoryService.UpdateCategory(ctx, ctx.Param("id"), req, v1.ParseIfMatch(ctx.GetHeader("If-Match")))
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", v1.ETag(category.Version))
+	v1.HandleSuccess(ctx, category)
+}
+
+// MergeCategory godoc
+// @Summary Merge a category into another
+// @Description Moves the products and subcategories of the category to the target in one transaction, then puts the category in the trash
+// @Tags Categories Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Category ID"
+// @Param request body v1.MergeCategoryRequest true "Category merge request"
+// @Success 200 {object} v1.MergeCategoryData
+// @Failure 400 {object} v1.Response "The target is in the subtree of the category or a product has attribute values outside the schema of the target"
+// @Router /categories/{id}/merge [post]
+func (h *CategoryHandler) MergeCategory(ctx *gin.Context) {
+	req := new(v1.MergeCategoryRequest)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.categoryService.MergeCategory(ctx, GetUserIdFromCtx(ctx), ctx.Param("id"), req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, data)
+}
+
+// DeleteCategory godoc
+// @Summary Delete a category
+// @Description Moves the category to the trash, it can be restored until it is purged
+// @Tags Categories Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Category ID"
+// @Param If-Match header string false "ETag of the category as last read, the delete is refused if it changed since"
+// @Success 200 {object} v1.Response
+// @Failure 412 {object} v1.Response "The category has been modified since it was read"
+// @Router /categories/{id} [delete]
+func (h *CategoryHandler) DeleteCategory(ctx *gin.Context) {
+	if err := h.categoryService.DeleteCategory(ctx, ctx.Param("id"), v1.ParseIfMatch(ctx.GetHeader("If-Match"))); err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, nil)
+}
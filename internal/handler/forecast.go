@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/service"
+)
+
+type ForecastHandler struct {
+	*Handler
+	forecastService service.ForecastService
+}
+
+func NewForecastHandler(
+	handler *Handler,
+	forecastService service.ForecastService,
+) *ForecastHandler {
+	return &ForecastHandler{
+		Handler:         handler,
+		forecastService: forecastService,
+	}
+}
+
+// GetForecasts godoc
+// @Summary Get demand forecasts and reorder suggestions
+// @Description Retrieve a paginated list of the forecasts computed by the demand_forecast task, the largest reorder first
+// @Tags Forecast Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param supplier_id query string false "Filter by supplier ID"
+// @Param reorder_only query bool false "Only the products with a reorder to place now"
+// @Param page query int true "Page number (must be >= 1)" default(1) example(1)
+// @Param size query int true "Items per page (between 10-100)" default(20) example(10)
+// @Success 200 {object} v1.SearchForecastResponse
+// @Router /forecasts [get]
+func (h *ForecastHandler) GetForecasts(ctx *gin.Context) {
+	var req v1.SearchForecastRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	forecasts, err := h.forecastService.GetForecasts(ctx, &req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, forecasts)
+}
+
+// GetProductForecast godoc
+// @Summary Get the demand forecast of a product
+// @Description Retrieve the forecast demand of a product and the reorder it suggests, as of the last run of the demand_forecast task
+// @Tags Forecast Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Success 200 {object} v1.GetProductForecastResponse
+// @Failure 404 {object} v1.Response "The product is not found or was not forecast yet"
+// @Router /products/{id}/forecast [get]
+func (h *ForecastHandler) GetProductForecast(ctx *gin.Context) {
+	forecast, err := h.forecastService.GetProductForecast(ctx, ctx.Param("id"))
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, forecast)
+}
+
+// ExportForecasts godoc
+// @Summary Export demand forecasts and reorder suggestions
+// @Description Download every forecast of the filter in the specified format (currently supports CSV), the largest reorder first
+// @Tags Forecast Modules
+// @Accept json
+// @Produce text/csv
+// @Security Bearer
+// @Param format path string true "Export format (e.g., 'csv')"
+// @Param supplier_id query string false "Filter by supplier ID"
+// @Param reorder_only query bool false "Only the products with a reorder to place now"
+// @Success 200 {file} file "Forecasts"
+// @Router /forecasts/export/{format} [get]
+func (h *ForecastHandler) ExportForecasts(ctx *gin.Context) {
+	var req v1.ExportForecastsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	var (
+		data        []byte
+		contentType string
+		err         error
+	)
+
+	switch ctx.Param("format") {
+	case "csv":
+		data, err = h.forecastService.ExportForecastsToCSV(ctx, &req)
+		contentType = "text/csv"
+	default:
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	fileName := fmt.Sprintf("forecasts_%s.%s", time.Now().Format("20060102_150405"), ctx.Param("format"))
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	ctx.Data(http.StatusOK, contentType, data)
+}
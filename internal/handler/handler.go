@@ -1,7 +1,15 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	"github.com/quydmfl/niveau-test/pkg/jwt"
 	"github.com/quydmfl/niveau-test/pkg/log"
 )
@@ -24,3 +32,57 @@ func GetUserIdFromCtx(ctx *gin.Context) string {
 	}
 	return v.(*jwt.MyCustomClaims).UserId
 }
+
+// BindMergePatch applies the JSON merge patch (RFC 7396) of the request body to obj and returns the JSON names
+// of the fields present. Each field present is validated with its binding tag, the others are left untouched.
+// Fields are not nullable, so null, which would remove a field, is refused like unknown fields.
+func BindMergePatch(ctx *gin.Context, obj interface{}) (map[string]bool, error) {
+	body, err := ctx.GetRawData()
+	if err != nil {
+		return nil, err
+	}
+
+	var patch map[string]json.RawMessage
+	if err := json.Unmarshal(body, &patch); err != nil {
+		return nil, fmt.Errorf("merge patch must be a JSON object: %w", err)
+	}
+
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return nil, errors.New("merge patch needs the validator engine")
+	}
+
+	target := reflect.ValueOf(obj).Elem()
+	fields := make(map[string]bool, len(patch))
+	for name, raw := range patch {
+		field, structField, ok := jsonField(target, name)
+		if !ok {
+			return nil, fmt.Errorf("unknown field %s", name)
+		}
+		if string(raw) == "null" {
+			return nil, fmt.Errorf("field %s can't be removed", name)
+		}
+		if err := json.Unmarshal(raw, field.Addr().Interface()); err != nil {
+			return nil, fmt.Errorf("field %s: %w", name, err)
+		}
+		if tag := structField.Tag.Get("binding"); tag != "" {
+			if err := validate.Var(field.Interface(), tag); err != nil {
+				return nil, fmt.Errorf("field %s: %w", name, err)
+			}
+		}
+		fields[name] = true
+	}
+
+	return fields, nil
+}
+
+// jsonField finds the field of the struct v by its JSON name
+func jsonField(v reflect.Value, name string) (reflect.Value, reflect.StructField, bool) {
+	for i := 0; i < v.NumField(); i++ {
+		structField := v.Type().Field(i)
+		if strings.Split(structField.Tag.Get("json"), ",")[0] == name {
+			return v.Field(i), structField, true
+		}
+	}
+	return reflect.Value{}, reflect.StructField{}, false
+}
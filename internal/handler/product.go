@@ -33,15 +33,21 @@ func NewProductHandler(
 // @Produce json
 // @Security Bearer
 // @Param reference query string false "Filter by Product Reference" example("PROD-202401-001")
+// @Param gtin query string false "Filter by GTIN, however it is written" example("4006381333931")
 // @Param product_name query string false "Filter by Product Name (3-100 chars)" example("Crunchy Munch")
 // @Param category_id query string false "Filter by Category ID (must be > 0)" example(1)
+// @Param include_subcategories query bool false "Include the products of the subcategories of category_id"
 // @Param supplier_id query string false "Filter by Supplier ID (must be > 0)" example(1)
 // @Param stock_location_id query string false "Filter by Stock Location ID (must be > 0)" example(1)
 // @Param min_price query number false "Filter by Minimum Price (must be > 0)" example(100000)
 // @Param max_price query number false "Filter by Maximum Price (must be > 0)" example(500000)
 // @Param date_added_from query string false "Filter by start date (YYYY-MM-DD)" format(date) example("2024-01-28")
 // @Param date_added_to query string false "Filter by end date (YYYY-MM-DD)" format(date) example("2024-01-28")
-// @Param status query string false "Filter by Product Status" Enums(Available, Out of Stock, On Order) example("Available")
+// @Param status query string false "Filter by Product Status" Enums(Draft, Available, Out of Stock, On Order, Discontinued, Archived) example("Available")
+// @Param include_archived query bool false "List the archived products too, they are hidden unless filtered by status"
+// @Param price_at query string false "Apply the price filters and sorting to the price in effect on this date (YYYY-MM-DD)" format(date) example("2024-02-01")
+// @Param collapse_variants query bool false "List a parent in place of its variants, with their total quantity"
+// @Param attribute query []string false "Filter on an attribute value as name:value, repeat it to filter on several attributes" collectionFormat(multi) example(color:red)
 // @Param sort_by query string false "Sort by field" Enums(price, name, added_date) default(added_date) example("price")
 // @Param sort_order query string false "Sort order" Enums(asc, desc) default(desc) example("asc")
 // @Param page query int true "Page number (must be >= 1)" default(1) example(1)
@@ -76,6 +82,7 @@ func (h *ProductHandler) GetProducts(ctx *gin.Context) {
 // @Security Bearer
 // @Param id path string true "Product Reference ID"
 // @Success 200 {object} v1.GetProductDetailData
+// @Header 200 {string} ETag "Version of the resource"
 // @Router /products/{id} [get]
 func (h *ProductHandler) GetProductDetail(ctx *gin.Context) {
 	userId := GetUserIdFromCtx(ctx)
@@ -97,6 +104,31 @@ func (h *ProductHandler) GetProductDetail(ctx *gin.Context) {
 		return
 	}
 
+	ctx.Header("ETag", v1.ETag(product.Version))
+	v1.HandleSuccess(ctx, product)
+}
+
+// GetProductByGTIN godoc
+// @Summary Look up a product by its GTIN
+// @Description Resolve a scanned EAN/UPC code to its product, the code matches however the GTIN was written, e.g. a UPC-A stored as 12 digits is found from the 13 digits of its EAN-13
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param gtin path string true "GTIN-8, GTIN-12, GTIN-13 or GTIN-14" example("4006381333931")
+// @Success 200 {object} v1.GetProductDetailData
+// @Header 200 {string} ETag "Version of the resource"
+// @Failure 400 {object} v1.Response "The check digit of the code is wrong"
+// @Failure 404 {object} v1.Response "No product has the code"
+// @Router /products/gtin/{gtin} [get]
+func (h *ProductHandler) GetProductByGTIN(ctx *gin.Context) {
+	product, err := h.productService.GetProductByGTIN(ctx, ctx.Param("gtin"))
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", v1.ETag(product.Version))
 	v1.HandleSuccess(ctx, product)
 }
 
@@ -109,6 +141,7 @@ func (h *ProductHandler) GetProductDetail(ctx *gin.Context) {
 // @Security Bearer
 // @Param request body v1.CreateProductRequest true "Product creation request"
 // @Success 201 {object} v1.Response "Product created successfully"
+// @Failure 409 {object} v1.Response "The GTIN belongs to another product"
 // @Router /products [post]
 func (h *ProductHandler) CreateProduct(ctx *gin.Context) {
 	userId := GetUserIdFromCtx(ctx)
@@ -124,7 +157,7 @@ func (h *ProductHandler) CreateProduct(ctx *gin.Context) {
 	}
 
 	if err := h.productService.CreateProduct(ctx, userId, req); err != nil {
-		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+		handleCatalogError(ctx, err)
 		return
 	}
 
@@ -140,7 +173,11 @@ func (h *ProductHandler) CreateProduct(ctx *gin.Context) {
 // @Security Bearer
 // @Param id path string true "Product Reference ID"
 // @Param body body v1.UpdateProductRequest true "Update Product Request Body"
+// @Param If-Match header string false "ETag of the product as last read, the update is refused if it changed since"
 // @Success 200 {object} map[string]interface{} "Success message"
+// @Failure 400 {object} v1.Response "The status differs from the current one, it changes through the transition of the product"
+// @Failure 409 {object} v1.Response "The GTIN belongs to another product"
+// @Failure 412 {object} v1.Response "The product has been modified since it was read"
 // @Router /products/{id} [put]
 func (h *ProductHandler) UpdateProduct(ctx *gin.Context) {
 	userId := GetUserIdFromCtx(ctx)
@@ -156,22 +193,69 @@ func (h *ProductHandler) UpdateProduct(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.productService.UpdateProduct(ctx, userId, productPref, &req); err != nil {
-		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+	if err := h.productService.UpdateProduct(ctx, userId, productPref, &req, v1.ParseIfMatch(ctx.GetHeader("If-Match"))); err != nil {
+		handleCatalogError(ctx, err)
 		return
 	}
 	v1.HandleSuccess(ctx, nil)
 }
 
+// PatchProduct godoc
+// @Summary Partially update a product by ID
+// @Description Apply a JSON merge patch (RFC 7396) to the product, only the fields present are validated and changed
+// @Tags Product Modules
+// @Accept json
+// @Accept application/merge-patch+json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Param body body v1.UpdateProductRequest true "Any subset of the fields of the update request"
+// @Param If-Match header string false "ETag of the product as last read, the update is refused if it changed since"
+// @Success 200 {object} v1.PatchProductData "Fields changed and new version"
+// @Header 200 {string} ETag "Version of the product"
+// @Failure 400 {object} v1.Response "The status differs from the current one, it changes through the transition of the product"
+// @Failure 409 {object} v1.Response "The GTIN belongs to another product"
+// @Failure 412 {object} v1.Response "The product has been modified since it was read"
+// @Router /products/{id} [patch]
+func (h *ProductHandler) PatchProduct(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	productPref := ctx.Param("id")
+	if productPref == "" {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	var (
+		req v1.PatchProductRequest
+		err error
+	)
+	if req.Fields, err = BindMergePatch(ctx, &req.UpdateProductRequest); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.productService.PatchProduct(ctx, userId, productPref, &req, v1.ParseIfMatch(ctx.GetHeader("If-Match")))
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", v1.ETag(data.Version))
+	v1.HandleSuccess(ctx, data)
+}
+
 // DeleteProduct godoc
 // @Summary Delete a product by ID
-// @Description Permanently deletes a product using the provided product reference (ID) in the URL
+// @Description Moves the product to the trash using the provided product reference (ID) in the URL, it can be restored until it is purged
 // @Tags Product Modules
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param id path string true "Product Reference ID"
+// @Param If-Match header string false "ETag of the product as last read, the delete is refused if it changed since"
 // @Success 200 {object} map[string]interface{} "Product deleted successfully"
+// @Failure 409 {object} v1.Response "The product still has variants or is a component of a bundle"
+// @Failure 412 {object} v1.Response "The product has been modified since it was read"
 // @Router /products/{id} [delete]
 func (h *ProductHandler) DeleteProduct(ctx *gin.Context) {
 	productPref := ctx.Param("id")
@@ -180,8 +264,8 @@ func (h *ProductHandler) DeleteProduct(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.productService.DeleteProduct(ctx, productPref); err != nil {
-		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+	if err := h.productService.DeleteProduct(ctx, productPref, v1.ParseIfMatch(ctx.GetHeader("If-Match"))); err != nil {
+		handleCatalogError(ctx, err)
 		return
 	}
 	v1.HandleSuccess(ctx, nil)
@@ -237,6 +321,7 @@ func (h *ProductHandler) GetProductsPerSupplier(ctx *gin.Context) {
 // @Security Bearer
 // @Param format path string true "Export format (e.g., 'pdf')"
 // @Param id path string true "Product ID (UUID)"
+// @Param price_date query string false "Print the price in effect on this date (YYYY-MM-DD)" format(date) example("2024-02-01")
 // @Success 200 {object} v1.Response "File generated successfully"
 // @Router /products/export/{format}/{id} [get]
 func (h *ProductHandler) ExportProducts(ctx *gin.Context) {
@@ -244,9 +329,15 @@ func (h *ProductHandler) ExportProducts(ctx *gin.Context) {
 	format := ctx.Param("format")
 	productId := ctx.Param("id")
 
+	var req v1.ExportProductRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
 	switch format {
 	case "pdf":
-		err = h.productService.ExportProductsToPDF(ctx, productId)
+		err = h.productService.ExportProductsToPDF(ctx, productId, &req)
 	default:
 		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
 		return
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+)
+
+// BulkUpdateProducts godoc
+// @Summary Update products in bulk
+// @Description Set the category, supplier or status of the products, or adjust their price by an amount or a percentage. The products are given by their references or by the filters of the product search. With preview the products are only counted and listed. Each product is updated on its own unless atomic is set, then a failure leaves every product as it was and is answered with 409.
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.BulkUpdateProductsRequest true "Target and change"
+// @Success 200 {object} v1.BulkProductsData
+// @Failure 400 {object} v1.Response "The target is missing, ambiguous or over the limit of products"
+// @Failure 409 {object} v1.Response{data=v1.BulkProductsData} "The atomic update failed on a product and was rolled back"
+// @Router /products/bulk/update [post]
+func (h *ProductHandler) BulkUpdateProducts(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+
+	var req v1.BulkUpdateProductsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil || !bindBulkFilter(&req.BulkProductTarget) {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.productService.BulkUpdateProducts(ctx, userId, &req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	handleBulkProducts(ctx, data)
+}
+
+// BulkDeleteProducts godoc
+// @Summary Delete products in bulk
+// @Description Move the products to the trash, they are given by their references or by the filters of the product search. With preview the products are only counted and listed. A product with variants left fails. Each product is deleted on its own unless atomic is set, then a failure leaves every product as it was and is answered with 409.
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.BulkDeleteProductsRequest true "Target"
+// @Success 200 {object} v1.BulkProductsData
+// @Failure 400 {object} v1.Response "The target is missing, ambiguous or over the limit of products"
+// @Failure 409 {object} v1.Response{data=v1.BulkProductsData} "The atomic delete failed on a product and was rolled back"
+// @Router /products/bulk/delete [post]
+func (h *ProductHandler) BulkDeleteProducts(ctx *gin.Context) {
+	var req v1.BulkDeleteProductsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil || !bindBulkFilter(&req.BulkProductTarget) {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.productService.BulkDeleteProducts(ctx, &req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	handleBulkProducts(ctx, data)
+}
+
+// bindBulkFilter validates the search filter of the target, its page is not required as every match is taken
+func bindBulkFilter(target *v1.BulkProductTarget) bool {
+	if target.Filter == nil {
+		return true
+	}
+	target.Filter.Page = v1.Page{Page: 1, Size: 100}
+	target.Filter.Sorting.SetDefault()
+	return binding.Validator.ValidateStruct(target.Filter) == nil
+}
+
+func handleBulkProducts(ctx *gin.Context, data *v1.BulkProductsData) {
+	if data.RolledBack {
+		v1.HandleError(ctx, http.StatusConflict, v1.ErrBulkRolledBack, data)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+)
+
+// SetBundleComponents godoc
+// @Summary Set the components of a bundle
+// @Description Make the product a bundle of other products with their quantity per bundle, the components replace those it had. Without components it is an ordinary product again. A bundle has no stock of its own, its quantity is how many bundles the stock of its components can make. Bundles are not nested and can't have variants.
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Param request body v1.SetBundleComponentsRequest true "Components"
+// @Success 200 {object} v1.GetProductDetailData
+// @Failure 400 {object} v1.Response "The product has stock of its own, or a component is not found or is a bundle"
+// @Router /products/{id}/components [put]
+func (h *ProductHandler) SetBundleComponents(ctx *gin.Context) {
+	var req v1.SetBundleComponentsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	product, err := h.productService.SetBundleComponents(ctx, ctx.Param("id"), &req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, product)
+}
+
+// IssueBundle godoc
+// @Summary Issue bundles
+// @Description Take the stock of the components of the bundles in one transaction, from the lots of each component that have not expired first. Nothing is issued when a component is short.
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Param request body v1.IssueBundleRequest true "Quantity of bundles to issue"
+// @Success 200 {object} v1.IssueBundleData
+// @Failure 400 {object} v1.Response "The product is not a bundle or a component is short"
+// @Router /products/{id}/issue [post]
+func (h *ProductHandler) IssueBundle(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+
+	var req v1.IssueBundleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.productService.IssueBundle(ctx, userId, ctx.Param("id"), &req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, data)
+}
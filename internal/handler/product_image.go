@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+)
+
+// GetProductImages godoc
+// @Summary Get the images of a product
+// @Description List the images of a product in their order with the URLs of their thumbnails, the first one is the primary image
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Success 200 {array} v1.ProductImageData
+// @Router /products/{id}/images [get]
+func (h *ProductHandler) GetProductImages(ctx *gin.Context) {
+	images, err := h.productService.GetProductImages(ctx, ctx.Param("id"))
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, images)
+}
+
+// UploadProductImage godoc
+// @Summary Upload an image of a product
+// @Description Add a JPEG, PNG, GIF or WebP image after the other images of the product, its thumbnails are generated on upload
+// @Tags Product Modules
+// @Accept multipart/form-data
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Param file formData file true "Image file"
+// @Success 200 {object} v1.ProductImageData
+// @Failure 400 {object} v1.Response "The file is not an accepted image or is too large"
+// @Router /products/{id}/images [post]
+func (h *ProductHandler) UploadProductImage(ctx *gin.Context) {
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	image, err := h.productService.UploadProductImage(ctx, ctx.Param("id"), file)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, image)
+}
+
+// ReorderProductImages godoc
+// @Summary Reorder the images of a product
+// @Description Give the images of the product the order of the list, it must contain all of them. The first one becomes the primary image.
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Param request body v1.ReorderProductImagesRequest true "Image IDs in their new order"
+// @Success 200 {array} v1.ProductImageData
+// @Router /products/{id}/images/order [put]
+func (h *ProductHandler) ReorderProductImages(ctx *gin.Context) {
+	var req v1.ReorderProductImagesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	images, err := h.productService.ReorderProductImages(ctx, ctx.Param("id"), &req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, images)
+}
+
+// DeleteProductImage godoc
+// @Summary Delete an image of a product
+// @Description Delete the image and its thumbnails, the images after it move up
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Param imageId path string true "Image ID"
+// @Success 200 {object} v1.Response
+// @Router /products/{id}/images/{imageId} [delete]
+func (h *ProductHandler) DeleteProductImage(ctx *gin.Context) {
+	if err := h.productService.DeleteProductImage(ctx, ctx.Param("id"), ctx.Param("imageId")); err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, nil)
+}
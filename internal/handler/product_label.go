@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+)
+
+// GetProductBarcode godoc
+// @Summary Get the barcode of a product
+// @Description Render the product as a Code128, EAN-13 or QR code, in PNG or SVG. EAN-13 encodes the GTIN of the product, the others its reference.
+// @Tags Product Modules
+// @Accept json
+// @Produce png
+// @Produce image/svg+xml
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Param format query string true "Barcode format" Enums(code128, ean13, qr)
+// @Param output query string false "Image type" Enums(png, svg) default(png)
+// @Param width query int false "Width in pixels (32-2000)" example(300)
+// @Param height query int false "Height in pixels (32-2000)" example(100)
+// @Success 200 {file} file "Barcode image"
+// @Failure 400 {object} v1.Response "The product can't be encoded in the format, e.g. it has no GTIN for EAN-13, or the size is too small for it"
+// @Router /products/{id}/barcode [get]
+func (h *ProductHandler) GetProductBarcode(ctx *gin.Context) {
+	var req v1.GetProductBarcodeRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	image, contentType, err := h.productService.GetProductBarcode(ctx, ctx.Param("id"), &req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	ctx.Data(http.StatusOK, contentType, image)
+}
+
+// ExportProductLabels godoc
+// @Summary Print product labels
+// @Description Print a label with the name, price and barcode of each product of a search page on an Avery sheet (PDF). Products that can't be encoded in the barcode format, e.g. without a GTIN for EAN-13, are skipped.
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param layout query string true "Avery sheet" Enums(L7160, L7163, L7651, 5160)
+// @Param barcode query string true "Barcode format" Enums(code128, ean13, qr)
+// @Param start_position query int false "First free label of the first sheet, from 1" example(4)
+// @Param page query int true "Page number (must be >= 1)" default(1) example(1)
+// @Param size query int true "Items per page (between 10-100)" default(20) example(10)
+// @Param reference query string false "Filter by Product Reference" example("PROD-202401-001")
+// @Param gtin query string false "Filter by GTIN, however it is written" example("4006381333931")
+// @Param product_name query string false "Filter by Product Name (3-100 chars)" example("Crunchy Munch")
+// @Param category_id query string false "Filter by Category ID" example(1)
+// @Param include_subcategories query bool false "Include the products of the subcategories of category_id"
+// @Param supplier_id query string false "Filter by Supplier ID" example(1)
+// @Param status query string false "Filter by Product Status" Enums(Draft, Available, Out of Stock, On Order, Discontinued, Archived)
+// @Param attribute query []string false "Filter by attribute value as name:value, repeat for several attributes" collectionFormat(multi)
+// @Param sort_by query string false "Sort by field" Enums(price, name, added_date) default(added_date) example("price")
+// @Param sort_order query string false "Sort order" Enums(asc, desc) example("desc")
+// @Success 200 {object} v1.ExportProductLabelsData "File generated successfully"
+// @Router /products/labels [get]
+func (h *ProductHandler) ExportProductLabels(ctx *gin.Context) {
+	var req v1.ExportProductLabelsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	// Set default value for sorting
+	req.Sorting.SetDefault()
+
+	data, err := h.productService.ExportProductLabelsToPDF(ctx, &req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, data)
+}
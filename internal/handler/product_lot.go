@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+)
+
+// GetProductLots godoc
+// @Summary Get the lots of a product
+// @Description List the lots of a product with stock left, the first to expire first
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Param include_empty query bool false "List the lots used up too"
+// @Success 200 {array} v1.ProductLotData
+// @Router /products/{id}/lots [get]
+func (h *ProductHandler) GetProductLots(ctx *gin.Context) {
+	var req v1.GetProductLotsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	lots, err := h.productService.GetProductLots(ctx, ctx.Param("id"), &req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, lots)
+}
+
+// ReceiveProductLot godoc
+// @Summary Receive a lot of a product
+// @Description Add a lot with its expiry date to the stock of the product, the lot number must be new for the product
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Param request body v1.ReceiveProductLotRequest true "Lot"
+// @Success 200 {object} v1.ProductLotData
+// @Failure 400 {object} v1.Response "The lot was already received or has expired"
+// @Router /products/{id}/lots [post]
+func (h *ProductHandler) ReceiveProductLot(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+
+	var req v1.ReceiveProductLotRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	lot, err := h.productService.ReceiveProductLot(ctx, userId, ctx.Param("id"), &req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, lot)
+}
+
+// ConsumeProductLots godoc
+// @Summary Issue stock of a product first-expired-first-out
+// @Description Take the quantity from the lots that have not expired, the first to expire first, then from the stock without lot. Expired lots are never issued.
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Param request body v1.ConsumeProductLotsRequest true "Quantity to issue"
+// @Success 200 {object} v1.ConsumeProductLotsData
+// @Failure 400 {object} v1.Response "There is not enough stock left outside the expired lots"
+// @Router /products/{id}/lots/consume [post]
+func (h *ProductHandler) ConsumeProductLots(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+
+	var req v1.ConsumeProductLotsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.productService.ConsumeProductLots(ctx, userId, ctx.Param("id"), &req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, data)
+}
+
+// GetExpiringLots godoc
+// @Summary Get the expiring stock
+// @Description List the lots with stock left whose expiry date is in the window, soonest first. The window is from today to 30 days later by default.
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param page query int true "Page number (must be >= 1)" default(1) example(1)
+// @Param size query int true "Items per page (between 10-100)" default(20) example(10)
+// @Param from query string false "First expiry date of the window" example("2024-04-01")
+// @Param to query string false "Last expiry date of the window" example("2024-04-30")
+// @Param stock_location query string false "Filter by the stock location of the lots" example("Ha Noi")
+// @Success 200 {object} v1.GetExpiringLotsResponse
+// @Router /products/lots/expiring [get]
+func (h *ProductHandler) GetExpiringLots(ctx *gin.Context) {
+	var req v1.GetExpiringLotsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	lots, err := h.productService.GetExpiringLots(ctx, &req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, lots)
+}
+
+// ExportExpiringLots godoc
+// @Summary Export the expiring stock
+// @Description Exports the lots with stock left whose expiry date is in the window in the specified format (currently supports PDF). The window is from today to 30 days later by default.
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param format path string true "Export format (e.g., 'pdf')"
+// @Param from query string false "First expiry date of the window" example("2024-04-01")
+// @Param to query string false "Last expiry date of the window" example("2024-04-30")
+// @Param stock_location query string false "Filter by the stock location of the lots" example("Ha Noi")
+// @Success 200 {object} v1.ExportExpiringLotsData "File generated successfully"
+// @Router /products/lots/expiring/export/{format} [get]
+func (h *ProductHandler) ExportExpiringLots(ctx *gin.Context) {
+	var req v1.ExportExpiringLotsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	var (
+		data *v1.ExportExpiringLotsData
+		err  error
+	)
+
+	switch ctx.Param("format") {
+	case "pdf":
+		data, err = h.productService.ExportExpiringLotsToPDF(ctx, &req)
+	default:
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, data)
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+)
+
+// GetProductPrices godoc
+// @Summary Get the price history of a product
+// @Description Retrieve the price ranges of a product, scheduled prices first
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Param page query int true "Page number (must be >= 1)" default(1) example(1)
+// @Param size query int true "Items per page (between 10-100)" default(20) example(10)
+// @Success 200 {object} v1.GetProductPricesResponse
+// @Router /products/{id}/prices [get]
+func (h *ProductHandler) GetProductPrices(ctx *gin.Context) {
+	var req v1.GetProductPricesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	prices, err := h.productService.GetProductPrices(ctx, ctx.Param("id"), &req)
+	if err != nil {
+		handleProductPriceError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, prices)
+}
+
+// ScheduleProductPrice godoc
+// @Summary Schedule a price change
+// @Description Make a price effective from the given time until the next scheduled price. The time must not be in the past.
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Param request body v1.ScheduleProductPriceRequest true "Price change"
+// @Success 200 {object} v1.GetProductPriceData
+// @Router /products/{id}/prices [post]
+func (h *ProductHandler) ScheduleProductPrice(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+
+	var req v1.ScheduleProductPriceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	price, err := h.productService.ScheduleProductPrice(ctx, userId, ctx.Param("id"), &req)
+	if err != nil {
+		handleProductPriceError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, price)
+}
+
+// CancelProductPrice godoc
+// @Summary Cancel a scheduled price
+// @Description Remove a price that is not in effect yet, the previous price stays in effect instead
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Param priceId path int true "Price ID"
+// @Success 200 {object} v1.Response
+// @Router /products/{id}/prices/{priceId} [delete]
+func (h *ProductHandler) CancelProductPrice(ctx *gin.Context) {
+	priceId, err := strconv.ParseUint(ctx.Param("priceId"), 10, 64)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	if err := h.productService.CancelProductPrice(ctx, ctx.Param("id"), priceId); err != nil {
+		handleProductPriceError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, nil)
+}
+
+func handleProductPriceError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, v1.ErrNotFound):
+		v1.HandleError(ctx, http.StatusNotFound, v1.ErrNotFound, nil)
+	case errors.Is(err, v1.ErrBadRequest):
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+	case errors.Is(err, v1.ErrPriceAlreadyEffective):
+		v1.HandleError(ctx, http.StatusConflict, v1.ErrPriceAlreadyEffective, nil)
+	default:
+		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+	}
+}
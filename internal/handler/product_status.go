@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+)
+
+// TransitionProduct godoc
+// @Summary Change the status of a product
+// @Description Move the product to another status of its lifecycle. Draft products become Available, On Order or Out of Stock, or are Archived. Available, On Order and Out of Stock move between each other or to Discontinued. Discontinued products are Archived or Available again, and Archived ones go back to Discontinued.
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Param request body v1.TransitionProductRequest true "New status and reason of the change"
+// @Param If-Match header string false "ETag of the product as last read, the change is refused if it changed since"
+// @Success 200 {object} v1.PatchProductData "Fields changed and new version"
+// @Header 200 {string} ETag "Version of the product"
+// @Failure 409 {object} v1.Response "The status can't move to the requested one"
+// @Failure 412 {object} v1.Response "The product has been modified since it was read"
+// @Router /products/{id}/transition [post]
+func (h *ProductHandler) TransitionProduct(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+
+	req := new(v1.TransitionProductRequest)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.productService.TransitionProduct(ctx, userId, ctx.Param("id"), req, v1.ParseIfMatch(ctx.GetHeader("If-Match")))
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", v1.ETag(data.Version))
+	v1.HandleSuccess(ctx, data)
+}
+
+// GetProductStatusHistory godoc
+// @Summary Get the status history of a product
+// @Description Retrieve the changes of status of a product with who made them and why, latest first
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Param page query int true "Page number (must be >= 1)" default(1) example(1)
+// @Param size query int true "Items per page (between 10-100)" default(20) example(10)
+// @Success 200 {object} v1.GetProductStatusHistoryResponse
+// @Router /products/{id}/status-history [get]
+func (h *ProductHandler) GetProductStatusHistory(ctx *gin.Context) {
+	var req v1.GetProductStatusHistoryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	history, err := h.productService.GetProductStatusHistory(ctx, ctx.Param("id"), &req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, history)
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+)
+
+// CreateVariant godoc
+// @Summary Add a variant to a product
+// @Description Create a variant of the product with its own reference, price, quantity and options, it gets the category, supplier and attribute values of the product
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Param request body v1.CreateVariantRequest true "Variant creation request"
+// @Success 200 {object} v1.GetProductDetailData
+// @Header 200 {string} ETag "Version of the variant"
+// @Failure 400 {object} v1.Response "The product is a variant or another variant has the same options"
+// @Failure 409 {object} v1.Response "The GTIN belongs to another product"
+// @Router /products/{id}/variants [post]
+func (h *ProductHandler) CreateVariant(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+
+	req := new(v1.CreateVariantRequest)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	variant, err := h.productService.CreateVariant(ctx, userId, ctx.Param("id"), req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", v1.ETag(variant.Version))
+	v1.HandleSuccess(ctx, variant)
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+)
+
+// GetProductVersions godoc
+// @Summary Get the versions of a product
+// @Description Retrieve the change history of a product, latest version first
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Param page query int true "Page number (must be >= 1)" default(1) example(1)
+// @Param size query int true "Items per page (between 10-100)" default(20) example(10)
+// @Success 200 {object} v1.GetProductVersionsResponse
+// @Router /products/{id}/versions [get]
+func (h *ProductHandler) GetProductVersions(ctx *gin.Context) {
+	var req v1.GetProductVersionsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	versions, err := h.productService.GetProductVersions(ctx, ctx.Param("id"), &req)
+	if err != nil {
+		handleProductVersionError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, versions)
+}
+
+// GetProductVersion godoc
+// @Summary Get a product as of a version
+// @Description Retrieve the snapshot of a product at the given version number
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Param version path int true "Version number"
+// @Success 200 {object} v1.GetProductVersionData
+// @Router /products/{id}/versions/{version} [get]
+func (h *ProductHandler) GetProductVersion(ctx *gin.Context) {
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil || version < 1 {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	productVersion, err := h.productService.GetProductVersion(ctx, ctx.Param("id"), version)
+	if err != nil {
+		handleProductVersionError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, productVersion)
+}
+
+// GetProductAsOf godoc
+// @Summary Get a product as of a timestamp
+// @Description Retrieve the version of a product that was in effect at the given time
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Param timestamp query string true "Point in time (RFC 3339)" example("2024-01-28T10:00:00Z")
+// @Success 200 {object} v1.GetProductVersionData
+// @Router /products/{id}/as-of [get]
+func (h *ProductHandler) GetProductAsOf(ctx *gin.Context) {
+	var req v1.GetProductAsOfRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	productVersion, err := h.productService.GetProductAsOf(ctx, ctx.Param("id"), &req)
+	if err != nil {
+		handleProductVersionError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, productVersion)
+}
+
+// DiffProductVersions godoc
+// @Summary Compare two versions of a product
+// @Description Retrieve the fields that differ between two versions of a product
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Param from query int true "Base version" example(1)
+// @Param to query int true "Compared version" example(3)
+// @Success 200 {object} v1.DiffProductVersionsData
+// @Router /products/{id}/diff [get]
+func (h *ProductHandler) DiffProductVersions(ctx *gin.Context) {
+	var req v1.DiffProductVersionsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	diff, err := h.productService.DiffProductVersions(ctx, ctx.Param("id"), &req)
+	if err != nil {
+		handleProductVersionError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, diff)
+}
+
+// RestoreProductVersion godoc
+// @Summary Restore a version of a product
+// @Description Apply the name, category, supplier, price, stock location, added date and GTIN of a version as a new update. Quantity and status are kept.
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Param version path int true "Version number to restore"
+// @Success 200 {object} v1.GetProductVersionData "The new version"
+// @Failure 409 {object} v1.Response "The GTIN of the version belongs to another product"
+// @Router /products/{id}/versions/{version}/restore [post]
+func (h *ProductHandler) RestoreProductVersion(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil || version < 1 {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	productVersion, err := h.productService.RestoreProductVersion(ctx, userId, ctx.Param("id"), version)
+	if err != nil {
+		handleProductVersionError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, productVersion)
+}
+
+func handleProductVersionError(ctx *gin.Context, err error) {
+	if errors.Is(err, v1.ErrNotFound) {
+		v1.HandleError(ctx, http.StatusNotFound, v1.ErrNotFound, nil)
+		return
+	}
+	if errors.Is(err, v1.ErrBadRequest) {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	// The GTIN of the version was given to another product since
+	if errors.Is(err, v1.ErrGTINInUse) {
+		v1.HandleError(ctx, http.StatusConflict, v1.ErrGTINInUse, nil)
+		return
+	}
+	v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+}
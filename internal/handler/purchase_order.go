@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/service"
+)
+
+type PurchaseOrderHandler struct {
+	*Handler
+	purchaseOrderService service.PurchaseOrderService
+}
+
+func NewPurchaseOrderHandler(
+	handler *Handler,
+	purchaseOrderService service.PurchaseOrderService,
+) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{
+		Handler:              handler,
+		purchaseOrderService: purchaseOrderService,
+	}
+}
+
+// GetPurchaseOrders godoc
+// @Summary Get list of purchase orders
+// @Description Retrieve a paginated list of purchase orders, latest first
+// @Tags Purchase Order Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param supplier_id query string false "Filter by supplier ID"
+// @Param status query string false "Filter by status" Enums(draft, sent, partially_received, received, cancelled)
+// @Param page query int true "Page number (must be >= 1)" default(1) example(1)
+// @Param size query int true "Items per page (between 10-100)" default(20) example(10)
+// @Success 200 {object} v1.SearchPurchaseOrderResponse
+// @Router /purchase-orders [get]
+func (h *PurchaseOrderHandler) GetPurchaseOrders(ctx *gin.Context) {
+	var req v1.SearchPurchaseOrderRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	orders, err := h.purchaseOrderService.GetPurchaseOrders(ctx, &req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, orders)
+}
+
+// GetPurchaseOrderDetail godoc
+// @Summary Get purchase order details
+// @Description Retrieve a purchase order with its lines
+// @Tags Purchase Order Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Purchase order ID"
+// @Success 200 {object} v1.GetPurchaseOrderDetailResponse
+// @Header 200 {string} ETag "Version of the purchase order"
+// @Router /purchase-orders/{id} [get]
+func (h *PurchaseOrderHandler) GetPurchaseOrderDetail(ctx *gin.Context) {
+	order, err := h.purchaseOrderService.GetPurchaseOrder(ctx, ctx.Param("id"))
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", v1.ETag(order.Version))
+	v1.HandleSuccess(ctx, order)
+}
+
+// CreatePurchaseOrder godoc
+// @Summary Create a purchase order
+// @Description Create a draft purchase order to an active supplier
+// @Tags Purchase Order Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.CreatePurchaseOrderRequest true "Purchase order creation request"
+// @Success 200 {object} v1.GetPurchaseOrderDetailData
+// @Header 200 {string} ETag "Version of the purchase order"
+// @Router /purchase-orders [post]
+func (h *PurchaseOrderHandler) CreatePurchaseOrder(ctx *gin.Context) {
+	req := new(v1.CreatePurchaseOrderRequest)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	order, err := h.purchaseOrderService.CreatePurchaseOrder(ctx, GetUserIdFromCtx(ctx), req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", v1.ETag(order.Version))
+	v1.HandleSuccess(ctx, order)
+}
+
+// UpdatePurchaseOrder godoc
+// @Summary Update a draft purchase order
+// @Description Replace the note and the lines of a purchase order that was not sent yet
+// @Tags Purchase Order Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Purchase order ID"
+// @Param request body v1.UpdatePurchaseOrderRequest true "Purchase order update request"
+// @Param If-Match header string false "ETag of the purchase order as last read, the update is refused if it changed since"
+// @Success 200 {object} v1.GetPurchaseOrderDetailData
+// @Header 200 {string} ETag "Version of the purchase order"
+// @Failure 409 {object} v1.Response "The purchase order is no longer a draft"
+// @Failure 412 {object} v1.Response "The purchase order has been modified since it was read"
+// @Router /purchase-orders/{id} [put]
+func (h *PurchaseOrderHandler) UpdatePurchaseOrder(ctx *gin.Context) {
+	req := new(v1.UpdatePurchaseOrderRequest)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	order, err := h.purchaseOrderService.UpdatePurchaseOrder(ctx, ctx.Param("id"), req, v1.ParseIfMatch(ctx.GetHeader("If-Match")))
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", v1.ETag(order.Version))
+	v1.HandleSuccess(ctx, order)
+}
+
+// SendPurchaseOrder godoc
+// @Summary Send a purchase order
+// @Description Mark a draft purchase order as sent to the supplier, the delivery is expected after the lead time of the supplier
+// @Tags Purchase Order Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Purchase order ID"
+// @Param If-Match header string false "ETag of the purchase order as last read, the order is not sent if it changed since"
+// @Success 200 {object} v1.GetPurchaseOrderDetailData
+// @Header 200 {string} ETag "Version of the purchase order"
+// @Failure 409 {object} v1.Response "The purchase order is not a draft"
+// @Failure 412 {object} v1.Response "The purchase order has been modified since it was read"
+// @Router /purchase-orders/{id}/send [post]
+func (h *PurchaseOrderHandler) SendPurchaseOrder(ctx *gin.Context) {
+	order, err := h.purchaseOrderService.SendPurchaseOrder(ctx, ctx.Param("id"), v1.ParseIfMatch(ctx.GetHeader("If-Match")))
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", v1.ETag(order.Version))
+	v1.HandleSuccess(ctx, order)
+}
+
+// CancelPurchaseOrder godoc
+// @Summary Cancel a purchase order
+// @Description Cancel a purchase order that is not fully received, the stock already received is kept
+// @Tags Purchase Order Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Purchase order ID"
+// @Param If-Match header string false "ETag of the purchase order as last read, the order is not cancelled if it changed since"
+// @Success 200 {object} v1.GetPurchaseOrderDetailData
+// @Header 200 {string} ETag "Version of the purchase order"
+// @Failure 409 {object} v1.Response "The purchase order is already received or cancelled"
+// @Failure 412 {object} v1.Response "The purchase order has been modified since it was read"
+// @Router /purchase-orders/{id}/cancel [post]
+func (h *PurchaseOrderHandler) CancelPurchaseOrder(ctx *gin.Context) {
+	order, err := h.purchaseOrderService.CancelPurchaseOrder(ctx, ctx.Param("id"), v1.ParseIfMatch(ctx.GetHeader("If-Match")))
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", v1.ETag(order.Version))
+	v1.HandleSuccess(ctx, order)
+}
+
+// ReceivePurchaseOrder godoc
+// @Summary Receive a purchase order
+// @Description Add the received quantities to the stock of the products in one transaction and set them Available. The order is received once every line is, partially received before.
+// @Tags Purchase Order Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Purchase order ID"
+// @Param request body v1.ReceivePurchaseOrderRequest true "Received quantities, everything still expected when empty"
+// @Success 200 {object} v1.GetPurchaseOrderDetailData
+// @Header 200 {string} ETag "Version of the purchase order"
+// @Failure 409 {object} v1.Response "The purchase order is not sent"
+// @Router /purchase-orders/{id}/receive [post]
+func (h *PurchaseOrderHandler) ReceivePurchaseOrder(ctx *gin.Context) {
+	req := new(v1.ReceivePurchaseOrderRequest)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	order, err := h.purchaseOrderService.ReceivePurchaseOrder(ctx, GetUserIdFromCtx(ctx), ctx.Param("id"), req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", v1.ETag(order.Version))
+	v1.HandleSuccess(ctx, order)
+}
+
+// ExportPurchaseOrder godoc
+// @Summary Export a purchase order
+// @Description Exports the purchase order with its lines in the specified format (currently supports PDF).
+// @Tags Purchase Order Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param format path string true "Export format (e.g., 'pdf')"
+// @Param id path string true "Purchase order ID"
+// @Success 200 {object} v1.ExportPurchaseOrderData "File generated successfully"
+// @Router /purchase-orders/{id}/export/{format} [get]
+func (h *PurchaseOrderHandler) ExportPurchaseOrder(ctx *gin.Context) {
+	var (
+		data *v1.ExportPurchaseOrderData
+		err  error
+	)
+
+	switch ctx.Param("format") {
+	case "pdf":
+		data, err = h.purchaseOrderService.ExportPurchaseOrderToPDF(ctx, ctx.Param("id"))
+	default:
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, data)
+}
@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/service"
+)
+
+type StocktakeHandler struct {
+	*Handler
+	stocktakeService service.StocktakeService
+}
+
+func NewStocktakeHandler(
+	handler *Handler,
+	stocktakeService service.StocktakeService,
+) *StocktakeHandler {
+	return &StocktakeHandler{
+		Handler:          handler,
+		stocktakeService: stocktakeService,
+	}
+}
+
+// GetStocktakes godoc
+// @Summary Get list of stocktakes
+// @Description Retrieve a paginated list of stocktakes without their lines, latest first
+// @Tags Stocktake Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param status query string false "Filter by status" Enums(open, approved, cancelled)
+// @Param stock_location query string false "Filter by stock location" example("Ha Noi")
+// @Param page query int true "Page number (must be >= 1)" default(1) example(1)
+// @Param size query int true "Items per page (between 10-100)" default(20) example(10)
+// @Success 200 {object} v1.SearchStocktakeResponse
+// @Router /stocktakes [get]
+func (h *StocktakeHandler) GetStocktakes(ctx *gin.Context) {
+	var req v1.SearchStocktakeRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	stocktakes, err := h.stocktakeService.GetStocktakes(ctx, &req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, stocktakes)
+}
+
+// GetStocktakeDetail godoc
+// @Summary Get stocktake details
+// @Description Retrieve a stocktake with its lines. While it is open the system quantity is the stock of the product now.
+// @Tags Stocktake Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Stocktake ID"
+// @Success 200 {object} v1.GetStocktakeDetailResponse
+// @Header 200 {string} ETag "Version of the stocktake"
+// @Router /stocktakes/{id} [get]
+func (h *StocktakeHandler) GetStocktakeDetail(ctx *gin.Context) {
+	stocktake, err := h.stocktakeService.GetStocktake(ctx, ctx.Param("id"))
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", v1.ETag(stocktake.Version))
+	v1.HandleSuccess(ctx, stocktake)
+}
+
+// CreateStocktake godoc
+// @Summary Open a stocktake
+// @Description Open a stocktake of the live products of a stock location, of a category, or of both. Archived products and bundles are not counted.
+// @Tags Stocktake Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.CreateStocktakeRequest true "Stocktake creation request"
+// @Success 200 {object} v1.GetStocktakeDetailData
+// @Header 200 {string} ETag "Version of the stocktake"
+// @Router /stocktakes [post]
+func (h *StocktakeHandler) CreateStocktake(ctx *gin.Context) {
+	req := new(v1.CreateStocktakeRequest)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	stocktake, err := h.stocktakeService.CreateStocktake(ctx, GetUserIdFromCtx(ctx), req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", v1.ETag(stocktake.Version))
+	v1.HandleSuccess(ctx, stocktake)
+}
+
+// SubmitStocktakeCounts godoc
+// @Summary Submit counted quantities
+// @Description Save what a device counted. A new count of a product replaces the previous one of the same device, the counts of the devices are added up.
+// @Tags Stocktake Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Stocktake ID"
+// @Param request body v1.SubmitStocktakeCountsRequest true "Counted quantities"
+// @Success 200 {object} v1.GetStocktakeDetailData
+// @Header 200 {string} ETag "Version of the stocktake"
+// @Failure 409 {object} v1.Response "The stocktake is no longer open"
+// @Router /stocktakes/{id}/counts [post]
+func (h *StocktakeHandler) SubmitStocktakeCounts(ctx *gin.Context) {
+	req := new(v1.SubmitStocktakeCountsRequest)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	stocktake, err := h.stocktakeService.SubmitStocktakeCounts(ctx, GetUserIdFromCtx(ctx), ctx.Param("id"), req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", v1.ETag(stocktake.Version))
+	v1.HandleSuccess(ctx, stocktake)
+}
+
+// GetStocktakeVariance godoc
+// @Summary Get the variance report of a stocktake
+// @Description List the counted products whose quantity differs from the system quantity with their value, the totals and the products not counted
+// @Tags Stocktake Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Stocktake ID"
+// @Success 200 {object} v1.GetStocktakeVarianceData
+// @Router /stocktakes/{id}/variance [get]
+func (h *StocktakeHandler) GetStocktakeVariance(ctx *gin.Context) {
+	variance, err := h.stocktakeService.GetStocktakeVariance(ctx, ctx.Param("id"))
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, variance)
+}
+
+// ApproveStocktake godoc
+// @Summary Approve a stocktake
+// @Description Set the stock of the counted products to their counted quantity in one transaction, each change is recorded as a stock movement. Only a supervisor can approve.
+// @Tags Stocktake Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Stocktake ID"
+// @Param request body v1.ApproveStocktakeRequest true "Stocktake approval request"
+// @Param If-Match header string false "ETag of the stocktake as last read, it is not approved if it changed since"
+// @Success 200 {object} v1.GetStocktakeDetailData
+// @Header 200 {string} ETag "Version of the stocktake"
+// @Failure 403 {object} v1.Response "The user is not a supervisor"
+// @Failure 409 {object} v1.Response "The stocktake is no longer open"
+// @Failure 412 {object} v1.Response "The stocktake has been modified since it was read"
+// @Router /stocktakes/{id}/approve [post]
+func (h *StocktakeHandler) ApproveStocktake(ctx *gin.Context) {
+	req := new(v1.ApproveStocktakeRequest)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	stocktake, err := h.stocktakeService.ApproveStocktake(ctx, GetUserIdFromCtx(ctx), ctx.Param("id"), req, v1.ParseIfMatch(ctx.GetHeader("If-Match")))
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", v1.ETag(stocktake.Version))
+	v1.HandleSuccess(ctx, stocktake)
+}
+
+// CancelStocktake godoc
+// @Summary Cancel a stocktake
+// @Description Cancel an open stocktake, the stock is left as is
+// @Tags Stocktake Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Stocktake ID"
+// @Param If-Match header string false "ETag of the stocktake as last read, it is not cancelled if it changed since"
+// @Success 200 {object} v1.GetStocktakeDetailData
+// @Header 200 {string} ETag "Version of the stocktake"
+// @Failure 409 {object} v1.Response "The stocktake is no longer open"
+// @Failure 412 {object} v1.Response "The stocktake has been modified since it was read"
+// @Router /stocktakes/{id}/cancel [post]
+func (h *StocktakeHandler) CancelStocktake(ctx *gin.Context) {
+	stocktake, err := h.stocktakeService.CancelStocktake(ctx, ctx.Param("id"), v1.ParseIfMatch(ctx.GetHeader("If-Match")))
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", v1.ETag(stocktake.Version))
+	v1.HandleSuccess(ctx, stocktake)
+}
+
+// ExportStocktakeSheet godoc
+// @Summary Export the count sheet of a stocktake
+// @Description Exports the products to count with a blank counted column in the specified format (currently supports PDF). The system quantities are left out.
+// @Tags Stocktake Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param format path string true "Export format (e.g., 'pdf')"
+// @Param id path string true "Stocktake ID"
+// @Success 200 {object} v1.ExportStocktakeData "File generated successfully"
+// @Router /stocktakes/{id}/sheet/export/{format} [get]
+func (h *StocktakeHandler) ExportStocktakeSheet(ctx *gin.Context) {
+	var (
+		data *v1.ExportStocktakeData
+		err  error
+	)
+
+	switch ctx.Param("format") {
+	case "pdf":
+		data, err = h.stocktakeService.ExportStocktakeSheetToPDF(ctx, ctx.Param("id"))
+	default:
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, data)
+}
+
+// ExportStocktakeVariance godoc
+// @Summary Export the variance report of a stocktake
+// @Description Exports the variance report in the specified format (currently supports PDF).
+// @Tags Stocktake Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param format path string true "Export format (e.g., 'pdf')"
+// @Param id path string true "Stocktake ID"
+// @Success 200 {object} v1.ExportStocktakeData "File generated successfully"
+// @Router /stocktakes/{id}/variance/export/{format} [get]
+func (h *StocktakeHandler) ExportStocktakeVariance(ctx *gin.Context) {
+	var (
+		data *v1.ExportStocktakeData
+		err  error
+	)
+
+	switch ctx.Param("format") {
+	case "pdf":
+		data, err = h.stocktakeService.ExportStocktakeVarianceToPDF(ctx, ctx.Param("id"))
+	default:
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, data)
+}
@@ -32,6 +32,7 @@ func NewSupplierHandler(
 // @Produce json
 // @Security Bearer
 // @Param name query string false "Filter by supplier name"
+// @Param status query string false "Filter by supplier status" Enums(active, deactive)
 // @Param sort_by query string false "Sort by field" Enums(name, id) default(name) example("name")
 // @Param sort_order query string false "Sort order" Enums(asc, desc) default(desc) example("asc")
 // @Param page query int true "Page number (must be >= 1)" default(1) example(1)
@@ -60,13 +61,14 @@ func (h *SupplierHandler) GetSuppliers(ctx *gin.Context) {
 // GetSupplierDetail godoc.
 //
 // @Summary Get supplier details
-// @Description Retrieve detailed information about a supplier by its ID.
+// @Description Retrieve detailed information about a supplier by its ID, with the count and stock value of its products.
 // @Tags Supplier Modules
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param id path string true "Supplier ID"
 // @Success 200 {object} v1.GetSupplierDetailResponse "Successful response"
+// @Header 200 {string} ETag "Version of the resource"
 // @Router /supplier/{id} [get]
 func (h *SupplierHandler) GetSupplierDetail(ctx *gin.Context) {
 	supplierID := ctx.Param("id")
@@ -82,6 +84,7 @@ func (h *SupplierHandler) GetSupplierDetail(ctx *gin.Context) {
 		return
 	}
 
+	ctx.Header("ETag", v1.ETag(supplier.Version))
 	v1.HandleSuccess(ctx, supplier)
 }
 
@@ -109,3 +112,82 @@ func (h *SupplierHandler) CreateSupplier(ctx *gin.Context) {
 
 	v1.HandleSuccess(ctx, nil)
 }
+
+// UpdateSupplier godoc
+// @Summary Update a supplier
+// @Description Update a supplier, its contacts and addresses are replaced by the given ones
+// @Tags Supplier Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Supplier ID"
+// @Param request body v1.UpdateSupplierRequest true "Supplier update request"
+// @Param If-Match header string false "ETag of the supplier as last read, the update is refused if it changed since"
+// @Success 200 {object} v1.GetSupplierDetailData
+// @Header 200 {string} ETag "Version of the supplier"
+// @Failure 412 {object} v1.Response "The supplier has been modified since it was read"
+// @Router /supplier/{id} [put]
+func (h *SupplierHandler) UpdateSupplier(ctx *gin.Context) {
+	req := new(v1.UpdateSupplierRequest)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	supplier, err := h.supplierService.UpdateSupplier(ctx, ctx.Param("id"), req, v1.ParseIfMatch(ctx.GetHeader("If-Match")))
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", v1.ETag(supplier.Version))
+	v1.HandleSuccess(ctx, supplier)
+}
+
+// MergeSupplier godoc
+// @Summary Merge a duplicated supplier into another
+// @Description Moves the products of the supplier to the target in one transaction, with the contacts and addresses the target doesn't have, then puts the supplier in the trash
+// @Tags Supplier Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Supplier ID"
+// @Param request body v1.MergeSupplierRequest true "Supplier merge request"
+// @Success 200 {object} v1.MergeSupplierData
+// @Router /supplier/{id}/merge [post]
+func (h *SupplierHandler) MergeSupplier(ctx *gin.Context) {
+	req := new(v1.MergeSupplierRequest)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.supplierService.MergeSupplier(ctx, GetUserIdFromCtx(ctx), ctx.Param("id"), req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, data)
+}
+
+// DeleteSupplier godoc
+// @Summary Delete a supplier
+// @Description Moves the supplier to the trash, it can be restored until it is purged
+// @Tags Supplier Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Supplier ID"
+// @Param If-Match header string false "ETag of the supplier as last read, the delete is refused if it changed since"
+// @Success 200 {object} v1.Response
+// @Failure 412 {object} v1.Response "The supplier has been modified since it was read"
+// @Router /supplier/{id} [delete]
+func (h *SupplierHandler) DeleteSupplier(ctx *gin.Context) {
+	if err := h.supplierService.DeleteSupplier(ctx, ctx.Param("id"), v1.ParseIfMatch(ctx.GetHeader("If-Match"))); err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, nil)
+}
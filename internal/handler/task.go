@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/service"
+	"go.uber.org/zap"
+)
+
+type TaskHandler struct {
+	*Handler
+	taskService service.TaskService
+}
+
+func NewTaskHandler(
+	handler *Handler,
+	taskService service.TaskService,
+) *TaskHandler {
+	return &TaskHandler{
+		Handler:     handler,
+		taskService: taskService,
+	}
+}
+
+// GetTasks godoc
+// @Summary Get list of scheduled tasks
+// @Description Retrieve the registered tasks with their cron expression, timeout and overlap policy.
+// @Tags Admin Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {array} v1.GetTaskData
+// @Failure 403 {object} v1.Response "The user is not an admin"
+// @Router /admin/tasks [get]
+func (h *TaskHandler) GetTasks(ctx *gin.Context) {
+	v1.HandleSuccess(ctx, h.taskService.GetTasks(ctx))
+}
+
+// GetTaskRuns godoc
+// @Summary Get task run history
+// @Description Retrieve a paginated list of task runs, latest first.
+// @Tags Admin Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param task_name query string false "Filter by task name" example("check_user")
+// @Param status query string false "Filter by status" Enums(pending, running, success, failed, timeout, skipped)
+// @Param date_from query string false "Filter by start date (YYYY-MM-DD)" format(date) example("2024-01-28")
+// @Param date_to query string false "Filter by end date (YYYY-MM-DD)" format(date) example("2024-01-28")
+// @Param page query int true "Page number (must be >= 1)" default(1) example(1)
+// @Param size query int true "Items per page (between 10-100)" default(20) example(10)
+// @Success 200 {object} v1.SearchTaskRunResponse
+// @Failure 403 {object} v1.Response "The user is not an admin"
+// @Router /admin/tasks/runs [get]
+func (h *TaskHandler) GetTaskRuns(ctx *gin.Context) {
+	var req v1.SearchTaskRunRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	runs, err := h.taskService.GetTaskRuns(ctx, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, runs)
+}
+
+// GetTaskRun godoc
+// @Summary Get a task run
+// @Description Retrieve a task run by its ID, including its result and error.
+// @Tags Admin Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Task run ID"
+// @Success 200 {object} v1.GetTaskRunResponse
+// @Failure 403 {object} v1.Response "The user is not an admin"
+// @Router /admin/tasks/runs/{id} [get]
+func (h *TaskHandler) GetTaskRun(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	run, err := h.taskService.GetTaskRun(ctx, id)
+	if err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			v1.HandleError(ctx, http.StatusNotFound, v1.ErrNotFound, nil)
+			return
+		}
+		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, run)
+}
+
+// TriggerTask godoc
+// @Summary Trigger a task manually
+// @Description Start a run of the task in background. The run is skipped if another run holds the task lock.
+// @Tags Admin Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param name path string true "Task name" example("check_user")
+// @Param dry_run query bool false "Only report what the task would change"
+// @Success 200 {object} v1.GetTaskRunResponse
+// @Failure 403 {object} v1.Response "The user is not an admin"
+// @Router /admin/tasks/{name}/trigger [post]
+func (h *TaskHandler) TriggerTask(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+
+	var req v1.TriggerTaskRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	run, err := h.taskService.TriggerTask(ctx, userId, ctx.Param("name"), &req)
+	if err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			v1.HandleError(ctx, http.StatusNotFound, v1.ErrNotFound, nil)
+			return
+		}
+		h.logger.WithContext(ctx).Error("taskService.TriggerTask error", zap.Error(err))
+		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, run)
+}
@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+)
+
+// GetDeletedProducts godoc
+// @Summary Get the product trash
+// @Description Retrieve the deleted products that can still be restored, last deleted first
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param page query int true "Page number (must be >= 1)" default(1) example(1)
+// @Param size query int true "Items per page (between 10-100)" default(20) example(10)
+// @Success 200 {object} v1.GetTrashResponse
+// @Router /products/trash [get]
+func (h *ProductHandler) GetDeletedProducts(ctx *gin.Context) {
+	var req v1.GetTrashRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	trash, err := h.productService.GetDeletedProducts(ctx, &req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, trash)
+}
+
+// RestoreProduct godoc
+// @Summary Restore a product from the trash
+// @Description Takes a deleted product out of the trash, its version changes
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Success 200 {object} v1.Response
+// @Router /products/trash/{id}/restore [post]
+func (h *ProductHandler) RestoreProduct(ctx *gin.Context) {
+	if err := h.productService.RestoreProduct(ctx, ctx.Param("id")); err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, nil)
+}
+
+// PurgeProduct godoc
+// @Summary Purge a product from the trash
+// @Description Deletes a product in the trash for good, with its price and version history
+// @Tags Product Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Product Reference ID"
+// @Success 200 {object} v1.Response
+// @Router /products/trash/{id} [delete]
+func (h *ProductHandler) PurgeProduct(ctx *gin.Context) {
+	if err := h.productService.PurgeProduct(ctx, ctx.Param("id")); err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, nil)
+}
+
+// GetDeletedCategories godoc
+// @Summary Get the category trash
+// @Description Retrieve the deleted categories that can still be restored, last deleted first
+// @Tags Categories Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param page query int true "Page number (must be >= 1)" default(1) example(1)
+// @Param size query int true "Items per page (between 10-100)" default(20) example(10)
+// @Success 200 {object} v1.GetTrashResponse
+// @Router /categories/trash [get]
+func (h *CategoryHandler) GetDeletedCategories(ctx *gin.Context) {
+	var req v1.GetTrashRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	trash, err := h.categoryService.GetDeletedCategories(ctx, &req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, trash)
+}
+
+// RestoreCategory godoc
+// @Summary Restore a category from the trash
+// @Description Takes a deleted category out of the trash, its version changes
+// @Tags Categories Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Category ID"
+// @Success 200 {object} v1.Response
+// @Router /categories/trash/{id}/restore [post]
+func (h *CategoryHandler) RestoreCategory(ctx *gin.Context) {
+	if err := h.categoryService.RestoreCategory(ctx, ctx.Param("id")); err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, nil)
+}
+
+// PurgeCategory godoc
+// @Summary Purge a category from the trash
+// @Description Deletes a category in the trash for good with its products in the trash, refused while it has live products or subcategories
+// @Tags Categories Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Category ID"
+// @Success 200 {object} v1.Response
+// @Router /categories/trash/{id} [delete]
+func (h *CategoryHandler) PurgeCategory(ctx *gin.Context) {
+	if err := h.categoryService.PurgeCategory(ctx, ctx.Param("id")); err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, nil)
+}
+
+// GetDeletedSuppliers godoc
+// @Summary Get the supplier trash
+// @Description Retrieve the deleted suppliers that can still be restored, last deleted first
+// @Tags Supplier Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param page query int true "Page number (must be >= 1)" default(1) example(1)
+// @Param size query int true "Items per page (between 10-100)" default(20) example(10)
+// @Success 200 {object} v1.GetTrashResponse
+// @Router /supplier/trash [get]
+func (h *SupplierHandler) GetDeletedSuppliers(ctx *gin.Context) {
+	var req v1.GetTrashRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	trash, err := h.supplierService.GetDeletedSuppliers(ctx, &req)
+	if err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, trash)
+}
+
+// RestoreSupplier godoc
+// @Summary Restore a supplier from the trash
+// @Description Takes a deleted supplier out of the trash, its version changes
+// @Tags Supplier Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Supplier ID"
+// @Success 200 {object} v1.Response
+// @Router /supplier/trash/{id}/restore [post]
+func (h *SupplierHandler) RestoreSupplier(ctx *gin.Context) {
+	if err := h.supplierService.RestoreSupplier(ctx, ctx.Param("id")); err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, nil)
+}
+
+// PurgeSupplier godoc
+// @Summary Purge a supplier from the trash
+// @Description Deletes a supplier in the trash for good, its products are left without supplier
+// @Tags Supplier Modules
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Supplier ID"
+// @Success 200 {object} v1.Response
+// @Router /supplier/trash/{id} [delete]
+func (h *SupplierHandler) PurgeSupplier(ctx *gin.Context) {
+	if err := h.supplierService.PurgeSupplier(ctx, ctx.Param("id")); err != nil {
+		handleCatalogError(ctx, err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, nil)
+}
+
+func handleCatalogError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, v1.ErrBadRequest):
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+	case errors.Is(err, v1.ErrForbidden):
+		v1.HandleError(ctx, http.StatusForbidden, v1.ErrForbidden, nil)
+	case errors.Is(err, v1.ErrNotFound):
+		v1.HandleError(ctx, http.StatusNotFound, v1.ErrNotFound, nil)
+	case errors.Is(err, v1.ErrVersionMismatch):
+		v1.HandleError(ctx, http.StatusPreconditionFailed, v1.ErrVersionMismatch, nil)
+	case errors.Is(err, v1.ErrCategoryHasProducts):
+		v1.HandleError(ctx, http.StatusConflict, v1.ErrCategoryHasProducts, nil)
+	case errors.Is(err, v1.ErrCategoryHasChildren):
+		v1.HandleError(ctx, http.StatusConflict, v1.ErrCategoryHasChildren, nil)
+	case errors.Is(err, v1.ErrPurchaseOrderStatus):
+		v1.HandleError(ctx, http.StatusConflict, v1.ErrPurchaseOrderStatus, nil)
+	case errors.Is(err, v1.ErrProductTransition):
+		v1.HandleError(ctx, http.StatusConflict, v1.ErrProductTransition, nil)
+	case errors.Is(err, v1.ErrProductHasVariants):
+		v1.HandleError(ctx, http.StatusConflict, v1.ErrProductHasVariants, nil)
+	case errors.Is(err, v1.ErrGTINInUse):
+		v1.HandleError(ctx, http.StatusConflict, v1.ErrGTINInUse, nil)
+	case errors.Is(err, v1.ErrProductInBundle):
+		v1.HandleError(ctx, http.StatusConflict, v1.ErrProductInBundle, nil)
+	case errors.Is(err, v1.ErrStocktakeStatus):
+		v1.HandleError(ctx, http.StatusConflict, v1.ErrStocktakeStatus, nil)
+	case errors.Is(err, v1.ErrStatusNeedsTransition):
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrStatusNeedsTransition, nil)
+	default:
+		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+	}
+}
@@ -0,0 +1,89 @@
+package helper
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/ean"
+	"github.com/boombuler/barcode/qr"
+)
+
+// Barcode formats
+const (
+	BarcodeCode128 = "code128"
+	BarcodeEAN13   = "ean13"
+	BarcodeQR      = "qr"
+)
+
+// EncodeBarcode encodes content in format, EAN-13 only takes 12 digits or 13 with a valid check digit
+func EncodeBarcode(format string, content string) (barcode.Barcode, error) {
+	switch format {
+	case BarcodeCode128:
+		return code128.Encode(content)
+	case BarcodeEAN13:
+		if len(content) != 12 && len(content) != 13 {
+			return nil, fmt.Errorf("EAN-13 takes 12 or 13 digits, got %q", content)
+		}
+		return ean.Encode(content)
+	case BarcodeQR:
+		return qr.Encode(content, qr.M, qr.Auto)
+	default:
+		return nil, fmt.Errorf("unknown barcode format %s", format)
+	}
+}
+
+// IsTwoDimensional tells if the barcode is a matrix, like QR codes, rather than bars
+func IsTwoDimensional(code barcode.Barcode) bool {
+	return code.Metadata().Dimensions == 2
+}
+
+// WriteBarcodePNG writes the barcode scaled to width x height pixels, they can't be less than its modules.
+// The image is 8-bit grayscale, gofpdf doesn't embed 16-bit PNG.
+func WriteBarcodePNG(w io.Writer, code barcode.Barcode, width, height int) error {
+	scaled, err := barcode.Scale(code, width, height)
+	if err != nil {
+		return err
+	}
+	gray := image.NewGray(scaled.Bounds())
+	draw.Draw(gray, gray.Bounds(), scaled, scaled.Bounds().Min, draw.Src)
+	return png.Encode(w, gray)
+}
+
+// WriteBarcodeSVG writes the barcode as one rectangle per run of dark modules, drawn at width x height
+func WriteBarcodeSVG(w io.Writer, code barcode.Barcode, width, height int) error {
+	bounds := code.Bounds()
+	if _, err := fmt.Fprintf(w,
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" preserveAspectRatio="none" shape-rendering="crispEdges">`+
+			`<rect width="100%%" height="100%%" fill="#fff"/><g fill="#000">`,
+		width, height, bounds.Dx(), bounds.Dy()); err != nil {
+		return err
+	}
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if !isDark(code, x, y) {
+				continue
+			}
+			start := x
+			for x+1 < bounds.Max.X && isDark(code, x+1, y) {
+				x++
+			}
+			if _, err := fmt.Fprintf(w, `<rect x="%d" y="%d" width="%d" height="1"/>`, start-bounds.Min.X, y-bounds.Min.Y, x-start+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, `</g></svg>`)
+	return err
+}
+
+func isDark(code barcode.Barcode, x, y int) bool {
+	r, _, _, _ := code.At(x, y).RGBA()
+	return r < 0x8000
+}
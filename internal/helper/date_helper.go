@@ -0,0 +1,8 @@
+package helper
+
+import "time"
+
+// Date return the calendar date of t at midnight UTC, the way the date columns are read back
+func Date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,46 @@
+package helper
+
+import "math"
+
+// MovingAverage return the mean of the last window values, of all of them when there are fewer
+func MovingAverage(values []float64, window int) float64 {
+	if window <= 0 || window > len(values) {
+		window = len(values)
+	}
+	if window == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, v := range values[len(values)-window:] {
+		sum += v
+	}
+	return sum / float64(window)
+}
+
+// ExponentialSmoothing return the last level of the values smoothed by alpha, the first value is the initial level
+func ExponentialSmoothing(values []float64, alpha float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	level := values[0]
+	for _, v := range values[1:] {
+		level = alpha*v + (1-alpha)*level
+	}
+	return level
+}
+
+// StandardDeviation return the sample standard deviation of the values, 0 with fewer than two of them
+func StandardDeviation(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+
+	mean := MovingAverage(values, 0)
+	sum := 0.0
+	for _, v := range values {
+		sum += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sum / float64(len(values)-1))
+}
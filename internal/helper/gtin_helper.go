@@ -0,0 +1,29 @@
+package helper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GTINForms return the ways the same GTIN can be written, as 8, 12, 13 or 14 digits padded with zeros on the left.
+// A scanner reading an EAN-13 gives 13 digits for a GTIN stored as 12 digits from a UPC-A.
+func GTINForms(gtin string) []string {
+	digits := strings.TrimLeft(gtin, "0")
+
+	var forms []string
+	for _, length := range []int{8, 12, 13, 14} {
+		if len(digits) <= length {
+			forms = append(forms, strings.Repeat("0", length-len(digits))+digits)
+		}
+	}
+	return forms
+}
+
+// GTINToEAN13 writes the GTIN as the 13 digits of an EAN-13, a GTIN-14 with a packaging indicator has no EAN-13
+func GTINToEAN13(gtin string) (string, error) {
+	digits := strings.TrimLeft(gtin, "0")
+	if len(digits) > 13 {
+		return "", fmt.Errorf("GTIN %s has no EAN-13", gtin)
+	}
+	return strings.Repeat("0", 13-len(digits)) + digits, nil
+}
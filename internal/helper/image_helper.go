@@ -0,0 +1,84 @@
+package helper
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+// ImageExtensions are the extensions of the accepted image types
+var ImageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// DecodeImage decodes an image of an accepted type, the type is sniffed from the content and not trusted from the client
+func DecodeImage(data []byte) (image.Image, string, error) {
+	contentType := http.DetectContentType(data)
+
+	var (
+		img image.Image
+		err error
+	)
+	reader := bytes.NewReader(data)
+	switch contentType {
+	case "image/jpeg":
+		img, err = jpeg.Decode(reader)
+	case "image/png":
+		img, err = png.Decode(reader)
+	case "image/gif":
+		img, err = gif.Decode(reader)
+	case "image/webp":
+		img, err = webp.Decode(reader)
+	default:
+		return nil, "", fmt.Errorf("content type %s is not an accepted image", contentType)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("decode %s: %w", contentType, err)
+	}
+	return img, contentType, nil
+}
+
+// Thumbnail scales img down so its longest side is size pixels, smaller images are kept at their size
+func Thumbnail(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= size && height <= size {
+		size = max(width, height)
+	}
+	if width >= height {
+		width, height = size, max(1, height*size/width)
+	} else {
+		width, height = max(1, width*size/height), size
+	}
+
+	thumbnail := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(thumbnail, thumbnail.Bounds(), img, bounds, draw.Src, nil)
+	return thumbnail
+}
+
+// EncodeThumbnail writes a thumbnail of an image of contentType and return its extension. Thumbnails of photos
+// are JPEG, the others PNG to keep their transparency, both can be embedded in a PDF.
+func EncodeThumbnail(w io.Writer, thumbnail image.Image, contentType string) (string, error) {
+	if contentType == "image/jpeg" {
+		return ".jpg", jpeg.Encode(w, thumbnail, &jpeg.Options{Quality: 85})
+	}
+	return ".png", png.Encode(w, thumbnail)
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
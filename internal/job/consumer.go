@@ -0,0 +1,340 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/quydmfl/niveau-test/pkg/broker"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// HandlerFunc handles one message. It runs inside a Transaction, repositories must be called with the given ctx.
+type HandlerFunc func(ctx context.Context, msg *broker.Message) error
+
+type handlerConfig struct {
+	concurrency     int
+	orderingKey     func(msg *broker.Message) string
+	maxRetries      int
+	backoff         time.Duration
+	deadLetterTopic string
+}
+
+type HandlerOption func(c *handlerConfig)
+
+// WithConcurrency sets how many messages of the topic are handled at the same time.
+// Messages with the same ordering key go to the same worker, so they are still handled in order.
+func WithConcurrency(n int) HandlerOption {
+	return func(c *handlerConfig) {
+		c.concurrency = n
+	}
+}
+
+// WithOrderingKey overrides the key that keeps messages in order, the message key by default.
+// Messages without a key are spread across the workers in no particular order.
+func WithOrderingKey(key func(msg *broker.Message) string) HandlerOption {
+	return func(c *handlerConfig) {
+		c.orderingKey = key
+	}
+}
+
+// WithRetry sets how many times a failed message is retried before being dead-lettered,
+// the delay between attempts doubles from backoff
+func WithRetry(maxRetries int, backoff time.Duration) HandlerOption {
+	return func(c *handlerConfig) {
+		c.maxRetries = maxRetries
+		c.backoff = backoff
+	}
+}
+
+// WithDeadLetterTopic overrides the default "<topic>.dlq" dead letter topic
+func WithDeadLetterTopic(topic string) HandlerOption {
+	return func(c *handlerConfig) {
+		c.deadLetterTopic = topic
+	}
+}
+
+// permanentError is not worth retrying, the message goes straight to the dead letter topic
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks err as not retryable
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+type Consumer interface {
+	// Register must be called before Run
+	Register(topic string, handler HandlerFunc, opts ...HandlerOption)
+	// Run consumes the registered topics until ctx is done, then waits for the in-flight messages
+	Run(ctx context.Context) error
+}
+
+func NewConsumer(
+	job *Job,
+	conf *viper.Viper,
+	broker broker.Broker,
+	processedMessageRepo repository.ProcessedMessageRepository,
+) Consumer {
+	c := &consumer{
+		Job:                  job,
+		broker:               broker,
+		processedMessageRepo: processedMessageRepo,
+		group:                conf.GetString("job.consumer.group"),
+		shutdownTimeout:      conf.GetDuration("job.consumer.shutdown_timeout"),
+		handlers:             make(map[string]*registration),
+	}
+
+	// Default values
+	if c.group == "" {
+		c.group = "niveau"
+	}
+	if c.shutdownTimeout <= 0 {
+		c.shutdownTimeout = 30 * time.Second
+	}
+
+	return c
+}
+
+type registration struct {
+	topic   string
+	handler HandlerFunc
+	config  handlerConfig
+}
+
+type consumer struct {
+	*Job
+	broker               broker.Broker
+	processedMessageRepo repository.ProcessedMessageRepository
+
+	group           string
+	shutdownTimeout time.Duration
+	handlers        map[string]*registration
+}
+
+func (c *consumer) Register(topic string, handler HandlerFunc, opts ...HandlerOption) {
+	cfg := handlerConfig{
+		concurrency:     1,
+		orderingKey:     func(msg *broker.Message) string { return msg.Key },
+		maxRetries:      3,
+		backoff:         time.Second,
+		deadLetterTopic: topic + ".dlq",
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.concurrency <= 0 {
+		cfg.concurrency = 1
+	}
+
+	c.handlers[topic] = &registration{
+		topic:   topic,
+		handler: handler,
+		config:  cfg,
+	}
+}
+
+func (c *consumer) Run(ctx context.Context) error {
+	// In-flight messages must not be interrupted by ctx, they get shutdownTimeout to finish
+	handleCtx, cancelHandle := context.WithCancel(context.Background())
+	defer cancelHandle()
+
+	var wg sync.WaitGroup
+	for _, reg := range c.handlers {
+		sub, err := c.broker.Subscribe(ctx, reg.topic, c.group)
+		if err != nil {
+			return err
+		}
+
+		wg.Add(1)
+		go func(reg *registration, sub broker.Subscription) {
+			defer wg.Done()
+			c.consume(ctx, handleCtx, reg, sub)
+		}(reg, sub)
+	}
+
+	<-ctx.Done()
+	c.logger.Info("Consumer stopping, waiting for in-flight messages")
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(c.shutdownTimeout):
+		c.logger.Warn("Consumer shutdown timeout, cancelling in-flight messages")
+		cancelHandle()
+		<-done
+	}
+
+	c.logger.Info("Consumer stop")
+	return nil
+}
+
+// consume fetches messages of one topic and hands them to concurrency workers by their ordering key,
+// a worker handles its messages one at a time
+func (c *consumer) consume(ctx context.Context, handleCtx context.Context, reg *registration, sub broker.Subscription) {
+	var (
+		wg      sync.WaitGroup
+		workers = make([]chan *broker.Message, reg.config.concurrency)
+	)
+	for i := range workers {
+		workers[i] = make(chan *broker.Message)
+		wg.Add(1)
+		go func(messages <-chan *broker.Message) {
+			defer wg.Done()
+			for msg := range messages {
+				c.process(ctx, handleCtx, reg, sub, msg)
+			}
+		}(workers[i])
+	}
+	defer func() {
+		for _, messages := range workers {
+			close(messages)
+		}
+		wg.Wait()
+		// Uncommitted messages are redelivered on the next run
+		_ = sub.Close()
+	}()
+
+	for {
+		msg, err := sub.Fetch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			c.logger.Error("Consumer fetch error", zap.String("topic", reg.topic), zap.Error(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(reg.config.backoff):
+			}
+			continue
+		}
+
+		// A message not handed over is left uncommitted and redelivered
+		select {
+		case <-ctx.Done():
+			return
+		case workers[worker(reg, msg)] <- msg:
+		}
+	}
+}
+
+// worker return the index of the worker of msg, the same for every message with its ordering key
+func worker(reg *registration, msg *broker.Message) int {
+	key := reg.config.orderingKey(msg)
+	if key == "" {
+		key = msg.ID
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(reg.config.concurrency))
+}
+
+func (c *consumer) process(ctx context.Context, handleCtx context.Context, reg *registration, sub broker.Subscription, msg *broker.Message) {
+	logger := c.logger.With(zap.String("topic", reg.topic), zap.String("message_id", msg.ID))
+
+	var (
+		err     error
+		backoff = reg.config.backoff
+	)
+	for attempt := 0; attempt <= reg.config.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				// shutting down: leave the message uncommitted so it is redelivered
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		if err = c.handle(handleCtx, reg, msg); err == nil {
+			break
+		}
+
+		logger.Warn("Consumer handle error", zap.Int("attempt", attempt+1), zap.Error(err))
+		var permanent *permanentError
+		if errors.As(err, &permanent) || handleCtx.Err() != nil {
+			break
+		}
+	}
+
+	if err != nil {
+		if handleCtx.Err() != nil {
+			return
+		}
+		if err := c.deadLetter(handleCtx, reg, msg, err); err != nil {
+			logger.Error("Consumer dead letter error", zap.Error(err))
+			return
+		}
+	}
+
+	if err := sub.Commit(handleCtx, msg); err != nil {
+		logger.Error("Consumer commit error", zap.Error(err))
+	}
+}
+
+// handle runs the handler once per message ID, the marker is stored in the same Transaction as the handler writes
+func (c *consumer) handle(ctx context.Context, reg *registration, msg *broker.Message) error {
+	name := c.group + ":" + reg.topic
+
+	return c.tm.Transaction(ctx, func(ctx context.Context) error {
+		processed, err := c.processedMessageRepo.Exists(ctx, name, msg.ID)
+		if err != nil {
+			return err
+		}
+		if processed {
+			c.logger.Info("Consumer skip duplicated message", zap.String("topic", reg.topic), zap.String("message_id", msg.ID))
+			return nil
+		}
+
+		if err := reg.handler(ctx, msg); err != nil {
+			return err
+		}
+
+		return c.processedMessageRepo.Create(ctx, &model.ProcessedMessage{
+			Consumer:    name,
+			MessageID:   msg.ID,
+			ProcessedAt: time.Now(),
+		})
+	})
+}
+
+func (c *consumer) deadLetter(ctx context.Context, reg *registration, msg *broker.Message, cause error) error {
+	headers := make(map[string]string, len(msg.Headers)+3)
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	headers["dlq_error"] = cause.Error()
+	headers["dlq_topic"] = reg.topic
+	headers["dlq_failed_at"] = strconv.FormatInt(time.Now().Unix(), 10)
+
+	return c.broker.Publish(ctx, &broker.Message{
+		ID:      msg.ID,
+		Topic:   reg.config.deadLetterTopic,
+		Key:     msg.Key,
+		Payload: msg.Payload,
+		Headers: headers,
+	})
+}
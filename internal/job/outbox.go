@@ -0,0 +1,154 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/quydmfl/niveau-test/pkg/broker"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type OutboxJob interface {
+	// Relay publishes pending outbox events until ctx is done
+	Relay(ctx context.Context) error
+	// Dispatch publishes one batch of pending events and return the number of published events
+	Dispatch(ctx context.Context) (int, error)
+	// Cleanup deletes the events published before the retention period
+	Cleanup(ctx context.Context) (int64, error)
+}
+
+func NewOutboxJob(
+	job *Job,
+	conf *viper.Viper,
+	outboxRepo repository.OutboxRepository,
+	broker broker.Broker,
+) OutboxJob {
+	o := &outboxJob{
+		Job:             job,
+		outboxRepo:      outboxRepo,
+		broker:          broker,
+		interval:        conf.GetDuration("job.outbox.interval"),
+		batchSize:       conf.GetInt("job.outbox.batch_size"),
+		retention:       conf.GetDuration("job.outbox.retention"),
+		cleanupInterval: conf.GetDuration("job.outbox.cleanup_interval"),
+	}
+
+	// Default values
+	if o.interval <= 0 {
+		o.interval = time.Second
+	}
+	if o.batchSize <= 0 {
+		o.batchSize = 100
+	}
+	if o.retention <= 0 {
+		o.retention = 7 * 24 * time.Hour
+	}
+	if o.cleanupInterval <= 0 {
+		o.cleanupInterval = time.Hour
+	}
+
+	return o
+}
+
+type outboxJob struct {
+	*Job
+	outboxRepo repository.OutboxRepository
+	broker     broker.Broker
+
+	interval        time.Duration
+	batchSize       int
+	retention       time.Duration
+	cleanupInterval time.Duration
+}
+
+func (o *outboxJob) Relay(ctx context.Context) error {
+	ticker := time.NewTicker(o.interval)
+	defer ticker.Stop()
+	cleanup := time.NewTicker(o.cleanupInterval)
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			o.logger.Info("OutboxRelay stop")
+			return nil
+		case <-ticker.C:
+			// Keep draining while full batches come back
+			for {
+				n, err := o.Dispatch(ctx)
+				if err != nil {
+					o.logger.Error("OutboxRelay dispatch error", zap.Error(err))
+					break
+				}
+				if n < o.batchSize || ctx.Err() != nil {
+					break
+				}
+			}
+		case <-cleanup.C:
+			deleted, err := o.Cleanup(ctx)
+			if err != nil {
+				o.logger.Error("OutboxRelay cleanup error", zap.Error(err))
+				continue
+			}
+			o.logger.Info("OutboxRelay cleanup", zap.Int64("deleted", deleted))
+		}
+	}
+}
+
+func (o *outboxJob) Dispatch(ctx context.Context) (int, error) {
+	published := 0
+
+	err := o.tm.Transaction(ctx, func(ctx context.Context) error {
+		events, err := o.outboxRepo.FetchPending(ctx, o.batchSize)
+		if err != nil {
+			return err
+		}
+
+		var (
+			ids = make([]uint64, 0, len(events))
+			// Once an event of an aggregate fails, the following ones must wait to keep the order
+			blocked = make(map[string]bool)
+		)
+		for _, event := range events {
+			key := event.AggregateType + ":" + event.AggregateID
+			if blocked[key] {
+				continue
+			}
+
+			err := o.broker.Publish(ctx, &broker.Message{
+				ID:      event.EventID.String(),
+				Topic:   event.Topic,
+				Key:     event.AggregateID,
+				Payload: []byte(event.Payload),
+				Headers: map[string]string{
+					"event_type":     event.EventType,
+					"aggregate_type": event.AggregateType,
+					"aggregate_id":   event.AggregateID,
+				},
+			})
+			if err != nil {
+				blocked[key] = true
+				o.logger.Warn("OutboxRelay publish error", zap.Uint64("id", event.ID), zap.Error(err))
+				if err := o.outboxRepo.MarkFailed(ctx, event.ID, err.Error()); err != nil {
+					return err
+				}
+				continue
+			}
+			ids = append(ids, event.ID)
+		}
+
+		published = len(ids)
+		return o.outboxRepo.MarkPublished(ctx, ids, time.Now())
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return published, nil
+}
+
+func (o *outboxJob) Cleanup(ctx context.Context) (int64, error) {
+	return o.outboxRepo.DeletePublishedBefore(ctx, time.Now().Add(-o.retention))
+}
@@ -0,0 +1,152 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/audit"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/quydmfl/niveau-test/pkg/broker"
+	"go.uber.org/zap"
+)
+
+type StockJob interface {
+	// HandleStockUpdate applies a v1.StockUpdateMessage pushed by an external system
+	HandleStockUpdate(ctx context.Context, msg *broker.Message) error
+}
+
+func NewStockJob(
+	job *Job,
+	productRepo repository.ProductRepository,
+	stockMovementRepo repository.StockMovementRepository,
+	statusChangeRepo repository.ProductStatusChangeRepository,
+	productLotRepo repository.ProductLotRepository,
+	bundleComponentRepo repository.BundleComponentRepository,
+) StockJob {
+	return &stockJob{
+		Job:               job,
+		productRepo:       productRepo,
+		stockMovementRepo: stockMovementRepo,
+		statusChangeRepo:  statusChangeRepo,
+		productLotRepo:    productLotRepo,
+
+		bundleComponentRepo: bundleComponentRepo,
+	}
+}
+
+type stockJob struct {
+	*Job
+	productRepo       repository.ProductRepository
+	stockMovementRepo repository.StockMovementRepository
+	statusChangeRepo  repository.ProductStatusChangeRepository
+	productLotRepo    repository.ProductLotRepository
+
+	bundleComponentRepo repository.BundleComponentRepository
+}
+
+// StockUpdateKey is the ordering key of a stock update, its reference: the updates of a product are applied in order
+// whatever the key the producer set
+func StockUpdateKey(msg *broker.Message) string {
+	var update v1.StockUpdateMessage
+	if err := json.Unmarshal(msg.Payload, &update); err != nil || update.Reference == "" {
+		return msg.Key
+	}
+	return update.Reference
+}
+
+func (t *stockJob) HandleStockUpdate(ctx context.Context, msg *broker.Message) error {
+	var update v1.StockUpdateMessage
+	if err := json.Unmarshal(msg.Payload, &update); err != nil {
+		return Permanent(fmt.Errorf("invalid stock update: %w", err))
+	}
+	if update.Reference == "" {
+		return Permanent(errors.New("invalid stock update: reference is required"))
+	}
+	ctx = audit.WithActor(ctx, audit.Actor{UserID: update.Source, TraceID: msg.ID})
+
+	product, err := t.productRepo.GetProductByPrefForUpdate(ctx, update.Reference)
+	if err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			return Permanent(fmt.Errorf("product %s not found", update.Reference))
+		}
+		return err
+	}
+
+	// A bundle is issued from the stock of its components
+	components, err := t.bundleComponentRepo.GetByBundle(ctx, product.ID)
+	if err != nil {
+		return err
+	}
+	if len(components) > 0 {
+		return Permanent(fmt.Errorf("stock of bundle %s is derived from its components", update.Reference))
+	}
+
+	quantity := product.Quantity
+	switch update.Mode {
+	case "set", "":
+		quantity = update.Quantity
+	case "adjust":
+		quantity += update.Quantity
+	default:
+		return Permanent(fmt.Errorf("invalid stock update mode: %s", update.Mode))
+	}
+	if quantity < 0 {
+		return Permanent(fmt.Errorf("stock of %s can not be negative", update.Reference))
+	}
+
+	delta := quantity - product.Quantity
+	if delta == 0 {
+		return nil
+	}
+
+	status := product.Status
+	product.Quantity = quantity
+	product.SyncStockStatus()
+
+	if err := t.productRepo.UpdateStock(ctx, product); err != nil {
+		return err
+	}
+	if product.Status != status {
+		change := model.NewProductStatusChange(status, product, model.StockMovementExternalSync, update.Source)
+		if err := t.statusChangeRepo.Create(ctx, change); err != nil {
+			return err
+		}
+	}
+
+	t.logger.Info("StockUpdate applied", zap.String("reference", product.Reference), zap.Int("delta", delta))
+	if delta > 0 {
+		return t.stockMovementRepo.Create(ctx, &model.StockMovement{
+			ProductID:     product.ID,
+			Delta:         delta,
+			QuantityAfter: quantity,
+			Reason:        model.StockMovementExternalSync,
+			SourceID:      msg.ID,
+			ActorID:       update.Source,
+		})
+	}
+
+	// A decrease is taken from the lots first-expired-first-out, expired lots included: they are written off first
+	lots, err := t.productLotRepo.GetByProduct(ctx, product.ID, false)
+	if err != nil {
+		return err
+	}
+	consumed := model.ConsumeFEFO(lots, -delta)
+	updated := make([]model.ProductLot, 0, len(consumed))
+	for _, consumption := range consumed {
+		updated = append(updated, *consumption.Lot)
+	}
+	if err := t.productLotRepo.UpdateQuantities(ctx, updated); err != nil {
+		return err
+	}
+	for _, movement := range model.LotMovements(product, quantity-delta, consumed) {
+		movement.Reason, movement.SourceID, movement.ActorID = model.StockMovementExternalSync, msg.ID, update.Source
+		if err := t.stockMovementRepo.Create(ctx, &movement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
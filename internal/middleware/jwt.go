@@ -5,6 +5,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/audit"
+	"github.com/quydmfl/niveau-test/internal/model"
 	"github.com/quydmfl/niveau-test/pkg/jwt"
 	"github.com/quydmfl/niveau-test/pkg/log"
 	"go.uber.org/zap"
@@ -40,6 +42,24 @@ func StrictAuth(j *jwt.JWT, logger *log.Logger) gin.HandlerFunc {
 	}
 }
 
+// AdminAuth only lets the users with the admin role through, it comes after StrictAuth which sets the claims
+func AdminAuth(logger *log.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		claims, ok := ctx.MustGet("claims").(*jwt.MyCustomClaims)
+		if !ok || claims.Role != model.UserRoleAdmin {
+			logger.WithContext(ctx).Warn("Not an admin", zap.Any("data", map[string]interface{}{
+				"url":    ctx.Request.URL,
+				"params": ctx.Params,
+			}))
+			v1.HandleError(ctx, http.StatusForbidden, v1.ErrForbidden, nil)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
 func NoStrictAuth(j *jwt.JWT, logger *log.Logger) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		tokenString := ctx.Request.Header.Get("Authorization")
@@ -69,5 +89,10 @@ func NoStrictAuth(j *jwt.JWT, logger *log.Logger) gin.HandlerFunc {
 func recoveryLoggerFunc(ctx *gin.Context, logger *log.Logger) {
 	if userInfo, ok := ctx.MustGet("claims").(*jwt.MyCustomClaims); ok {
 		logger.WithValue(ctx, zap.String("UserId", userInfo.UserId))
+
+		// Write operations of the request are audited under the user
+		actor := audit.ActorFromContext(ctx)
+		actor.UserID = userInfo.UserId
+		audit.SetActor(ctx, actor)
 	}
 }
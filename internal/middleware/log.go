@@ -8,6 +8,7 @@ import (
 	"github.com/duke-git/lancet/v2/cryptor"
 	"github.com/duke-git/lancet/v2/random"
 	"github.com/gin-gonic/gin"
+	"github.com/quydmfl/niveau-test/internal/audit"
 	"github.com/quydmfl/niveau-test/pkg/log"
 	"go.uber.org/zap"
 )
@@ -21,6 +22,7 @@ func RequestLogMiddleware(logger *log.Logger) gin.HandlerFunc {
 		}
 		trace := cryptor.Md5String(uuid)
 		logger.WithValue(ctx, zap.String("trace", trace))
+		audit.SetActor(ctx, audit.Actor{TraceID: trace, IP: ctx.ClientIP()})
 		logger.WithValue(ctx, zap.String("request_method", ctx.Request.Method))
 		logger.WithValue(ctx, zap.Any("request_headers", ctx.Request.Header))
 		logger.WithValue(ctx, zap.String("request_url", ctx.Request.URL.String()))
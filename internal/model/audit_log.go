@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+)
+
+// Action of an audit log
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+type AuditLog struct {
+	ID         uint64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	ActorID    string `gorm:"type:varchar(100);index" json:"actor_id"`
+	Action     string `gorm:"type:varchar(20);not null" json:"action"`
+	EntityType string `gorm:"type:varchar(100);not null;index:idx_audit_logs_entity" json:"entity_type"`
+	EntityID   string `gorm:"type:varchar(255);not null;index:idx_audit_logs_entity" json:"entity_id"`
+	// Changes is a JSON object of the changed columns: {"price": {"old": 10, "new": 12}}
+	Changes   string    `gorm:"type:text" json:"changes"`
+	TraceID   string    `gorm:"type:varchar(64);index" json:"trace_id"`
+	IP        string    `gorm:"type:varchar(45)" json:"ip"`
+	CreatedAt time.Time `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;index" json:"created_at"`
+}
+
+func (m *AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+func (m *AuditLog) SkipAudit() bool {
+	return true
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+// BundleComponent is a product that goes in a bundle, e.g. the products of a gift box. A bundle has no stock of
+// its own, it is built from the stock of its components: issuing a bundle takes the stock of each of them.
+type BundleComponent struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	BundleID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_bundle_components_component" json:"bundle_id"`
+	ComponentID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_bundle_components_component;index" json:"component_id"`
+	// Quantity is how many of the component go in one bundle
+	Quantity int `gorm:"type:int;not null;check:quantity > 0" json:"quantity"`
+
+	// Relationship
+	Component *Product `gorm:"foreignKey:ComponentID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"component,omitempty"`
+}
+
+func (m *BundleComponent) TableName() string {
+	return "product_bundle_components"
+}
+
+// Buildable is how many bundles the stock of the component is enough for, a component in the trash makes none
+func (m *BundleComponent) Buildable() int {
+	if m.Component == nil {
+		return 0
+	}
+	return m.Component.Quantity / m.Quantity
+}
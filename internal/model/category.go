@@ -1,23 +1,53 @@
 package model
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Category struct {
 	ID     uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
 	Name   string    `gorm:"type:varchar(255);not null" json:"name"`
 	Status string    `gorm:"type:varchar(25);not null" json:"status"`
+	// ParentID is nil for the root categories
+	ParentID *uuid.UUID `gorm:"type:uuid;default:null;index" json:"parent_id"`
+	// Path lists the IDs from the root down to the category, e.g. /<root id>/<parent id>/<id>/,
+	// so a subtree is the categories whose path starts with the path of its root
+	Path string `gorm:"type:varchar(1000);not null;default:'';index" json:"path"`
+	// ReferencePrefix is the {CAT} of the references generated for the products of the category
+	ReferencePrefix string `gorm:"type:varchar(10);default:null" json:"reference_prefix"`
+	// Version is incremented by every write of the row, it is the ETag of the category
+	Version int `gorm:"type:int;not null;default:1" json:"version"`
+	// DeletedAt puts the category in the trash, it is purged after the retention
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 
 	CreatedAt time.Time `gorm:"type:timestamp;not null;default:now()" json:"created_at"`
 	UpdatedAt time.Time `gorm:"type:timestamp;not null;default:now()" json:"updated_at"`
 
 	// Relationship
-	Products []Product `gorm:"foreignKey:CategoryID" json:"products,omitempty"`
+	Parent *Category `gorm:"foreignKey:ParentID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"parent,omitempty"`
+	// The constraint of a relationship declared on both sides is the one of this side
+	Products []Product `gorm:"foreignKey:CategoryID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"products,omitempty"`
+	// Attributes is the schema of the attribute values of the products
+	Attributes []CategoryAttribute `gorm:"foreignKey:CategoryID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"attributes,omitempty"`
 }
 
 func (m *Category) TableName() string {
 	return "product_categories"
 }
+
+// CategoryPath return the path of a category with the given parent path, an empty one for the root categories
+func CategoryPath(parentPath string, id uuid.UUID) string {
+	if parentPath == "" {
+		parentPath = "/"
+	}
+	return parentPath + id.String() + "/"
+}
+
+// IsAncestorOf reports whether m is other or one of its ancestors
+func (m *Category) IsAncestorOf(other *Category) bool {
+	return strings.HasPrefix(other.Path, m.Path)
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Type of the values of a category attribute
+const (
+	AttributeString = "string"
+	AttributeNumber = "number"
+	AttributeBool   = "bool"
+	// AttributeDate values are formatted as 2006-01-02
+	AttributeDate = "date"
+	// AttributeEnum values are one of the allowed values of the attribute
+	AttributeEnum = "enum"
+)
+
+// CategoryAttribute is an entry of the attribute schema of a category, the products of the category
+// store a value for it as a ProductAttribute
+type CategoryAttribute struct {
+	ID         uint64    `gorm:"primaryKey" json:"id"`
+	CategoryID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_category_attributes_name" json:"category_id"`
+	Name       string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_category_attributes_name" json:"name"`
+	Type       string    `gorm:"type:varchar(10);not null;check:type IN ('string', 'number', 'bool', 'date', 'enum')" json:"type"`
+	// Required attributes must have a value in every product of the category
+	Required bool `gorm:"not null" json:"required"`
+	// AllowedValues are the values of an enum attribute
+	AllowedValues []string `gorm:"type:text;serializer:json" json:"allowed_values,omitempty"`
+}
+
+func (m *CategoryAttribute) TableName() string {
+	return "category_attributes"
+}
+
+// Validate checks the definition of the attribute, only enum attributes have allowed values
+func (m *CategoryAttribute) Validate() error {
+	if m.Type == AttributeEnum && len(m.AllowedValues) == 0 {
+		return fmt.Errorf("enum attribute %s has no allowed values", m.Name)
+	}
+	if m.Type != AttributeEnum && len(m.AllowedValues) > 0 {
+		return fmt.Errorf("attribute %s of type %s can't have allowed values", m.Name, m.Type)
+	}
+	return nil
+}
+
+// Format checks value, as decoded from JSON, against the type of the attribute and return it as stored
+func (m *CategoryAttribute) Format(value interface{}) (string, error) {
+	switch m.Type {
+	case AttributeNumber:
+		if number, ok := value.(float64); ok {
+			return strconv.FormatFloat(number, 'f', -1, 64), nil
+		}
+	case AttributeBool:
+		if b, ok := value.(bool); ok {
+			return strconv.FormatBool(b), nil
+		}
+	case AttributeDate:
+		if s, ok := value.(string); ok {
+			if _, err := time.Parse("2006-01-02", s); err == nil {
+				return s, nil
+			}
+		}
+	case AttributeEnum:
+		if s, ok := value.(string); ok {
+			for _, allowed := range m.AllowedValues {
+				if s == allowed {
+					return s, nil
+				}
+			}
+		}
+	default:
+		if s, ok := value.(string); ok && len(s) <= 255 {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("attribute %s: %v is not a valid %s", m.Name, value, m.Type)
+}
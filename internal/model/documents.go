@@ -6,15 +6,32 @@ import (
 	"github.com/google/uuid"
 )
 
+// Kind of a document, retention is configured per kind
+const (
+	DocumentKindProductPDF       = "product_pdf"
+	DocumentKindPurchaseOrderPDF = "purchase_order_pdf"
+	DocumentKindLabelSheetPDF    = "label_sheet_pdf"
+	DocumentKindExpiringLotsPDF  = "expiring_lots_pdf"
+	// The count sheet and the variance report of a stocktake
+	DocumentKindStocktakeSheetPDF    = "stocktake_sheet_pdf"
+	DocumentKindStocktakeVariancePDF = "stocktake_variance_pdf"
+)
+
 type Documents struct {
 	ID       uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
 	Filename string    `gorm:"type:varchar(255);not null" json:"filename"`
 	Path     string    `gorm:"type:text;not null" json:"path"`
+	Kind     string    `gorm:"type:varchar(50);not null;default:product_pdf;index" json:"kind"`
 
 	ProductID *uuid.UUID `gorm:"type:uuid;default:null" json:"product_id"`
 	Product   *Product   `gorm:"foreignKey:ProductID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"product,omitempty"`
 
+	PurchaseOrderID *uuid.UUID     `gorm:"type:uuid;default:null;index" json:"purchase_order_id"`
+	PurchaseOrder   *PurchaseOrder `gorm:"foreignKey:PurchaseOrderID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"purchase_order,omitempty"`
+
 	UploadedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"uploaded_at"`
+	// MissingAt is set by the cleanup task when the file no longer exists in storage
+	MissingAt *time.Time `gorm:"type:timestamp;default:null" json:"missing_at"`
 }
 
 func (f *Documents) TableName() string {
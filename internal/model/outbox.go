@@ -0,0 +1,50 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OutboxEvent struct {
+	ID            uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	EventID       uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"event_id"`
+	Topic         string    `gorm:"type:varchar(100);not null" json:"topic"`
+	AggregateType string    `gorm:"type:varchar(50);not null" json:"aggregate_type"`
+	AggregateID   string    `gorm:"type:varchar(100);not null;index" json:"aggregate_id"`
+	EventType     string    `gorm:"type:varchar(100);not null" json:"event_type"`
+	Payload       string    `gorm:"type:text;not null" json:"payload"`
+	Attempts      int       `gorm:"type:int;not null;default:0" json:"attempts"`
+	LastError     string    `gorm:"type:text" json:"last_error"`
+
+	CreatedAt   time.Time  `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	PublishedAt *time.Time `gorm:"type:timestamp;default:null;index" json:"published_at"`
+}
+
+func (e *OutboxEvent) TableName() string {
+	return "outbox_events"
+}
+
+// NewOutboxEvent build an event for the given aggregate, the topic is named after the aggregate type
+func NewOutboxEvent(aggregateType string, aggregateID string, eventType string, payload interface{}) (*OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutboxEvent{
+		EventID:       uuid.New(),
+		Topic:         aggregateType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       string(data),
+		CreatedAt:     time.Now(),
+	}, nil
+}
+
+// Outbox rows mirror the audited write of their aggregate
+func (e *OutboxEvent) SkipAudit() bool {
+	return true
+}
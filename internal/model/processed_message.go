@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+)
+
+// ProcessedMessage records the messages already handled by a consumer, so redelivered ones are skipped
+type ProcessedMessage struct {
+	Consumer    string    `gorm:"type:varchar(150);primaryKey" json:"consumer"`
+	MessageID   string    `gorm:"type:varchar(150);primaryKey" json:"message_id"`
+	ProcessedAt time.Time `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;index" json:"processed_at"`
+}
+
+func (m *ProcessedMessage) TableName() string {
+	return "processed_messages"
+}
+
+// Consumer bookkeeping is not a business change
+func (m *ProcessedMessage) SkipAudit() bool {
+	return true
+}
@@ -4,7 +4,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/quydmfl/niveau-test/internal/helper"
 	"gorm.io/gorm"
 )
 
@@ -13,35 +12,124 @@ type Product struct {
 	Reference  string    `gorm:"type:varchar(50);not null;unique" json:"reference"`
 	Name       string    `gorm:"type:varchar(255);not null" json:"name"`
 	DateAdded  time.Time `gorm:"column:added_date;type:date;default:CURRENT_DATE" json:"added_date"`
-	Status     string    `gorm:"type:varchar(50);check:status IN ('Available', 'Out of Stock', 'On Order')" json:"status"`
+	Status     string    `gorm:"type:varchar(50);check:status IN ('Draft', 'Available', 'Out of Stock', 'On Order', 'Discontinued', 'Archived')" json:"status"`
 	CategoryID uuid.UUID `gorm:"type:uuid;not null" json:"category_id"`
 	Price      float64   `gorm:"type:numeric(10,2);default:0" json:"price"`
 	StockCity  string    `gorm:"type:varchar(100);default:null" json:"stock_city"`
 	SupplierID uuid.UUID `gorm:"type:uuid;default:null" json:"supplier_id"`
 	Quantity   int       `gorm:"type:int;default:0" json:"quantity"`
+	// ParentID is set for the variants of a product, they share its category and supplier
+	ParentID *uuid.UUID `gorm:"type:uuid;default:null;index" json:"parent_id,omitempty"`
+	// Options are the values that tell a variant from the other variants of its parent, e.g. size and colour
+	Options map[string]string `gorm:"type:text;serializer:json" json:"options,omitempty"`
+	// GTIN is the optional EAN/UPC code of the product, as 8, 12, 13 or 14 digits with a valid check digit
+	GTIN *string `gorm:"column:gtin;type:varchar(14);default:null;uniqueIndex" json:"gtin,omitempty"`
+	// Version is incremented by every write of the row, it is the ETag of the product
+	Version int `gorm:"type:int;not null;default:1" json:"version"`
+	// DeletedAt puts the product in the trash, it is purged after the retention
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 
 	// Relationship
 	Supplier  Supplier    `gorm:"foreignKey:SupplierID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"supplier,omitempty"`
-	Documents []Documents `gorm:"foreignKey:ProductID" json:"documents,omitempty"`
-	Category  Category    `gorm:"foreignKey:CategoryID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"category"`
+	Documents []Documents `gorm:"foreignKey:ProductID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"documents,omitempty"`
+	Category  Category    `gorm:"foreignKey:CategoryID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"category"`
+	// Attributes are the values for the attribute schema of the category
+	Attributes []ProductAttribute `gorm:"foreignKey:ProductID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"attributes,omitempty"`
+	// Images are ordered by position, the first one is the primary image
+	Images []ProductImage `gorm:"foreignKey:ProductID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"images,omitempty"`
+	// Lots are the batches of a perishable product with their expiry dates
+	Lots []ProductLot `gorm:"foreignKey:ProductID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"lots,omitempty"`
+	// Components make the product a bundle, its quantity is derived from their stock
+	Components []BundleComponent `gorm:"foreignKey:BundleID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"components,omitempty"`
+	// A parent in the trash has no live variants, they are purged with it
+	Parent   *Product  `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
+	Variants []Product `gorm:"foreignKey:ParentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"variants,omitempty"`
+}
+
+// Lifecycle status of a product, see Product.CanBecome for the allowed transitions
+const (
+	ProductDraft = "Draft"
+	// ProductAvailable is the active status, the product is sold from its stock
+	ProductAvailable    = "Available"
+	ProductOutOfStock   = "Out of Stock"
+	ProductOnOrder      = "On Order"
+	ProductDiscontinued = "Discontinued"
+	// ProductArchived products are hidden from the search unless asked for
+	ProductArchived = "Archived"
+)
+
+var productTransitions = map[string][]string{
+	ProductDraft:        {ProductAvailable, ProductOutOfStock, ProductOnOrder, ProductArchived},
+	ProductAvailable:    {ProductOutOfStock, ProductOnOrder, ProductDiscontinued},
+	ProductOutOfStock:   {ProductAvailable, ProductOnOrder, ProductDiscontinued},
+	ProductOnOrder:      {ProductAvailable, ProductOutOfStock, ProductDiscontinued},
+	ProductDiscontinued: {ProductAvailable, ProductArchived},
+	ProductArchived:     {ProductDiscontinued},
 }
 
 func (p *Product) TableName() string {
 	return "products"
 }
 
-func (p *Product) BeforeCreate(tx *gorm.DB) error {
-	if p.Reference == "" {
-		for {
-			p.Reference = helper.GenerateProductReference()
+// IsVariant reports whether the product is a variant of another one
+func (p *Product) IsVariant() bool {
+	return p.ParentID != nil
+}
 
-			var count int64
-			tx.Model(&Product{}).Where("reference = ?", p.Reference).Count(&count)
-			if count == 0 {
-				break // Exit loop if unique
-			}
+// IsBundle reports whether the product is made of other products, the components must be loaded
+func (p *Product) IsBundle() bool {
+	return len(p.Components) > 0
+}
+
+// BuildableQuantity is how many bundles the stock of the components can make, the components must be loaded
+func (p *Product) BuildableQuantity() int {
+	buildable := 0
+	for i := range p.Components {
+		if n := p.Components[i].Buildable(); i == 0 || n < buildable {
+			buildable = n
 		}
 	}
+	return buildable
+}
 
-	return nil
+// SameOptions reports whether both products have the same option values
+func (p *Product) SameOptions(other *Product) bool {
+	if len(p.Options) != len(other.Options) {
+		return false
+	}
+	for name, value := range p.Options {
+		if otherValue, ok := other.Options[name]; !ok || otherValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// CanBecome reports whether the product can move from its status to status
+func (p *Product) CanBecome(status string) bool {
+	for _, next := range productTransitions[p.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// SyncStockStatus moves the product between Available and Out of Stock after its quantity changed,
+// the other statuses are not driven by the stock
+func (p *Product) SyncStockStatus() {
+	switch {
+	case p.Quantity == 0 && p.Status == ProductAvailable:
+		p.Status = ProductOutOfStock
+	case p.Quantity > 0 && p.Status == ProductOutOfStock:
+		p.Status = ProductAvailable
+	}
+}
+
+// Restock adds received quantity to the stock, a product waiting for it becomes Available
+func (p *Product) Restock(quantity int) {
+	p.Quantity += quantity
+	if p.Status == ProductOnOrder || p.Status == ProductOutOfStock {
+		p.Status = ProductAvailable
+	}
 }
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// ProductAttribute is the value of a product for an attribute of its category.
+// Values are stored as text whatever their type, numbers in their shortest form and bools as true or false,
+// so they are filtered the same way on every driver.
+type ProductAttribute struct {
+	ID        uint64    `gorm:"primaryKey" json:"id"`
+	ProductID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_product_attributes_name" json:"product_id"`
+	Name      string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_product_attributes_name;index:idx_product_attributes_value" json:"name"`
+	// Type is the one of the attribute when the value was written
+	Type  string `gorm:"type:varchar(10);not null" json:"type"`
+	Value string `gorm:"type:varchar(255);not null;index:idx_product_attributes_value" json:"value"`
+}
+
+func (m *ProductAttribute) TableName() string {
+	return "product_attributes"
+}
+
+// The values are kept in the product versions
+func (m *ProductAttribute) SkipAudit() bool {
+	return true
+}
+
+// TypedValue return the value as decoded from JSON, a float64 for numbers and a bool for bools
+func (m *ProductAttribute) TypedValue() interface{} {
+	switch m.Type {
+	case AttributeNumber:
+		if number, err := strconv.ParseFloat(m.Value, 64); err == nil {
+			return number
+		}
+	case AttributeBool:
+		if b, err := strconv.ParseBool(m.Value); err == nil {
+			return b
+		}
+	}
+	return m.Value
+}
+
+// NewProductAttributes checks values against the attribute schema of a category, every required attribute
+// must have a value and the others may not be set
+func NewProductAttributes(schema []CategoryAttribute, values map[string]interface{}) ([]ProductAttribute, error) {
+	known := make(map[string]bool, len(schema))
+	attributes := make([]ProductAttribute, 0, len(values))
+	for i := range schema {
+		attribute := &schema[i]
+		known[attribute.Name] = true
+
+		value, ok := values[attribute.Name]
+		if !ok || value == nil {
+			if attribute.Required {
+				return nil, fmt.Errorf("attribute %s is required", attribute.Name)
+			}
+			continue
+		}
+		formatted, err := attribute.Format(value)
+		if err != nil {
+			return nil, err
+		}
+		attributes = append(attributes, ProductAttribute{Name: attribute.Name, Type: attribute.Type, Value: formatted})
+	}
+
+	for name, value := range values {
+		if !known[name] && value != nil {
+			return nil, fmt.Errorf("attribute %s is not in the schema of the category", name)
+		}
+	}
+
+	return attributes, nil
+}
+
+// AttributeValues return the attribute values of the product by name, nil if it has none
+func (p *Product) AttributeValues() map[string]interface{} {
+	if len(p.Attributes) == 0 {
+		return nil
+	}
+	values := make(map[string]interface{}, len(p.Attributes))
+	for i := range p.Attributes {
+		values[p.Attributes[i].Name] = p.Attributes[i].TypedValue()
+	}
+	return values
+}
@@ -0,0 +1,46 @@
+package model
+
+// Product domain events, published through the transactional outbox
+const (
+	ProductAggregate     = "products"
+	ProductCreatedEvent  = "product.created"
+	ProductUpdatedEvent  = "product.updated"
+	ProductDeletedEvent  = "product.deleted"
+	ProductRestoredEvent = "product.restored"
+)
+
+type ProductEventPayload struct {
+	ID         string  `json:"id"`
+	Reference  string  `json:"reference"`
+	GTIN       string  `json:"gtin,omitempty"`
+	Name       string  `json:"name"`
+	CategoryID string  `json:"category_id"`
+	SupplierID string  `json:"supplier_id"`
+	Price      float64 `json:"price"`
+	Quantity   int     `json:"quantity"`
+	Status     string  `json:"status"`
+	ParentID   string  `json:"parent_id,omitempty"`
+}
+
+// NewProductEvent build the outbox event of a product change, it must be stored in the transaction of the change
+func NewProductEvent(eventType string, product *Product) (*OutboxEvent, error) {
+	var parentID, gtin string
+	if product.ParentID != nil {
+		parentID = product.ParentID.String()
+	}
+	if product.GTIN != nil {
+		gtin = *product.GTIN
+	}
+	return NewOutboxEvent(ProductAggregate, product.ID.String(), eventType, ProductEventPayload{
+		ID:         product.ID.String(),
+		Reference:  product.Reference,
+		GTIN:       gtin,
+		Name:       product.Name,
+		CategoryID: product.CategoryID.String(),
+		SupplierID: product.SupplierID.String(),
+		Price:      product.Price,
+		Quantity:   product.Quantity,
+		Status:     product.Status,
+		ParentID:   parentID,
+	})
+}
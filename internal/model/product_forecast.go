@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Methods of the demand forecast, the one configured drives the reorder suggestion
+const (
+	ForecastMovingAverage        = "moving_average"
+	ForecastExponentialSmoothing = "exponential_smoothing"
+)
+
+// ProductForecast is the demand of a product forecast from its stock decreases and the reorder it suggests.
+// The forecasts are computed again by the demand_forecast task, the reference, name and supplier are copied
+// so that they are read without joins.
+type ProductForecast struct {
+	ProductID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"product_id"`
+	Reference    string    `gorm:"type:varchar(50);not null;index" json:"reference"`
+	Name         string    `gorm:"type:varchar(255);not null" json:"name"`
+	SupplierID   uuid.UUID `gorm:"type:uuid;index" json:"supplier_id"`
+	SupplierName string    `gorm:"type:varchar(255)" json:"supplier_name"`
+	Method       string    `gorm:"type:varchar(25);not null" json:"method"`
+	// Periods of PeriodDays days of history, those before the product was added are left out
+	PeriodDays int `gorm:"type:int;not null" json:"period_days"`
+	Periods    int `gorm:"type:int;not null" json:"periods"`
+	// The demands are per day, DailyDemand is the one of Method
+	MovingAverage        float64 `gorm:"type:numeric(12,4);not null;default:0" json:"moving_average"`
+	ExponentialSmoothing float64 `gorm:"type:numeric(12,4);not null;default:0" json:"exponential_smoothing"`
+	DailyDemand          float64 `gorm:"type:numeric(12,4);not null;default:0" json:"daily_demand"`
+	DemandStdDev         float64 `gorm:"type:numeric(12,4);not null;default:0" json:"demand_std_dev"`
+	LeadTimeDays         int     `gorm:"type:int;not null;default:0" json:"lead_time_days"`
+	SafetyStock          int     `gorm:"type:int;not null;default:0" json:"safety_stock"`
+	// Quantity and OnOrder are the stock and what is still expected from the sent purchase orders
+	Quantity int `gorm:"type:int;not null;default:0" json:"quantity"`
+	OnOrder  int `gorm:"type:int;not null;default:0" json:"on_order"`
+	// ReorderQuantity is what to order now, it is 0 while the stock and the orders are above ReorderPoint
+	ReorderPoint    int `gorm:"type:int;not null;default:0" json:"reorder_point"`
+	ReorderQuantity int `gorm:"type:int;not null;default:0;index" json:"reorder_quantity"`
+
+	ComputedAt time.Time `gorm:"type:timestamp;not null;default:now()" json:"computed_at"`
+
+	// Relationship
+	Product *Product `gorm:"foreignKey:ProductID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"product,omitempty"`
+}
+
+func (m *ProductForecast) TableName() string {
+	return "product_forecasts"
+}
+
+// Forecasts are computed, not edited
+func (m *ProductForecast) SkipAudit() bool {
+	return true
+}
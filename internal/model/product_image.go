@@ -0,0 +1,79 @@
+package model
+
+import (
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// ProductImageURLPrefix is the URL the image storage is served from
+	ProductImageURLPrefix = "/images"
+	// ProductImageStoragePath is the default image storage
+	ProductImageStoragePath = "./storage/images"
+)
+
+// ProductImage is an image uploaded for a product, with its thumbnails next to it in storage
+type ProductImage struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ProductID uuid.UUID `gorm:"type:uuid;not null;index" json:"product_id"`
+	// Filename is the name of the uploaded file, Path the file stored
+	Filename    string `gorm:"type:varchar(255);not null" json:"filename"`
+	Path        string `gorm:"type:text;not null" json:"path"`
+	ContentType string `gorm:"type:varchar(50);not null" json:"content_type"`
+	Size        int64  `gorm:"not null" json:"size"`
+	Width       int    `gorm:"not null" json:"width"`
+	Height      int    `gorm:"not null" json:"height"`
+	// Position orders the images of a product, the first one is the primary image
+	Position int `gorm:"not null;default:0" json:"position"`
+	// Thumbnails are the file names of the thumbnails by their longest side in pixels
+	Thumbnails map[int]string `gorm:"type:text;serializer:json" json:"thumbnails"`
+
+	UploadedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"uploaded_at"`
+
+	// Relationship
+	Product *Product `gorm:"foreignKey:ProductID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"product,omitempty"`
+}
+
+func (m *ProductImage) TableName() string {
+	return "product_images"
+}
+
+// URL return the URL of the image
+func (m *ProductImage) URL() string {
+	return path.Join(ProductImageURLPrefix, filepath.Base(m.Path))
+}
+
+// ThumbnailURLs return the URLs of the thumbnails by size
+func (m *ProductImage) ThumbnailURLs() map[int]string {
+	urls := make(map[int]string, len(m.Thumbnails))
+	for size, name := range m.Thumbnails {
+		urls[size] = path.Join(ProductImageURLPrefix, name)
+	}
+	return urls
+}
+
+// LargestThumbnail return the path of the largest thumbnail, empty if there is none
+func (m *ProductImage) LargestThumbnail() string {
+	largest, name := 0, ""
+	for size, thumbnail := range m.Thumbnails {
+		if size > largest {
+			largest, name = size, thumbnail
+		}
+	}
+	if name == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(m.Path), name)
+}
+
+// Files return the paths of the image and its thumbnails in storage
+func (m *ProductImage) Files() []string {
+	files := []string{m.Path}
+	for _, name := range m.Thumbnails {
+		files = append(files, filepath.Join(filepath.Dir(m.Path), name))
+	}
+	return files
+}
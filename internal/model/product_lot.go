@@ -0,0 +1,130 @@
+package model
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quydmfl/niveau-test/internal/helper"
+)
+
+// ProductLotExpiringEvent is published when the lot expiry task flags a lot nearing its expiry date
+const ProductLotExpiringEvent = "product.lot_expiring"
+
+// ProductLot is a batch of a perishable product received together, the lots of a product are consumed
+// first-expired-first-out. The stock held in lots is part of Product.Quantity, the rest is stock without lot.
+type ProductLot struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ProductID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_product_lots_number" json:"product_id"`
+	LotNumber string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_product_lots_number" json:"lot_number"`
+	// Quantity is what is left of the lot, ReceivedQuantity what was received
+	Quantity         int        `gorm:"type:int;not null;default:0" json:"quantity"`
+	ReceivedQuantity int        `gorm:"type:int;not null;default:0" json:"received_quantity"`
+	ManufacturedAt   *time.Time `gorm:"type:date;default:null" json:"manufactured_at"`
+	ExpiresAt        time.Time  `gorm:"type:date;not null;index" json:"expires_at"`
+	// StockCity is where the lot is stored, the stock location of the product when it was received by default
+	StockCity string `gorm:"type:varchar(100);default:null" json:"stock_city"`
+	// ExpiryFlaggedAt is set by the lot expiry task when the lot comes within the warning window of its expiry date
+	ExpiryFlaggedAt *time.Time `gorm:"type:timestamp;default:null" json:"expiry_flagged_at"`
+
+	ReceivedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"received_at"`
+
+	// Relationship
+	Product *Product `gorm:"foreignKey:ProductID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"product,omitempty"`
+}
+
+func (m *ProductLot) TableName() string {
+	return "product_lots"
+}
+
+// DaysLeft counts the days from the date of t to the expiry date, it is negative once the lot has expired
+func (m *ProductLot) DaysLeft(t time.Time) int {
+	return int(helper.Date(m.ExpiresAt).Sub(helper.Date(t)).Hours() / 24)
+}
+
+// Expired tells if the expiry date of the lot is before the date of t, a lot can be used on its expiry date
+func (m *ProductLot) Expired(t time.Time) bool {
+	return m.DaysLeft(t) < 0
+}
+
+// LotConsumption is the quantity taken from a lot
+type LotConsumption struct {
+	Lot      *ProductLot
+	Quantity int
+}
+
+// ConsumeFEFO takes quantity from the lots, the first to expire first, and return what was taken from each lot.
+// Less than quantity is taken when the lots run out, the lots are left sorted by expiry date.
+func ConsumeFEFO(lots []ProductLot, quantity int) []LotConsumption {
+	sort.SliceStable(lots, func(i, j int) bool {
+		if !lots[i].ExpiresAt.Equal(lots[j].ExpiresAt) {
+			return lots[i].ExpiresAt.Before(lots[j].ExpiresAt)
+		}
+		return lots[i].LotNumber < lots[j].LotNumber
+	})
+
+	var consumed []LotConsumption
+	for i := range lots {
+		if quantity == 0 {
+			break
+		}
+		if lots[i].Quantity <= 0 {
+			continue
+		}
+		taken := lots[i].Quantity
+		if taken > quantity {
+			taken = quantity
+		}
+		lots[i].Quantity -= taken
+		quantity -= taken
+		consumed = append(consumed, LotConsumption{Lot: &lots[i], Quantity: taken})
+	}
+	return consumed
+}
+
+// LotMovements splits the decrease of the stock of product from quantityBefore into one movement per lot consumed,
+// what the lots didn't cover was taken from the stock without lot and is the last movement.
+// The reason, source and actor of the movements are left to the caller.
+func LotMovements(product *Product, quantityBefore int, consumed []LotConsumption) []StockMovement {
+	var movements []StockMovement
+	quantity := quantityBefore
+	for _, consumption := range consumed {
+		lotID := consumption.Lot.ID
+		quantity -= consumption.Quantity
+		movements = append(movements, StockMovement{
+			ProductID:     product.ID,
+			LotID:         &lotID,
+			Delta:         -consumption.Quantity,
+			QuantityAfter: quantity,
+		})
+	}
+	if rest := quantity - product.Quantity; rest > 0 {
+		movements = append(movements, StockMovement{
+			ProductID:     product.ID,
+			Delta:         -rest,
+			QuantityAfter: product.Quantity,
+		})
+	}
+	return movements
+}
+
+type ProductLotEventPayload struct {
+	ProductID string `json:"product_id"`
+	Reference string `json:"reference"`
+	LotNumber string `json:"lot_number"`
+	Quantity  int    `json:"quantity"`
+	ExpiresAt string `json:"expires_at"`
+	StockCity string `json:"stock_city,omitempty"`
+}
+
+// NewProductLotEvent build the outbox event of a lot of product, it is published with the events of the product
+func NewProductLotEvent(eventType string, product *Product, lot *ProductLot) (*OutboxEvent, error) {
+	return NewOutboxEvent(ProductAggregate, product.ID.String(), eventType, ProductLotEventPayload{
+		ProductID: product.ID.String(),
+		Reference: product.Reference,
+		LotNumber: lot.LotNumber,
+		Quantity:  lot.Quantity,
+		ExpiresAt: lot.ExpiresAt.Format("2006-01-02"),
+		StockCity: lot.StockCity,
+	})
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductPrice is the price of a product over [EffectiveFrom, EffectiveTo), an open range has no EffectiveTo.
+// The ranges of a product never overlap, Product.Price caches the one in effect now.
+type ProductPrice struct {
+	ID            uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductID     uuid.UUID  `gorm:"type:uuid;not null;index:idx_product_prices_range" json:"product_id"`
+	Price         float64    `gorm:"type:numeric(10,2);not null" json:"price"`
+	EffectiveFrom time.Time  `gorm:"type:timestamp;not null;index:idx_product_prices_range" json:"effective_from"`
+	EffectiveTo   *time.Time `gorm:"type:timestamp;default:null" json:"effective_to"`
+	AuthorID      string     `gorm:"type:varchar(100)" json:"author_id"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+
+	// Relationship
+	Product *Product `gorm:"foreignKey:ProductID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"product,omitempty"`
+}
+
+func (m *ProductPrice) TableName() string {
+	return "product_prices"
+}
+
+// Covers reports whether the price is in effect at t
+func (m *ProductPrice) Covers(t time.Time) bool {
+	return !m.EffectiveFrom.After(t) && (m.EffectiveTo == nil || m.EffectiveTo.After(t))
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductStatusChange is an entry of the lifecycle history of a product, one row per change of Product.Status
+type ProductStatusChange struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductID  uuid.UUID `gorm:"type:uuid;not null;index" json:"product_id"`
+	FromStatus string    `gorm:"type:varchar(50);not null" json:"from_status"`
+	ToStatus   string    `gorm:"type:varchar(50);not null" json:"to_status"`
+	Reason     string    `gorm:"type:varchar(255)" json:"reason"`
+	ActorID    string    `gorm:"type:varchar(100)" json:"actor_id"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;index" json:"created_at"`
+
+	// Relationship
+	Product *Product `gorm:"foreignKey:ProductID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"product,omitempty"`
+}
+
+func (m *ProductStatusChange) TableName() string {
+	return "product_status_changes"
+}
+
+// The history is an append-only log, the product update is audited
+func (m *ProductStatusChange) SkipAudit() bool {
+	return true
+}
+
+func NewProductStatusChange(fromStatus string, product *Product, reason string, actorID string) *ProductStatusChange {
+	return &ProductStatusChange{
+		ProductID:  product.ID,
+		FromStatus: fromStatus,
+		ToStatus:   product.Status,
+		Reason:     reason,
+		ActorID:    actorID,
+		CreatedAt:  time.Now(),
+	}
+}
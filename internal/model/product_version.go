@@ -0,0 +1,65 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductVersion is a snapshot of a product after each change, version 1 is the product as created
+type ProductVersion struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_product_versions_version" json:"product_id"`
+	Version   int       `gorm:"type:int;not null;uniqueIndex:idx_product_versions_version" json:"version"`
+	AuthorID  string    `gorm:"type:varchar(100)" json:"author_id"`
+
+	// Snapshot of the product
+	Reference  string    `gorm:"type:varchar(50);not null" json:"reference"`
+	GTIN       *string   `gorm:"column:gtin;type:varchar(14);default:null" json:"gtin,omitempty"`
+	Name       string    `gorm:"type:varchar(255);not null" json:"name"`
+	DateAdded  time.Time `gorm:"column:added_date;type:date" json:"added_date"`
+	Status     string    `gorm:"type:varchar(50)" json:"status"`
+	CategoryID uuid.UUID `gorm:"type:uuid;not null" json:"category_id"`
+	Price      float64   `gorm:"type:numeric(10,2);default:0" json:"price"`
+	StockCity  string    `gorm:"type:varchar(100);default:null" json:"stock_city"`
+	SupplierID uuid.UUID `gorm:"type:uuid;default:null" json:"supplier_id"`
+	Quantity   int       `gorm:"type:int;default:0" json:"quantity"`
+	// Attributes are the attribute values by name
+	Attributes map[string]interface{} `gorm:"type:text;serializer:json" json:"attributes,omitempty"`
+	Options    map[string]string      `gorm:"type:text;serializer:json" json:"options,omitempty"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;index" json:"created_at"`
+
+	// Relationship
+	Product *Product `gorm:"foreignKey:ProductID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"product,omitempty"`
+}
+
+func (m *ProductVersion) TableName() string {
+	return "product_versions"
+}
+
+// Versions are already the history of the product
+func (m *ProductVersion) SkipAudit() bool {
+	return true
+}
+
+func NewProductVersion(product *Product, version int, authorID string) *ProductVersion {
+	return &ProductVersion{
+		ProductID:  product.ID,
+		Version:    version,
+		AuthorID:   authorID,
+		Reference:  product.Reference,
+		GTIN:       product.GTIN,
+		Name:       product.Name,
+		DateAdded:  product.DateAdded,
+		Status:     product.Status,
+		CategoryID: product.CategoryID,
+		Price:      product.Price,
+		StockCity:  product.StockCity,
+		SupplierID: product.SupplierID,
+		Quantity:   product.Quantity,
+		Attributes: product.AttributeValues(),
+		Options:    product.Options,
+		CreatedAt:  time.Now(),
+	}
+}
@@ -0,0 +1,105 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status of a purchase order, see PurchaseOrder.CanBecome for the allowed transitions
+const (
+	PurchaseOrderDraft             = "draft"
+	PurchaseOrderSent              = "sent"
+	PurchaseOrderPartiallyReceived = "partially_received"
+	PurchaseOrderReceived          = "received"
+	PurchaseOrderCancelled         = "cancelled"
+)
+
+var purchaseOrderTransitions = map[string][]string{
+	PurchaseOrderDraft:             {PurchaseOrderSent, PurchaseOrderCancelled},
+	PurchaseOrderSent:              {PurchaseOrderPartiallyReceived, PurchaseOrderReceived, PurchaseOrderCancelled},
+	PurchaseOrderPartiallyReceived: {PurchaseOrderPartiallyReceived, PurchaseOrderReceived, PurchaseOrderCancelled},
+}
+
+// PurchaseOrder is an order of products to a supplier, receiving it adds its lines to the stock.
+// The supplier name and the product names are copied so the order still reads the same once they change.
+type PurchaseOrder struct {
+	ID           uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Number       string     `gorm:"type:varchar(50);not null;uniqueIndex" json:"number"`
+	SupplierID   *uuid.UUID `gorm:"type:uuid;default:null;index" json:"supplier_id"`
+	SupplierName string     `gorm:"type:varchar(255);not null" json:"supplier_name"`
+	Status       string     `gorm:"type:varchar(25);not null;default:draft;index;check:status IN ('draft', 'sent', 'partially_received', 'received', 'cancelled')" json:"status"`
+	Currency     string     `gorm:"type:varchar(3);not null" json:"currency"`
+	Note         string     `gorm:"type:text;default:null" json:"note"`
+	CreatedBy    string     `gorm:"type:varchar(100)" json:"created_by"`
+	// ExpectedAt is set when the order is sent, from the lead time of the supplier
+	ExpectedAt  *time.Time `gorm:"type:timestamp;default:null" json:"expected_at"`
+	SentAt      *time.Time `gorm:"type:timestamp;default:null" json:"sent_at"`
+	ReceivedAt  *time.Time `gorm:"type:timestamp;default:null" json:"received_at"`
+	CancelledAt *time.Time `gorm:"type:timestamp;default:null" json:"cancelled_at"`
+	// Version is incremented by every write of the row, it is the ETag of the order
+	Version int `gorm:"type:int;not null;default:1" json:"version"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;not null;default:now()" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp;not null;default:now()" json:"updated_at"`
+
+	// Relationship
+	Supplier *Supplier           `gorm:"foreignKey:SupplierID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"supplier,omitempty"`
+	Lines    []PurchaseOrderLine `gorm:"foreignKey:PurchaseOrderID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"lines,omitempty"`
+}
+
+func (m *PurchaseOrder) TableName() string {
+	return "purchase_orders"
+}
+
+// CanBecome reports whether the order can move from its status to status
+func (m *PurchaseOrder) CanBecome(status string) bool {
+	for _, next := range purchaseOrderTransitions[m.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Total return the ordered cost of the lines
+func (m *PurchaseOrder) Total() float64 {
+	var total float64
+	for _, line := range m.Lines {
+		total += float64(line.Quantity) * line.UnitCost
+	}
+	return total
+}
+
+// FullyReceived reports whether every line has been received
+func (m *PurchaseOrder) FullyReceived() bool {
+	for _, line := range m.Lines {
+		if line.Remaining() > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+type PurchaseOrderLine struct {
+	ID               uint64     `gorm:"primaryKey" json:"id"`
+	PurchaseOrderID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"purchase_order_id"`
+	ProductID        *uuid.UUID `gorm:"type:uuid;default:null;index" json:"product_id"`
+	Reference        string     `gorm:"type:varchar(255);not null" json:"reference"`
+	Name             string     `gorm:"type:varchar(255);not null" json:"name"`
+	Quantity         int        `gorm:"type:int;not null" json:"quantity"`
+	ReceivedQuantity int        `gorm:"type:int;not null;default:0" json:"received_quantity"`
+	UnitCost         float64    `gorm:"type:numeric(10,2);not null" json:"unit_cost"`
+
+	// Relationship
+	Product *Product `gorm:"foreignKey:ProductID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"product,omitempty"`
+}
+
+func (m *PurchaseOrderLine) TableName() string {
+	return "purchase_order_lines"
+}
+
+// Remaining return the quantity still to receive
+func (m *PurchaseOrderLine) Remaining() int {
+	return m.Quantity - m.ReceivedQuantity
+}
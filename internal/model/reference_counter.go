@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// ReferenceCounter is the last sequence number given to a scope of product references, see reference.Format.Scope
+type ReferenceCounter struct {
+	Scope     string    `gorm:"type:varchar(100);primaryKey" json:"scope"`
+	Value     int64     `gorm:"not null;default:0" json:"value"`
+	UpdatedAt time.Time `gorm:"type:timestamp" json:"updated_at"`
+}
+
+func (m *ReferenceCounter) TableName() string {
+	return "reference_counters"
+}
+
+// Counters are bumped with each product created, the product is audited
+func (m *ReferenceCounter) SkipAudit() bool {
+	return true
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reasons of a stock movement
+const (
+	StockMovementExternalSync    = "external_sync"
+	StockMovementPurchaseReceipt = "purchase_receipt"
+	StockMovementLotReceipt      = "lot_receipt"
+	// StockMovementConsumption is stock issued first-expired-first-out from the lots of the product
+	StockMovementConsumption = "consumption"
+	// StockMovementBundleIssue is the stock of a component taken to build the bundles issued
+	StockMovementBundleIssue = "bundle_issue"
+	// StockMovementStocktake sets the stock to the quantity counted by an approved stocktake
+	StockMovementStocktake = "stocktake"
+	// StockMovementManualAdjustment is a quantity set by a product update, one by one or in bulk
+	StockMovementManualAdjustment = "manual_adjustment"
+)
+
+// StockMovement is an entry of the stock ledger, one row per change of Product.Quantity
+type StockMovement struct {
+	ID            uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductID     uuid.UUID `gorm:"type:uuid;not null;index" json:"product_id"`
+	Delta         int       `gorm:"type:int;not null" json:"delta"`
+	QuantityAfter int       `gorm:"type:int;not null" json:"quantity_after"`
+	Reason        string    `gorm:"type:varchar(50);not null;index" json:"reason"`
+	SourceID      string    `gorm:"type:varchar(150)" json:"source_id"`
+	ActorID       string    `gorm:"type:varchar(100)" json:"actor_id"`
+	// LotID is the lot the stock was received in or taken from, it is not set for the stock without lot
+	LotID *uuid.UUID `gorm:"type:uuid;default:null;index" json:"lot_id,omitempty"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;index" json:"created_at"`
+
+	// Relationship
+	Product *Product    `gorm:"foreignKey:ProductID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"product,omitempty"`
+	Lot     *ProductLot `gorm:"foreignKey:LotID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"lot,omitempty"`
+}
+
+func (m *StockMovement) TableName() string {
+	return "stock_movements"
+}
+
+// Movements are an append-only ledger, the product update is audited
+func (m *StockMovement) SkipAudit() bool {
+	return true
+}
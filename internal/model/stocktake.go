@@ -0,0 +1,94 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status of a stocktake, the counts are only taken while it is open
+const (
+	StocktakeOpen      = "open"
+	StocktakeApproved  = "approved"
+	StocktakeCancelled = "cancelled"
+)
+
+// Stocktake is a physical count of the products of a stock location, of a category, or of both.
+// Its lines are the products in scope when it was opened, approving it sets their stock to the counted quantity.
+type Stocktake struct {
+	ID     uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Number string    `gorm:"type:varchar(50);not null;uniqueIndex" json:"number"`
+	Status string    `gorm:"type:varchar(25);not null;default:open;index;check:status IN ('open', 'approved', 'cancelled')" json:"status"`
+	// StockCity and CategoryID are the scope, CategoryID with its subcategories when IncludeSubcategories
+	StockCity            string     `gorm:"type:varchar(100);default:null" json:"stock_city"`
+	CategoryID           *uuid.UUID `gorm:"type:uuid;default:null;index" json:"category_id"`
+	IncludeSubcategories bool       `gorm:"not null;default:false" json:"include_subcategories"`
+	Note                 string     `gorm:"type:text;default:null" json:"note"`
+	CreatedBy            string     `gorm:"type:varchar(100)" json:"created_by"`
+	// ApprovedBy is the supervisor who applied the adjustments
+	ApprovedBy  string     `gorm:"type:varchar(100);default:null" json:"approved_by"`
+	ApprovedAt  *time.Time `gorm:"type:timestamp;default:null" json:"approved_at"`
+	CancelledAt *time.Time `gorm:"type:timestamp;default:null" json:"cancelled_at"`
+	// Version is incremented by every write of the row and by every count, it is the ETag of the stocktake
+	Version int `gorm:"type:int;not null;default:1" json:"version"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;not null;default:now()" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp;not null;default:now()" json:"updated_at"`
+
+	// Relationship
+	Category *Category       `gorm:"foreignKey:CategoryID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"category,omitempty"`
+	Lines    []StocktakeLine `gorm:"foreignKey:StocktakeID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"lines,omitempty"`
+}
+
+func (m *Stocktake) TableName() string {
+	return "stocktakes"
+}
+
+// StocktakeLine is a product to count. The reference and name are copied so the stocktake still reads the same
+// once the product changes.
+type StocktakeLine struct {
+	ID          uint64    `gorm:"primaryKey" json:"id"`
+	StocktakeID uuid.UUID `gorm:"type:uuid;not null;index" json:"stocktake_id"`
+	ProductID   uuid.UUID `gorm:"type:uuid;not null;index" json:"product_id"`
+	Reference   string    `gorm:"type:varchar(50);not null" json:"reference"`
+	Name        string    `gorm:"type:varchar(255);not null" json:"name"`
+	StockCity   string    `gorm:"type:varchar(100);default:null" json:"stock_city"`
+	// SystemQuantity is the stock of the product when the stocktake was opened, then when it was approved
+	SystemQuantity int `gorm:"type:int;not null;default:0" json:"system_quantity"`
+	// CountedQuantity is the sum of the counts of the devices, it is not set until the product is counted
+	CountedQuantity *int `gorm:"type:int;default:null" json:"counted_quantity"`
+	// Adjustment is the change of stock applied by the approval
+	Adjustment *int `gorm:"type:int;default:null" json:"adjustment"`
+
+	// Relationship
+	Product *Product         `gorm:"foreignKey:ProductID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"product,omitempty"`
+	Counts  []StocktakeCount `gorm:"foreignKey:LineID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"counts,omitempty"`
+}
+
+func (m *StocktakeLine) TableName() string {
+	return "stocktake_lines"
+}
+
+// Variance is the counted quantity less the system quantity, it is not set until the product is counted
+func (m *StocktakeLine) Variance() *int {
+	if m.CountedQuantity == nil {
+		return nil
+	}
+	variance := *m.CountedQuantity - m.SystemQuantity
+	return &variance
+}
+
+// StocktakeCount is what a device counted of a product, a new count of the device replaces its previous one.
+// Devices count separate places, so the quantity of the line is the sum of their counts.
+type StocktakeCount struct {
+	ID        uint64    `gorm:"primaryKey" json:"id"`
+	LineID    uint64    `gorm:"not null;uniqueIndex:idx_stocktake_counts_device" json:"line_id"`
+	DeviceID  string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_stocktake_counts_device" json:"device_id"`
+	Quantity  int       `gorm:"type:int;not null" json:"quantity"`
+	CountedBy string    `gorm:"type:varchar(100)" json:"counted_by"`
+	CountedAt time.Time `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP" json:"counted_at"`
+}
+
+func (m *StocktakeCount) TableName() string {
+	return "stocktake_counts"
+}
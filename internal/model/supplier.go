@@ -1,14 +1,93 @@
 package model
 
 import (
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Supplier struct {
-	ID   uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
-	Name string    `gorm:"type:varchar(255);not null" json:"name"`
+	ID    uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Name  string    `gorm:"type:varchar(255);not null" json:"name"`
+	Email string    `gorm:"type:varchar(255);default:null" json:"email"`
+	Phone string    `gorm:"type:varchar(50);default:null" json:"phone"`
+	// Currency is the ISO 4217 code the supplier invoices in
+	Currency     string `gorm:"type:varchar(3);not null;default:VND" json:"currency"`
+	PaymentTerms string `gorm:"type:varchar(100);default:null" json:"payment_terms"`
+	// LeadTimeDays is the usual delay between an order and its delivery
+	LeadTimeDays int `gorm:"type:int;not null;default:0" json:"lead_time_days"`
+	// Status is active or deactive like for categories, deactive suppliers are kept for their products
+	Status string `gorm:"type:varchar(25);not null;default:active" json:"status"`
+	// Version is incremented by every write of the row, it is the ETag of the supplier
+	Version int `gorm:"type:int;not null;default:1" json:"version"`
+	// DeletedAt puts the supplier in the trash, it is purged after the retention
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;not null;default:now()" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp;not null;default:now()" json:"updated_at"`
+
+	// Relationship
+	Contacts  []SupplierContact `gorm:"foreignKey:SupplierID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"contacts,omitempty"`
+	Addresses []SupplierAddress `gorm:"foreignKey:SupplierID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"addresses,omitempty"`
 }
 
 func (m *Supplier) TableName() string {
 	return "suppliers"
 }
+
+// SupplierContact is a person to reach at a supplier
+type SupplierContact struct {
+	ID         uint64    `gorm:"primaryKey" json:"id"`
+	SupplierID uuid.UUID `gorm:"type:uuid;not null;index" json:"supplier_id"`
+	Name       string    `gorm:"type:varchar(100);not null" json:"name"`
+	Role       string    `gorm:"type:varchar(100);default:null" json:"role"`
+	Email      string    `gorm:"type:varchar(255);default:null" json:"email"`
+	Phone      string    `gorm:"type:varchar(50);default:null" json:"phone"`
+}
+
+func (m *SupplierContact) TableName() string {
+	return "supplier_contacts"
+}
+
+// SameAs reports whether both contacts are the same person, by email or else by name
+func (m *SupplierContact) SameAs(other *SupplierContact) bool {
+	if m.Email != "" && other.Email != "" {
+		return strings.EqualFold(m.Email, other.Email)
+	}
+	return strings.EqualFold(m.Name, other.Name)
+}
+
+// Kind of a supplier address
+const (
+	SupplierAddressBilling   = "billing"
+	SupplierAddressShipping  = "shipping"
+	SupplierAddressOffice    = "office"
+	SupplierAddressWarehouse = "warehouse"
+)
+
+type SupplierAddress struct {
+	ID         uint64    `gorm:"primaryKey" json:"id"`
+	SupplierID uuid.UUID `gorm:"type:uuid;not null;index" json:"supplier_id"`
+	Kind       string    `gorm:"type:varchar(25);not null" json:"kind"`
+	Line1      string    `gorm:"type:varchar(255);not null" json:"line1"`
+	Line2      string    `gorm:"type:varchar(255);default:null" json:"line2"`
+	City       string    `gorm:"type:varchar(100);not null" json:"city"`
+	PostalCode string    `gorm:"type:varchar(20);default:null" json:"postal_code"`
+	// Country is the ISO 3166-1 alpha-2 code
+	Country string `gorm:"type:varchar(2);not null" json:"country"`
+}
+
+func (m *SupplierAddress) TableName() string {
+	return "supplier_addresses"
+}
+
+// SameAs reports whether both addresses are the same place for the same use
+func (m *SupplierAddress) SameAs(other *SupplierAddress) bool {
+	return m.Kind == other.Kind &&
+		strings.EqualFold(m.Line1, other.Line1) &&
+		strings.EqualFold(m.City, other.City) &&
+		strings.EqualFold(m.PostalCode, other.PostalCode) &&
+		strings.EqualFold(m.Country, other.Country)
+}
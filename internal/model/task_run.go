@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+)
+
+// Status of a task run
+const (
+	TaskRunPending = "pending"
+	TaskRunRunning = "running"
+	TaskRunSuccess = "success"
+	TaskRunFailed  = "failed"
+	TaskRunTimeout = "timeout"
+	TaskRunSkipped = "skipped"
+)
+
+// Trigger of a task run
+const (
+	TaskTriggerSchedule = "schedule"
+	TaskTriggerManual   = "manual"
+)
+
+type TaskRun struct {
+	ID       uint64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	TaskName string `gorm:"type:varchar(100);not null;uniqueIndex:idx_task_runs_tick" json:"task_name"`
+	// ScheduledAt is the cron tick of a scheduled run, the unique index lets a single replica claim it
+	ScheduledAt *time.Time `gorm:"type:timestamp;default:null;uniqueIndex:idx_task_runs_tick" json:"scheduled_at"`
+	Trigger     string     `gorm:"type:varchar(20);not null" json:"trigger"`
+	TriggeredBy string     `gorm:"type:varchar(100)" json:"triggered_by"`
+	// DryRun runs only report what they would change
+	DryRun bool   `gorm:"not null;default:false" json:"dry_run"`
+	Status string `gorm:"type:varchar(20);not null;index" json:"status"`
+	Error  string `gorm:"type:text" json:"error"`
+	Result string `gorm:"type:text" json:"result"`
+	Host   string `gorm:"type:varchar(255)" json:"host"`
+
+	StartedAt  *time.Time `gorm:"type:timestamp;default:null" json:"started_at"`
+	FinishedAt *time.Time `gorm:"type:timestamp;default:null" json:"finished_at"`
+	CreatedAt  time.Time  `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;index" json:"created_at"`
+}
+
+func (m *TaskRun) TableName() string {
+	return "task_runs"
+}
+
+// The run history is already a trail of its own
+func (m *TaskRun) SkipAudit() bool {
+	return true
+}
@@ -9,13 +9,21 @@ type User struct {
 	Id        uint   `gorm:"primarykey"`
 	UserId    string `gorm:"unique;not null"`
 	Nickname  string `gorm:"not null"`
-	Password  string `gorm:"not null"`
+	Password  string `gorm:"not null" audit:"-"`
 	Email     string `gorm:"not null"`
+	Role      string `gorm:"type:varchar(20);not null;default:'user'"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
 
+// Roles of a user, the admin role opens the /admin routes.
+// The role is given to the token at login, a change applies from the next login.
+const (
+	UserRoleUser  = "user"
+	UserRoleAdmin = "admin"
+)
+
 func (u *User) TableName() string {
 	return "users"
 }
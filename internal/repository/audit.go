@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/quydmfl/niveau-test/internal/audit"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const auditSnapshotKey = "audit:snapshot"
+
+// fieldChange is one entry of AuditLog.Changes
+type fieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// RegisterAuditCallbacks records every create, update and delete in audit_logs within the transaction of the write.
+// Every model is covered unless it implements audit.Skipper, columns tagged `audit:"-"` are never recorded.
+func RegisterAuditCallbacks(db *gorm.DB) error {
+	if err := db.Callback().Create().After("gorm:create").Register("audit:create", auditCreate); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("audit:before_update", auditSnapshot); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().After("gorm:update").Register("audit:update", auditUpdate); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().Before("gorm:delete").Register("audit:before_delete", auditSnapshot); err != nil {
+		return err
+	}
+	return db.Callback().Delete().After("gorm:delete").Register("audit:delete", auditDelete)
+}
+
+// audited reports whether the statement writes an audited model
+func audited(db *gorm.DB) bool {
+	if db.Error != nil || db.DryRun || db.Statement.Schema == nil {
+		return false
+	}
+	if skipper, ok := reflect.New(db.Statement.Schema.ModelType).Interface().(audit.Skipper); ok && skipper.SkipAudit() {
+		return false
+	}
+	return true
+}
+
+func auditCreate(db *gorm.DB) {
+	if !audited(db) || db.RowsAffected == 0 {
+		return
+	}
+
+	stmt := db.Statement
+	var logs []model.AuditLog
+	eachStruct(stmt.ReflectValue, func(rv reflect.Value) {
+		changes := make(map[string]fieldChange)
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" || isMasked(field) {
+				continue
+			}
+			if value, zero := field.ValueOf(stmt.Context, rv); !zero {
+				changes[field.DBName] = fieldChange{New: normalize(value)}
+			}
+		}
+		logs = append(logs, newAuditLog(db, model.AuditActionCreate, structEntityID(db, rv), changes))
+	})
+
+	writeAuditLogs(db, logs)
+}
+
+// auditSnapshot loads the rows matched by an update or delete before they are changed
+func auditSnapshot(db *gorm.DB) {
+	if !audited(db) {
+		return
+	}
+
+	stmt := db.Statement
+	query := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Table(stmt.Table)
+	conditioned := false
+
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok && len(where.Exprs) > 0 {
+			query = query.Clauses(clause.Where{Exprs: where.Exprs})
+			conditioned = true
+		}
+	}
+
+	// The primary keys of the model value, e.g. Save(product) or Delete(&products)
+	if len(stmt.Schema.PrimaryFields) == 1 {
+		field := stmt.Schema.PrimaryFields[0]
+		var ids []interface{}
+		eachStruct(stmt.ReflectValue, func(rv reflect.Value) {
+			if value, zero := field.ValueOf(stmt.Context, rv); !zero {
+				ids = append(ids, value)
+			}
+		})
+		if len(ids) > 0 {
+			query = query.Where(clause.IN{Column: clause.Column{Name: field.DBName}, Values: ids})
+			conditioned = true
+		}
+	}
+
+	// gorm refuses the write anyway without conditions
+	if !conditioned && !db.AllowGlobalUpdate {
+		return
+	}
+
+	var rows []map[string]interface{}
+	if err := query.Find(&rows).Error; err != nil {
+		_ = db.AddError(fmt.Errorf("audit snapshot: %w", err))
+		return
+	}
+	db.InstanceSet(auditSnapshotKey, rows)
+}
+
+func auditUpdate(db *gorm.DB) {
+	if !audited(db) {
+		return
+	}
+	before := snapshotRows(db)
+	if len(before) == 0 {
+		return
+	}
+
+	after, err := reloadRows(db, before)
+	if err != nil {
+		_ = db.AddError(fmt.Errorf("audit reload: %w", err))
+		return
+	}
+
+	var logs []model.AuditLog
+	for _, old := range before {
+		id := rowEntityID(db, old)
+		row, ok := after[id]
+		if !ok {
+			continue
+		}
+
+		changes := make(map[string]fieldChange)
+		for _, field := range db.Statement.Schema.Fields {
+			if field.DBName == "" || isMasked(field) {
+				continue
+			}
+			oldValue, newValue := normalize(old[field.DBName]), normalize(row[field.DBName])
+			if !reflect.DeepEqual(oldValue, newValue) {
+				changes[field.DBName] = fieldChange{Old: oldValue, New: newValue}
+			}
+		}
+		if len(changes) > 0 {
+			logs = append(logs, newAuditLog(db, model.AuditActionUpdate, id, changes))
+		}
+	}
+
+	writeAuditLogs(db, logs)
+}
+
+func auditDelete(db *gorm.DB) {
+	if !audited(db) || db.RowsAffected == 0 {
+		return
+	}
+
+	var logs []model.AuditLog
+	for _, old := range snapshotRows(db) {
+		changes := make(map[string]fieldChange)
+		for _, field := range db.Statement.Schema.Fields {
+			if field.DBName == "" || isMasked(field) {
+				continue
+			}
+			if value := normalize(old[field.DBName]); value != nil {
+				changes[field.DBName] = fieldChange{Old: value}
+			}
+		}
+		logs = append(logs, newAuditLog(db, model.AuditActionDelete, rowEntityID(db, old), changes))
+	}
+
+	writeAuditLogs(db, logs)
+}
+
+func snapshotRows(db *gorm.DB) []map[string]interface{} {
+	v, ok := db.InstanceGet(auditSnapshotKey)
+	if !ok {
+		return nil
+	}
+	rows, _ := v.([]map[string]interface{})
+	return rows
+}
+
+// reloadRows reads the rows of the snapshot again, indexed by entity ID
+func reloadRows(db *gorm.DB, before []map[string]interface{}) (map[string]map[string]interface{}, error) {
+	stmt := db.Statement
+	query := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Table(stmt.Table)
+
+	var rows []map[string]interface{}
+	if len(stmt.Schema.PrimaryFields) == 1 {
+		column := stmt.Schema.PrimaryFields[0].DBName
+		ids := make([]interface{}, 0, len(before))
+		for _, row := range before {
+			ids = append(ids, row[column])
+		}
+		if err := query.Where(clause.IN{Column: clause.Column{Name: column}, Values: ids}).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+	} else {
+		for _, row := range before {
+			conditions := make(map[string]interface{})
+			for _, field := range stmt.Schema.PrimaryFields {
+				conditions[field.DBName] = row[field.DBName]
+			}
+			var found []map[string]interface{}
+			if err := query.Where(conditions).Find(&found).Error; err != nil {
+				return nil, err
+			}
+			rows = append(rows, found...)
+		}
+	}
+
+	result := make(map[string]map[string]interface{}, len(rows))
+	for _, row := range rows {
+		result[rowEntityID(db, row)] = row
+	}
+	return result, nil
+}
+
+func newAuditLog(db *gorm.DB, action string, entityID string, changes map[string]fieldChange) model.AuditLog {
+	actor := audit.ActorFromContext(db.Statement.Context)
+
+	data, err := json.Marshal(changes)
+	if err != nil {
+		data = []byte("{}")
+	}
+
+	return model.AuditLog{
+		ActorID:    actor.UserID,
+		Action:     action,
+		EntityType: db.Statement.Table,
+		EntityID:   entityID,
+		Changes:    string(data),
+		TraceID:    actor.TraceID,
+		IP:         actor.IP,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// writeAuditLogs uses the connection of the statement, the logs are rolled back with the write
+func writeAuditLogs(db *gorm.DB, logs []model.AuditLog) {
+	if len(logs) == 0 {
+		return
+	}
+	if err := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Create(&logs).Error; err != nil {
+		_ = db.AddError(fmt.Errorf("audit write: %w", err))
+	}
+}
+
+func structEntityID(db *gorm.DB, rv reflect.Value) string {
+	var parts []string
+	for _, field := range db.Statement.Schema.PrimaryFields {
+		value, _ := field.ValueOf(db.Statement.Context, rv)
+		parts = append(parts, fmt.Sprint(normalize(value)))
+	}
+	return strings.Join(parts, ",")
+}
+
+func rowEntityID(db *gorm.DB, row map[string]interface{}) string {
+	var parts []string
+	for _, field := range db.Statement.Schema.PrimaryFields {
+		parts = append(parts, fmt.Sprint(normalize(row[field.DBName])))
+	}
+	return strings.Join(parts, ",")
+}
+
+// eachStruct calls fn for the struct, or every struct of the slice, held by rv
+func eachStruct(rv reflect.Value, fn func(reflect.Value)) {
+	rv = reflect.Indirect(rv)
+	switch rv.Kind() {
+	case reflect.Struct:
+		fn(rv)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if elem := reflect.Indirect(rv.Index(i)); elem.Kind() == reflect.Struct {
+				fn(elem)
+			}
+		}
+	}
+}
+
+func isMasked(field *schema.Field) bool {
+	return field.Tag.Get("audit") == "-"
+}
+
+// normalize makes values read by different drivers comparable and JSON friendly
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case fmt.Stringer:
+		if _, ok := v.(time.Time); !ok {
+			return v.String()
+		}
+	}
+	return value
+}
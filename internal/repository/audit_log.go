@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+)
+
+type AuditLogRepository interface {
+	Search(ctx context.Context, req *v1.SearchAuditLogRequest) (*[]model.AuditLog, int, error)
+}
+
+func NewAuditLogRepository(
+	repository *Repository,
+) AuditLogRepository {
+	return &auditLogRepository{
+		Repository: repository,
+	}
+}
+
+type auditLogRepository struct {
+	*Repository
+}
+
+func (r *auditLogRepository) Search(ctx context.Context, req *v1.SearchAuditLogRequest) (*[]model.AuditLog, int, error) {
+	var (
+		logs      []model.AuditLog
+		totalRows int64
+	)
+
+	query := r.DB(ctx).Model(&model.AuditLog{})
+
+	// Apply filters
+	if req.EntityType != "" {
+		query = query.Where("entity_type = ?", req.EntityType)
+	}
+	if req.EntityID != "" {
+		query = query.Where("entity_id = ?", req.EntityID)
+	}
+	if req.ActorID != "" {
+		query = query.Where("actor_id = ?", req.ActorID)
+	}
+	if req.Action != "" {
+		query = query.Where("action = ?", req.Action)
+	}
+	if req.DateFrom != "" {
+		query = query.Where("created_at >= ?", req.DateFrom)
+	}
+	if req.DateTo != "" {
+		// DateTo is inclusive
+		dateTo, err := time.Parse("2006-01-02", req.DateTo)
+		if err != nil {
+			return nil, 0, err
+		}
+		query = query.Where("created_at < ?", dateTo.AddDate(0, 0, 1))
+	}
+
+	// Latest changes first
+	query = query.Order("id DESC")
+
+	// Get total count before pagination
+	if err := query.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Pagination
+	if req.Page.Page > 0 && req.Size > 0 {
+		query = query.Offset((req.Page.Page - 1) * req.Size).Limit(req.Size)
+	}
+
+	if err := query.Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return &logs, int(totalRows), nil
+}
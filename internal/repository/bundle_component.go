@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/quydmfl/niveau-test/internal/model"
+)
+
+type BundleComponentRepository interface {
+	// GetByBundle return the components of the bundle with their product, a component in the trash has none
+	GetByBundle(ctx context.Context, bundleID uuid.UUID) ([]model.BundleComponent, error)
+	// Replace saves components in place of the components of the bundle
+	Replace(ctx context.Context, bundleID uuid.UUID, components []model.BundleComponent) error
+	// CountBundles counts the bundles the product is a component of, those in the trash included
+	CountBundles(ctx context.Context, componentID uuid.UUID) (int64, error)
+}
+
+func NewBundleComponentRepository(
+	repository *Repository,
+) BundleComponentRepository {
+	return &bundleComponentRepository{
+		Repository: repository,
+	}
+}
+
+type bundleComponentRepository struct {
+	*Repository
+}
+
+func (r *bundleComponentRepository) GetByBundle(ctx context.Context, bundleID uuid.UUID) ([]model.BundleComponent, error) {
+	var components []model.BundleComponent
+	if err := orderById(r.DB(ctx)).Preload("Component").Where("bundle_id = ?", bundleID).Find(&components).Error; err != nil {
+		return nil, err
+	}
+	return components, nil
+}
+
+func (r *bundleComponentRepository) Replace(ctx context.Context, bundleID uuid.UUID, components []model.BundleComponent) error {
+	if err := r.DB(ctx).Where("bundle_id = ?", bundleID).Delete(&model.BundleComponent{}).Error; err != nil {
+		return err
+	}
+	if len(components) == 0 {
+		return nil
+	}
+	for i := range components {
+		components[i].BundleID = bundleID
+	}
+	return r.DB(ctx).Omit("Component").Create(&components).Error
+}
+
+func (r *bundleComponentRepository) CountBundles(ctx context.Context, componentID uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.DB(ctx).Model(&model.BundleComponent{}).Where("component_id = ?", componentID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
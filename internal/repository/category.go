@@ -3,17 +3,40 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	v1 "github.com/quydmfl/niveau-test/api/v1"
 	"github.com/quydmfl/niveau-test/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CategoryRepository interface {
 	Create(ctx context.Context, category *model.Category) error
 	GetCategoryById(ctx context.Context, id uuid.UUID) (*model.Category, error)
+	// GetCategoryByIdForUpdate locks the category row until the end of the surrounding Transaction
+	GetCategoryByIdForUpdate(ctx context.Context, id uuid.UUID) (*model.Category, error)
+	// Update only applies to the version of category, v1.ErrVersionMismatch is returned otherwise
+	Update(ctx context.Context, category *model.Category) error
+	// GetChildren return the subcategories of the category, with those in the trash
+	GetChildren(ctx context.Context, id uuid.UUID) ([]model.Category, error)
+	// CountChildren counts the live subcategories of the category, and those in the trash with withDeleted
+	CountChildren(ctx context.Context, id uuid.UUID, withDeleted bool) (int64, error)
+	// UpdatePaths replaces the oldPath prefix of the paths of a subtree that moved to newPath
+	UpdatePaths(ctx context.Context, oldPath string, newPath string) error
+	// Delete puts the category in the trash if it is still at its version, v1.ErrVersionMismatch is returned otherwise
+	Delete(ctx context.Context, category *model.Category) error
+	GetDeletedCategoryById(ctx context.Context, id uuid.UUID) (*model.Category, error)
+	SearchDeleted(ctx context.Context, req *v1.GetTrashRequest) (*[]model.Category, int, error)
+	GetDeletedBefore(ctx context.Context, before time.Time) ([]model.Category, error)
+	Restore(ctx context.Context, category *model.Category) error
+	Purge(ctx context.Context, category *model.Category) error
 	Search(ctx context.Context, req *v1.SearchCategoryRequest) (*[]model.Category, int, error)
+	// GetAttributes return the attribute schema of the category
+	GetAttributes(ctx context.Context, id uuid.UUID) ([]model.CategoryAttribute, error)
+	// ReplaceAttributes saves the attribute schema of category in place of the stored one
+	ReplaceAttributes(ctx context.Context, category *model.Category) error
 }
 
 func NewCategoryRepository(
@@ -48,6 +71,67 @@ func (r *categoryRepository) GetCategoryById(ctx context.Context, id uuid.UUID)
 	return &category, nil
 }
 
+func (r *categoryRepository) GetCategoryByIdForUpdate(ctx context.Context, id uuid.UUID) (*model.Category, error) {
+	var category model.Category
+
+	if err := lockForUpdate(r.DB(ctx)).Where("id = ?", id).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+func (r *categoryRepository) Update(ctx context.Context, category *model.Category) error {
+	version := category.Version
+	category.Version++
+
+	// Subcategories in the trash move with their parent too
+	result := r.DB(ctx).Unscoped().Model(category).Where("version = ?", version).
+		Select("*").Omit(clause.Associations, "deleted_at", "created_at").Updates(category)
+	if result.Error != nil {
+		category.Version = version
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		category.Version = version
+		return v1.ErrVersionMismatch
+	}
+	return nil
+}
+
+func (r *categoryRepository) GetChildren(ctx context.Context, id uuid.UUID) ([]model.Category, error) {
+	var categories []model.Category
+	if err := r.DB(ctx).Unscoped().Where("parent_id = ?", id).Order("name").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (r *categoryRepository) CountChildren(ctx context.Context, id uuid.UUID, withDeleted bool) (int64, error) {
+	var count int64
+	query := r.DB(ctx).Model(&model.Category{})
+	if withDeleted {
+		query = query.Unscoped()
+	}
+	if err := query.Where("parent_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *categoryRepository) UpdatePaths(ctx context.Context, oldPath string, newPath string) error {
+	db := r.DB(ctx)
+	return db.Unscoped().Model(&model.Category{}).
+		Where("path LIKE ?", oldPath+"%").
+		Updates(map[string]interface{}{
+			"path":    gorm.Expr(concat(db, "?", "SUBSTR(path, ?)"), newPath, len(oldPath)+1),
+			"version": gorm.Expr("version + 1"),
+		}).Error
+}
+
 func (r *categoryRepository) Search(ctx context.Context, req *v1.SearchCategoryRequest) (*[]model.Category, int, error) {
 	var (
 		categories []model.Category
@@ -65,6 +149,10 @@ func (r *categoryRepository) Search(ctx context.Context, req *v1.SearchCategoryR
 		query = query.Where("status = ?", req.Status)
 	}
 
+	if req.ParentId != "" {
+		query = query.Where("parent_id = ?", req.ParentId)
+	}
+
 	// Apply sorting
 	query = query.Order(req.SortBy + " " + req.SortOrder)
 
@@ -85,3 +173,67 @@ func (r *categoryRepository) Search(ctx context.Context, req *v1.SearchCategoryR
 
 	return &categories, int(totalRows), nil
 }
+
+func (r *categoryRepository) Delete(ctx context.Context, category *model.Category) error {
+	return softDelete(r.DB(ctx), category, category.Version)
+}
+
+func (r *categoryRepository) GetDeletedCategoryById(ctx context.Context, id uuid.UUID) (*model.Category, error) {
+	var category model.Category
+	if err := firstDeleted(r.DB(ctx).Where("id = ?", id), &category); err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) SearchDeleted(ctx context.Context, req *v1.GetTrashRequest) (*[]model.Category, int, error) {
+	var categories []model.Category
+	totalRows, err := searchDeleted(r.DB(ctx).Model(&model.Category{}), &categories, req.Page)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &categories, totalRows, nil
+}
+
+func (r *categoryRepository) GetDeletedBefore(ctx context.Context, before time.Time) ([]model.Category, error) {
+	var categories []model.Category
+	if err := deletedBefore(r.DB(ctx).Model(&model.Category{}), &categories, before); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (r *categoryRepository) Restore(ctx context.Context, category *model.Category) error {
+	if err := restore(r.DB(ctx), category); err != nil {
+		return err
+	}
+	category.DeletedAt = gorm.DeletedAt{}
+	category.Version++
+	return nil
+}
+
+func (r *categoryRepository) Purge(ctx context.Context, category *model.Category) error {
+	return purge(r.DB(ctx), category)
+}
+
+func (r *categoryRepository) GetAttributes(ctx context.Context, id uuid.UUID) ([]model.CategoryAttribute, error) {
+	var attributes []model.CategoryAttribute
+	if err := orderById(r.DB(ctx)).Where("category_id = ?", id).Find(&attributes).Error; err != nil {
+		return nil, err
+	}
+	return attributes, nil
+}
+
+func (r *categoryRepository) ReplaceAttributes(ctx context.Context, category *model.Category) error {
+	if err := r.DB(ctx).Where("category_id = ?", category.ID).Delete(&model.CategoryAttribute{}).Error; err != nil {
+		return err
+	}
+	if len(category.Attributes) == 0 {
+		return nil
+	}
+	for i := range category.Attributes {
+		category.Attributes[i].ID = 0
+		category.Attributes[i].CategoryID = category.ID
+	}
+	return r.DB(ctx).Create(&category.Attributes).Error
+}
@@ -2,12 +2,20 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/quydmfl/niveau-test/internal/model"
 )
 
 type DocumentsRepository interface {
 	Create(ctx context.Context, document *model.Documents) error
+	GetAll(ctx context.Context) ([]model.Documents, error)
+	GetExpired(ctx context.Context, kind string, before time.Time) ([]model.Documents, error)
+	GetDetached(ctx context.Context, before time.Time) ([]model.Documents, error)
+	DeleteByIds(ctx context.Context, ids []uuid.UUID) error
+	MarkMissing(ctx context.Context, ids []uuid.UUID, at time.Time) error
+	ClearMissing(ctx context.Context, ids []uuid.UUID) error
 }
 
 func NewDocumentsRepository(
@@ -28,3 +36,60 @@ func (r *documentsRepository) Create(ctx context.Context, document *model.Docume
 	}
 	return nil
 }
+
+func (r *documentsRepository) GetAll(ctx context.Context) ([]model.Documents, error) {
+	var documents []model.Documents
+	if err := r.DB(ctx).Order("uploaded_at").Find(&documents).Error; err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+// GetExpired return the documents of kind uploaded before the given time
+func (r *documentsRepository) GetExpired(ctx context.Context, kind string, before time.Time) ([]model.Documents, error) {
+	var documents []model.Documents
+	if err := r.DB(ctx).
+		Where("kind = ? AND uploaded_at < ?", kind, before).
+		Order("uploaded_at").
+		Find(&documents).Error; err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+// GetDetached return the documents whose product or purchase order was deleted before the given time
+func (r *documentsRepository) GetDetached(ctx context.Context, before time.Time) ([]model.Documents, error) {
+	var documents []model.Documents
+	if err := r.DB(ctx).
+		Where("product_id IS NULL AND purchase_order_id IS NULL AND uploaded_at < ?", before).
+		Order("uploaded_at").
+		Find(&documents).Error; err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+func (r *documentsRepository) DeleteByIds(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.DB(ctx).Where("id IN ?", ids).Delete(&model.Documents{}).Error
+}
+
+func (r *documentsRepository) MarkMissing(ctx context.Context, ids []uuid.UUID, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.DB(ctx).Model(&model.Documents{}).
+		Where("id IN ? AND missing_at IS NULL", ids).
+		Update("missing_at", at).Error
+}
+
+func (r *documentsRepository) ClearMissing(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.DB(ctx).Model(&model.Documents{}).
+		Where("id IN ?", ids).
+		Update("missing_at", nil).Error
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/quydmfl/niveau-test/internal/model"
+	"gorm.io/gorm"
+)
+
+type OutboxRepository interface {
+	// Create must be called with the ctx of the surrounding Transaction, so the event is only stored if it commits
+	Create(ctx context.Context, event *model.OutboxEvent) error
+	FetchPending(ctx context.Context, limit int) ([]model.OutboxEvent, error)
+	MarkPublished(ctx context.Context, ids []uint64, publishedAt time.Time) error
+	MarkFailed(ctx context.Context, id uint64, reason string) error
+	DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+func NewOutboxRepository(
+	repository *Repository,
+) OutboxRepository {
+	return &outboxRepository{
+		Repository: repository,
+	}
+}
+
+type outboxRepository struct {
+	*Repository
+}
+
+func (r *outboxRepository) Create(ctx context.Context, event *model.OutboxEvent) error {
+	if err := r.DB(ctx).Create(event).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// FetchPending return the oldest unpublished events, those of the aggregates whose head failed to publish come
+// after all the others so the blocked aggregates can't fill the batch and hold back the rest.
+// Inside a Transaction the rows are locked, so concurrent relays are serialized and per-aggregate order holds.
+func (r *outboxRepository) FetchPending(ctx context.Context, limit int) ([]model.OutboxEvent, error) {
+	var events []model.OutboxEvent
+
+	query := lockForUpdate(r.DB(ctx)).Where("published_at IS NULL").
+		Order("CASE WHEN EXISTS (SELECT 1 FROM outbox_events head WHERE head.published_at IS NULL AND head.attempts > 0 " +
+			"AND head.aggregate_type = outbox_events.aggregate_type AND head.aggregate_id = outbox_events.aggregate_id) " +
+			"THEN 1 ELSE 0 END").
+		Order("id ASC").Limit(limit)
+	if err := query.Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *outboxRepository) MarkPublished(ctx context.Context, ids []uint64, publishedAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return r.DB(ctx).Model(&model.OutboxEvent{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{"published_at": publishedAt, "last_error": ""}).Error
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, id uint64, reason string) error {
+	return r.DB(ctx).Model(&model.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"attempts": gorm.Expr("attempts + 1"), "last_error": reason}).Error
+}
+
+func (r *outboxRepository) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.DB(ctx).Where("published_at IS NOT NULL AND published_at < ?", before).Delete(&model.OutboxEvent{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
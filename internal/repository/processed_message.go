@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/quydmfl/niveau-test/internal/model"
+	"gorm.io/gorm"
+)
+
+type ProcessedMessageRepository interface {
+	Exists(ctx context.Context, consumer string, messageID string) (bool, error)
+	Create(ctx context.Context, message *model.ProcessedMessage) error
+}
+
+func NewProcessedMessageRepository(
+	repository *Repository,
+) ProcessedMessageRepository {
+	return &processedMessageRepository{
+		Repository: repository,
+	}
+}
+
+type processedMessageRepository struct {
+	*Repository
+}
+
+func (r *processedMessageRepository) Exists(ctx context.Context, consumer string, messageID string) (bool, error) {
+	var message model.ProcessedMessage
+	if err := r.DB(ctx).Where("consumer = ? AND message_id = ?", consumer, messageID).First(&message).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *processedMessageRepository) Create(ctx context.Context, message *model.ProcessedMessage) error {
+	if err := r.DB(ctx).Create(message).Error; err != nil {
+		return err
+	}
+	return nil
+}
@@ -3,22 +3,71 @@ package repository
 import (
 	"context"
 	"errors"
-	"fmt"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/helper"
 	"github.com/quydmfl/niveau-test/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ProductRepository interface {
 	Create(ctx context.Context, product *model.Product) error
 	Update(ctx context.Context, product *model.Product) error
-	Delete(ctx context.Context, id string) error
+	// Update and Delete only apply to the version of product, v1.ErrVersionMismatch is returned otherwise.
+	// Delete puts the product in the trash.
+	Delete(ctx context.Context, product *model.Product) error
 	GetProductByPref(ctx context.Context, id string) (*model.Product, error)
+	GetProductByPrefForUpdate(ctx context.Context, id string) (*model.Product, error)
+	// ReferenceExists tells if a product has the reference, a product in the trash keeps its reference
+	ReferenceExists(ctx context.Context, reference string) (bool, error)
+	// GetProductByGTIN finds the product of the GTIN however it is written, a product in the trash only withDeleted
+	GetProductByGTIN(ctx context.Context, gtin string, withDeleted bool) (*model.Product, error)
+	// GetLiveReferences return the references of the live products among references
+	GetLiveReferences(ctx context.Context, references []string) ([]string, error)
+	UpdateStock(ctx context.Context, product *model.Product) error
+	GetProductByIdForUpdate(ctx context.Context, id uuid.UUID) (*model.Product, error)
+	UpdatePrice(ctx context.Context, product *model.Product) error
 	Search(ctx context.Context, req *v1.SearchProductRequest) (*[]model.Product, int, error)
 	SumQuantityProducts(ctx context.Context) (int64, error)
 	StatsProductsPerCategory(ctx context.Context, totalQuantity int64) ([]v1.ProductCategoryStatsResponse, error)
 	StatsProductsPerSupplier(ctx context.Context, totalQuantity int64) ([]v1.ProductSupplierStatsResponse, error)
+	// CountByCategory counts the live products of the category, and those in the trash with withDeleted
+	CountByCategory(ctx context.Context, categoryID uuid.UUID, withDeleted bool) (int64, error)
+	GetDeletedProductByPref(ctx context.Context, id string) (*model.Product, error)
+	SearchDeleted(ctx context.Context, req *v1.GetTrashRequest) (*[]model.Product, int, error)
+	GetDeletedBefore(ctx context.Context, before time.Time) ([]model.Product, error)
+	Restore(ctx context.Context, product *model.Product) error
+	Purge(ctx context.Context, product *model.Product) error
+	// GetByCategoryForUpdate locks the live products of the category until the end of the surrounding Transaction
+	GetByCategoryForUpdate(ctx context.Context, categoryID uuid.UUID) ([]model.Product, error)
+	// MoveCategory moves every product of a category to another, those in the trash included
+	MoveCategory(ctx context.Context, from uuid.UUID, to uuid.UUID) (int64, error)
+	// PurgeByCategory deletes for good the products of the category that are in the trash
+	PurgeByCategory(ctx context.Context, categoryID uuid.UUID) error
+	// GetBySupplierForUpdate locks the live products of the supplier until the end of the surrounding Transaction
+	GetBySupplierForUpdate(ctx context.Context, supplierID uuid.UUID) ([]model.Product, error)
+	// MoveSupplier moves every product of a supplier to another, those in the trash included
+	MoveSupplier(ctx context.Context, from uuid.UUID, to uuid.UUID) (int64, error)
+	StatsSupplierStock(ctx context.Context, supplierID uuid.UUID) (*v1.SupplierStockData, error)
+	// ReplaceAttributes saves the attribute values of product in place of the stored ones
+	ReplaceAttributes(ctx context.Context, product *model.Product) error
+	// GetVariantsForUpdate locks the live variants of the product until the end of the surrounding Transaction
+	GetVariantsForUpdate(ctx context.Context, parentID uuid.UUID) ([]model.Product, error)
+	// CountVariants counts the live variants of the product, and those in the trash with withDeleted
+	CountVariants(ctx context.Context, parentID uuid.UUID, withDeleted bool) (int64, error)
+	// MoveVariants gives the category and supplier of product to its variants, those in the trash included
+	MoveVariants(ctx context.Context, product *model.Product) error
+	// SumVariantQuantities return the number and total quantity of the live variants of each product
+	SumVariantQuantities(ctx context.Context, parentIDs []uuid.UUID) (map[uuid.UUID]VariantStock, error)
+}
+
+type VariantStock struct {
+	Count    int
+	Quantity int
 }
 
 func NewProductRepository(
@@ -41,41 +90,107 @@ func (r *productRepository) Create(ctx context.Context, product *model.Product)
 }
 
 func (r *productRepository) Update(ctx context.Context, product *model.Product) error {
-	if err := r.DB(ctx).Save(product).Error; err != nil {
+	version := product.Version
+	product.Version++
+
+	result := r.DB(ctx).Model(product).Where("version = ?", version).Select("*").Omit(clause.Associations).Updates(product)
+	if result.Error != nil {
+		product.Version = version
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		product.Version = version
+		return v1.ErrVersionMismatch
+	}
+	return nil
+}
+
+func (r *productRepository) Delete(ctx context.Context, product *model.Product) error {
+	return softDelete(r.DB(ctx), product, product.Version)
+}
+
+// updateColumns writes columns of product and increments its version, stock and price changes
+// are serialized by the row lock so they don't check the version
+func (r *productRepository) updateColumns(ctx context.Context, product *model.Product, columns map[string]interface{}) error {
+	columns["version"] = gorm.Expr("version + 1")
+	if err := r.DB(ctx).Model(product).Updates(columns).Error; err != nil {
 		return err
 	}
+	product.Version++
 	return nil
 }
 
-func (r *productRepository) Delete(ctx context.Context, id string) error {
-	db := r.DB(ctx)
+func (r *productRepository) GetProductByPref(ctx context.Context, id string) (*model.Product, error) {
+	var product model.Product
+	if err := r.DB(ctx).Preload("Category", unscoped).Preload("Supplier", unscoped).Preload("Attributes", orderById).
+		Preload("Parent", unscoped).Preload("Variants", orderByReference).Preload("Images", orderByPosition).
+		Preload("Components", orderById).Preload("Components.Component").Where("reference = ?", id).First(&product).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &product, nil
+}
 
-	// Check if product exists
+// GetProductByPrefForUpdate locks the product row until the end of the surrounding Transaction
+func (r *productRepository) GetProductByPrefForUpdate(ctx context.Context, id string) (*model.Product, error) {
 	var product model.Product
-	if err := db.Where("reference = ?", id).First(&product).Error; err != nil {
+	if err := lockForUpdate(r.DB(ctx)).Preload("Attributes", orderById).Where("reference = ?", id).First(&product).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return v1.ErrNotFound
+			return nil, v1.ErrNotFound
 		}
-		return err
+		return nil, err
 	}
 
-	// Perform delete
-	result := db.Where("reference = ?", id).Delete(&model.Product{})
-	if result.Error != nil {
-		return result.Error
+	return &product, nil
+}
+
+func (r *productRepository) ReferenceExists(ctx context.Context, reference string) (bool, error) {
+	var count int64
+	if err := r.DB(ctx).Unscoped().Model(&model.Product{}).Where("reference = ?", reference).Count(&count).Error; err != nil {
+		return false, err
 	}
+	return count > 0, nil
+}
 
-	// Check if rows were affected
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("no product deleted, reference %s may not exist", id)
+func (r *productRepository) GetProductByGTIN(ctx context.Context, gtin string, withDeleted bool) (*model.Product, error) {
+	db := r.DB(ctx)
+	if withDeleted {
+		db = db.Unscoped()
 	}
 
-	return nil
+	var product model.Product
+	if err := db.Where("gtin IN ?", helper.GTINForms(gtin)).First(&product).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &product, nil
 }
 
-func (r *productRepository) GetProductByPref(ctx context.Context, id string) (*model.Product, error) {
+func (r *productRepository) GetLiveReferences(ctx context.Context, references []string) ([]string, error) {
+	var live []string
+	if len(references) == 0 {
+		return live, nil
+	}
+	if err := r.DB(ctx).Model(&model.Product{}).Where("reference IN ?", references).Pluck("reference", &live).Error; err != nil {
+		return nil, err
+	}
+	return live, nil
+}
+
+func (r *productRepository) UpdateStock(ctx context.Context, product *model.Product) error {
+	return r.updateColumns(ctx, product, map[string]interface{}{"quantity": product.Quantity, "status": product.Status})
+}
+
+// GetProductByIdForUpdate locks the product row until the end of the surrounding Transaction
+func (r *productRepository) GetProductByIdForUpdate(ctx context.Context, id uuid.UUID) (*model.Product, error) {
 	var product model.Product
-	if err := r.DB(ctx).Preload("Category").Preload("Supplier").Where("reference = ?", id).First(&product).Error; err != nil {
+	if err := lockForUpdate(r.DB(ctx)).Preload("Attributes", orderById).Where("id = ?", id).First(&product).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, v1.ErrNotFound
 		}
@@ -85,22 +200,35 @@ func (r *productRepository) GetProductByPref(ctx context.Context, id string) (*m
 	return &product, nil
 }
 
+func (r *productRepository) UpdatePrice(ctx context.Context, product *model.Product) error {
+	return r.updateColumns(ctx, product, map[string]interface{}{"price": product.Price})
+}
+
 func (r *productRepository) Search(ctx context.Context, req *v1.SearchProductRequest) (*[]model.Product, int, error) {
 	var (
 		products  []model.Product
 		totalRows int64
 	)
 
-	query := r.DB(ctx).Model(&model.Product{})
+	query, priceColumn, err := joinPrices(r.DB(ctx).Model(&model.Product{}), req.PriceAt)
+	if err != nil {
+		return nil, 0, err
+	}
 
 	// Apply filters
 	if req.Reference != "" {
 		query = query.Where("reference = ?", req.Reference)
 	}
+	if req.GTIN != "" {
+		query = query.Where("gtin IN ?", helper.GTINForms(req.GTIN))
+	}
 	if req.ProductName != "" {
 		query = query.Where("product_name ILIKE ?", "%"+req.ProductName+"%")
 	}
-	if req.CategoryId != "" {
+	if req.CategoryId != "" && req.IncludeSubcategories {
+		query = query.Where("category_id IN (?)", r.DB(ctx).Model(&model.Category{}).Select("id").
+			Where("path LIKE (?)", r.DB(ctx).Model(&model.Category{}).Select(concat(r.DB(ctx), "path", "'%'")).Where("id = ?", req.CategoryId)))
+	} else if req.CategoryId != "" {
 		query = query.Where("category_id = ?", req.CategoryId)
 	}
 	if req.SupplierId != "" {
@@ -110,10 +238,10 @@ func (r *productRepository) Search(ctx context.Context, req *v1.SearchProductReq
 		query = query.Where("stock_location_id = ?", req.StockLocationId)
 	}
 	if req.MinPrice > 0 {
-		query = query.Where("price >= ?", req.MinPrice)
+		query = query.Where(priceColumn+" >= ?", req.MinPrice)
 	}
 	if req.MaxPrice > 0 {
-		query = query.Where("price <= ?", req.MaxPrice)
+		query = query.Where(priceColumn+" <= ?", req.MaxPrice)
 	}
 	if req.DateAddedFrom != "" {
 		query = query.Where("added_date >= ?", req.DateAddedFrom)
@@ -123,10 +251,30 @@ func (r *productRepository) Search(ctx context.Context, req *v1.SearchProductReq
 	}
 	if req.Status != "" {
 		query = query.Where("status = ?", req.Status)
+	} else if !req.IncludeArchived {
+		query = query.Where("products.status <> ?", model.ProductArchived)
+	}
+	for _, filter := range req.Attributes {
+		name, value, _ := strings.Cut(filter, ":")
+		query = query.Where("EXISTS (?)", r.DB(ctx).Model(&model.ProductAttribute{}).Select("1").
+			Where("product_attributes.product_id = products.id AND product_attributes.name = ? AND product_attributes.value = ?", name, value))
+	}
+
+	// A parent is listed in place of its variants when it or one of them matches the filters
+	if req.CollapseVariants {
+		matches := query.Select("COALESCE(products.parent_id, products.id)")
+		if query, priceColumn, err = joinPrices(r.DB(ctx).Model(&model.Product{}), req.PriceAt); err != nil {
+			return nil, 0, err
+		}
+		query = query.Where("products.id IN (?)", matches)
 	}
 
 	// Apply sorting
-	query = query.Order(req.SortBy + " " + req.SortOrder)
+	sortColumn := "products." + req.SortBy
+	if req.SortBy == "price" {
+		sortColumn = priceColumn
+	}
+	query = query.Order(sortColumn + " " + req.SortOrder)
 
 	// Get total count before pagination
 	if err := query.Count(&totalRows).Error; err != nil {
@@ -139,12 +287,30 @@ func (r *productRepository) Search(ctx context.Context, req *v1.SearchProductReq
 	}
 
 	// Fetch results
-	if err := query.Preload("Category").Preload("Supplier").Find(&products).Error; err != nil {
+	if err := query.Select("products.*").Preload("Category", unscoped).Preload("Supplier", unscoped).Preload("Attributes", orderById).
+		Preload("Parent", unscoped).Preload("Images", orderByPosition).Preload("Components", orderById).Preload("Components.Component").
+		Find(&products).Error; err != nil {
 		return nil, 0, err
 	}
 	return &products, int(totalRows), nil
 }
 
+// joinPrices makes the price filters and sorting use the price in effect at priceAt, products without history
+// keep their price. It return the price column to use.
+func joinPrices(query *gorm.DB, priceAt string) (*gorm.DB, string, error) {
+	if priceAt == "" {
+		return query, "products.price", nil
+	}
+	at, err := time.Parse("2006-01-02", priceAt)
+	if err != nil {
+		return nil, "", err
+	}
+	query = query.Joins("LEFT JOIN product_prices ON product_prices.product_id = products.id"+
+		" AND product_prices.effective_from <= ?"+
+		" AND (product_prices.effective_to IS NULL OR product_prices.effective_to > ?)", at, at)
+	return query, "COALESCE(product_prices.price, products.price)", nil
+}
+
 func (r *productRepository) SumQuantityProducts(ctx context.Context) (int64, error) {
 	var count int64
 	if err := r.DB(ctx).Model(&model.Product{}).Select("SUM(quantity)").Scan(&count).Error; err != nil {
@@ -155,10 +321,17 @@ func (r *productRepository) SumQuantityProducts(ctx context.Context) (int64, err
 
 func (r *productRepository) StatsProductsPerCategory(ctx context.Context, totalQuantity int64) ([]v1.ProductCategoryStatsResponse, error) {
 	var categoryStats []v1.ProductCategoryStatsResponse
-	if err := r.DB(ctx).Table("products").
-		Select("product_categories.id as category_id, product_categories.name as category_name, ROUND((SUM(products.quantity) * 100.0 / ?)) as percentage", totalQuantity).
-		Joins("JOIN product_categories ON product_categories.id = products.category_id").
-		Group("product_categories.id, product_categories.name").
+	// Each category is joined to its subtree, the products of the subcategories are rolled up into it
+	if err := r.DB(ctx).Table("product_categories").
+		Select("product_categories.id as category_id, product_categories.name as category_name, product_categories.parent_id as parent_id, "+
+			"ROUND((SUM(products.quantity) * 100.0 / ?)) as percentage, "+
+			"ROUND((SUM(CASE WHEN subtree.id = product_categories.id THEN products.quantity ELSE 0 END) * 100.0 / ?)) as own_percentage",
+			totalQuantity, totalQuantity).
+		Joins("JOIN product_categories subtree ON subtree.path LIKE " + concat(r.DB(ctx), "product_categories.path", "'%'")).
+		Joins("JOIN products ON products.category_id = subtree.id").
+		Where("products.deleted_at IS NULL").
+		Group("product_categories.id, product_categories.name, product_categories.parent_id, product_categories.path").
+		Order("product_categories.path").
 		Scan(&categoryStats).Error; err != nil {
 		return nil, err
 	}
@@ -171,6 +344,7 @@ func (r *productRepository) StatsProductsPerSupplier(ctx context.Context, totalQ
 	if err := r.DB(ctx).Table("products").
 		Select("suppliers.id as supplier_id, suppliers.name as supplier_name, ROUND((SUM(products.quantity) * 100.0 / ?)) as percentage", totalQuantity).
 		Joins("JOIN suppliers ON suppliers.id = products.supplier_id").
+		Where("products.deleted_at IS NULL").
 		Group("suppliers.id, suppliers.name").
 		Scan(&supplierStats).Error; err != nil {
 		return nil, err
@@ -178,3 +352,176 @@ func (r *productRepository) StatsProductsPerSupplier(ctx context.Context, totalQ
 
 	return supplierStats, nil
 }
+
+func (r *productRepository) CountByCategory(ctx context.Context, categoryID uuid.UUID, withDeleted bool) (int64, error) {
+	var count int64
+	query := r.DB(ctx).Model(&model.Product{})
+	if withDeleted {
+		query = query.Unscoped()
+	}
+	if err := query.Where("category_id = ?", categoryID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *productRepository) GetDeletedProductByPref(ctx context.Context, id string) (*model.Product, error) {
+	var product model.Product
+	if err := firstDeleted(r.DB(ctx).Where("reference = ?", id), &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *productRepository) SearchDeleted(ctx context.Context, req *v1.GetTrashRequest) (*[]model.Product, int, error) {
+	var products []model.Product
+	totalRows, err := searchDeleted(r.DB(ctx).Model(&model.Product{}), &products, req.Page)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &products, totalRows, nil
+}
+
+func (r *productRepository) GetDeletedBefore(ctx context.Context, before time.Time) ([]model.Product, error) {
+	var products []model.Product
+	if err := deletedBefore(r.DB(ctx).Model(&model.Product{}), &products, before); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *productRepository) Restore(ctx context.Context, product *model.Product) error {
+	if err := restore(r.DB(ctx), product); err != nil {
+		return err
+	}
+	product.DeletedAt = gorm.DeletedAt{}
+	product.Version++
+	return nil
+}
+
+func (r *productRepository) Purge(ctx context.Context, product *model.Product) error {
+	return purge(r.DB(ctx), product)
+}
+
+func (r *productRepository) PurgeByCategory(ctx context.Context, categoryID uuid.UUID) error {
+	return r.DB(ctx).Unscoped().Where("category_id = ? AND deleted_at IS NOT NULL", categoryID).Delete(&model.Product{}).Error
+}
+
+func (r *productRepository) GetByCategoryForUpdate(ctx context.Context, categoryID uuid.UUID) ([]model.Product, error) {
+	var products []model.Product
+	if err := lockForUpdate(r.DB(ctx)).Preload("Attributes", orderById).Where("category_id = ?", categoryID).Order("reference").Find(&products).Error; err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *productRepository) MoveCategory(ctx context.Context, from uuid.UUID, to uuid.UUID) (int64, error) {
+	result := r.DB(ctx).Unscoped().Model(&model.Product{}).Where("category_id = ?", from).Updates(map[string]interface{}{
+		"category_id": to,
+		"version":     gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *productRepository) GetBySupplierForUpdate(ctx context.Context, supplierID uuid.UUID) ([]model.Product, error) {
+	var products []model.Product
+	if err := lockForUpdate(r.DB(ctx)).Preload("Attributes", orderById).Where("supplier_id = ?", supplierID).Order("reference").Find(&products).Error; err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *productRepository) MoveSupplier(ctx context.Context, from uuid.UUID, to uuid.UUID) (int64, error) {
+	result := r.DB(ctx).Unscoped().Model(&model.Product{}).Where("supplier_id = ?", from).Updates(map[string]interface{}{
+		"supplier_id": to,
+		"version":     gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *productRepository) StatsSupplierStock(ctx context.Context, supplierID uuid.UUID) (*v1.SupplierStockData, error) {
+	var stock v1.SupplierStockData
+	if err := r.DB(ctx).Model(&model.Product{}).
+		Select("COUNT(*) as product_count, COALESCE(SUM(quantity), 0) as quantity, COALESCE(SUM(quantity * price), 0) as stock_value").
+		Where("supplier_id = ?", supplierID).
+		Scan(&stock).Error; err != nil {
+		return nil, err
+	}
+	return &stock, nil
+}
+
+func (r *productRepository) ReplaceAttributes(ctx context.Context, product *model.Product) error {
+	if err := r.DB(ctx).Where("product_id = ?", product.ID).Delete(&model.ProductAttribute{}).Error; err != nil {
+		return err
+	}
+	if len(product.Attributes) == 0 {
+		return nil
+	}
+	for i := range product.Attributes {
+		product.Attributes[i].ID = 0
+		product.Attributes[i].ProductID = product.ID
+	}
+	return r.DB(ctx).Create(&product.Attributes).Error
+}
+
+func (r *productRepository) GetVariantsForUpdate(ctx context.Context, parentID uuid.UUID) ([]model.Product, error) {
+	var products []model.Product
+	if err := lockForUpdate(r.DB(ctx)).Preload("Attributes", orderById).Where("parent_id = ?", parentID).Order("reference").Find(&products).Error; err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *productRepository) CountVariants(ctx context.Context, parentID uuid.UUID, withDeleted bool) (int64, error) {
+	var count int64
+	query := r.DB(ctx).Model(&model.Product{})
+	if withDeleted {
+		query = query.Unscoped()
+	}
+	if err := query.Where("parent_id = ?", parentID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *productRepository) MoveVariants(ctx context.Context, product *model.Product) error {
+	return r.DB(ctx).Unscoped().Model(&model.Product{}).Where("parent_id = ?", product.ID).Updates(map[string]interface{}{
+		"category_id": product.CategoryID,
+		"supplier_id": product.SupplierID,
+		"version":     gorm.Expr("version + 1"),
+	}).Error
+}
+
+func (r *productRepository) SumVariantQuantities(ctx context.Context, parentIDs []uuid.UUID) (map[uuid.UUID]VariantStock, error) {
+	stocks := make(map[uuid.UUID]VariantStock)
+	if len(parentIDs) == 0 {
+		return stocks, nil
+	}
+
+	var rows []struct {
+		ParentID uuid.UUID
+		Count    int
+		Quantity int
+	}
+	if err := r.DB(ctx).Model(&model.Product{}).
+		Select("parent_id, COUNT(*) as count, COALESCE(SUM(quantity), 0) as quantity").
+		Where("parent_id IN ?", parentIDs).
+		Group("parent_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		stocks[row.ParentID] = VariantStock{Count: row.Count, Quantity: row.Quantity}
+	}
+	return stocks, nil
+}
+
+func orderByReference(db *gorm.DB) *gorm.DB {
+	return db.Order("reference")
+}
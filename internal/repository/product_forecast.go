@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"gorm.io/gorm"
+)
+
+type ProductForecastRepository interface {
+	// GetProductsToForecast return the live products with their supplier, even one in the trash.
+	// Archived products and bundles, which have no stock of their own, are left out.
+	GetProductsToForecast(ctx context.Context) ([]model.Product, error)
+	// Replace saves forecasts in place of all the stored ones
+	Replace(ctx context.Context, forecasts []model.ProductForecast) error
+	GetByProduct(ctx context.Context, productID uuid.UUID) (*model.ProductForecast, error)
+	// Search return the forecasts with the largest reorder first, all of them without a page
+	Search(ctx context.Context, req *v1.SearchForecastRequest) (*[]model.ProductForecast, int, error)
+}
+
+func NewProductForecastRepository(
+	repository *Repository,
+) ProductForecastRepository {
+	return &productForecastRepository{
+		Repository: repository,
+	}
+}
+
+type productForecastRepository struct {
+	*Repository
+}
+
+func (r *productForecastRepository) GetProductsToForecast(ctx context.Context) ([]model.Product, error) {
+	var products []model.Product
+	if err := orderByReference(r.DB(ctx).Model(&model.Product{})).Preload("Supplier", unscoped).
+		Where("products.status <> ?", model.ProductArchived).
+		Where("NOT EXISTS (?)", r.DB(ctx).Model(&model.BundleComponent{}).Select("1").
+			Where("product_bundle_components.bundle_id = products.id")).
+		Find(&products).Error; err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *productForecastRepository) Replace(ctx context.Context, forecasts []model.ProductForecast) error {
+	return r.Transaction(ctx, func(ctx context.Context) error {
+		if err := r.DB(ctx).Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&model.ProductForecast{}).Error; err != nil {
+			return err
+		}
+		if len(forecasts) == 0 {
+			return nil
+		}
+		return r.DB(ctx).Omit("Product").CreateInBatches(forecasts, 100).Error
+	})
+}
+
+func (r *productForecastRepository) GetByProduct(ctx context.Context, productID uuid.UUID) (*model.ProductForecast, error) {
+	var forecast model.ProductForecast
+
+	if err := r.DB(ctx).Where("product_id = ?", productID).First(&forecast).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &forecast, nil
+}
+
+func (r *productForecastRepository) Search(ctx context.Context, req *v1.SearchForecastRequest) (*[]model.ProductForecast, int, error) {
+	var (
+		forecasts []model.ProductForecast
+		totalRows int64
+	)
+
+	query := r.DB(ctx).Model(&model.ProductForecast{})
+
+	// Apply filters
+	if req.SupplierId != "" {
+		query = query.Where("supplier_id = ?", req.SupplierId)
+	}
+	if req.ReorderOnly {
+		query = query.Where("reorder_quantity > 0")
+	}
+
+	// Get total count before pagination
+	if err := query.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Pagination
+	if req.Page.Page > 0 && req.Size > 0 {
+		query = query.Offset((req.Page.Page - 1) * req.Size).Limit(req.Size)
+	}
+
+	if err := query.Order("reorder_quantity DESC").Order("reference").Find(&forecasts).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return &forecasts, int(totalRows), nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"gorm.io/gorm"
+)
+
+type ProductImageRepository interface {
+	Create(ctx context.Context, image *model.ProductImage) error
+	// GetByProduct return the images of the product ordered by position
+	GetByProduct(ctx context.Context, productID uuid.UUID) ([]model.ProductImage, error)
+	GetById(ctx context.Context, productID uuid.UUID, id uuid.UUID) (*model.ProductImage, error)
+	// GetByProductWithVariants return the images of the product and of its variants, purging the product deletes them all
+	GetByProductWithVariants(ctx context.Context, productID uuid.UUID) ([]model.ProductImage, error)
+	// UpdatePositions saves the position of each image
+	UpdatePositions(ctx context.Context, images []model.ProductImage) error
+	Delete(ctx context.Context, image *model.ProductImage) error
+}
+
+func NewProductImageRepository(
+	repository *Repository,
+) ProductImageRepository {
+	return &productImageRepository{
+		Repository: repository,
+	}
+}
+
+type productImageRepository struct {
+	*Repository
+}
+
+func (r *productImageRepository) Create(ctx context.Context, image *model.ProductImage) error {
+	if err := r.DB(ctx).Create(image).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *productImageRepository) GetByProduct(ctx context.Context, productID uuid.UUID) ([]model.ProductImage, error) {
+	var images []model.ProductImage
+	if err := orderByPosition(r.DB(ctx)).Where("product_id = ?", productID).Find(&images).Error; err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+func (r *productImageRepository) GetById(ctx context.Context, productID uuid.UUID, id uuid.UUID) (*model.ProductImage, error) {
+	var image model.ProductImage
+	if err := r.DB(ctx).Where("product_id = ? AND id = ?", productID, id).First(&image).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+	return &image, nil
+}
+
+func (r *productImageRepository) GetByProductWithVariants(ctx context.Context, productID uuid.UUID) ([]model.ProductImage, error) {
+	var images []model.ProductImage
+	variants := r.DB(ctx).Unscoped().Model(&model.Product{}).Select("id").Where("parent_id = ?", productID)
+	if err := r.DB(ctx).Where("product_id = ? OR product_id IN (?)", productID, variants).Find(&images).Error; err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+func (r *productImageRepository) UpdatePositions(ctx context.Context, images []model.ProductImage) error {
+	for i := range images {
+		if err := r.DB(ctx).Model(&images[i]).Update("position", images[i].Position).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *productImageRepository) Delete(ctx context.Context, image *model.ProductImage) error {
+	return r.DB(ctx).Delete(image).Error
+}
+
+func orderByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("position, uploaded_at")
+}
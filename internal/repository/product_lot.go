@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"gorm.io/gorm"
+)
+
+type ProductLotRepository interface {
+	Create(ctx context.Context, lot *model.ProductLot) error
+	// GetByProduct return the lots of the product, the first to expire first. Lots used up are only listed withEmpty.
+	GetByProduct(ctx context.Context, productID uuid.UUID, withEmpty bool) ([]model.ProductLot, error)
+	GetByNumber(ctx context.Context, productID uuid.UUID, lotNumber string) (*model.ProductLot, error)
+	// SumQuantity is the stock of the product held in lots
+	SumQuantity(ctx context.Context, productID uuid.UUID) (int, error)
+	// UpdateQuantities saves the quantity left of each lot
+	UpdateQuantities(ctx context.Context, lots []model.ProductLot) error
+	// GetExpiring return the lots of live products with stock left that expire between from and to included, soonest first.
+	// A nil page return them all.
+	GetExpiring(ctx context.Context, from time.Time, to time.Time, stockCity string, page *v1.Page) ([]model.ProductLot, int, error)
+	// GetToFlag return the lots of live products with stock left that expire by before and were not flagged yet
+	GetToFlag(ctx context.Context, before time.Time) ([]model.ProductLot, error)
+	MarkFlagged(ctx context.Context, lot *model.ProductLot, at time.Time) error
+}
+
+func NewProductLotRepository(
+	repository *Repository,
+) ProductLotRepository {
+	return &productLotRepository{
+		Repository: repository,
+	}
+}
+
+type productLotRepository struct {
+	*Repository
+}
+
+func (r *productLotRepository) Create(ctx context.Context, lot *model.ProductLot) error {
+	if err := r.DB(ctx).Create(lot).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *productLotRepository) GetByProduct(ctx context.Context, productID uuid.UUID, withEmpty bool) ([]model.ProductLot, error) {
+	var lots []model.ProductLot
+	query := orderByExpiry(r.DB(ctx)).Where("product_id = ?", productID)
+	if !withEmpty {
+		query = query.Where("quantity > 0")
+	}
+	if err := query.Find(&lots).Error; err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+
+func (r *productLotRepository) GetByNumber(ctx context.Context, productID uuid.UUID, lotNumber string) (*model.ProductLot, error) {
+	var lot model.ProductLot
+	if err := r.DB(ctx).Where("product_id = ? AND lot_number = ?", productID, lotNumber).First(&lot).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+	return &lot, nil
+}
+
+func (r *productLotRepository) SumQuantity(ctx context.Context, productID uuid.UUID) (int, error) {
+	var total int
+	if err := r.DB(ctx).Model(&model.ProductLot{}).Select("COALESCE(SUM(quantity), 0)").
+		Where("product_id = ?", productID).Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (r *productLotRepository) UpdateQuantities(ctx context.Context, lots []model.ProductLot) error {
+	for i := range lots {
+		if err := r.DB(ctx).Model(&lots[i]).Update("quantity", lots[i].Quantity).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *productLotRepository) GetExpiring(ctx context.Context, from time.Time, to time.Time, stockCity string, page *v1.Page) ([]model.ProductLot, int, error) {
+	var (
+		lots      []model.ProductLot
+		totalRows int64
+	)
+
+	query := r.liveStock(ctx).Where("expires_at >= ? AND expires_at <= ?", from, to)
+	if stockCity != "" {
+		query = query.Where("stock_city = ?", stockCity)
+	}
+
+	// Get total count before pagination
+	if err := query.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = orderByExpiry(query).Preload("Product")
+
+	// Pagination
+	if page != nil && page.Page > 0 && page.Size > 0 {
+		query = query.Offset((page.Page - 1) * page.Size).Limit(page.Size)
+	}
+
+	if err := query.Find(&lots).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return lots, int(totalRows), nil
+}
+
+func (r *productLotRepository) GetToFlag(ctx context.Context, before time.Time) ([]model.ProductLot, error) {
+	var lots []model.ProductLot
+	if err := orderByExpiry(r.liveStock(ctx)).Preload("Product").
+		Where("expiry_flagged_at IS NULL AND expires_at <= ?", before).Find(&lots).Error; err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+
+func (r *productLotRepository) MarkFlagged(ctx context.Context, lot *model.ProductLot, at time.Time) error {
+	if err := r.DB(ctx).Model(lot).Update("expiry_flagged_at", at).Error; err != nil {
+		return err
+	}
+	lot.ExpiryFlaggedAt = &at
+	return nil
+}
+
+// liveStock is the lots with stock left of the products that are not in the trash
+func (r *productLotRepository) liveStock(ctx context.Context) *gorm.DB {
+	products := r.DB(ctx).Model(&model.Product{}).Select("id")
+	return r.DB(ctx).Model(&model.ProductLot{}).Where("quantity > 0 AND product_id IN (?)", products)
+}
+
+func orderByExpiry(db *gorm.DB) *gorm.DB {
+	return db.Order("expires_at, lot_number")
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"gorm.io/gorm"
+)
+
+type ProductPriceRepository interface {
+	Create(ctx context.Context, price *model.ProductPrice) error
+	Update(ctx context.Context, price *model.ProductPrice) error
+	Delete(ctx context.Context, id uint64) error
+	Count(ctx context.Context, productID uuid.UUID) (int64, error)
+	GetProductPriceById(ctx context.Context, productID uuid.UUID, id uint64) (*model.ProductPrice, error)
+	// GetAt return the price in effect at the given time
+	GetAt(ctx context.Context, productID uuid.UUID, at time.Time) (*model.ProductPrice, error)
+	// GetNextAfter return the first price starting after the given time
+	GetNextAfter(ctx context.Context, productID uuid.UUID, at time.Time) (*model.ProductPrice, error)
+	// GetEndingAt return the price whose range ends at the given time
+	GetEndingAt(ctx context.Context, productID uuid.UUID, at time.Time) (*model.ProductPrice, error)
+	// GetEffectivePrices return the prices in effect at the given time by product, products without history are absent
+	GetEffectivePrices(ctx context.Context, productIDs []uuid.UUID, at time.Time) (map[uuid.UUID]float64, error)
+	// GetDueActivations return the prices in effect at the given time that differ from Product.Price
+	GetDueActivations(ctx context.Context, at time.Time) ([]model.ProductPrice, error)
+	Search(ctx context.Context, productID uuid.UUID, req *v1.GetProductPricesRequest) (*[]model.ProductPrice, int, error)
+}
+
+func NewProductPriceRepository(
+	repository *Repository,
+) ProductPriceRepository {
+	return &productPriceRepository{
+		Repository: repository,
+	}
+}
+
+type productPriceRepository struct {
+	*Repository
+}
+
+func (r *productPriceRepository) Create(ctx context.Context, price *model.ProductPrice) error {
+	if err := r.DB(ctx).Create(price).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *productPriceRepository) Update(ctx context.Context, price *model.ProductPrice) error {
+	if err := r.DB(ctx).Save(price).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *productPriceRepository) Delete(ctx context.Context, id uint64) error {
+	return r.DB(ctx).Where("id = ?", id).Delete(&model.ProductPrice{}).Error
+}
+
+func (r *productPriceRepository) Count(ctx context.Context, productID uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.DB(ctx).Model(&model.ProductPrice{}).Where("product_id = ?", productID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *productPriceRepository) GetProductPriceById(ctx context.Context, productID uuid.UUID, id uint64) (*model.ProductPrice, error) {
+	return r.first(r.DB(ctx).Where("product_id = ? AND id = ?", productID, id))
+}
+
+func (r *productPriceRepository) GetAt(ctx context.Context, productID uuid.UUID, at time.Time) (*model.ProductPrice, error) {
+	return r.first(r.DB(ctx).
+		Where("product_id = ? AND effective_from <= ?", productID, at).
+		Where("effective_to IS NULL OR effective_to > ?", at))
+}
+
+func (r *productPriceRepository) GetNextAfter(ctx context.Context, productID uuid.UUID, at time.Time) (*model.ProductPrice, error) {
+	return r.first(r.DB(ctx).Where("product_id = ? AND effective_from > ?", productID, at).Order("effective_from"))
+}
+
+func (r *productPriceRepository) GetEndingAt(ctx context.Context, productID uuid.UUID, at time.Time) (*model.ProductPrice, error) {
+	return r.first(r.DB(ctx).Where("product_id = ? AND effective_to = ?", productID, at))
+}
+
+func (r *productPriceRepository) first(query *gorm.DB) (*model.ProductPrice, error) {
+	var price model.ProductPrice
+	if err := query.First(&price).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &price, nil
+}
+
+func (r *productPriceRepository) GetEffectivePrices(ctx context.Context, productIDs []uuid.UUID, at time.Time) (map[uuid.UUID]float64, error) {
+	result := make(map[uuid.UUID]float64)
+	if len(productIDs) == 0 {
+		return result, nil
+	}
+
+	var prices []model.ProductPrice
+	if err := r.DB(ctx).
+		Where("product_id IN ? AND effective_from <= ?", productIDs, at).
+		Where("effective_to IS NULL OR effective_to > ?", at).
+		Find(&prices).Error; err != nil {
+		return nil, err
+	}
+
+	for _, price := range prices {
+		result[price.ProductID] = price.Price
+	}
+	return result, nil
+}
+
+func (r *productPriceRepository) GetDueActivations(ctx context.Context, at time.Time) ([]model.ProductPrice, error) {
+	var prices []model.ProductPrice
+	if err := r.DB(ctx).
+		Joins("JOIN products ON products.id = product_prices.product_id").
+		Where("product_prices.effective_from <= ?", at).
+		Where("product_prices.effective_to IS NULL OR product_prices.effective_to > ?", at).
+		Where("products.price <> product_prices.price").
+		Where("products.deleted_at IS NULL").
+		Order("product_prices.effective_from").
+		Find(&prices).Error; err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+func (r *productPriceRepository) Search(ctx context.Context, productID uuid.UUID, req *v1.GetProductPricesRequest) (*[]model.ProductPrice, int, error) {
+	var (
+		prices    []model.ProductPrice
+		totalRows int64
+	)
+
+	query := r.DB(ctx).Model(&model.ProductPrice{}).Where("product_id = ?", productID)
+
+	// Get total count before pagination
+	if err := query.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Latest ranges first, scheduled prices on top
+	query = query.Order("effective_from DESC")
+
+	// Pagination
+	if req.Page.Page > 0 && req.Size > 0 {
+		query = query.Offset((req.Page.Page - 1) * req.Size).Limit(req.Size)
+	}
+
+	if err := query.Find(&prices).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return &prices, int(totalRows), nil
+}
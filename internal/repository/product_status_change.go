@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+)
+
+type ProductStatusChangeRepository interface {
+	Create(ctx context.Context, change *model.ProductStatusChange) error
+	Search(ctx context.Context, productID uuid.UUID, req *v1.GetProductStatusHistoryRequest) (*[]model.ProductStatusChange, int, error)
+}
+
+func NewProductStatusChangeRepository(
+	repository *Repository,
+) ProductStatusChangeRepository {
+	return &productStatusChangeRepository{
+		Repository: repository,
+	}
+}
+
+type productStatusChangeRepository struct {
+	*Repository
+}
+
+func (r *productStatusChangeRepository) Create(ctx context.Context, change *model.ProductStatusChange) error {
+	if err := r.DB(ctx).Create(change).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *productStatusChangeRepository) Search(ctx context.Context, productID uuid.UUID, req *v1.GetProductStatusHistoryRequest) (*[]model.ProductStatusChange, int, error) {
+	var (
+		changes   []model.ProductStatusChange
+		totalRows int64
+	)
+
+	query := r.DB(ctx).Model(&model.ProductStatusChange{}).Where("product_id = ?", productID)
+
+	// Get total count before pagination
+	if err := query.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Latest changes first
+	query = query.Order("id DESC")
+
+	// Pagination
+	if req.Page.Page > 0 && req.Size > 0 {
+		query = query.Offset((req.Page.Page - 1) * req.Size).Limit(req.Size)
+	}
+
+	if err := query.Find(&changes).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return &changes, int(totalRows), nil
+}
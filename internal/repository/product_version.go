@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"gorm.io/gorm"
+)
+
+type ProductVersionRepository interface {
+	Create(ctx context.Context, version *model.ProductVersion) error
+	// Append stores product as its next version, before is the product prior to the change, nil on creation.
+	// It must be called inside a Transaction holding the product row lock.
+	Append(ctx context.Context, before *model.Product, product *model.Product, authorID string) (*model.ProductVersion, error)
+	GetLatest(ctx context.Context, productID uuid.UUID) (*model.ProductVersion, error)
+	GetByVersion(ctx context.Context, productID uuid.UUID, version int) (*model.ProductVersion, error)
+	// GetAsOf return the version in effect at the given time
+	GetAsOf(ctx context.Context, productID uuid.UUID, at time.Time) (*model.ProductVersion, error)
+	Search(ctx context.Context, productID uuid.UUID, req *v1.GetProductVersionsRequest) (*[]model.ProductVersion, int, error)
+}
+
+func NewProductVersionRepository(
+	repository *Repository,
+) ProductVersionRepository {
+	return &productVersionRepository{
+		Repository: repository,
+	}
+}
+
+type productVersionRepository struct {
+	*Repository
+}
+
+func (r *productVersionRepository) Create(ctx context.Context, version *model.ProductVersion) error {
+	if err := r.DB(ctx).Create(version).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *productVersionRepository) Append(ctx context.Context, before *model.Product, product *model.Product, authorID string) (*model.ProductVersion, error) {
+	next := 1
+	latest, err := r.GetLatest(ctx, product.ID)
+	switch {
+	case err == nil:
+		next = latest.Version + 1
+	case !errors.Is(err, v1.ErrNotFound):
+		return nil, err
+	case before != nil:
+		// Products created before the history existed get their previous state as version 1, author unknown
+		if err := r.Create(ctx, model.NewProductVersion(before, next, "")); err != nil {
+			return nil, err
+		}
+		next++
+	}
+
+	version := model.NewProductVersion(product, next, authorID)
+	if err := r.Create(ctx, version); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+func (r *productVersionRepository) GetLatest(ctx context.Context, productID uuid.UUID) (*model.ProductVersion, error) {
+	return r.first(r.DB(ctx).Where("product_id = ?", productID).Order("version DESC"))
+}
+
+func (r *productVersionRepository) GetByVersion(ctx context.Context, productID uuid.UUID, version int) (*model.ProductVersion, error) {
+	return r.first(r.DB(ctx).Where("product_id = ? AND version = ?", productID, version))
+}
+
+func (r *productVersionRepository) GetAsOf(ctx context.Context, productID uuid.UUID, at time.Time) (*model.ProductVersion, error) {
+	return r.first(r.DB(ctx).Where("product_id = ? AND created_at <= ?", productID, at).Order("version DESC"))
+}
+
+func (r *productVersionRepository) first(query *gorm.DB) (*model.ProductVersion, error) {
+	var version model.ProductVersion
+	if err := query.First(&version).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &version, nil
+}
+
+func (r *productVersionRepository) Search(ctx context.Context, productID uuid.UUID, req *v1.GetProductVersionsRequest) (*[]model.ProductVersion, int, error) {
+	var (
+		versions  []model.ProductVersion
+		totalRows int64
+	)
+
+	query := r.DB(ctx).Model(&model.ProductVersion{}).Where("product_id = ?", productID)
+
+	// Get total count before pagination
+	if err := query.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Latest versions first
+	query = query.Order("version DESC")
+
+	// Pagination
+	if req.Page.Page > 0 && req.Size > 0 {
+		query = query.Offset((req.Page.Page - 1) * req.Size).Limit(req.Size)
+	}
+
+	if err := query.Find(&versions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return &versions, int(totalRows), nil
+}
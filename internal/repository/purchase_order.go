@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PurchaseOrderRepository interface {
+	// Create saves the order with its lines
+	Create(ctx context.Context, order *model.PurchaseOrder) error
+	GetPurchaseOrderById(ctx context.Context, id uuid.UUID) (*model.PurchaseOrder, error)
+	// GetPurchaseOrderByIdForUpdate locks the order until the end of the surrounding Transaction
+	GetPurchaseOrderByIdForUpdate(ctx context.Context, id uuid.UUID) (*model.PurchaseOrder, error)
+	// Update only applies to the version of order, v1.ErrVersionMismatch is returned otherwise.
+	// The lines are left as is.
+	Update(ctx context.Context, order *model.PurchaseOrder) error
+	// ReplaceLines saves the lines of order in place of the stored ones
+	ReplaceLines(ctx context.Context, order *model.PurchaseOrder) error
+	// UpdateReceived saves the received quantity of the lines
+	UpdateReceived(ctx context.Context, lines []model.PurchaseOrderLine) error
+	Search(ctx context.Context, req *v1.SearchPurchaseOrderRequest) (*[]model.PurchaseOrder, int, error)
+	// SumOnOrder return per product the quantity still expected from the sent and partially received orders
+	SumOnOrder(ctx context.Context) (map[uuid.UUID]int, error)
+}
+
+func NewPurchaseOrderRepository(
+	repository *Repository,
+) PurchaseOrderRepository {
+	return &purchaseOrderRepository{
+		Repository: repository,
+	}
+}
+
+type purchaseOrderRepository struct {
+	*Repository
+}
+
+func (r *purchaseOrderRepository) Create(ctx context.Context, order *model.PurchaseOrder) error {
+	if err := r.DB(ctx).Create(order).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *purchaseOrderRepository) GetPurchaseOrderById(ctx context.Context, id uuid.UUID) (*model.PurchaseOrder, error) {
+	var order model.PurchaseOrder
+
+	if err := r.DB(ctx).Preload("Lines", orderById).Where("id = ?", id).First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func (r *purchaseOrderRepository) GetPurchaseOrderByIdForUpdate(ctx context.Context, id uuid.UUID) (*model.PurchaseOrder, error) {
+	var order model.PurchaseOrder
+
+	if err := lockForUpdate(r.DB(ctx)).Where("id = ?", id).First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+	if err := orderById(r.DB(ctx)).Where("purchase_order_id = ?", id).Find(&order.Lines).Error; err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func (r *purchaseOrderRepository) Update(ctx context.Context, order *model.PurchaseOrder) error {
+	version := order.Version
+	order.Version++
+
+	result := r.DB(ctx).Model(order).Where("version = ?", version).
+		Select("*").Omit(clause.Associations, "created_at").Updates(order)
+	if result.Error != nil {
+		order.Version = version
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		order.Version = version
+		return v1.ErrVersionMismatch
+	}
+	return nil
+}
+
+func (r *purchaseOrderRepository) ReplaceLines(ctx context.Context, order *model.PurchaseOrder) error {
+	if err := r.DB(ctx).Where("purchase_order_id = ?", order.ID).Delete(&model.PurchaseOrderLine{}).Error; err != nil {
+		return err
+	}
+	if len(order.Lines) == 0 {
+		return nil
+	}
+	for i := range order.Lines {
+		order.Lines[i].ID = 0
+		order.Lines[i].PurchaseOrderID = order.ID
+	}
+	return r.DB(ctx).Create(&order.Lines).Error
+}
+
+func (r *purchaseOrderRepository) UpdateReceived(ctx context.Context, lines []model.PurchaseOrderLine) error {
+	for i := range lines {
+		if err := r.DB(ctx).Model(&lines[i]).Update("received_quantity", lines[i].ReceivedQuantity).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *purchaseOrderRepository) Search(ctx context.Context, req *v1.SearchPurchaseOrderRequest) (*[]model.PurchaseOrder, int, error) {
+	var (
+		orders    []model.PurchaseOrder
+		totalRows int64
+	)
+
+	query := r.DB(ctx).Model(&model.PurchaseOrder{})
+
+	// Apply filters
+	if req.SupplierId != "" {
+		query = query.Where("supplier_id = ?", req.SupplierId)
+	}
+
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+
+	// Get total count before pagination
+	if err := query.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Pagination
+	if req.Page.Page > 0 && req.Size > 0 {
+		query = query.Offset((req.Page.Page - 1) * req.Size).Limit(req.Size)
+	}
+
+	if err := query.Preload("Lines", orderById).Order("created_at DESC").Find(&orders).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return &orders, int(totalRows), nil
+}
+
+func (r *purchaseOrderRepository) SumOnOrder(ctx context.Context) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		ProductID uuid.UUID
+		Quantity  int
+	}
+	if err := r.DB(ctx).Model(&model.PurchaseOrderLine{}).
+		Select("purchase_order_lines.product_id, SUM(purchase_order_lines.quantity - purchase_order_lines.received_quantity) as quantity").
+		Joins("JOIN purchase_orders ON purchase_orders.id = purchase_order_lines.purchase_order_id").
+		Where("purchase_orders.status IN ?", []string{model.PurchaseOrderSent, model.PurchaseOrderPartiallyReceived}).
+		Where("purchase_order_lines.product_id IS NOT NULL").
+		Group("purchase_order_lines.product_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	onOrder := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		onOrder[row.ProductID] = row.Quantity
+	}
+	return onOrder, nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/quydmfl/niveau-test/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReferenceCounterRepository interface {
+	// Next bumps the counter of scope and return its new value, it must be called inside a Transaction
+	Next(ctx context.Context, scope string) (int64, error)
+}
+
+func NewReferenceCounterRepository(
+	repository *Repository,
+) ReferenceCounterRepository {
+	return &referenceCounterRepository{
+		Repository: repository,
+	}
+}
+
+type referenceCounterRepository struct {
+	*Repository
+}
+
+// Next creates the counter of a new scope at 1. The bump locks the row until the end of the surrounding Transaction,
+// concurrent creates in the same scope get the next values one after the other.
+func (r *referenceCounterRepository) Next(ctx context.Context, scope string) (int64, error) {
+	counter := model.ReferenceCounter{Scope: scope, Value: 1, UpdatedAt: time.Now()}
+	if err := r.DB(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "scope"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"value":      gorm.Expr("reference_counters.value + 1"),
+			"updated_at": counter.UpdatedAt,
+		}),
+	}).Create(&counter).Error; err != nil {
+		return 0, err
+	}
+
+	if err := r.DB(ctx).Where("scope = ?", scope).First(&counter).Error; err != nil {
+		return 0, err
+	}
+	return counter.Value, nil
+}
@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/glebarez/sqlite"
+	"github.com/quydmfl/niveau-test/pkg/lock"
 	"github.com/quydmfl/niveau-test/pkg/log"
 	"github.com/quydmfl/niveau-test/pkg/zapgorm2"
 	"github.com/redis/go-redis/v9"
@@ -13,6 +15,7 @@ import (
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 const ctxTxKey = "TxKey"
@@ -49,19 +52,39 @@ func (r *Repository) DB(ctx context.Context) *gorm.DB {
 	v := ctx.Value(ctxTxKey)
 	if v != nil {
 		if tx, ok := v.(*gorm.DB); ok {
-			return tx
+			// Values added to ctx inside the Transaction, like the audit actor, must reach the statements
+			return tx.WithContext(ctx)
 		}
 	}
 	return r.db.WithContext(ctx)
 }
 
+// Transaction runs fn in a new Transaction, or in a savepoint of the Transaction of ctx.
+// A nested Transaction that fails is rolled back alone, the outer one decides what is committed.
 func (r *Repository) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	return r.DB(ctx).Transaction(func(tx *gorm.DB) error {
 		ctx = context.WithValue(ctx, ctxTxKey, tx)
 		return fn(ctx)
 	})
 }
 
+// lockForUpdate adds a FOR UPDATE clause when the driver supports row locks.
+// The lock is only held until the end of the surrounding Transaction.
+func lockForUpdate(db *gorm.DB) *gorm.DB {
+	if db.Dialector.Name() == "sqlite" {
+		return db
+	}
+	return db.Clauses(clause.Locking{Strength: "UPDATE"})
+}
+
+// concat joins the SQL expressions as strings, || is a logical OR on MySQL
+func concat(db *gorm.DB, exprs ...string) string {
+	if db.Dialector.Name() == "mysql" {
+		return "CONCAT(" + strings.Join(exprs, ", ") + ")"
+	}
+	return strings.Join(exprs, " || ")
+}
+
 func NewDB(conf *viper.Viper, l *log.Logger) *gorm.DB {
 	var (
 		db  *gorm.DB
@@ -93,6 +116,10 @@ func NewDB(conf *viper.Viper, l *log.Logger) *gorm.DB {
 	}
 	db = db.Debug()
 
+	if err := RegisterAuditCallbacks(db); err != nil {
+		panic(err)
+	}
+
 	// Connection Pool config
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -120,3 +147,13 @@ func NewRedis(conf *viper.Viper) *redis.Client {
 
 	return rdb
 }
+
+// NewLocker build the distributed locker configured by task.lock.driver
+func NewLocker(conf *viper.Viper, db *gorm.DB) lock.Locker {
+	switch conf.GetString("task.lock.driver") {
+	case "redis":
+		return lock.NewRedisLocker(NewRedis(conf))
+	default:
+		return lock.NewDatabaseLocker(db)
+	}
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/quydmfl/niveau-test/internal/model"
+)
+
+type StockMovementRepository interface {
+	Create(ctx context.Context, movement *model.StockMovement) error
+	// GetDemand return the decreases of stock since the time, oldest first. The corrections of a stocktake
+	// are not demand and are left out, the manual adjustments are stock that left without another record and count.
+	GetDemand(ctx context.Context, since time.Time) ([]model.StockMovement, error)
+}
+
+func NewStockMovementRepository(
+	repository *Repository,
+) StockMovementRepository {
+	return &stockMovementRepository{
+		Repository: repository,
+	}
+}
+
+type stockMovementRepository struct {
+	*Repository
+}
+
+func (r *stockMovementRepository) Create(ctx context.Context, movement *model.StockMovement) error {
+	if err := r.DB(ctx).Create(movement).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *stockMovementRepository) GetDemand(ctx context.Context, since time.Time) ([]model.StockMovement, error) {
+	var movements []model.StockMovement
+	if err := r.DB(ctx).Select("product_id", "delta", "created_at").
+		Where("delta < 0 AND reason <> ? AND created_at >= ?", model.StockMovementStocktake, since).
+		Order("created_at").Find(&movements).Error; err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
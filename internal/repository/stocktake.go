@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StocktakeRepository interface {
+	// Create saves the stocktake with its lines
+	Create(ctx context.Context, stocktake *model.Stocktake) error
+	// GetStocktakeById return the stocktake with its lines and their product, those in the trash included
+	GetStocktakeById(ctx context.Context, id uuid.UUID) (*model.Stocktake, error)
+	// GetStocktakeByIdForUpdate locks the stocktake until the end of the surrounding Transaction
+	GetStocktakeByIdForUpdate(ctx context.Context, id uuid.UUID) (*model.Stocktake, error)
+	// Update only applies to the version of stocktake, v1.ErrVersionMismatch is returned otherwise.
+	// The lines are left as is.
+	Update(ctx context.Context, stocktake *model.Stocktake) error
+	// UpdateLines saves the system, counted and adjusted quantities of the lines
+	UpdateLines(ctx context.Context, lines []model.StocktakeLine) error
+	// SaveCounts replaces the previous counts of the devices and sums the counts of each line again
+	SaveCounts(ctx context.Context, counts []model.StocktakeCount) error
+	Search(ctx context.Context, req *v1.SearchStocktakeRequest) (*[]model.Stocktake, int, error)
+	// GetProductsInScope return the live products to count, ordered by reference. Archived products and bundles,
+	// which have no stock of their own, are left out.
+	GetProductsInScope(ctx context.Context, stockCity string, categoryID *uuid.UUID, includeSubcategories bool) ([]model.Product, error)
+}
+
+func NewStocktakeRepository(
+	repository *Repository,
+) StocktakeRepository {
+	return &stocktakeRepository{
+		Repository: repository,
+	}
+}
+
+type stocktakeRepository struct {
+	*Repository
+}
+
+func (r *stocktakeRepository) Create(ctx context.Context, stocktake *model.Stocktake) error {
+	if err := r.DB(ctx).Omit("Lines.Product").Create(stocktake).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *stocktakeRepository) GetStocktakeById(ctx context.Context, id uuid.UUID) (*model.Stocktake, error) {
+	var stocktake model.Stocktake
+
+	if err := r.DB(ctx).Preload("Lines", orderById).Preload("Lines.Product", unscoped).Where("id = ?", id).First(&stocktake).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &stocktake, nil
+}
+
+func (r *stocktakeRepository) GetStocktakeByIdForUpdate(ctx context.Context, id uuid.UUID) (*model.Stocktake, error) {
+	var stocktake model.Stocktake
+
+	if err := lockForUpdate(r.DB(ctx)).Where("id = ?", id).First(&stocktake).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+	if err := orderById(r.DB(ctx)).Where("stocktake_id = ?", id).Find(&stocktake.Lines).Error; err != nil {
+		return nil, err
+	}
+
+	return &stocktake, nil
+}
+
+func (r *stocktakeRepository) Update(ctx context.Context, stocktake *model.Stocktake) error {
+	version := stocktake.Version
+	stocktake.Version++
+
+	result := r.DB(ctx).Model(stocktake).Where("version = ?", version).
+		Select("*").Omit(clause.Associations, "created_at").Updates(stocktake)
+	if result.Error != nil {
+		stocktake.Version = version
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		stocktake.Version = version
+		return v1.ErrVersionMismatch
+	}
+	return nil
+}
+
+func (r *stocktakeRepository) UpdateLines(ctx context.Context, lines []model.StocktakeLine) error {
+	for i := range lines {
+		if err := r.DB(ctx).Model(&lines[i]).Select("system_quantity", "counted_quantity", "adjustment").
+			Updates(&lines[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *stocktakeRepository) SaveCounts(ctx context.Context, counts []model.StocktakeCount) error {
+	lineIDs := make([]uint64, 0, len(counts))
+	for i := range counts {
+		if err := r.DB(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "line_id"}, {Name: "device_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"quantity", "counted_by", "counted_at"}),
+		}).Create(&counts[i]).Error; err != nil {
+			return err
+		}
+		lineIDs = append(lineIDs, counts[i].LineID)
+	}
+	if len(lineIDs) == 0 {
+		return nil
+	}
+
+	return r.DB(ctx).Model(&model.StocktakeLine{}).Where("id IN ?", lineIDs).
+		Update("counted_quantity", r.DB(ctx).Model(&model.StocktakeCount{}).Select("SUM(quantity)").
+			Where("stocktake_counts.line_id = stocktake_lines.id")).Error
+}
+
+func (r *stocktakeRepository) Search(ctx context.Context, req *v1.SearchStocktakeRequest) (*[]model.Stocktake, int, error) {
+	var (
+		stocktakes []model.Stocktake
+		totalRows  int64
+	)
+
+	query := r.DB(ctx).Model(&model.Stocktake{})
+
+	// Apply filters
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.StockLocation != "" {
+		query = query.Where("stock_city = ?", req.StockLocation)
+	}
+
+	// Get total count before pagination
+	if err := query.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Pagination
+	if req.Page.Page > 0 && req.Size > 0 {
+		query = query.Offset((req.Page.Page - 1) * req.Size).Limit(req.Size)
+	}
+
+	if err := query.Preload("Lines", orderById).Order("created_at DESC").Find(&stocktakes).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return &stocktakes, int(totalRows), nil
+}
+
+func (r *stocktakeRepository) GetProductsInScope(ctx context.Context, stockCity string, categoryID *uuid.UUID, includeSubcategories bool) ([]model.Product, error) {
+	query := r.DB(ctx).Model(&model.Product{}).Where("products.status <> ?", model.ProductArchived).
+		Where("NOT EXISTS (?)", r.DB(ctx).Model(&model.BundleComponent{}).Select("1").
+			Where("product_bundle_components.bundle_id = products.id"))
+
+	if stockCity != "" {
+		query = query.Where("stock_city = ?", stockCity)
+	}
+	if categoryID != nil && includeSubcategories {
+		query = query.Where("category_id IN (?)", r.DB(ctx).Model(&model.Category{}).Select("id").
+			Where("path LIKE (?)", r.DB(ctx).Model(&model.Category{}).Select(concat(r.DB(ctx), "path", "'%'")).Where("id = ?", categoryID)))
+	} else if categoryID != nil {
+		query = query.Where("category_id = ?", categoryID)
+	}
+
+	var products []model.Product
+	if err := orderByReference(query).Find(&products).Error; err != nil {
+		return nil, err
+	}
+	return products, nil
+}
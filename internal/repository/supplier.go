@@ -3,16 +3,39 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	v1 "github.com/quydmfl/niveau-test/api/v1"
 	"github.com/quydmfl/niveau-test/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type SupplierRepository interface {
 	Create(ctx context.Context, category *model.Supplier) error
 	GetSupplierById(ctx context.Context, id uuid.UUID) (*model.Supplier, error)
+	// GetSupplierDetailById returns the supplier with its contacts and addresses
+	GetSupplierDetailById(ctx context.Context, id uuid.UUID) (*model.Supplier, error)
+	// GetSupplierByIdForUpdate locks the supplier until the end of the surrounding Transaction,
+	// it is returned with its contacts and addresses
+	GetSupplierByIdForUpdate(ctx context.Context, id uuid.UUID) (*model.Supplier, error)
+	// Update only applies to the version of supplier, v1.ErrVersionMismatch is returned otherwise.
+	// Contacts and addresses are left as is.
+	Update(ctx context.Context, supplier *model.Supplier) error
+	// ReplaceContacts and ReplaceAddresses save the contacts and addresses of supplier in place of the stored ones
+	ReplaceContacts(ctx context.Context, supplier *model.Supplier) error
+	ReplaceAddresses(ctx context.Context, supplier *model.Supplier) error
+	// MoveContacts and MoveAddresses give the contacts and addresses with the IDs to another supplier
+	MoveContacts(ctx context.Context, ids []uint64, to uuid.UUID) error
+	MoveAddresses(ctx context.Context, ids []uint64, to uuid.UUID) error
+	// Delete puts the supplier in the trash if it is still at its version, v1.ErrVersionMismatch is returned otherwise
+	Delete(ctx context.Context, supplier *model.Supplier) error
+	GetDeletedSupplierById(ctx context.Context, id uuid.UUID) (*model.Supplier, error)
+	SearchDeleted(ctx context.Context, req *v1.GetTrashRequest) (*[]model.Supplier, int, error)
+	GetDeletedBefore(ctx context.Context, before time.Time) ([]model.Supplier, error)
+	Restore(ctx context.Context, supplier *model.Supplier) error
+	Purge(ctx context.Context, supplier *model.Supplier) error
 	Search(ctx context.Context, req *v1.SearchSupplierRequest) (*[]model.Supplier, int, error)
 }
 
@@ -48,6 +71,98 @@ func (r *supplierRepository) GetSupplierById(ctx context.Context, id uuid.UUID)
 	return &supplier, nil
 }
 
+func (r *supplierRepository) GetSupplierDetailById(ctx context.Context, id uuid.UUID) (*model.Supplier, error) {
+	var supplier model.Supplier
+
+	if err := r.DB(ctx).Preload("Contacts", orderById).Preload("Addresses", orderById).
+		Where("id = ?", id).First(&supplier).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &supplier, nil
+}
+
+func (r *supplierRepository) GetSupplierByIdForUpdate(ctx context.Context, id uuid.UUID) (*model.Supplier, error) {
+	var supplier model.Supplier
+
+	if err := lockForUpdate(r.DB(ctx)).Where("id = ?", id).First(&supplier).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+	if err := orderById(r.DB(ctx)).Where("supplier_id = ?", id).Find(&supplier.Contacts).Error; err != nil {
+		return nil, err
+	}
+	if err := orderById(r.DB(ctx)).Where("supplier_id = ?", id).Find(&supplier.Addresses).Error; err != nil {
+		return nil, err
+	}
+
+	return &supplier, nil
+}
+
+func (r *supplierRepository) Update(ctx context.Context, supplier *model.Supplier) error {
+	version := supplier.Version
+	supplier.Version++
+
+	result := r.DB(ctx).Model(supplier).Where("version = ?", version).
+		Select("*").Omit(clause.Associations, "deleted_at", "created_at").Updates(supplier)
+	if result.Error != nil {
+		supplier.Version = version
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		supplier.Version = version
+		return v1.ErrVersionMismatch
+	}
+	return nil
+}
+
+func (r *supplierRepository) ReplaceContacts(ctx context.Context, supplier *model.Supplier) error {
+	if err := r.DB(ctx).Where("supplier_id = ?", supplier.ID).Delete(&model.SupplierContact{}).Error; err != nil {
+		return err
+	}
+	if len(supplier.Contacts) == 0 {
+		return nil
+	}
+	for i := range supplier.Contacts {
+		supplier.Contacts[i].ID = 0
+		supplier.Contacts[i].SupplierID = supplier.ID
+	}
+	return r.DB(ctx).Create(&supplier.Contacts).Error
+}
+
+func (r *supplierRepository) ReplaceAddresses(ctx context.Context, supplier *model.Supplier) error {
+	if err := r.DB(ctx).Where("supplier_id = ?", supplier.ID).Delete(&model.SupplierAddress{}).Error; err != nil {
+		return err
+	}
+	if len(supplier.Addresses) == 0 {
+		return nil
+	}
+	for i := range supplier.Addresses {
+		supplier.Addresses[i].ID = 0
+		supplier.Addresses[i].SupplierID = supplier.ID
+	}
+	return r.DB(ctx).Create(&supplier.Addresses).Error
+}
+
+func (r *supplierRepository) MoveContacts(ctx context.Context, ids []uint64, to uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.DB(ctx).Model(&model.SupplierContact{}).Where("id IN ?", ids).Update("supplier_id", to).Error
+}
+
+func (r *supplierRepository) MoveAddresses(ctx context.Context, ids []uint64, to uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.DB(ctx).Model(&model.SupplierAddress{}).Where("id IN ?", ids).Update("supplier_id", to).Error
+}
+
 func (r *supplierRepository) Search(ctx context.Context, req *v1.SearchSupplierRequest) (*[]model.Supplier, int, error) {
 	var (
 		suppliers []model.Supplier
@@ -61,6 +176,10 @@ func (r *supplierRepository) Search(ctx context.Context, req *v1.SearchSupplierR
 		query = query.Where("name ILIKE ?", "%"+req.Name+"%")
 	}
 
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+
 	// Apply sorting
 	query = query.Order(req.SortBy + " " + req.SortOrder)
 
@@ -80,3 +199,50 @@ func (r *supplierRepository) Search(ctx context.Context, req *v1.SearchSupplierR
 
 	return &suppliers, int(totalRows), nil
 }
+
+func (r *supplierRepository) Delete(ctx context.Context, supplier *model.Supplier) error {
+	return softDelete(r.DB(ctx), supplier, supplier.Version)
+}
+
+func (r *supplierRepository) GetDeletedSupplierById(ctx context.Context, id uuid.UUID) (*model.Supplier, error) {
+	var supplier model.Supplier
+	if err := firstDeleted(r.DB(ctx).Where("id = ?", id), &supplier); err != nil {
+		return nil, err
+	}
+	return &supplier, nil
+}
+
+func (r *supplierRepository) SearchDeleted(ctx context.Context, req *v1.GetTrashRequest) (*[]model.Supplier, int, error) {
+	var suppliers []model.Supplier
+	totalRows, err := searchDeleted(r.DB(ctx).Model(&model.Supplier{}), &suppliers, req.Page)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &suppliers, totalRows, nil
+}
+
+func (r *supplierRepository) GetDeletedBefore(ctx context.Context, before time.Time) ([]model.Supplier, error) {
+	var suppliers []model.Supplier
+	if err := deletedBefore(r.DB(ctx).Model(&model.Supplier{}), &suppliers, before); err != nil {
+		return nil, err
+	}
+	return suppliers, nil
+}
+
+func (r *supplierRepository) Restore(ctx context.Context, supplier *model.Supplier) error {
+	if err := restore(r.DB(ctx), supplier); err != nil {
+		return err
+	}
+	supplier.DeletedAt = gorm.DeletedAt{}
+	supplier.Version++
+	return nil
+}
+
+func (r *supplierRepository) Purge(ctx context.Context, supplier *model.Supplier) error {
+	return purge(r.DB(ctx), supplier)
+}
+
+// orderById preloads the contacts and addresses in the order they were added
+func orderById(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TaskRunRepository interface {
+	// Claim inserts run, claimed is false when another replica already inserted the same scheduled tick
+	Claim(ctx context.Context, run *model.TaskRun) (bool, error)
+	Update(ctx context.Context, run *model.TaskRun) error
+	GetTaskRunById(ctx context.Context, id uint64) (*model.TaskRun, error)
+	Search(ctx context.Context, req *v1.SearchTaskRunRequest) (*[]model.TaskRun, int, error)
+}
+
+func NewTaskRunRepository(
+	repository *Repository,
+) TaskRunRepository {
+	return &taskRunRepository{
+		Repository: repository,
+	}
+}
+
+type taskRunRepository struct {
+	*Repository
+}
+
+func (r *taskRunRepository) Claim(ctx context.Context, run *model.TaskRun) (bool, error) {
+	result := r.DB(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(run)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *taskRunRepository) Update(ctx context.Context, run *model.TaskRun) error {
+	if err := r.DB(ctx).Save(run).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *taskRunRepository) GetTaskRunById(ctx context.Context, id uint64) (*model.TaskRun, error) {
+	var run model.TaskRun
+	if err := r.DB(ctx).Where("id = ?", id).First(&run).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &run, nil
+}
+
+func (r *taskRunRepository) Search(ctx context.Context, req *v1.SearchTaskRunRequest) (*[]model.TaskRun, int, error) {
+	var (
+		runs      []model.TaskRun
+		totalRows int64
+	)
+
+	query := r.DB(ctx).Model(&model.TaskRun{})
+
+	// Apply filters
+	if req.TaskName != "" {
+		query = query.Where("task_name = ?", req.TaskName)
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.DateFrom != "" {
+		query = query.Where("created_at >= ?", req.DateFrom)
+	}
+	if req.DateTo != "" {
+		// DateTo is inclusive
+		dateTo, err := time.Parse("2006-01-02", req.DateTo)
+		if err != nil {
+			return nil, 0, err
+		}
+		query = query.Where("created_at < ?", dateTo.AddDate(0, 0, 1))
+	}
+
+	// Latest runs first
+	query = query.Order("id DESC")
+
+	// Get total count before pagination
+	if err := query.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Pagination
+	if req.Page.Page > 0 && req.Size > 0 {
+		query = query.Offset((req.Page.Page - 1) * req.Size).Limit(req.Size)
+	}
+
+	if err := query.Find(&runs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return &runs, int(totalRows), nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"gorm.io/gorm"
+)
+
+// Helpers shared by the models with soft delete, the trash is made of their rows with deleted_at set
+
+// unscoped includes the trash in a preload, a product keeps showing its category or supplier once they are in the trash
+func unscoped(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+// softDelete puts value in the trash if it is still at its version
+func softDelete(db *gorm.DB, value interface{}, version int) error {
+	result := db.Where("version = ?", version).Delete(value)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return v1.ErrVersionMismatch
+	}
+	return nil
+}
+
+// firstDeleted loads into dest the first row of query that is in the trash
+func firstDeleted(query *gorm.DB, dest interface{}) error {
+	if err := lockForUpdate(query.Unscoped()).Where("deleted_at IS NOT NULL").First(dest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return v1.ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// searchDeleted lists the trash of the model of query, last deleted first
+func searchDeleted(query *gorm.DB, dest interface{}, page v1.Page) (int, error) {
+	var totalRows int64
+
+	query = query.Unscoped().Where("deleted_at IS NOT NULL")
+	if err := query.Count(&totalRows).Error; err != nil {
+		return 0, err
+	}
+
+	query = query.Order("deleted_at DESC")
+	if page.Page > 0 && page.Size > 0 {
+		query = query.Offset((page.Page - 1) * page.Size).Limit(page.Size)
+	}
+
+	if err := query.Find(dest).Error; err != nil {
+		return 0, err
+	}
+	return int(totalRows), nil
+}
+
+// deletedBefore loads into dest the rows of query put in the trash before the given time
+func deletedBefore(query *gorm.DB, dest interface{}, before time.Time) error {
+	return query.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", before).Order("deleted_at").Find(dest).Error
+}
+
+// restore takes value out of the trash, the version changes with it
+func restore(db *gorm.DB, value interface{}) error {
+	return db.Unscoped().Model(value).Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"version":    gorm.Expr("version + 1"),
+	}).Error
+}
+
+// purge deletes value for good, it must be in the trash
+func purge(db *gorm.DB, value interface{}) error {
+	result := db.Unscoped().Where("deleted_at IS NOT NULL").Delete(value)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return v1.ErrNotFound
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/quydmfl/niveau-test/docs"
 	"github.com/quydmfl/niveau-test/internal/handler"
 	"github.com/quydmfl/niveau-test/internal/middleware"
+	"github.com/quydmfl/niveau-test/internal/model"
 	"github.com/quydmfl/niveau-test/pkg/jwt"
 	"github.com/quydmfl/niveau-test/pkg/log"
 	"github.com/quydmfl/niveau-test/pkg/server/http"
@@ -23,11 +24,16 @@ func NewHTTPServer(
 	productHandler *handler.ProductHandler,
 	categoryHandler *handler.CategoryHandler,
 	supplierHandler *handler.SupplierHandler,
+	taskHandler *handler.TaskHandler,
+	auditHandler *handler.AuditHandler,
+	purchaseOrderHandler *handler.PurchaseOrderHandler,
+	stocktakeHandler *handler.StocktakeHandler,
+	forecastHandler *handler.ForecastHandler,
 ) *http.Server {
 	gin.SetMode(gin.DebugMode)
 
 	// register custom validators
-	validators.RegisterCustomValidators()
+	validators.RegisterCustomValidators(conf)
 
 	s := http.NewServer(
 		gin.Default(),
@@ -58,6 +64,13 @@ func NewHTTPServer(
 		})
 	})
 
+	// product images and their thumbnails
+	imageStoragePath := conf.GetString("product_image.storage_path")
+	if imageStoragePath == "" {
+		imageStoragePath = model.ProductImageStoragePath
+	}
+	s.Static(model.ProductImageURLPrefix, imageStoragePath)
+
 	s.GET("/health", func(ctx *gin.Context) {
 		logger.WithContext(ctx).Info("hello")
 		apiV1.HandleSuccess(ctx, map[string]interface{}{
@@ -81,11 +94,43 @@ func NewHTTPServer(
 			{
 				products.GET("/export/:format/:id", productHandler.ExportProducts)
 				products.GET("/distance/ip/:city", productHandler.GetDistanceBetweenIPAndCity)
+				products.GET("/labels", productHandler.ExportProductLabels)
+				products.GET("/gtin/:gtin", productHandler.GetProductByGTIN)
+				products.GET("/lots/expiring", productHandler.GetExpiringLots)
+				products.GET("/lots/expiring/export/:format", productHandler.ExportExpiringLots)
+				products.POST("/bulk/update", productHandler.BulkUpdateProducts)
+				products.POST("/bulk/delete", productHandler.BulkDeleteProducts)
 				products.GET("/", productHandler.GetProducts)
 				products.GET("/:id", productHandler.GetProductDetail)
 				products.POST("/", productHandler.CreateProduct)
+				products.POST("/:id/variants", productHandler.CreateVariant)
 				products.PUT("/:id", productHandler.UpdateProduct)
+				products.PATCH("/:id", productHandler.PatchProduct)
 				products.DELETE("/:id", productHandler.DeleteProduct)
+				products.GET("/trash", productHandler.GetDeletedProducts)
+				products.POST("/trash/:id/restore", productHandler.RestoreProduct)
+				products.DELETE("/trash/:id", productHandler.PurgeProduct)
+				products.GET("/:id/versions", productHandler.GetProductVersions)
+				products.GET("/:id/versions/:version", productHandler.GetProductVersion)
+				products.POST("/:id/versions/:version/restore", productHandler.RestoreProductVersion)
+				products.GET("/:id/as-of", productHandler.GetProductAsOf)
+				products.GET("/:id/diff", productHandler.DiffProductVersions)
+				products.GET("/:id/prices", productHandler.GetProductPrices)
+				products.POST("/:id/prices", productHandler.ScheduleProductPrice)
+				products.DELETE("/:id/prices/:priceId", productHandler.CancelProductPrice)
+				products.POST("/:id/transition", productHandler.TransitionProduct)
+				products.GET("/:id/status-history", productHandler.GetProductStatusHistory)
+				products.GET("/:id/images", productHandler.GetProductImages)
+				products.POST("/:id/images", productHandler.UploadProductImage)
+				products.PUT("/:id/images/order", productHandler.ReorderProductImages)
+				products.DELETE("/:id/images/:imageId", productHandler.DeleteProductImage)
+				products.GET("/:id/barcode", productHandler.GetProductBarcode)
+				products.GET("/:id/lots", productHandler.GetProductLots)
+				products.POST("/:id/lots", productHandler.ReceiveProductLot)
+				products.POST("/:id/lots/consume", productHandler.ConsumeProductLots)
+				products.PUT("/:id/components", productHandler.SetBundleComponents)
+				products.POST("/:id/issue", productHandler.IssueBundle)
+				products.GET("/:id/forecast", forecastHandler.GetProductForecast)
 			}
 
 			// categories //
@@ -94,6 +139,12 @@ func NewHTTPServer(
 				categories.GET("/", categoryHandler.GetCategories)
 				categories.GET("/:id", categoryHandler.GetCategoryDetail)
 				categories.POST("/", categoryHandler.CreateCategory)
+				categories.PUT("/:id", categoryHandler.UpdateCategory)
+				categories.DELETE("/:id", categoryHandler.DeleteCategory)
+				categories.POST("/:id/merge", categoryHandler.MergeCategory)
+				categories.GET("/trash", categoryHandler.GetDeletedCategories)
+				categories.POST("/trash/:id/restore", categoryHandler.RestoreCategory)
+				categories.DELETE("/trash/:id", categoryHandler.PurgeCategory)
 			}
 
 			// supplier //
@@ -102,6 +153,46 @@ func NewHTTPServer(
 				supplier.GET("/", supplierHandler.GetSuppliers)
 				supplier.GET("/:id", supplierHandler.GetSupplierDetail)
 				supplier.POST("/", supplierHandler.CreateSupplier)
+				supplier.PUT("/:id", supplierHandler.UpdateSupplier)
+				supplier.POST("/:id/merge", supplierHandler.MergeSupplier)
+				supplier.DELETE("/:id", supplierHandler.DeleteSupplier)
+				supplier.GET("/trash", supplierHandler.GetDeletedSuppliers)
+				supplier.POST("/trash/:id/restore", supplierHandler.RestoreSupplier)
+				supplier.DELETE("/trash/:id", supplierHandler.PurgeSupplier)
+			}
+
+			// purchase orders //
+			purchaseOrders := v1.Group("/purchase-orders").Use(middleware.StrictAuth(jwt, logger))
+			{
+				purchaseOrders.GET("/", purchaseOrderHandler.GetPurchaseOrders)
+				purchaseOrders.GET("/:id", purchaseOrderHandler.GetPurchaseOrderDetail)
+				purchaseOrders.POST("/", purchaseOrderHandler.CreatePurchaseOrder)
+				purchaseOrders.PUT("/:id", purchaseOrderHandler.UpdatePurchaseOrder)
+				purchaseOrders.POST("/:id/send", purchaseOrderHandler.SendPurchaseOrder)
+				purchaseOrders.POST("/:id/cancel", purchaseOrderHandler.CancelPurchaseOrder)
+				purchaseOrders.POST("/:id/receive", purchaseOrderHandler.ReceivePurchaseOrder)
+				purchaseOrders.GET("/:id/export/:format", purchaseOrderHandler.ExportPurchaseOrder)
+			}
+
+			// stocktakes //
+			stocktakes := v1.Group("/stocktakes").Use(middleware.StrictAuth(jwt, logger))
+			{
+				stocktakes.GET("/", stocktakeHandler.GetStocktakes)
+				stocktakes.GET("/:id", stocktakeHandler.GetStocktakeDetail)
+				stocktakes.POST("/", stocktakeHandler.CreateStocktake)
+				stocktakes.POST("/:id/counts", stocktakeHandler.SubmitStocktakeCounts)
+				stocktakes.GET("/:id/variance", stocktakeHandler.GetStocktakeVariance)
+				stocktakes.POST("/:id/approve", stocktakeHandler.ApproveStocktake)
+				stocktakes.POST("/:id/cancel", stocktakeHandler.CancelStocktake)
+				stocktakes.GET("/:id/sheet/export/:format", stocktakeHandler.ExportStocktakeSheet)
+				stocktakes.GET("/:id/variance/export/:format", stocktakeHandler.ExportStocktakeVariance)
+			}
+
+			// forecasts //
+			forecasts := v1.Group("/forecasts").Use(middleware.StrictAuth(jwt, logger))
+			{
+				forecasts.GET("/", forecastHandler.GetForecasts)
+				forecasts.GET("/export/:format", forecastHandler.ExportForecasts)
 			}
 
 			// statistics //
@@ -110,6 +201,21 @@ func NewHTTPServer(
 				statistics.GET("/products-per-category", productHandler.GetProductsPerCategory)
 				statistics.GET("/products-per-supplier", productHandler.GetProductsPerSupplier)
 			}
+
+			// tasks //
+			tasks := v1.Group("/admin/tasks").Use(middleware.StrictAuth(jwt, logger), middleware.AdminAuth(logger))
+			{
+				tasks.GET("/", taskHandler.GetTasks)
+				tasks.GET("/runs", taskHandler.GetTaskRuns)
+				tasks.GET("/runs/:id", taskHandler.GetTaskRun)
+				tasks.POST("/:name/trigger", taskHandler.TriggerTask)
+			}
+
+			// audit logs //
+			auditLogs := v1.Group("/admin/audit-logs").Use(middleware.StrictAuth(jwt, logger), middleware.AdminAuth(logger))
+			{
+				auditLogs.GET("/", auditHandler.GetAuditLogs)
+			}
 		}
 	}
 
@@ -2,33 +2,76 @@ package server
 
 import (
 	"context"
+	"sync"
 
 	"github.com/quydmfl/niveau-test/internal/job"
 	"github.com/quydmfl/niveau-test/pkg/log"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
 )
 
 type JobServer struct {
-	log     *log.Logger
-	userJob job.UserJob
+	log       *log.Logger
+	conf      *viper.Viper
+	consumer  job.Consumer
+	outboxJob job.OutboxJob
+	stockJob  job.StockJob
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
 }
 
 func NewJobServer(
 	log *log.Logger,
-	userJob job.UserJob,
+	conf *viper.Viper,
+	consumer job.Consumer,
+	outboxJob job.OutboxJob,
+	stockJob job.StockJob,
 ) *JobServer {
 	return &JobServer{
-		log:     log,
-		userJob: userJob,
+		log:       log,
+		conf:      conf,
+		consumer:  consumer,
+		outboxJob: outboxJob,
+		stockJob:  stockJob,
 	}
 }
 
 func (j *JobServer) Start(ctx context.Context) error {
 	// Tips: If you want job to start as a separate process, just refer to the task implementation and adjust the code accordingly.
+	ctx, j.cancel = context.WithCancel(ctx)
+
+	// publish domain events stored by the transactional outbox
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		if err := j.outboxJob.Relay(ctx); err != nil {
+			j.log.Error("OutboxRelay error", zap.Error(err))
+		}
+	}()
+
+	// consumers
+	j.consumer.Register(
+		j.conf.GetString("job.consumer.stock_updates.topic"),
+		j.stockJob.HandleStockUpdate,
+		job.WithConcurrency(j.conf.GetInt("job.consumer.stock_updates.concurrency")),
+		job.WithOrderingKey(job.StockUpdateKey),
+		job.WithRetry(j.conf.GetInt("job.consumer.stock_updates.max_retries"), j.conf.GetDuration("job.consumer.stock_updates.backoff")),
+	)
 
-	// eg: kafka consumer
-	err := j.userJob.KafkaConsumer(ctx)
-	return err
+	j.wg.Add(1)
+	defer j.wg.Done()
+	if err := j.consumer.Run(ctx); err != nil {
+		j.log.Error("Consumer error", zap.Error(err))
+		return err
+	}
+	return nil
 }
 func (j *JobServer) Stop(ctx context.Context) error {
+	if j.cancel != nil {
+		j.cancel()
+	}
+	j.wg.Wait()
+	j.log.Info("JobServer stop...")
 	return nil
 }
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	"github.com/quydmfl/niveau-test/internal/model"
@@ -22,20 +23,121 @@ func NewMigrateServer(db *gorm.DB, log *log.Logger) *MigrateServer {
 	}
 }
 func (m *MigrateServer) Start(ctx context.Context) error {
+	// AutoMigrate creates missing check constraints only, the product status one is dropped
+	// so it is created again with the statuses of the lifecycle. The migrator drops it with the syntax of
+	// the dialect, sqlite can't alter the constraints of a table.
+	if migrator := m.db.Migrator(); m.db.Dialector.Name() != "sqlite" && migrator.HasTable(&model.Product{}) &&
+		migrator.HasConstraint(&model.Product{}, "chk_products_status") {
+		if err := migrator.DropConstraint(&model.Product{}, "chk_products_status"); err != nil {
+			m.log.Error("check constraint migrate error", zap.Error(err))
+			return err
+		}
+	}
+
 	if err := m.db.AutoMigrate(
 		&model.User{},
 		&model.Product{},
 		&model.Category{},
+		&model.CategoryAttribute{},
+		&model.ProductAttribute{},
+		&model.ProductImage{},
+		&model.ProductLot{},
+		&model.BundleComponent{},
+		&model.ReferenceCounter{},
 		&model.Supplier{},
+		&model.SupplierContact{},
+		&model.SupplierAddress{},
 		&model.Documents{},
+		&model.OutboxEvent{},
+		&model.ProcessedMessage{},
+		&model.StockMovement{},
+		&model.TaskRun{},
+		&model.AuditLog{},
+		&model.ProductVersion{},
+		&model.ProductPrice{},
+		&model.PurchaseOrder{},
+		&model.PurchaseOrderLine{},
+		&model.Stocktake{},
+		&model.StocktakeLine{},
+		&model.StocktakeCount{},
+		&model.ProductForecast{},
+		&model.ProductStatusChange{},
 	); err != nil {
 		m.log.Error("user migrate error", zap.Error(err))
 		return err
 	}
+
+	// AutoMigrate creates missing constraints only, those declared with other actions before are created again
+	for _, c := range []struct {
+		model    interface{}
+		name     string
+		onDelete string
+	}{
+		{&model.Category{}, "Products", "RESTRICT"},
+		{&model.Product{}, "Documents", "SET NULL"},
+	} {
+		if err := m.migrateConstraint(c.model, c.name, c.onDelete); err != nil {
+			m.log.Error("constraint migrate error", zap.String("constraint", c.name), zap.Error(err))
+			return err
+		}
+	}
+	// Categories created before the tree are roots, the path is built here as || is a logical OR on MySQL
+	var categories []model.Category
+	if err := m.db.Unscoped().Where("path = ''").Find(&categories).Error; err != nil {
+		m.log.Error("category path migrate error", zap.Error(err))
+		return err
+	}
+	for _, category := range categories {
+		if err := m.db.Unscoped().Model(&category).
+			UpdateColumn("path", model.CategoryPath("", category.ID)).Error; err != nil {
+			m.log.Error("category path migrate error", zap.Error(err))
+			return err
+		}
+	}
+
 	m.log.Info("AutoMigrate success")
 	os.Exit(0)
 	return nil
 }
+
+// migrateConstraint drops and creates the foreign key of the relationship name when its delete rule is not onDelete
+func (m *MigrateServer) migrateConstraint(value interface{}, name string, onDelete string) error {
+	// sqlite can't alter the constraints of a table
+	if m.db.Dialector.Name() == "sqlite" {
+		return nil
+	}
+
+	stmt := &gorm.Statement{DB: m.db}
+	if err := stmt.Parse(value); err != nil {
+		return err
+	}
+	constraint := stmt.Schema.Relationships.Relations[name].ParseConstraint()
+	if constraint == nil {
+		return fmt.Errorf("relationship %s has no constraint", name)
+	}
+
+	var deleteRule string
+	if err := m.db.Raw("SELECT delete_rule FROM information_schema.referential_constraints WHERE constraint_name = ?", constraint.Name).
+		Scan(&deleteRule).Error; err != nil {
+		return err
+	}
+	if deleteRule == onDelete {
+		return nil
+	}
+
+	migrator := m.db.Migrator()
+	if deleteRule != "" {
+		if err := migrator.DropConstraint(value, constraint.Name); err != nil {
+			return err
+		}
+	}
+	if err := migrator.CreateConstraint(value, constraint.Name); err != nil {
+		return err
+	}
+	m.log.Info("Constraint migrated", zap.String("constraint", constraint.Name), zap.String("from", deleteRule), zap.String("to", onDelete))
+	return nil
+}
+
 func (m *MigrateServer) Stop(ctx context.Context) error {
 	m.log.Info("AutoMigrate stop")
 	return nil
@@ -2,55 +2,31 @@ package server
 
 import (
 	"context"
-	"time"
 
-	"github.com/go-co-op/gocron"
 	"github.com/quydmfl/niveau-test/internal/task"
 	"github.com/quydmfl/niveau-test/pkg/log"
-	"go.uber.org/zap"
 )
 
 type TaskServer struct {
-	log       *log.Logger
-	scheduler *gocron.Scheduler
-	userTask  task.UserTask
+	log      *log.Logger
+	registry *task.Registry
 }
 
 func NewTaskServer(
 	log *log.Logger,
-	userTask task.UserTask,
+	registry *task.Registry,
 ) *TaskServer {
 	return &TaskServer{
 		log:      log,
-		userTask: userTask,
+		registry: registry,
 	}
 }
 func (t *TaskServer) Start(ctx context.Context) error {
-	gocron.SetPanicHandler(func(jobName string, recoverData interface{}) {
-		t.log.Error("TaskServer Panic", zap.String("job", jobName), zap.Any("recover", recoverData))
-	})
-
-	// eg: crontab task
-	t.scheduler = gocron.NewScheduler(time.UTC)
-	// if you are in China, you will need to change the time zone as follows
-	// t.scheduler = gocron.NewScheduler(time.FixedZone("PRC", 8*60*60))
-
-	//_, err := t.scheduler.Every("3s").Do(func()
-	_, err := t.scheduler.CronWithSeconds("0/3 * * * * *").Do(func() {
-		err := t.userTask.CheckUser(ctx)
-		if err != nil {
-			t.log.Error("CheckUser error", zap.Error(err))
-		}
-	})
-	if err != nil {
-		t.log.Error("CheckUser error", zap.Error(err))
-	}
-
-	t.scheduler.StartBlocking()
-	return nil
+	// Every replica schedules all tasks, the registry makes sure a tick only runs once
+	return t.registry.Start()
 }
 func (t *TaskServer) Stop(ctx context.Context) error {
-	t.scheduler.Stop()
+	t.registry.Stop()
 	t.log.Info("TaskServer stop...")
 	return nil
 }
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/repository"
+)
+
+type AuditService interface {
+	GetAuditLogs(ctx context.Context, req *v1.SearchAuditLogRequest) (*v1.SearchAuditLogResponse, error)
+}
+
+func NewAuditService(
+	service *Service,
+	auditLogRepository repository.AuditLogRepository,
+) AuditService {
+	return &auditService{
+		Service:            service,
+		auditLogRepository: auditLogRepository,
+	}
+}
+
+type auditService struct {
+	*Service
+	auditLogRepository repository.AuditLogRepository
+}
+
+func (s *auditService) GetAuditLogs(ctx context.Context, req *v1.SearchAuditLogRequest) (*v1.SearchAuditLogResponse, error) {
+	var result []v1.GetAuditLogData
+
+	logs, total, err := s.auditLogRepository.Search(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range *logs {
+		data := v1.GetAuditLogData{
+			ID:         entry.ID,
+			ActorID:    entry.ActorID,
+			Action:     entry.Action,
+			EntityType: entry.EntityType,
+			EntityID:   entry.EntityID,
+			TraceID:    entry.TraceID,
+			IP:         entry.IP,
+			CreatedAt:  formatTime(&entry.CreatedAt),
+		}
+		if entry.Changes != "" {
+			data.Changes = json.RawMessage(entry.Changes)
+		}
+		result = append(result, data)
+	}
+
+	// Calculate total pages
+	totalPages := int(total / req.Size)
+	if total%req.Size > 0 {
+		totalPages++
+	}
+
+	return &v1.SearchAuditLogResponse{
+		Pagination: v1.Pagination{
+			Page:       req.Page,
+			TotalRows:  total,
+			TotalPages: totalPages,
+		},
+		Response: v1.Response{
+			Data: result,
+		},
+	}, nil
+}
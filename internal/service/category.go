@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,21 +15,36 @@ type CategoryService interface {
 	GetCategories(ctx context.Context, req *v1.SearchCategoryRequest) (*v1.SearchCategoryResponse, error)
 	GetCategory(ctx context.Context, id string) (*v1.GetCategoryDetailData, error)
 	CreateCategory(ctx context.Context, req *v1.CreateCategoryRequest) error
+	UpdateCategory(ctx context.Context, id string, req *v1.UpdateCategoryRequest, ifMatch v1.IfMatch) (*v1.GetCategoryDetailData, error)
+	MergeCategory(ctx context.Context, userId string, id string, req *v1.MergeCategoryRequest) (*v1.MergeCategoryData, error)
+	DeleteCategory(ctx context.Context, id string, ifMatch v1.IfMatch) error
+	GetDeletedCategories(ctx context.Context, req *v1.GetTrashRequest) (*v1.GetTrashResponse, error)
+	RestoreCategory(ctx context.Context, id string) error
+	PurgeCategory(ctx context.Context, id string) error
 }
 
 func NewCategoryService(
 	service *Service,
 	categoryRepository repository.CategoryRepository,
+	productRepository repository.ProductRepository,
+	productVersionRepository repository.ProductVersionRepository,
+	outboxRepository repository.OutboxRepository,
 ) CategoryService {
 	return &categoryService{
-		Service:            service,
-		categoryRepository: categoryRepository,
+		Service:                  service,
+		categoryRepository:       categoryRepository,
+		productRepository:        productRepository,
+		productVersionRepository: productVersionRepository,
+		outboxRepository:         outboxRepository,
 	}
 }
 
 type categoryService struct {
 	*Service
-	categoryRepository repository.CategoryRepository
+	categoryRepository       repository.CategoryRepository
+	productRepository        repository.ProductRepository
+	productVersionRepository repository.ProductVersionRepository
+	outboxRepository         repository.OutboxRepository
 }
 
 func (s *categoryService) GetCategories(ctx context.Context, req *v1.SearchCategoryRequest) (*v1.SearchCategoryResponse, error) {
@@ -39,14 +55,8 @@ func (s *categoryService) GetCategories(ctx context.Context, req *v1.SearchCateg
 		return nil, err
 	}
 
-	for _, category := range *categories {
-		result = append(result, v1.GetCategoryDetailData{
-			ID:        category.ID.String(),
-			Name:      category.Name,
-			Status:    category.Status,
-			CreatedAt: category.CreatedAt.Format("2006-01-02"),
-			UpdatedAt: category.UpdatedAt.Format("2006-01-02"),
-		})
+	for i := range *categories {
+		result = append(result, categoryDetailData(&(*categories)[i]))
 	}
 
 	// Calculate total pages
@@ -77,25 +87,42 @@ func (s *categoryService) GetCategory(ctx context.Context, id string) (*v1.GetCa
 	if err != nil {
 		return nil, err
 	}
+	if category.Attributes, err = s.categoryRepository.GetAttributes(ctx, category.ID); err != nil {
+		return nil, err
+	}
 
-	return &v1.GetCategoryDetailData{
-		ID:        category.ID.String(),
-		Name:      category.Name,
-		Status:    category.Status,
-		CreatedAt: category.CreatedAt.Format("2006-01-02"),
-		UpdatedAt: category.UpdatedAt.Format("2006-01-02"),
-	}, nil
+	data := categoryDetailData(category)
+	return &data, nil
 }
 
 func (s *categoryService) CreateCategory(ctx context.Context, req *v1.CreateCategoryRequest) error {
+	attributes, err := categoryAttributes(req.Attributes)
+	if err != nil {
+		return err
+	}
+
+	// The ID is known before the insert to build the path
 	category := &model.Category{
-		Name:      req.Name,
-		Status:    req.Status,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:         uuid.New(),
+		Name:       req.Name,
+		Status:     req.Status,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+		Attributes: attributes,
+
+		ReferencePrefix: req.ReferencePrefix,
 	}
 
-	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		parent, err := s.parentCategory(ctx, req.ParentId)
+		if err != nil {
+			return err
+		}
+		category.ParentID, category.Path = nil, model.CategoryPath("", category.ID)
+		if parent != nil {
+			category.ParentID, category.Path = &parent.ID, model.CategoryPath(parent.Path, category.ID)
+		}
+
 		if err := s.categoryRepository.Create(ctx, category); err != nil {
 			return err
 		}
@@ -104,3 +131,202 @@ func (s *categoryService) CreateCategory(ctx context.Context, req *v1.CreateCate
 
 	return err
 }
+
+func (s *categoryService) UpdateCategory(ctx context.Context, id string, req *v1.UpdateCategoryRequest, ifMatch v1.IfMatch) (*v1.GetCategoryDetailData, error) {
+	categoryUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, v1.ErrBadRequest
+	}
+	attributes, err := categoryAttributes(req.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	var category *model.Category
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		category, err = s.categoryRepository.GetCategoryByIdForUpdate(ctx, categoryUUID)
+		if err != nil {
+			return err
+		}
+		if !ifMatch.Matches(category.Version) {
+			return v1.ErrVersionMismatch
+		}
+
+		category.Name = req.Name
+		category.Status = req.Status
+		category.ReferencePrefix = req.ReferencePrefix
+		category.UpdatedAt = time.Now()
+
+		parent, err := s.parentCategory(ctx, req.ParentId)
+		if err != nil {
+			return err
+		}
+		if err := s.moveCategory(ctx, category, parent); err != nil {
+			return err
+		}
+
+		category.Attributes = attributes
+		return s.categoryRepository.ReplaceAttributes(ctx, category)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := categoryDetailData(category)
+	return &data, nil
+}
+
+// MergeCategory moves the products and subcategories of a category to the target, then puts it in the trash.
+// The moved products get a new version and a product update event like any other change,
+// their attribute values must fit the schema of the target.
+func (s *categoryService) MergeCategory(ctx context.Context, userId string, id string, req *v1.MergeCategoryRequest) (*v1.MergeCategoryData, error) {
+	sourceUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, v1.ErrBadRequest
+	}
+	targetUUID, _ := uuid.Parse(req.TargetId)
+
+	data := &v1.MergeCategoryData{}
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		source, err := s.categoryRepository.GetCategoryByIdForUpdate(ctx, sourceUUID)
+		if err != nil {
+			return err
+		}
+		target, err := s.categoryRepository.GetCategoryByIdForUpdate(ctx, targetUUID)
+		if err != nil {
+			return fmt.Errorf("category %s not found: %w", req.TargetId, v1.ErrBadRequest)
+		}
+		// The subcategories would move under themselves
+		if source.IsAncestorOf(target) {
+			return fmt.Errorf("category %s is in the subtree of %s: %w", target.ID, source.ID, v1.ErrBadRequest)
+		}
+
+		products, err := s.productRepository.GetByCategoryForUpdate(ctx, source.ID)
+		if err != nil {
+			return err
+		}
+		// The values are checked again against the schema of the target, a product that doesn't fit fails the merge
+		attributes := make(map[uuid.UUID][]model.ProductAttribute, len(products))
+		for i := range products {
+			if attributes[products[i].ID], err = productAttributes(ctx, s.categoryRepository, target.ID, products[i].AttributeValues()); err != nil {
+				return fmt.Errorf("product %s: %w", products[i].Reference, err)
+			}
+		}
+		if data.MovedProducts, err = s.productRepository.MoveCategory(ctx, source.ID, target.ID); err != nil {
+			return err
+		}
+		err = recordMovedProducts(ctx, s.productVersionRepository, s.outboxRepository, userId, products, func(product *model.Product) {
+			product.CategoryID = target.ID
+			product.Attributes = attributes[product.ID]
+		})
+		if err != nil {
+			return err
+		}
+		for i := range products {
+			if err := s.productRepository.ReplaceAttributes(ctx, &products[i]); err != nil {
+				return err
+			}
+		}
+
+		children, err := s.categoryRepository.GetChildren(ctx, source.ID)
+		if err != nil {
+			return err
+		}
+		for i := range children {
+			if err := s.moveCategory(ctx, &children[i], target); err != nil {
+				return err
+			}
+		}
+		data.MovedSubcategories = len(children)
+
+		return s.categoryRepository.Delete(ctx, source)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// parentCategory return the live category parentId, nil for an empty parentId
+func (s *categoryService) parentCategory(ctx context.Context, parentId string) (*model.Category, error) {
+	if parentId == "" {
+		return nil, nil
+	}
+	parentUUID, _ := uuid.Parse(parentId)
+	parent, err := s.categoryRepository.GetCategoryById(ctx, parentUUID)
+	if err != nil {
+		return nil, fmt.Errorf("parent category %s not found: %w", parentId, v1.ErrBadRequest)
+	}
+	return parent, nil
+}
+
+// moveCategory saves category under parent, nil for the root, its subtree follows it
+func (s *categoryService) moveCategory(ctx context.Context, category *model.Category, parent *model.Category) error {
+	oldPath := category.Path
+	category.ParentID, category.Path = nil, model.CategoryPath("", category.ID)
+	if parent != nil {
+		if category.IsAncestorOf(parent) {
+			return fmt.Errorf("category %s can't move under its subtree: %w", category.ID, v1.ErrBadRequest)
+		}
+		category.ParentID, category.Path = &parent.ID, model.CategoryPath(parent.Path, category.ID)
+	}
+
+	if err := s.categoryRepository.Update(ctx, category); err != nil {
+		return err
+	}
+	if category.Path == oldPath {
+		return nil
+	}
+	return s.categoryRepository.UpdatePaths(ctx, oldPath, category.Path)
+}
+
+func categoryDetailData(category *model.Category) v1.GetCategoryDetailData {
+	data := v1.GetCategoryDetailData{
+		ID:        category.ID.String(),
+		Name:      category.Name,
+		Status:    category.Status,
+		Path:      category.Path,
+		CreatedAt: category.CreatedAt.Format("2006-01-02"),
+		UpdatedAt: category.UpdatedAt.Format("2006-01-02"),
+		Version:   category.Version,
+
+		ReferencePrefix: category.ReferencePrefix,
+	}
+	if category.ParentID != nil {
+		data.ParentID = category.ParentID.String()
+	}
+	for _, attribute := range category.Attributes {
+		data.Attributes = append(data.Attributes, v1.CategoryAttributeData{
+			Name:          attribute.Name,
+			Type:          attribute.Type,
+			Required:      attribute.Required,
+			AllowedValues: attribute.AllowedValues,
+		})
+	}
+	return data
+}
+
+// categoryAttributes return the attribute schema of the requests, the names are unique in a category
+func categoryAttributes(requests []v1.CategoryAttributeRequest) ([]model.CategoryAttribute, error) {
+	names := make(map[string]bool, len(requests))
+	attributes := make([]model.CategoryAttribute, 0, len(requests))
+	for _, req := range requests {
+		if names[req.Name] {
+			return nil, fmt.Errorf("attribute %s is defined twice: %w", req.Name, v1.ErrBadRequest)
+		}
+		names[req.Name] = true
+
+		attribute := model.CategoryAttribute{
+			Name:          req.Name,
+			Type:          req.Type,
+			Required:      req.Required,
+			AllowedValues: req.AllowedValues,
+		}
+		if err := attribute.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", err, v1.ErrBadRequest)
+		}
+		attributes = append(attributes, attribute)
+	}
+	return attributes, nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strconv"
+	"time"
+
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+)
+
+// ForecastService reads the forecasts stored by the demand_forecast task
+type ForecastService interface {
+	GetForecasts(ctx context.Context, req *v1.SearchForecastRequest) (*v1.SearchForecastResponse, error)
+	GetProductForecast(ctx context.Context, productRef string) (*v1.ForecastData, error)
+	// ExportForecastsToCSV return every forecast of the filter as CSV, the largest reorder first
+	ExportForecastsToCSV(ctx context.Context, req *v1.ExportForecastsRequest) ([]byte, error)
+}
+
+func NewForecastService(
+	service *Service,
+	productForecastRepository repository.ProductForecastRepository,
+	productRepository repository.ProductRepository,
+) ForecastService {
+	return &forecastService{
+		Service:                   service,
+		productForecastRepository: productForecastRepository,
+		productRepository:         productRepository,
+	}
+}
+
+type forecastService struct {
+	*Service
+	productForecastRepository repository.ProductForecastRepository
+	productRepository         repository.ProductRepository
+}
+
+func (s *forecastService) GetForecasts(ctx context.Context, req *v1.SearchForecastRequest) (*v1.SearchForecastResponse, error) {
+	var result []v1.ForecastData
+
+	forecasts, total, err := s.productForecastRepository.Search(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range *forecasts {
+		result = append(result, forecastData(&(*forecasts)[i]))
+	}
+
+	// Calculate total pages
+	totalPages := int(total / req.Size)
+	if total%req.Size > 0 {
+		totalPages++
+	}
+
+	return &v1.SearchForecastResponse{
+		Pagination: v1.Pagination{
+			Page:       req.Page,
+			TotalRows:  total,
+			TotalPages: totalPages,
+		},
+		Response: v1.Response{
+			Data: result,
+		},
+	}, nil
+}
+
+func (s *forecastService) GetProductForecast(ctx context.Context, productRef string) (*v1.ForecastData, error) {
+	product, err := s.productRepository.GetProductByPref(ctx, productRef)
+	if err != nil {
+		return nil, err
+	}
+
+	// Not found until the task ran since the product was added
+	forecast, err := s.productForecastRepository.GetByProduct(ctx, product.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := forecastData(forecast)
+	return &data, nil
+}
+
+func (s *forecastService) ExportForecastsToCSV(ctx context.Context, req *v1.ExportForecastsRequest) ([]byte, error) {
+	forecasts, _, err := s.productForecastRepository.Search(ctx, &v1.SearchForecastRequest{ForecastFilter: req.ForecastFilter})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	records := [][]string{{
+		"reference", "name", "supplier", "method", "daily_demand", "moving_average", "exponential_smoothing",
+		"demand_std_dev", "lead_time_days", "safety_stock", "quantity", "on_order", "reorder_point",
+		"reorder_quantity", "computed_at",
+	}}
+	for _, forecast := range *forecasts {
+		records = append(records, []string{
+			forecast.Reference,
+			forecast.Name,
+			forecast.SupplierName,
+			forecast.Method,
+			strconv.FormatFloat(forecast.DailyDemand, 'f', -1, 64),
+			strconv.FormatFloat(forecast.MovingAverage, 'f', -1, 64),
+			strconv.FormatFloat(forecast.ExponentialSmoothing, 'f', -1, 64),
+			strconv.FormatFloat(forecast.DemandStdDev, 'f', -1, 64),
+			strconv.Itoa(forecast.LeadTimeDays),
+			strconv.Itoa(forecast.SafetyStock),
+			strconv.Itoa(forecast.Quantity),
+			strconv.Itoa(forecast.OnOrder),
+			strconv.Itoa(forecast.ReorderPoint),
+			strconv.Itoa(forecast.ReorderQuantity),
+			forecast.ComputedAt.Format(time.RFC3339),
+		})
+	}
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func forecastData(forecast *model.ProductForecast) v1.ForecastData {
+	return v1.ForecastData{
+		Reference:            forecast.Reference,
+		ProductName:          forecast.Name,
+		SupplierID:           forecast.SupplierID.String(),
+		SupplierName:         forecast.SupplierName,
+		Method:               forecast.Method,
+		PeriodDays:           forecast.PeriodDays,
+		Periods:              forecast.Periods,
+		MovingAverage:        forecast.MovingAverage,
+		ExponentialSmoothing: forecast.ExponentialSmoothing,
+		DailyDemand:          forecast.DailyDemand,
+		DemandStdDev:         forecast.DemandStdDev,
+		LeadTimeDays:         forecast.LeadTimeDays,
+		SafetyStock:          forecast.SafetyStock,
+		Quantity:             forecast.Quantity,
+		OnOrder:              forecast.OnOrder,
+		ReorderPoint:         forecast.ReorderPoint,
+		ReorderQuantity:      forecast.ReorderQuantity,
+		ComputedAt:           forecast.ComputedAt.Format(time.RFC3339),
+	}
+}
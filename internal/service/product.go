@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 	"fmt"
+	"mime/multipart"
 	"os"
 	"path/filepath"
+	"reflect"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,28 +14,72 @@ import (
 	"github.com/quydmfl/niveau-test/internal/helper"
 	"github.com/quydmfl/niveau-test/internal/model"
 	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/quydmfl/niveau-test/pkg/reference"
 
 	"github.com/jung-kurt/gofpdf"
+	"github.com/spf13/viper"
 )
 
 type ProductService interface {
 	SearchProduct(ctx context.Context, req *v1.SearchProductRequest) (*v1.SearchProductResponse, error)
 	GetProduct(ctx context.Context, productRef string) (*v1.GetProductDetailData, error)
+	// GetProductByGTIN resolves a scanned code to its product
+	GetProductByGTIN(ctx context.Context, gtin string) (*v1.GetProductDetailData, error)
 	CreateProduct(ctx context.Context, userId string, req *v1.CreateProductRequest) error
-	UpdateProduct(ctx context.Context, userId string, productRef string, req *v1.UpdateProductRequest) error
-	DeleteProduct(ctx context.Context, productRef string) error
-	ExportProductsToPDF(ctx context.Context, productPref string) error
+	CreateVariant(ctx context.Context, userId string, productRef string, req *v1.CreateVariantRequest) (*v1.GetProductDetailData, error)
+	UpdateProduct(ctx context.Context, userId string, productRef string, req *v1.UpdateProductRequest, ifMatch v1.IfMatch) error
+	PatchProduct(ctx context.Context, userId string, productRef string, req *v1.PatchProductRequest, ifMatch v1.IfMatch) (*v1.PatchProductData, error)
+	TransitionProduct(ctx context.Context, userId string, productRef string, req *v1.TransitionProductRequest, ifMatch v1.IfMatch) (*v1.PatchProductData, error)
+	GetProductStatusHistory(ctx context.Context, productRef string, req *v1.GetProductStatusHistoryRequest) (*v1.GetProductStatusHistoryResponse, error)
+	DeleteProduct(ctx context.Context, productRef string, ifMatch v1.IfMatch) error
+	ExportProductsToPDF(ctx context.Context, productPref string, req *v1.ExportProductRequest) error
 	CalculateDistance(ctx context.Context, ip string, city string) (from string, to string, distance float64, err error)
 	StatsProductsPerCategory(ctx context.Context) ([]v1.ProductCategoryStatsResponse, error)
 	StatsProductsPerSupplier(ctx context.Context) ([]v1.ProductSupplierStatsResponse, error)
+	GetProductVersions(ctx context.Context, productRef string, req *v1.GetProductVersionsRequest) (*v1.GetProductVersionsResponse, error)
+	GetProductVersion(ctx context.Context, productRef string, version int) (*v1.GetProductVersionData, error)
+	GetProductAsOf(ctx context.Context, productRef string, req *v1.GetProductAsOfRequest) (*v1.GetProductVersionData, error)
+	DiffProductVersions(ctx context.Context, productRef string, req *v1.DiffProductVersionsRequest) (*v1.DiffProductVersionsData, error)
+	RestoreProductVersion(ctx context.Context, userId string, productRef string, version int) (*v1.GetProductVersionData, error)
+	GetProductPrices(ctx context.Context, productRef string, req *v1.GetProductPricesRequest) (*v1.GetProductPricesResponse, error)
+	ScheduleProductPrice(ctx context.Context, userId string, productRef string, req *v1.ScheduleProductPriceRequest) (*v1.GetProductPriceData, error)
+	CancelProductPrice(ctx context.Context, productRef string, priceId uint64) error
+	GetDeletedProducts(ctx context.Context, req *v1.GetTrashRequest) (*v1.GetTrashResponse, error)
+	RestoreProduct(ctx context.Context, productRef string) error
+	PurgeProduct(ctx context.Context, productRef string) error
+	GetProductImages(ctx context.Context, productRef string) ([]v1.ProductImageData, error)
+	UploadProductImage(ctx context.Context, productRef string, file *multipart.FileHeader) (*v1.ProductImageData, error)
+	ReorderProductImages(ctx context.Context, productRef string, req *v1.ReorderProductImagesRequest) ([]v1.ProductImageData, error)
+	DeleteProductImage(ctx context.Context, productRef string, imageId string) error
+	GetProductBarcode(ctx context.Context, productRef string, req *v1.GetProductBarcodeRequest) ([]byte, string, error)
+	ExportProductLabelsToPDF(ctx context.Context, req *v1.ExportProductLabelsRequest) (*v1.ExportProductLabelsData, error)
+	GetProductLots(ctx context.Context, productRef string, req *v1.GetProductLotsRequest) ([]v1.ProductLotData, error)
+	ReceiveProductLot(ctx context.Context, userId string, productRef string, req *v1.ReceiveProductLotRequest) (*v1.ProductLotData, error)
+	ConsumeProductLots(ctx context.Context, userId string, productRef string, req *v1.ConsumeProductLotsRequest) (*v1.ConsumeProductLotsData, error)
+	GetExpiringLots(ctx context.Context, req *v1.GetExpiringLotsRequest) (*v1.GetExpiringLotsResponse, error)
+	ExportExpiringLotsToPDF(ctx context.Context, req *v1.ExportExpiringLotsRequest) (*v1.ExportExpiringLotsData, error)
+	BulkUpdateProducts(ctx context.Context, userId string, req *v1.BulkUpdateProductsRequest) (*v1.BulkProductsData, error)
+	BulkDeleteProducts(ctx context.Context, req *v1.BulkDeleteProductsRequest) (*v1.BulkProductsData, error)
+	SetBundleComponents(ctx context.Context, productRef string, req *v1.SetBundleComponentsRequest) (*v1.GetProductDetailData, error)
+	IssueBundle(ctx context.Context, userId string, productRef string, req *v1.IssueBundleRequest) (*v1.IssueBundleData, error)
 }
 
 func NewProductService(
 	service *Service,
+	conf *viper.Viper,
 	productRepository repository.ProductRepository,
 	categoryRepository repository.CategoryRepository,
 	documentRepository repository.DocumentsRepository,
 	supplierRepository repository.SupplierRepository,
+	outboxRepository repository.OutboxRepository,
+	productVersionRepository repository.ProductVersionRepository,
+	productPriceRepository repository.ProductPriceRepository,
+	productStatusChangeRepository repository.ProductStatusChangeRepository,
+	productImageRepository repository.ProductImageRepository,
+	productLotRepository repository.ProductLotRepository,
+	stockMovementRepository repository.StockMovementRepository,
+	referenceCounterRepository repository.ReferenceCounterRepository,
+	bundleComponentRepository repository.BundleComponentRepository,
 ) ProductService {
 	return &productService{
 		Service:            service,
@@ -41,6 +87,24 @@ func NewProductService(
 		categoryRepository: categoryRepository,
 		documentRepository: documentRepository,
 		supplierRepository: supplierRepository,
+		outboxRepository:   outboxRepository,
+
+		productVersionRepository: productVersionRepository,
+		productPriceRepository:   productPriceRepository,
+
+		productStatusChangeRepository: productStatusChangeRepository,
+		productImageRepository:        productImageRepository,
+		imageConfig:                   newProductImageConfig(conf),
+
+		productLotRepository:    productLotRepository,
+		stockMovementRepository: stockMovementRepository,
+
+		referenceCounterRepository: referenceCounterRepository,
+		referenceFormat:            reference.NewFormat(conf),
+
+		bulkLimit: productBulkLimit(conf),
+
+		bundleComponentRepository: bundleComponentRepository,
 	}
 }
 
@@ -50,6 +114,34 @@ type productService struct {
 	documentRepository repository.DocumentsRepository
 	categoryRepository repository.CategoryRepository
 	supplierRepository repository.SupplierRepository
+	outboxRepository   repository.OutboxRepository
+
+	productVersionRepository repository.ProductVersionRepository
+	productPriceRepository   repository.ProductPriceRepository
+
+	productStatusChangeRepository repository.ProductStatusChangeRepository
+	productImageRepository        repository.ProductImageRepository
+	imageConfig                   productImageConfig
+
+	productLotRepository    repository.ProductLotRepository
+	stockMovementRepository repository.StockMovementRepository
+
+	referenceCounterRepository repository.ReferenceCounterRepository
+	referenceFormat            *reference.Format
+
+	bulkLimit int
+
+	bundleComponentRepository repository.BundleComponentRepository
+}
+
+// addProductEvent must be called inside a Transaction
+func (s *productService) addProductEvent(ctx context.Context, eventType string, product *model.Product) error {
+	event, err := model.NewProductEvent(eventType, product)
+	if err != nil {
+		return err
+	}
+
+	return s.outboxRepository.Create(ctx, event)
 }
 
 func (s *productService) SearchProduct(ctx context.Context, req *v1.SearchProductRequest) (*v1.SearchProductResponse, error) {
@@ -59,18 +151,49 @@ func (s *productService) SearchProduct(ctx context.Context, req *v1.SearchProduc
 		return nil, err
 	}
 
-	for _, product := range *products {
-		result = append(result, v1.GetProductDetailData{
-			Reference:     product.Reference,
-			ProductName:   product.Name,
-			Category:      product.Category.Name,
-			Price:         product.Price,
-			Status:        product.Status,
-			StockLocation: product.StockCity,
-			DateAdded:     product.DateAdded.Format("2006-01-02"),
-			Quantity:      product.Quantity,
-			Supplier:      product.Supplier.Name,
-		})
+	var effectivePrices map[uuid.UUID]float64
+	if req.PriceAt != "" {
+		priceAt, err := time.Parse("2006-01-02", req.PriceAt)
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]uuid.UUID, 0, len(*products))
+		for _, product := range *products {
+			ids = append(ids, product.ID)
+		}
+		if effectivePrices, err = s.productPriceRepository.GetEffectivePrices(ctx, ids, priceAt); err != nil {
+			return nil, err
+		}
+	}
+
+	// Collapsed parents show the stock of their variants
+	var variantStocks map[uuid.UUID]repository.VariantStock
+	if req.CollapseVariants {
+		ids := make([]uuid.UUID, 0, len(*products))
+		for _, product := range *products {
+			ids = append(ids, product.ID)
+		}
+		if variantStocks, err = s.productRepository.SumVariantQuantities(ctx, ids); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range *products {
+		product := &(*products)[i]
+		data := productDetailData(product)
+		if stock, ok := variantStocks[product.ID]; ok {
+			totalQuantity := product.Quantity + stock.Quantity
+			data.VariantCount = stock.Count
+			data.TotalQuantity = &totalQuantity
+		}
+		if effectivePrices != nil {
+			price, ok := effectivePrices[product.ID]
+			if !ok {
+				price = product.Price
+			}
+			data.EffectivePrice = &price
+		}
+		result = append(result, *data)
 	}
 
 	// Calculate total pages
@@ -97,17 +220,7 @@ func (s *productService) GetProduct(ctx context.Context, productRef string) (*v1
 		return nil, err
 	}
 
-	return &v1.GetProductDetailData{
-		Reference:     product.Reference,
-		ProductName:   product.Name,
-		Category:      product.Category.Name,
-		Price:         product.Price,
-		Status:        product.Status,
-		StockLocation: product.StockCity,
-		DateAdded:     product.DateAdded.Format("2006-01-02"),
-		Quantity:      product.Quantity,
-		Supplier:      product.Supplier.Name,
-	}, nil
+	return productDetailData(product), nil
 }
 
 func (s *productService) CreateProduct(ctx context.Context, userId string, req *v1.CreateProductRequest) error {
@@ -121,6 +234,11 @@ func (s *productService) CreateProduct(ctx context.Context, userId string, req *
 		return fmt.Errorf("supplier %s not found", req.SupplierId)
 	}
 
+	attributes, err := productAttributes(ctx, s.categoryRepository, categoryUUID, req.Attributes)
+	if err != nil {
+		return err
+	}
+
 	product := &model.Product{
 		Reference:  req.Reference,
 		Name:       req.ProductName,
@@ -131,19 +249,42 @@ func (s *productService) CreateProduct(ctx context.Context, userId string, req *
 		Status:     req.Status,
 		DateAdded:  time.Now(),
 		Quantity:   req.Quantity,
+		GTIN:       newGTIN(req.GTIN),
+		Attributes: attributes,
 	}
 
-	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
-		if err := s.productRepository.Create(ctx, product); err != nil {
-			return err
-		}
-		return nil
+	return s.tm.Transaction(ctx, func(ctx context.Context) error {
+		return s.createProduct(ctx, userId, product)
 	})
+}
 
-	return err
+// createProduct stores a new product with its first price and version, it must be called inside a Transaction
+func (s *productService) createProduct(ctx context.Context, userId string, product *model.Product) error {
+	if err := s.setReference(ctx, product); err != nil {
+		return err
+	}
+	if err := s.checkGTIN(ctx, product); err != nil {
+		return err
+	}
+	if err := s.productRepository.Create(ctx, product); err != nil {
+		return err
+	}
+	if err := s.productPriceRepository.Create(ctx, &model.ProductPrice{
+		ProductID:     product.ID,
+		Price:         product.Price,
+		EffectiveFrom: product.DateAdded,
+		AuthorID:      userId,
+		CreatedAt:     time.Now(),
+	}); err != nil {
+		return err
+	}
+	if _, err := s.productVersionRepository.Append(ctx, nil, product, userId); err != nil {
+		return err
+	}
+	return s.addProductEvent(ctx, model.ProductCreatedEvent, product)
 }
 
-func (s *productService) UpdateProduct(ctx context.Context, userId string, productRef string, req *v1.UpdateProductRequest) error {
+func (s *productService) UpdateProduct(ctx context.Context, userId string, productRef string, req *v1.UpdateProductRequest, ifMatch v1.IfMatch) error {
 	// Before validator from request, we're skip check error in here
 	dateAdded, err := time.Parse("2006-01-02", req.DateAdded)
 	if err != nil {
@@ -160,37 +301,338 @@ func (s *productService) UpdateProduct(ctx context.Context, userId string, produ
 		return fmt.Errorf("supplier %s not found", req.SupplierId)
 	}
 
-	product, err := s.productRepository.GetProductByPref(ctx, productRef)
+	_, _, err = s.updateProduct(ctx, userId, productRef, "", ifMatch, func(ctx context.Context, product *model.Product) error {
+		// The status only changes through a transition, which keeps the reason of the change
+		if req.Status != product.Status {
+			return v1.ErrStatusNeedsTransition
+		}
+		product.Name = req.ProductName
+		product.CategoryID = categoryUUID
+		product.StockCity = req.StockLocation
+		product.DateAdded = dateAdded
+		product.SupplierID = supplierUUID
+		product.Quantity = req.Quantity
+		product.GTIN = newGTIN(req.GTIN)
+
+		product.Options = productOptions(nil, req.Options)
+
+		var err error
+		if product.Attributes, err = productAttributes(ctx, s.categoryRepository, categoryUUID, req.Attributes); err != nil {
+			return err
+		}
+
+		// A new price takes effect now and is kept in the price history
+		if req.Price != product.Price {
+			if _, err := s.schedulePrice(ctx, userId, product, req.Price, time.Now()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *productService) PatchProduct(ctx context.Context, userId string, productRef string, req *v1.PatchProductRequest, ifMatch v1.IfMatch) (*v1.PatchProductData, error) {
+	product, changes, err := s.updateProduct(ctx, userId, productRef, "", ifMatch, func(ctx context.Context, product *model.Product) error {
+		if req.Fields["product_name"] {
+			product.Name = req.ProductName
+		}
+		if req.Fields["status"] && req.Status != product.Status {
+			return v1.ErrStatusNeedsTransition
+		}
+		if req.Fields["stock_location"] {
+			product.StockCity = req.StockLocation
+		}
+		if req.Fields["options"] {
+			product.Options = productOptions(product.Options, req.Options)
+		}
+		if req.Fields["quantity"] {
+			product.Quantity = req.Quantity
+		}
+		if req.Fields["gtin"] {
+			product.GTIN = newGTIN(req.GTIN)
+		}
+		if req.Fields["added_date"] {
+			dateAdded, err := time.Parse("2006-01-02", req.DateAdded)
+			if err != nil {
+				return fmt.Errorf("added date %s: %w", req.DateAdded, v1.ErrBadRequest)
+			}
+			product.DateAdded = dateAdded
+		}
+
+		// References are only checked when they change
+		if req.Fields["category_id"] {
+			categoryUUID, _ := uuid.Parse(req.CategoryId)
+			if categoryUUID != product.CategoryID {
+				if _, err := s.categoryRepository.GetCategoryById(ctx, categoryUUID); err != nil {
+					return fmt.Errorf("category %s not found: %w", req.CategoryId, v1.ErrBadRequest)
+				}
+				product.CategoryID = categoryUUID
+			}
+		}
+		// The values are checked again against the schema of a new category
+		if req.Fields["attributes"] || req.Fields["category_id"] {
+			values := product.AttributeValues()
+			if values == nil {
+				values = make(map[string]interface{}, len(req.Attributes))
+			}
+			for name, value := range req.Attributes {
+				if value == nil {
+					delete(values, name)
+					continue
+				}
+				values[name] = value
+			}
+
+			var err error
+			if product.Attributes, err = productAttributes(ctx, s.categoryRepository, product.CategoryID, values); err != nil {
+				return err
+			}
+		}
+		if req.Fields["supplier_id"] {
+			supplierUUID, _ := uuid.Parse(req.SupplierId)
+			if supplierUUID != product.SupplierID {
+				if _, err := s.supplierRepository.GetSupplierById(ctx, supplierUUID); err != nil {
+					return fmt.Errorf("supplier %s not found: %w", req.SupplierId, v1.ErrBadRequest)
+				}
+				product.SupplierID = supplierUUID
+			}
+		}
+
+		if req.Fields["price"] && req.Price != product.Price {
+			if _, err := s.schedulePrice(ctx, userId, product, req.Price, time.Now()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	return &v1.PatchProductData{
+		Version: product.Version,
+		Changes: changes,
+	}, nil
+}
+
+// updateProduct locks the product and lets apply change it, the changes are saved as a new version of the product.
+// Nothing is written when apply leaves the product as it was.
+// A change of status must be allowed from the current one, it is kept in the status history with reason.
+func (s *productService) updateProduct(
+	ctx context.Context,
+	userId string,
+	productRef string,
+	reason string,
+	ifMatch v1.IfMatch,
+	apply func(ctx context.Context, product *model.Product) error,
+) (*model.Product, map[string]v1.ProductFieldChange, error) {
+	var (
+		product *model.Product
+		changes map[string]v1.ProductFieldChange
+	)
+
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		// The row lock serializes the version numbers of concurrent updates
+		product, err = s.productRepository.GetProductByPrefForUpdate(ctx, productRef)
+		if err != nil {
+			return err
+		}
+		if !ifMatch.Matches(product.Version) {
+			return v1.ErrVersionMismatch
+		}
+		before := *product
+
+		if err := apply(ctx, product); err != nil {
+			return err
+		}
+		changes = productChanges(&before, product)
+		if len(changes) == 0 {
+			return nil
+		}
+		if product.Status != before.Status && !before.CanBecome(product.Status) {
+			return fmt.Errorf("product %s can't move from %s to %s: %w", product.Reference, before.Status, product.Status, v1.ErrProductTransition)
+		}
+		if err := s.checkVariant(ctx, &before, product); err != nil {
+			return err
+		}
+		if _, ok := changes["gtin"]; ok {
+			if err := s.checkGTIN(ctx, product); err != nil {
+				return err
+			}
+		}
+		if product.Quantity < before.Quantity {
+			if err := s.checkLotStock(ctx, product); err != nil {
+				return err
+			}
+		}
+		if _, ok := changes["quantity"]; ok {
+			if err := checkBundleStock(ctx, s.bundleComponentRepository, product); err != nil {
+				return err
+			}
+		}
+
+		if err := s.productRepository.Update(ctx, product); err != nil {
+			return err
+		}
+		if _, ok := changes["attributes"]; ok {
+			if err := s.productRepository.ReplaceAttributes(ctx, product); err != nil {
+				return err
+			}
+		}
+		if err := s.moveVariants(ctx, userId, &before, product); err != nil {
+			return err
+		}
+		if _, err := s.productVersionRepository.Append(ctx, &before, product, userId); err != nil {
+			return err
+		}
+		if product.Status != before.Status {
+			if err := s.productStatusChangeRepository.Create(ctx, model.NewProductStatusChange(before.Status, product, reason, userId)); err != nil {
+				return err
+			}
+		}
+		// The stock ledger has a row per change of the quantity
+		if _, ok := changes["quantity"]; ok {
+			if err := s.stockMovementRepository.Create(ctx, &model.StockMovement{
+				ProductID:     product.ID,
+				Delta:         product.Quantity - before.Quantity,
+				QuantityAfter: product.Quantity,
+				Reason:        model.StockMovementManualAdjustment,
+				ActorID:       userId,
+			}); err != nil {
+				return err
+			}
+		}
+		return s.addProductEvent(ctx, model.ProductUpdatedEvent, product)
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 
-	product.Name = req.ProductName
-	product.CategoryID = categoryUUID
-	product.Price = req.Price
-	product.Status = req.Status
-	product.DateAdded = dateAdded
-	product.SupplierID = supplierUUID
-	product.Quantity = req.Quantity
+	return product, changes, nil
+}
 
-	if err = s.productRepository.Update(ctx, product); err != nil {
-		return err
+// productChanges compares the fields a client can update, by their name in the requests
+func productChanges(before *model.Product, after *model.Product) map[string]v1.ProductFieldChange {
+	changes := make(map[string]v1.ProductFieldChange)
+	compare := func(name string, oldValue interface{}, newValue interface{}) {
+		if oldValue != newValue {
+			changes[name] = v1.ProductFieldChange{Old: oldValue, New: newValue}
+		}
 	}
 
-	return nil
+	compare("product_name", before.Name, after.Name)
+	compare("category_id", before.CategoryID.String(), after.CategoryID.String())
+	compare("price", before.Price, after.Price)
+	compare("status", before.Status, after.Status)
+	compare("stock_location", before.StockCity, after.StockCity)
+	compare("added_date", before.DateAdded.Format("2006-01-02"), after.DateAdded.Format("2006-01-02"))
+	compare("supplier_id", before.SupplierID.String(), after.SupplierID.String())
+	compare("quantity", before.Quantity, after.Quantity)
+	compare("gtin", gtinValue(before.GTIN), gtinValue(after.GTIN))
+
+	// Maps are not comparable with !=
+	if oldValues, newValues := before.AttributeValues(), after.AttributeValues(); !reflect.DeepEqual(oldValues, newValues) {
+		changes["attributes"] = v1.ProductFieldChange{Old: oldValues, New: newValues}
+	}
+	if !reflect.DeepEqual(before.Options, after.Options) {
+		changes["options"] = v1.ProductFieldChange{Old: before.Options, New: after.Options}
+	}
+
+	return changes
+}
+
+// productOptions merges the options of a request into options, an empty value removes the option.
+// It return nil for no options, as they are read from the database.
+func productOptions(options map[string]string, changes map[string]string) map[string]string {
+	merged := make(map[string]string, len(options)+len(changes))
+	for name, value := range options {
+		merged[name] = value
+	}
+	for name, value := range changes {
+		if value == "" {
+			delete(merged, name)
+			continue
+		}
+		merged[name] = value
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
 }
 
-func (s *productService) DeleteProduct(ctx context.Context, productRef string) error {
-	return s.productRepository.Delete(ctx, productRef)
+// productAttributes checks the attribute values against the schema of the category
+func productAttributes(
+	ctx context.Context,
+	categoryRepository repository.CategoryRepository,
+	categoryID uuid.UUID,
+	values map[string]interface{},
+) ([]model.ProductAttribute, error) {
+	schema, err := categoryRepository.GetAttributes(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	attributes, err := model.NewProductAttributes(schema, values)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", err, v1.ErrBadRequest)
+	}
+	return attributes, nil
 }
 
-func (s *productService) ExportProductsToPDF(ctx context.Context, productPref string) error {
+func (s *productService) DeleteProduct(ctx context.Context, productRef string, ifMatch v1.IfMatch) error {
+	return s.tm.Transaction(ctx, func(ctx context.Context) error {
+		product, err := s.productRepository.GetProductByPrefForUpdate(ctx, productRef)
+		if err != nil {
+			return err
+		}
+		if !ifMatch.Matches(product.Version) {
+			return v1.ErrVersionMismatch
+		}
+
+		// The variants are deleted first
+		count, err := s.productRepository.CountVariants(ctx, product.ID, false)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return v1.ErrProductHasVariants
+		}
+		// A bundle would lose the component
+		if count, err = s.bundleComponentRepository.CountBundles(ctx, product.ID); err != nil {
+			return err
+		}
+		if count > 0 {
+			return v1.ErrProductInBundle
+		}
+
+		if err := s.productRepository.Delete(ctx, product); err != nil {
+			return err
+		}
+		return s.addProductEvent(ctx, model.ProductDeletedEvent, product)
+	})
+}
+
+func (s *productService) ExportProductsToPDF(ctx context.Context, productPref string, req *v1.ExportProductRequest) error {
 	product, err := s.productRepository.GetProductByPref(ctx, productPref)
 	if err != nil {
 		return err
 	}
 
-	fileName, filePath, err := exportProductPdf(ctx, product)
+	priceLabel := "Price"
+	if req.PriceDate != "" {
+		priceDate, err := time.Parse("2006-01-02", req.PriceDate)
+		if err != nil {
+			return err
+		}
+		if product.Price, err = s.priceAt(ctx, product, priceDate); err != nil {
+			return err
+		}
+		priceLabel = fmt.Sprintf("Price on %s", req.PriceDate)
+	}
+
+	fileName, filePath, err := exportProductPdf(ctx, product, priceLabel)
 	if err != nil {
 		return err
 	}
@@ -199,6 +641,7 @@ func (s *productService) ExportProductsToPDF(ctx context.Context, productPref st
 	document := model.Documents{
 		Filename:   fileName,
 		Path:       filePath,
+		Kind:       model.DocumentKindProductPDF,
 		ProductID:  &product.ID,
 		UploadedAt: time.Now(),
 	}
@@ -210,7 +653,7 @@ func (s *productService) ExportProductsToPDF(ctx context.Context, productPref st
 	return nil
 }
 
-func exportProductPdf(ctx context.Context, product *model.Product) (string, string, error) {
+func exportProductPdf(ctx context.Context, product *model.Product, priceLabel string) (string, string, error) {
 	storagePath := "./storage/pdf"
 	err := os.MkdirAll(storagePath, os.ModePerm)
 	if err != nil {
@@ -223,6 +666,15 @@ func exportProductPdf(ctx context.Context, product *model.Product) (string, stri
 
 	// Heading //
 	pdf.Cell(40, 10, "Product Information")
+
+	// Primary image, at the top right next to the details //
+	if len(product.Images) > 0 {
+		if thumbnail := product.Images[0].LargestThumbnail(); thumbnail != "" {
+			if _, err := os.Stat(thumbnail); err == nil {
+				pdf.ImageOptions(thumbnail, 145, 20, 50, 0, false, gofpdf.ImageOptions{}, 0, "")
+			}
+		}
+	}
 	pdf.Ln(12)
 
 	// Product Details //
@@ -237,7 +689,7 @@ func exportProductPdf(ctx context.Context, product *model.Product) (string, stri
 	pdf.Ln(8)
 	pdf.Cell(40, 10, fmt.Sprintf("Category Name: %s", product.Category.Name))
 	pdf.Ln(8)
-	pdf.Cell(40, 10, fmt.Sprintf("Price: $%.2f", product.Price))
+	pdf.Cell(40, 10, fmt.Sprintf("%s: $%.2f", priceLabel, product.Price))
 	pdf.Ln(8)
 	pdf.Cell(40, 10, fmt.Sprintf("Quantity: %d", product.Quantity))
 	pdf.Ln(8)
@@ -245,6 +697,37 @@ func exportProductPdf(ctx context.Context, product *model.Product) (string, stri
 	pdf.Ln(8)
 	pdf.Cell(40, 10, fmt.Sprintf("Date Added: %s", product.DateAdded.Format("2006-01-02")))
 	pdf.Ln(8)
+	if product.Parent != nil {
+		pdf.Cell(40, 10, fmt.Sprintf("Variant of: %s (%s)", product.Parent.Reference, formatOptions(product.Options)))
+		pdf.Ln(8)
+	}
+
+	// Variants //
+	if len(product.Variants) > 0 {
+		pdf.Ln(4)
+		widths := []float64{45, 65, 30, 20, 30}
+		pdf.SetFont("Arial", "B", 11)
+		for i, header := range []string{"Reference", "Options", "Price", "Qty", "Status"} {
+			pdf.CellFormat(widths[i], 8, header, "1", 0, "C", false, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Arial", "", 10)
+		totalQuantity := product.Quantity
+		for _, variant := range product.Variants {
+			pdf.CellFormat(widths[0], 8, variant.Reference, "1", 0, "L", false, 0, "")
+			pdf.CellFormat(widths[1], 8, formatOptions(variant.Options), "1", 0, "L", false, 0, "")
+			pdf.CellFormat(widths[2], 8, fmt.Sprintf("%.2f", variant.Price), "1", 0, "R", false, 0, "")
+			pdf.CellFormat(widths[3], 8, fmt.Sprintf("%d", variant.Quantity), "1", 0, "R", false, 0, "")
+			pdf.CellFormat(widths[4], 8, variant.Status, "1", 0, "L", false, 0, "")
+			pdf.Ln(-1)
+			totalQuantity += variant.Quantity
+		}
+		pdf.SetFont("Arial", "B", 11)
+		pdf.CellFormat(widths[0]+widths[1]+widths[2], 8, "Total Quantity", "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 8, fmt.Sprintf("%d", totalQuantity), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[4], 8, "", "1", 0, "L", false, 0, "")
+		pdf.Ln(-1)
+	}
 
 	// Unique file name //
 	fileName := fmt.Sprintf("product_%s_%s.pdf", product.Reference, time.Now().Format("20060102_150405"))
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// productBulkLimit is the most products a bulk operation may change, a target matching more is refused
+func productBulkLimit(conf *viper.Viper) int {
+	limit := conf.GetInt("product_bulk.max_products")
+
+	// Default values
+	if limit <= 0 {
+		limit = 500
+	}
+
+	return limit
+}
+
+// BulkUpdateProducts applies one change to every product of the target, each product is updated as with a PATCH
+// and gets a new version, its own status transition and price history
+func (s *productService) BulkUpdateProducts(ctx context.Context, userId string, req *v1.BulkUpdateProductsRequest) (*v1.BulkProductsData, error) {
+	apply, err := s.bulkUpdate(ctx, userId, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.bulkProducts(ctx, &req.BulkProductTarget, func(ctx context.Context, reference string) (v1.BulkProductResult, error) {
+		product, changes, err := s.updateProduct(ctx, userId, reference, req.Reason, v1.IfMatch{}, apply)
+		if err != nil {
+			return v1.BulkProductResult{}, err
+		}
+
+		result := v1.BulkProductResult{Reference: reference, Status: v1.BulkResultUpdated, Version: product.Version}
+		if len(changes) == 0 {
+			result.Status = v1.BulkResultUnchanged
+		}
+		return result, nil
+	})
+}
+
+// BulkDeleteProducts puts every product of the target in the trash, a product with variants left fails
+func (s *productService) BulkDeleteProducts(ctx context.Context, req *v1.BulkDeleteProductsRequest) (*v1.BulkProductsData, error) {
+	return s.bulkProducts(ctx, &req.BulkProductTarget, func(ctx context.Context, reference string) (v1.BulkProductResult, error) {
+		if err := s.DeleteProduct(ctx, reference, v1.IfMatch{}); err != nil {
+			return v1.BulkProductResult{}, err
+		}
+		return v1.BulkProductResult{Reference: reference, Status: v1.BulkResultDeleted}, nil
+	})
+}
+
+// bulkUpdate checks the change of the request once and return how it applies to each product
+func (s *productService) bulkUpdate(ctx context.Context, userId string, req *v1.BulkUpdateProductsRequest) (func(ctx context.Context, product *model.Product) error, error) {
+	switch req.Action {
+	case v1.BulkSetCategory:
+		categoryUUID, _ := uuid.Parse(req.CategoryId)
+		if _, err := s.categoryRepository.GetCategoryById(ctx, categoryUUID); err != nil {
+			return nil, fmt.Errorf("category %s not found: %w", req.CategoryId, v1.ErrBadRequest)
+		}
+		return func(ctx context.Context, product *model.Product) error {
+			if product.CategoryID == categoryUUID {
+				return nil
+			}
+			product.CategoryID = categoryUUID
+
+			// The values are checked again against the schema of the new category
+			var err error
+			product.Attributes, err = productAttributes(ctx, s.categoryRepository, categoryUUID, product.AttributeValues())
+			return err
+		}, nil
+
+	case v1.BulkSetSupplier:
+		supplierUUID, _ := uuid.Parse(req.SupplierId)
+		if _, err := s.supplierRepository.GetSupplierById(ctx, supplierUUID); err != nil {
+			return nil, fmt.Errorf("supplier %s not found: %w", req.SupplierId, v1.ErrBadRequest)
+		}
+		return func(ctx context.Context, product *model.Product) error {
+			product.SupplierID = supplierUUID
+			return nil
+		}, nil
+
+	case v1.BulkSetStatus:
+		if req.Reason == "" {
+			return nil, fmt.Errorf("a reason is required to set the status: %w", v1.ErrBadRequest)
+		}
+		return func(ctx context.Context, product *model.Product) error {
+			product.Status = req.Status
+			return nil
+		}, nil
+
+	case v1.BulkAdjustPrice:
+		if (req.PriceAmount == 0) == (req.PricePercent == 0) {
+			return nil, fmt.Errorf("adjust the price by an amount or a percentage: %w", v1.ErrBadRequest)
+		}
+		return func(ctx context.Context, product *model.Product) error {
+			price := product.Price + req.PriceAmount
+			if req.PricePercent != 0 {
+				price = product.Price * (1 + req.PricePercent/100)
+			}
+			price = math.Round(price*100) / 100
+			if price <= 0 {
+				return fmt.Errorf("price of %s would be %.2f: %w", product.Reference, price, v1.ErrBadRequest)
+			}
+
+			// The new price takes effect now and is kept in the price history
+			if price != product.Price {
+				if _, err := s.schedulePrice(ctx, userId, product, price, time.Now()); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown action %s: %w", req.Action, v1.ErrBadRequest)
+}
+
+// bulkProducts runs do for each product of the target. Each product is done in its own Transaction, or all of them
+// in one with Atomic: the first failure rolls back the products done before it and the rest is not tried.
+func (s *productService) bulkProducts(
+	ctx context.Context,
+	target *v1.BulkProductTarget,
+	do func(ctx context.Context, reference string) (v1.BulkProductResult, error),
+) (*v1.BulkProductsData, error) {
+	references, matched, err := s.bulkReferences(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &v1.BulkProductsData{Preview: target.Preview, Atomic: target.Atomic, Matched: len(matched)}
+	if target.Preview {
+		for _, reference := range references {
+			result := v1.BulkProductResult{Reference: reference, Status: v1.BulkResultMatched}
+			if !matched[reference] {
+				result.Status = v1.BulkResultFailed
+				result.Error = v1.ErrNotFound.Error()
+			}
+			data.Results = append(data.Results, result)
+		}
+		return data, nil
+	}
+
+	run := func(ctx context.Context, reference string) bool {
+		result, err := do(ctx, reference)
+		if err != nil {
+			result = v1.BulkProductResult{Reference: reference, Status: v1.BulkResultFailed, Error: s.bulkError(ctx, reference, err)}
+		}
+		data.Results = append(data.Results, result)
+		return err == nil
+	}
+
+	if !target.Atomic {
+		for _, reference := range references {
+			run(ctx, reference)
+		}
+	} else {
+		failed := false
+		err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+			for _, reference := range references {
+				if !run(ctx, reference) {
+					failed = true
+					return v1.ErrBulkRolledBack
+				}
+			}
+			return nil
+		})
+		if err != nil && !failed {
+			return nil, err
+		}
+
+		// The products done before the failure are back as they were
+		if failed {
+			data.RolledBack = true
+			for i := range data.Results[:len(data.Results)-1] {
+				data.Results[i].Status = v1.BulkResultRolledBack
+				data.Results[i].Version = 0
+			}
+		}
+	}
+
+	for _, result := range data.Results {
+		switch result.Status {
+		case v1.BulkResultFailed:
+			data.Failed++
+		case v1.BulkResultRolledBack:
+		default:
+			data.Succeeded++
+		}
+	}
+	return data, nil
+}
+
+// bulkReferences return the references of the target in order, and which of them are live products.
+// Either references or a filter must be given, the target can't reach more than the limit of products.
+func (s *productService) bulkReferences(ctx context.Context, target *v1.BulkProductTarget) ([]string, map[string]bool, error) {
+	if (len(target.References) == 0) == (target.Filter == nil) {
+		return nil, nil, fmt.Errorf("target products by references or by a filter: %w", v1.ErrBadRequest)
+	}
+
+	var references []string
+	matched := make(map[string]bool)
+	if target.Filter != nil {
+		// One more than the limit tells that the target is too large
+		filter := *target.Filter
+		filter.Page = v1.Page{Page: 1, Size: s.bulkLimit + 1}
+		products, _, err := s.productRepository.Search(ctx, &filter)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, product := range *products {
+			references = append(references, product.Reference)
+			matched[product.Reference] = true
+		}
+	} else {
+		seen := make(map[string]bool, len(target.References))
+		for _, reference := range target.References {
+			if !seen[reference] {
+				seen[reference] = true
+				references = append(references, reference)
+			}
+		}
+	}
+	if len(references) > s.bulkLimit {
+		return nil, nil, fmt.Errorf("the target is over the limit of %d products: %w", s.bulkLimit, v1.ErrBadRequest)
+	}
+
+	if target.Filter == nil {
+		live, err := s.productRepository.GetLiveReferences(ctx, references)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, reference := range live {
+			matched[reference] = true
+		}
+	}
+	return references, matched, nil
+}
+
+// bulkError is the message of the failure of a product, the internal errors are only logged
+func (s *productService) bulkError(ctx context.Context, reference string, err error) string {
+	for _, known := range []error{
+		v1.ErrBadRequest,
+		v1.ErrNotFound,
+		v1.ErrVersionMismatch,
+		v1.ErrProductTransition,
+		v1.ErrProductHasVariants,
+		v1.ErrProductInBundle,
+		v1.ErrGTINInUse,
+	} {
+		if errors.Is(err, known) {
+			return err.Error()
+		}
+	}
+
+	s.logger.WithContext(ctx).Error("bulk product", zap.String("reference", reference), zap.Error(err))
+	return v1.ErrInternalServerError.Error()
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+)
+
+// SetBundleComponents makes the product a bundle of the components, or an ordinary product again without any.
+// Bundles are not nested and have no stock of their own.
+func (s *productService) SetBundleComponents(ctx context.Context, productRef string, req *v1.SetBundleComponentsRequest) (*v1.GetProductDetailData, error) {
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		bundle, err := s.productRepository.GetProductByPrefForUpdate(ctx, productRef)
+		if err != nil {
+			return err
+		}
+
+		components := make([]model.BundleComponent, 0, len(req.Components))
+		if len(req.Components) > 0 {
+			if err := s.checkBundle(ctx, bundle); err != nil {
+				return err
+			}
+		}
+		seen := make(map[string]bool, len(req.Components))
+		for _, c := range req.Components {
+			if seen[c.Reference] {
+				return fmt.Errorf("component %s is listed twice: %w", c.Reference, v1.ErrBadRequest)
+			}
+			seen[c.Reference] = true
+
+			component, err := s.productRepository.GetProductByPref(ctx, c.Reference)
+			if errors.Is(err, v1.ErrNotFound) {
+				return fmt.Errorf("component %s not found: %w", c.Reference, v1.ErrBadRequest)
+			} else if err != nil {
+				return err
+			}
+			if component.ID == bundle.ID || component.IsBundle() {
+				return fmt.Errorf("%s can't be a component, bundles are not nested: %w", c.Reference, v1.ErrBadRequest)
+			}
+			components = append(components, model.BundleComponent{ComponentID: component.ID, Quantity: c.Quantity})
+		}
+
+		if err := s.bundleComponentRepository.Replace(ctx, bundle.ID, components); err != nil {
+			return err
+		}
+		return s.addProductEvent(ctx, model.ProductUpdatedEvent, bundle)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetProduct(ctx, productRef)
+}
+
+// checkBundle tells if the product can be made a bundle
+func (s *productService) checkBundle(ctx context.Context, product *model.Product) error {
+	if product.Quantity > 0 {
+		return fmt.Errorf("%s has stock of its own, a bundle is built from its components: %w", product.Reference, v1.ErrBadRequest)
+	}
+	if product.IsVariant() {
+		return fmt.Errorf("variant %s can't be a bundle: %w", product.Reference, v1.ErrBadRequest)
+	}
+	variants, err := s.productRepository.CountVariants(ctx, product.ID, false)
+	if err != nil {
+		return err
+	}
+	if variants > 0 {
+		return fmt.Errorf("%s has variants, it can't be a bundle: %w", product.Reference, v1.ErrBadRequest)
+	}
+	bundles, err := s.bundleComponentRepository.CountBundles(ctx, product.ID)
+	if err != nil {
+		return err
+	}
+	if bundles > 0 {
+		return fmt.Errorf("%s is a component, bundles are not nested: %w", product.Reference, v1.ErrBadRequest)
+	}
+	return nil
+}
+
+// checkBundleStock refuses to give stock to a bundle, its quantity is derived from its components
+func checkBundleStock(ctx context.Context, bundleComponentRepository repository.BundleComponentRepository, product *model.Product) error {
+	components, err := bundleComponentRepository.GetByBundle(ctx, product.ID)
+	if err != nil {
+		return err
+	}
+	if len(components) > 0 {
+		return fmt.Errorf("the quantity of bundle %s is derived from its components: %w", product.Reference, v1.ErrBadRequest)
+	}
+	return nil
+}
+
+// IssueBundle takes the stock of the components of quantity bundles in one Transaction, from the lots of each
+// component that have not expired first. Nothing is issued when a component is short.
+func (s *productService) IssueBundle(ctx context.Context, userId string, productRef string, req *v1.IssueBundleRequest) (*v1.IssueBundleData, error) {
+	data := &v1.IssueBundleData{Components: []v1.IssuedComponentData{}}
+
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		bundle, err := s.productRepository.GetProductByPrefForUpdate(ctx, productRef)
+		if err != nil {
+			return err
+		}
+		components, err := s.bundleComponentRepository.GetByBundle(ctx, bundle.ID)
+		if err != nil {
+			return err
+		}
+		if len(components) == 0 {
+			return fmt.Errorf("%s is not a bundle: %w", bundle.Reference, v1.ErrBadRequest)
+		}
+
+		sourceID := req.SourceID
+		if sourceID == "" {
+			sourceID = bundle.Reference
+		}
+
+		// The components are locked in the same order by every issue
+		sort.Slice(components, func(i, j int) bool {
+			return components[i].ComponentID.String() < components[j].ComponentID.String()
+		})
+		for i := range components {
+			if components[i].Component == nil {
+				return fmt.Errorf("a component of %s is in the trash: %w", bundle.Reference, v1.ErrBadRequest)
+			}
+			component, err := s.productRepository.GetProductByIdForUpdate(ctx, components[i].ComponentID)
+			if err != nil {
+				return err
+			}
+
+			issued := components[i].Quantity * req.Quantity
+			consumed, err := s.consumeStock(ctx, userId, component, issued, model.StockMovementBundleIssue, sourceID)
+			if err != nil {
+				return err
+			}
+			components[i].Component = component
+			data.Components = append(data.Components, v1.IssuedComponentData{
+				Reference:  component.Reference,
+				Issued:     issued,
+				Stock:      consumed.Quantity,
+				Lots:       consumed.Lots,
+				WithoutLot: consumed.WithoutLot,
+			})
+		}
+
+		bundle.Components = components
+		data.Quantity = bundle.BuildableQuantity()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func bundleComponentData(component *model.BundleComponent) v1.BundleComponentData {
+	data := v1.BundleComponentData{
+		Quantity:  component.Quantity,
+		Buildable: component.Buildable(),
+	}
+	if component.Component != nil {
+		data.Reference = component.Component.Reference
+		data.ProductName = component.Component.Name
+		data.Status = component.Component.Status
+		data.Stock = component.Component.Quantity
+	}
+	return data
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/pkg/validators"
+)
+
+func (s *productService) GetProductByGTIN(ctx context.Context, gtin string) (*v1.GetProductDetailData, error) {
+	if !validators.ValidGTIN(gtin) {
+		return nil, fmt.Errorf("%s is not a valid GTIN: %w", gtin, v1.ErrBadRequest)
+	}
+
+	product, err := s.productRepository.GetProductByGTIN(ctx, gtin, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetProduct(ctx, product.Reference)
+}
+
+// checkGTIN checks that no other product has the GTIN of product, written the same way or not.
+// Products in the trash keep their GTIN until they are purged.
+func (s *productService) checkGTIN(ctx context.Context, product *model.Product) error {
+	if product.GTIN == nil {
+		return nil
+	}
+
+	other, err := s.productRepository.GetProductByGTIN(ctx, *product.GTIN, true)
+	if errors.Is(err, v1.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if other.ID != product.ID {
+		return fmt.Errorf("GTIN %s belongs to %s: %w", *product.GTIN, other.Reference, v1.ErrGTINInUse)
+	}
+	return nil
+}
+
+// newGTIN return the GTIN of a request, nil when it is empty
+func newGTIN(gtin string) *string {
+	if gtin == "" {
+		return nil
+	}
+	return &gtin
+}
+
+func gtinValue(gtin *string) string {
+	if gtin == nil {
+		return ""
+	}
+	return *gtin
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/helper"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// productImageConfig is where the images are stored and how they are checked and scaled
+type productImageConfig struct {
+	storagePath    string
+	maxSize        int64
+	thumbnailSizes []int
+}
+
+func newProductImageConfig(conf *viper.Viper) productImageConfig {
+	c := productImageConfig{
+		storagePath:    conf.GetString("product_image.storage_path"),
+		maxSize:        conf.GetInt64("product_image.max_size"),
+		thumbnailSizes: conf.GetIntSlice("product_image.thumbnail_sizes"),
+	}
+
+	// Default values
+	if c.storagePath == "" {
+		c.storagePath = model.ProductImageStoragePath
+	}
+	if c.maxSize <= 0 {
+		c.maxSize = 5 << 20
+	}
+	if len(c.thumbnailSizes) == 0 {
+		c.thumbnailSizes = []int{64, 256, 512}
+	}
+
+	return c
+}
+
+func (s *productService) GetProductImages(ctx context.Context, productRef string) ([]v1.ProductImageData, error) {
+	product, err := s.productRepository.GetProductByPref(ctx, productRef)
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := s.productImageRepository.GetByProduct(ctx, product.ID)
+	if err != nil {
+		return nil, err
+	}
+	return productImagesData(images), nil
+}
+
+// UploadProductImage stores the image with its thumbnails and adds it after the other images of the product.
+// The type is sniffed from the content, only JPEG, PNG, GIF and WebP images are accepted.
+func (s *productService) UploadProductImage(ctx context.Context, productRef string, file *multipart.FileHeader) (*v1.ProductImageData, error) {
+	if file.Size > s.imageConfig.maxSize {
+		return nil, fmt.Errorf("image is larger than %d bytes: %w", s.imageConfig.maxSize, v1.ErrBadRequest)
+	}
+
+	// Nothing is written for a product that doesn't exist
+	if _, err := s.productRepository.GetProductByPref(ctx, productRef); err != nil {
+		return nil, err
+	}
+
+	data, err := readUpload(file, s.imageConfig.maxSize)
+	if err != nil {
+		return nil, err
+	}
+	decoded, contentType, err := helper.DecodeImage(data)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", err, v1.ErrBadRequest)
+	}
+
+	image := &model.ProductImage{
+		ID:          uuid.New(),
+		Filename:    filepath.Base(file.Filename),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Width:       decoded.Bounds().Dx(),
+		Height:      decoded.Bounds().Dy(),
+		Thumbnails:  make(map[int]string, len(s.imageConfig.thumbnailSizes)),
+		UploadedAt:  time.Now(),
+	}
+	image.Path = filepath.Join(s.imageConfig.storagePath, image.ID.String()+helper.ImageExtensions[contentType])
+
+	if err := s.writeProductImage(image, data, decoded); err != nil {
+		s.removeImageFiles(ctx, image)
+		return nil, err
+	}
+
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		// The lock serializes the positions of concurrent uploads
+		product, err := s.productRepository.GetProductByPrefForUpdate(ctx, productRef)
+		if err != nil {
+			return err
+		}
+		images, err := s.productImageRepository.GetByProduct(ctx, product.ID)
+		if err != nil {
+			return err
+		}
+
+		image.ProductID = product.ID
+		image.Position = len(images)
+		return s.productImageRepository.Create(ctx, image)
+	})
+	if err != nil {
+		s.removeImageFiles(ctx, image)
+		return nil, err
+	}
+
+	result := productImageData(image)
+	return &result, nil
+}
+
+// ReorderProductImages gives the images of the product the order of the request, it must list all of them
+func (s *productService) ReorderProductImages(ctx context.Context, productRef string, req *v1.ReorderProductImagesRequest) ([]v1.ProductImageData, error) {
+	var images []model.ProductImage
+
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		product, err := s.productRepository.GetProductByPrefForUpdate(ctx, productRef)
+		if err != nil {
+			return err
+		}
+		images, err = s.productImageRepository.GetByProduct(ctx, product.ID)
+		if err != nil {
+			return err
+		}
+		if len(req.ImageIds) != len(images) {
+			return fmt.Errorf("the order lists %d images, the product has %d: %w", len(req.ImageIds), len(images), v1.ErrBadRequest)
+		}
+
+		positions := make(map[uuid.UUID]int, len(req.ImageIds))
+		for position, id := range req.ImageIds {
+			imageID, err := uuid.Parse(id)
+			if err != nil {
+				return v1.ErrBadRequest
+			}
+			if _, ok := positions[imageID]; ok {
+				return fmt.Errorf("image %s is listed twice: %w", id, v1.ErrBadRequest)
+			}
+			positions[imageID] = position
+		}
+		for i := range images {
+			position, ok := positions[images[i].ID]
+			if !ok {
+				return fmt.Errorf("image %s is not listed: %w", images[i].ID, v1.ErrBadRequest)
+			}
+			images[i].Position = position
+		}
+
+		if err := s.productImageRepository.UpdatePositions(ctx, images); err != nil {
+			return err
+		}
+		images, err = s.productImageRepository.GetByProduct(ctx, product.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return productImagesData(images), nil
+}
+
+// DeleteProductImage deletes the image, the images after it move up, its files are removed once it is committed
+func (s *productService) DeleteProductImage(ctx context.Context, productRef string, imageId string) error {
+	imageID, err := uuid.Parse(imageId)
+	if err != nil {
+		return v1.ErrBadRequest
+	}
+
+	var image *model.ProductImage
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		product, err := s.productRepository.GetProductByPrefForUpdate(ctx, productRef)
+		if err != nil {
+			return err
+		}
+		image, err = s.productImageRepository.GetById(ctx, product.ID, imageID)
+		if err != nil {
+			return err
+		}
+		if err := s.productImageRepository.Delete(ctx, image); err != nil {
+			return err
+		}
+
+		images, err := s.productImageRepository.GetByProduct(ctx, product.ID)
+		if err != nil {
+			return err
+		}
+		for i := range images {
+			images[i].Position = i
+		}
+		return s.productImageRepository.UpdatePositions(ctx, images)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.removeImageFiles(ctx, image)
+	return nil
+}
+
+// writeProductImage writes the uploaded file and the thumbnails of decoded to storage
+func (s *productService) writeProductImage(productImage *model.ProductImage, data []byte, decoded image.Image) error {
+	if err := os.MkdirAll(s.imageConfig.storagePath, os.ModePerm); err != nil {
+		return err
+	}
+	if err := os.WriteFile(productImage.Path, data, 0o644); err != nil {
+		return err
+	}
+
+	for _, size := range s.imageConfig.thumbnailSizes {
+		var buf bytes.Buffer
+		ext, err := helper.EncodeThumbnail(&buf, helper.Thumbnail(decoded, size), productImage.ContentType)
+		if err != nil {
+			return err
+		}
+		name := fmt.Sprintf("%s_%d%s", productImage.ID, size, ext)
+		if err := os.WriteFile(filepath.Join(s.imageConfig.storagePath, name), buf.Bytes(), 0o644); err != nil {
+			return err
+		}
+		productImage.Thumbnails[size] = name
+	}
+	return nil
+}
+
+// removeImageFiles removes the files of the images, a file that can't be removed is only logged
+func (s *productService) removeImageFiles(ctx context.Context, images ...*model.ProductImage) {
+	for _, image := range images {
+		for _, path := range image.Files() {
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				s.logger.WithContext(ctx).Warn("remove product image", zap.String("path", path), zap.Error(err))
+			}
+		}
+	}
+}
+
+// readUpload reads the uploaded file, up to maxSize bytes
+func readUpload(file *multipart.FileHeader, maxSize int64) ([]byte, error) {
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("image is larger than %d bytes: %w", maxSize, v1.ErrBadRequest)
+	}
+	return data, nil
+}
+
+func productImageData(image *model.ProductImage) v1.ProductImageData {
+	return v1.ProductImageData{
+		ID:          image.ID.String(),
+		Filename:    image.Filename,
+		ContentType: image.ContentType,
+		Size:        image.Size,
+		Width:       image.Width,
+		Height:      image.Height,
+		Position:    image.Position,
+		URL:         image.URL(),
+		Thumbnails:  image.ThumbnailURLs(),
+	}
+}
+
+func productImagesData(images []model.ProductImage) []v1.ProductImageData {
+	result := make([]v1.ProductImageData, 0, len(images))
+	for i := range images {
+		result = append(result, productImageData(&images[i]))
+	}
+	return result
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/boombuler/barcode"
+	"github.com/google/uuid"
+	"github.com/jung-kurt/gofpdf"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/helper"
+	"github.com/quydmfl/niveau-test/internal/model"
+)
+
+// labelLayout is a sheet of labels, the sizes are in mm
+type labelLayout struct {
+	pageSize      string
+	columns, rows int
+	width, height float64
+	top, left     float64
+	// pitchX and pitchY are from the start of a label to the start of the next one
+	pitchX, pitchY float64
+}
+
+// labelLayouts are the Avery sheets labels can be printed on
+var labelLayouts = map[string]labelLayout{
+	"L7160": {pageSize: "A4", columns: 3, rows: 7, width: 63.5, height: 38.1, top: 15.15, left: 7.25, pitchX: 66, pitchY: 38.1},
+	"L7163": {pageSize: "A4", columns: 2, rows: 7, width: 99.1, height: 38.1, top: 15.15, left: 4.65, pitchX: 101.6, pitchY: 38.1},
+	"L7651": {pageSize: "A4", columns: 5, rows: 13, width: 38.1, height: 21.2, top: 10.7, left: 4.75, pitchX: 40.6, pitchY: 21.2},
+	"5160":  {pageSize: "Letter", columns: 3, rows: 10, width: 66.675, height: 25.4, top: 12.7, left: 4.7625, pitchX: 69.85, pitchY: 25.4},
+}
+
+func (l labelLayout) perSheet() int {
+	return l.columns * l.rows
+}
+
+// GetProductBarcode renders the product as a barcode image, it return the image and its content type
+func (s *productService) GetProductBarcode(ctx context.Context, productRef string, req *v1.GetProductBarcodeRequest) ([]byte, string, error) {
+	product, err := s.productRepository.GetProductByPref(ctx, productRef)
+	if err != nil {
+		return nil, "", err
+	}
+
+	code, err := productBarcode(req.Format, product)
+	if err != nil {
+		return nil, "", fmt.Errorf("%v: %w", err, v1.ErrBadRequest)
+	}
+
+	width, height := 300, 100
+	if helper.IsTwoDimensional(code) {
+		width, height = 256, 256
+	}
+	if req.Width != 0 {
+		width = req.Width
+	}
+	if req.Height != 0 {
+		height = req.Height
+	}
+
+	var buf bytes.Buffer
+	if req.Output == "svg" {
+		if err := helper.WriteBarcodeSVG(&buf, code, width, height); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/svg+xml", nil
+	}
+	// A barcode can't be scaled below one pixel per module
+	if err := helper.WriteBarcodePNG(&buf, code, width, height); err != nil {
+		return nil, "", fmt.Errorf("%v: %w", err, v1.ErrBadRequest)
+	}
+	return buf.Bytes(), "image/png", nil
+}
+
+// ExportProductLabelsToPDF prints a label with the name, price and barcode of each product of the search page
+func (s *productService) ExportProductLabelsToPDF(ctx context.Context, req *v1.ExportProductLabelsRequest) (*v1.ExportProductLabelsData, error) {
+	layout := labelLayouts[req.Layout]
+	if req.StartPosition > layout.perSheet() {
+		return nil, fmt.Errorf("a %s sheet has %d labels: %w", req.Layout, layout.perSheet(), v1.ErrBadRequest)
+	}
+
+	products, _, err := s.productRepository.Search(ctx, &req.SearchProductRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	data, filePath, err := exportProductLabelsPdf(ctx, *products, req, layout)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store file information in the database //
+	document := model.Documents{
+		ID:         uuid.New(),
+		Filename:   data.Filename,
+		Path:       filePath,
+		Kind:       model.DocumentKindLabelSheetPDF,
+		UploadedAt: time.Now(),
+	}
+	if err := s.documentRepository.Create(ctx, &document); err != nil {
+		return nil, err
+	}
+
+	data.DocumentID = document.ID.String()
+	return data, nil
+}
+
+func exportProductLabelsPdf(ctx context.Context, products []model.Product, req *v1.ExportProductLabelsRequest, layout labelLayout) (*v1.ExportProductLabelsData, string, error) {
+	storagePath := "./storage/pdf"
+	err := os.MkdirAll(storagePath, os.ModePerm)
+	if err != nil {
+		return nil, "", err
+	}
+
+	pdf := gofpdf.New("P", "mm", layout.pageSize, "")
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetMargins(0, 0, 0)
+
+	data := &v1.ExportProductLabelsData{}
+	position := req.StartPosition - 1
+	if position < 0 {
+		position = 0
+	}
+	for i := range products {
+		code, err := productBarcode(req.Barcode, &products[i])
+		if err != nil {
+			data.Skipped = append(data.Skipped, products[i].Reference)
+			continue
+		}
+
+		slot := position % layout.perSheet()
+		if slot == 0 || data.Sheets == 0 {
+			pdf.AddPage()
+			data.Sheets++
+		}
+		x := layout.left + float64(slot%layout.columns)*layout.pitchX
+		y := layout.top + float64(slot/layout.columns)*layout.pitchY
+		if err := printProductLabel(pdf, &products[i], code, x, y, layout); err != nil {
+			return nil, "", err
+		}
+
+		position++
+		data.Labels++
+	}
+	// A sheet is printed even when there is no label, the document is always there
+	if data.Sheets == 0 {
+		pdf.AddPage()
+		data.Sheets++
+	}
+
+	// Unique file name //
+	data.Filename = fmt.Sprintf("labels_%s_%s.pdf", req.Layout, time.Now().Format("20060102_150405"))
+	filePath := filepath.Join(storagePath, data.Filename)
+
+	// Save the PDF file //
+	if err := pdf.OutputFileAndClose(filePath); err != nil {
+		return nil, "", err
+	}
+
+	return data, filePath, nil
+}
+
+// productBarcode encodes the reference of the product, or its GTIN for an EAN-13
+func productBarcode(format string, product *model.Product) (barcode.Barcode, error) {
+	if format != helper.BarcodeEAN13 {
+		return helper.EncodeBarcode(format, product.Reference)
+	}
+
+	if product.GTIN == nil {
+		return nil, fmt.Errorf("product %s has no GTIN", product.Reference)
+	}
+	content, err := helper.GTINToEAN13(*product.GTIN)
+	if err != nil {
+		return nil, err
+	}
+	return helper.EncodeBarcode(format, content)
+}
+
+// printProductLabel prints the label of the product at x, y: the name and price above a barcode,
+// the reference under it, or next to it for a QR code
+func printProductLabel(pdf *gofpdf.Fpdf, product *model.Product, code barcode.Barcode, x, y float64, layout labelLayout) error {
+	const padding = 2.0
+	x, y = x+padding, y+padding
+	width, height := layout.width-2*padding, layout.height-2*padding
+
+	// The text follows the size of the label, 1pt is 0.3528mm
+	lineHeight := height / 5
+	if lineHeight > 5 {
+		lineHeight = 5
+	}
+	fontSize := lineHeight / 0.3528 * 0.8
+
+	textWidth := width
+	if helper.IsTwoDimensional(code) {
+		textWidth = width - height - padding
+	}
+
+	pdf.SetFont("Arial", "B", fontSize)
+	pdf.SetXY(x, y)
+	pdf.CellFormat(textWidth, lineHeight, fitText(pdf, product.Name, textWidth), "", 2, "L", false, 0, "")
+	pdf.SetFont("Arial", "", fontSize)
+	pdf.CellFormat(textWidth, lineHeight, fmt.Sprintf("$%.2f", product.Price), "", 2, "L", false, 0, "")
+
+	if helper.IsTwoDimensional(code) {
+		pdf.CellFormat(textWidth, lineHeight, fitText(pdf, product.Reference, textWidth), "", 2, "L", false, 0, "")
+		return printBarcode(pdf, code, x+width-height, y, height, height)
+	}
+
+	barcodeHeight := height - 3*lineHeight
+	if err := printBarcode(pdf, code, x, y+2*lineHeight, width, barcodeHeight); err != nil {
+		return err
+	}
+	pdf.SetXY(x, y+2*lineHeight+barcodeHeight)
+	pdf.CellFormat(width, lineHeight, product.Reference, "", 2, "C", false, 0, "")
+	return nil
+}
+
+// printBarcode puts the barcode in the box, scaled to whole pixels per module so the bars stay sharp
+func printBarcode(pdf *gofpdf.Fpdf, code barcode.Barcode, x, y, width, height float64) error {
+	name := fmt.Sprintf("barcode-%s-%s", code.Metadata().CodeKind, code.Content())
+	options := gofpdf.ImageOptions{ImageType: "PNG"}
+
+	if pdf.GetImageInfo(name) == nil {
+		const modulePixels = 4
+		bounds := code.Bounds()
+		pixelHeight := bounds.Dy() * modulePixels
+		if !helper.IsTwoDimensional(code) {
+			pixelHeight = 64
+		}
+
+		var buf bytes.Buffer
+		if err := helper.WriteBarcodePNG(&buf, code, bounds.Dx()*modulePixels, pixelHeight); err != nil {
+			return err
+		}
+		pdf.RegisterImageOptionsReader(name, options, &buf)
+	}
+
+	pdf.ImageOptions(name, x, y, width, height, false, options, 0, "")
+	return pdf.Error()
+}
+
+// fitText cuts text to fit in width
+func fitText(pdf *gofpdf.Fpdf, text string, width float64) string {
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"...") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
@@ -0,0 +1,407 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jung-kurt/gofpdf"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/helper"
+	"github.com/quydmfl/niveau-test/internal/model"
+)
+
+// expiringLotsWindow is the default window of GetExpiringLots and ExportExpiringLotsToPDF
+const expiringLotsWindow = 30 * 24 * time.Hour
+
+func (s *productService) GetProductLots(ctx context.Context, productRef string, req *v1.GetProductLotsRequest) ([]v1.ProductLotData, error) {
+	product, err := s.productRepository.GetProductByPref(ctx, productRef)
+	if err != nil {
+		return nil, err
+	}
+
+	lots, err := s.productLotRepository.GetByProduct(ctx, product.ID, req.IncludeEmpty)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := make([]v1.ProductLotData, 0, len(lots))
+	for i := range lots {
+		result = append(result, productLotData(&lots[i], now))
+	}
+	return result, nil
+}
+
+// ReceiveProductLot adds a lot to the stock of the product, the lot number must be new for the product
+func (s *productService) ReceiveProductLot(ctx context.Context, userId string, productRef string, req *v1.ReceiveProductLotRequest) (*v1.ProductLotData, error) {
+	expiresAt, err := time.Parse("2006-01-02", req.ExpiresAt)
+	if err != nil {
+		return nil, v1.ErrBadRequest
+	}
+	now := time.Now()
+	if expiresAt.Before(helper.Date(now)) {
+		return nil, fmt.Errorf("lot %s expired on %s: %w", req.LotNumber, req.ExpiresAt, v1.ErrBadRequest)
+	}
+	lot := &model.ProductLot{
+		LotNumber:        req.LotNumber,
+		Quantity:         req.Quantity,
+		ReceivedQuantity: req.Quantity,
+		ExpiresAt:        expiresAt,
+		StockCity:        req.StockLocation,
+		ReceivedAt:       now,
+	}
+	if req.ManufacturedAt != "" {
+		manufacturedAt, err := time.Parse("2006-01-02", req.ManufacturedAt)
+		if err != nil {
+			return nil, v1.ErrBadRequest
+		}
+		if manufacturedAt.After(expiresAt) {
+			return nil, fmt.Errorf("lot %s is manufactured after it expires: %w", req.LotNumber, v1.ErrBadRequest)
+		}
+		lot.ManufacturedAt = &manufacturedAt
+	}
+
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		product, err := s.productRepository.GetProductByPrefForUpdate(ctx, productRef)
+		if err != nil {
+			return err
+		}
+		if err := checkBundleStock(ctx, s.bundleComponentRepository, product); err != nil {
+			return err
+		}
+		if _, err := s.productLotRepository.GetByNumber(ctx, product.ID, req.LotNumber); err == nil {
+			return fmt.Errorf("lot %s of %s was already received: %w", req.LotNumber, product.Reference, v1.ErrBadRequest)
+		} else if !errors.Is(err, v1.ErrNotFound) {
+			return err
+		}
+
+		lot.ProductID = product.ID
+		if lot.StockCity == "" {
+			lot.StockCity = product.StockCity
+		}
+		if err := s.productLotRepository.Create(ctx, lot); err != nil {
+			return err
+		}
+
+		status := product.Status
+		product.Restock(lot.Quantity)
+		if err := s.productRepository.UpdateStock(ctx, product); err != nil {
+			return err
+		}
+		if product.Status != status {
+			change := model.NewProductStatusChange(status, product, model.StockMovementLotReceipt, userId)
+			if err := s.productStatusChangeRepository.Create(ctx, change); err != nil {
+				return err
+			}
+		}
+
+		return s.stockMovementRepository.Create(ctx, &model.StockMovement{
+			ProductID:     product.ID,
+			LotID:         &lot.ID,
+			Delta:         lot.Quantity,
+			QuantityAfter: product.Quantity,
+			Reason:        model.StockMovementLotReceipt,
+			SourceID:      lot.LotNumber,
+			ActorID:       userId,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := productLotData(lot, now)
+	return &data, nil
+}
+
+// ConsumeProductLots issues quantity from the lots of the product that have not expired, the first to expire first.
+// What the lots don't cover is taken from the stock without lot, expired lots are never issued.
+func (s *productService) ConsumeProductLots(ctx context.Context, userId string, productRef string, req *v1.ConsumeProductLotsRequest) (*v1.ConsumeProductLotsData, error) {
+	var data *v1.ConsumeProductLotsData
+
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		// The product row lock serializes the consumptions of its lots
+		product, err := s.productRepository.GetProductByPrefForUpdate(ctx, productRef)
+		if err != nil {
+			return err
+		}
+		data, err = s.consumeStock(ctx, userId, product, req.Quantity, model.StockMovementConsumption, req.SourceID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// consumeStock issues quantity of the product as ConsumeProductLots does, the movements are kept with reason.
+// It must be called inside a Transaction holding the product row lock.
+func (s *productService) consumeStock(
+	ctx context.Context,
+	userId string,
+	product *model.Product,
+	quantity int,
+	reason string,
+	sourceID string,
+) (*v1.ConsumeProductLotsData, error) {
+	data := &v1.ConsumeProductLotsData{Lots: []v1.LotConsumptionData{}}
+
+	lots, err := s.productLotRepository.GetByProduct(ctx, product.ID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	inLots, usable := 0, make([]model.ProductLot, 0, len(lots))
+	for _, lot := range lots {
+		inLots += lot.Quantity
+		if !lot.Expired(now) {
+			usable = append(usable, lot)
+		}
+	}
+	available := product.Quantity - inLots
+	for _, lot := range usable {
+		available += lot.Quantity
+	}
+	if quantity > available {
+		return nil, fmt.Errorf("only %d of %s can be issued: %w", available, product.Reference, v1.ErrBadRequest)
+	}
+
+	consumed := model.ConsumeFEFO(usable, quantity)
+	updated := make([]model.ProductLot, 0, len(consumed))
+	for _, consumption := range consumed {
+		updated = append(updated, *consumption.Lot)
+		data.Lots = append(data.Lots, v1.LotConsumptionData{
+			LotNumber: consumption.Lot.LotNumber,
+			Quantity:  consumption.Quantity,
+			ExpiresAt: consumption.Lot.ExpiresAt.Format("2006-01-02"),
+		})
+	}
+	if err := s.productLotRepository.UpdateQuantities(ctx, updated); err != nil {
+		return nil, err
+	}
+
+	before, status := product.Quantity, product.Status
+	product.Quantity -= quantity
+	product.SyncStockStatus()
+	if err := s.productRepository.UpdateStock(ctx, product); err != nil {
+		return nil, err
+	}
+	if product.Status != status {
+		change := model.NewProductStatusChange(status, product, reason, userId)
+		if err := s.productStatusChangeRepository.Create(ctx, change); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, movement := range model.LotMovements(product, before, consumed) {
+		movement.Reason, movement.SourceID, movement.ActorID = reason, sourceID, userId
+		if movement.LotID == nil {
+			data.WithoutLot = -movement.Delta
+		}
+		if err := s.stockMovementRepository.Create(ctx, &movement); err != nil {
+			return nil, err
+		}
+	}
+	data.Quantity = product.Quantity
+	return data, nil
+}
+
+// checkLotStock refuses to lower the stock of the product below what is held in its lots, they must be consumed instead
+func (s *productService) checkLotStock(ctx context.Context, product *model.Product) error {
+	inLots, err := s.productLotRepository.SumQuantity(ctx, product.ID)
+	if err != nil {
+		return err
+	}
+	if product.Quantity < inLots {
+		return fmt.Errorf("%d of %s are held in lots, consume them instead: %w", inLots, product.Reference, v1.ErrBadRequest)
+	}
+	return nil
+}
+
+func (s *productService) GetExpiringLots(ctx context.Context, req *v1.GetExpiringLotsRequest) (*v1.GetExpiringLotsResponse, error) {
+	from, to, err := expiryWindow(req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	lots, total, err := s.productLotRepository.GetExpiring(ctx, from, to, req.StockLocation, &req.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := make([]v1.ProductLotData, 0, len(lots))
+	for i := range lots {
+		result = append(result, productLotData(&lots[i], now))
+	}
+
+	// Calculate total pages
+	totalPages := int(total / req.Size)
+	if total%req.Size > 0 {
+		totalPages++
+	}
+
+	return &v1.GetExpiringLotsResponse{
+		Pagination: v1.Pagination{
+			Page:       req.Page,
+			TotalRows:  total,
+			TotalPages: totalPages,
+		},
+		Response: v1.Response{
+			Data: result,
+		},
+	}, nil
+}
+
+// ExportExpiringLotsToPDF prints the stock left in the lots expiring in the window, by expiry date
+func (s *productService) ExportExpiringLotsToPDF(ctx context.Context, req *v1.ExportExpiringLotsRequest) (*v1.ExportExpiringLotsData, error) {
+	from, to, err := expiryWindow(req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	lots, _, err := s.productLotRepository.GetExpiring(ctx, from, to, req.StockLocation, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data, filePath, err := exportExpiringLotsPdf(ctx, lots, from, to, req.StockLocation)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store file information in the database //
+	document := model.Documents{
+		ID:         uuid.New(),
+		Filename:   data.Filename,
+		Path:       filePath,
+		Kind:       model.DocumentKindExpiringLotsPDF,
+		UploadedAt: time.Now(),
+	}
+	if err := s.documentRepository.Create(ctx, &document); err != nil {
+		return nil, err
+	}
+
+	data.DocumentID = document.ID.String()
+	return data, nil
+}
+
+func exportExpiringLotsPdf(ctx context.Context, lots []model.ProductLot, from time.Time, to time.Time, stockCity string) (*v1.ExportExpiringLotsData, string, error) {
+	storagePath := "./storage/pdf"
+	err := os.MkdirAll(storagePath, os.ModePerm)
+	if err != nil {
+		return nil, "", err
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Arial", "B", 16)
+
+	// Heading //
+	pdf.Cell(40, 10, "Expiring Stock")
+	pdf.Ln(12)
+
+	pdf.SetFont("Arial", "", 12)
+	pdf.Cell(40, 10, fmt.Sprintf("Expiry Dates: %s to %s", from.Format("2006-01-02"), to.Format("2006-01-02")))
+	pdf.Ln(8)
+	if stockCity != "" {
+		pdf.Cell(40, 10, fmt.Sprintf("Stock Location: %s", stockCity))
+		pdf.Ln(8)
+	}
+	pdf.Cell(40, 10, fmt.Sprintf("Date: %s", time.Now().Format("2006-01-02")))
+	pdf.Ln(12)
+
+	// Lots //
+	now := time.Now()
+	data := &v1.ExportExpiringLotsData{}
+	widths := []float64{40, 50, 30, 25, 15, 25}
+	pdf.SetFont("Arial", "B", 11)
+	for i, header := range []string{"Reference", "Name", "Lot", "Expires", "Days", "Qty"} {
+		pdf.CellFormat(widths[i], 8, header, "1", 0, "C", false, 0, "")
+	}
+	pdf.Ln(-1)
+	pdf.SetFont("Arial", "", 10)
+	for i := range lots {
+		lot := &lots[i]
+		var reference, name string
+		if lot.Product != nil {
+			reference, name = lot.Product.Reference, lot.Product.Name
+		}
+		pdf.CellFormat(widths[0], 8, reference, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 8, fitText(pdf, name, widths[1]-2), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[2], 8, fitText(pdf, lot.LotNumber, widths[2]-2), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[3], 8, lot.ExpiresAt.Format("2006-01-02"), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(widths[4], 8, fmt.Sprintf("%d", lot.DaysLeft(now)), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[5], 8, fmt.Sprintf("%d", lot.Quantity), "1", 0, "R", false, 0, "")
+		pdf.Ln(-1)
+
+		data.Lots++
+		data.Quantity += lot.Quantity
+	}
+	pdf.SetFont("Arial", "B", 11)
+	pdf.CellFormat(widths[0]+widths[1]+widths[2]+widths[3]+widths[4], 8, "Total Quantity", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(widths[5], 8, fmt.Sprintf("%d", data.Quantity), "1", 0, "R", false, 0, "")
+	pdf.Ln(-1)
+
+	// Unique file name //
+	data.Filename = fmt.Sprintf("expiring_lots_%s_%s_%s.pdf", from.Format("20060102"), to.Format("20060102"), time.Now().Format("20060102_150405"))
+	filePath := filepath.Join(storagePath, data.Filename)
+
+	// Save the PDF file //
+	if err := pdf.OutputFileAndClose(filePath); err != nil {
+		return nil, "", err
+	}
+
+	return data, filePath, nil
+}
+
+// expiryWindow parses a window of expiry dates, it starts today and lasts expiringLotsWindow by default
+func expiryWindow(fromDate string, toDate string) (time.Time, time.Time, error) {
+	from := helper.Date(time.Now())
+	if fromDate != "" {
+		var err error
+		if from, err = time.Parse("2006-01-02", fromDate); err != nil {
+			return time.Time{}, time.Time{}, v1.ErrBadRequest
+		}
+	}
+	to := from.Add(expiringLotsWindow)
+	if toDate != "" {
+		var err error
+		if to, err = time.Parse("2006-01-02", toDate); err != nil {
+			return time.Time{}, time.Time{}, v1.ErrBadRequest
+		}
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("the window ends before %s: %w", from.Format("2006-01-02"), v1.ErrBadRequest)
+	}
+	return from, to, nil
+}
+
+func productLotData(lot *model.ProductLot, now time.Time) v1.ProductLotData {
+	data := v1.ProductLotData{
+		ID:               lot.ID.String(),
+		LotNumber:        lot.LotNumber,
+		Quantity:         lot.Quantity,
+		ReceivedQuantity: lot.ReceivedQuantity,
+		ExpiresAt:        lot.ExpiresAt.Format("2006-01-02"),
+		DaysLeft:         lot.DaysLeft(now),
+		StockLocation:    lot.StockCity,
+		ReceivedAt:       lot.ReceivedAt.Format(time.RFC3339),
+	}
+	if lot.Product != nil {
+		data.Reference, data.ProductName = lot.Product.Reference, lot.Product.Name
+	}
+	if lot.ManufacturedAt != nil {
+		data.ManufacturedAt = lot.ManufacturedAt.Format("2006-01-02")
+	}
+	if lot.ExpiryFlaggedAt != nil {
+		data.ExpiryFlaggedAt = lot.ExpiryFlaggedAt.Format(time.RFC3339)
+	}
+	return data
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+)
+
+// schedulePrice makes price effective from the given time until the next scheduled price, it must be called
+// inside a Transaction holding the product row lock. Product.Price is set when the price is in effect now,
+// the caller saves the product.
+func (s *productService) schedulePrice(ctx context.Context, userId string, product *model.Product, price float64, from time.Time) (*model.ProductPrice, error) {
+	// Products created before the history existed get their price from the date they were added
+	count, err := s.productPriceRepository.Count(ctx, product.ID)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		baseline := &model.ProductPrice{
+			ProductID:     product.ID,
+			Price:         product.Price,
+			EffectiveFrom: product.DateAdded,
+			CreatedAt:     time.Now(),
+		}
+		if from.Before(baseline.EffectiveFrom) {
+			baseline.EffectiveFrom = from
+		}
+		if err := s.productPriceRepository.Create(ctx, baseline); err != nil {
+			return nil, err
+		}
+	}
+
+	scheduled := &model.ProductPrice{
+		ProductID:     product.ID,
+		Price:         price,
+		EffectiveFrom: from,
+		AuthorID:      userId,
+		CreatedAt:     time.Now(),
+	}
+
+	current, err := s.productPriceRepository.GetAt(ctx, product.ID, from)
+	switch {
+	case err == nil && current.EffectiveFrom.Equal(from):
+		// Same start, the price is replaced
+		current.Price = price
+		current.AuthorID = userId
+		if err := s.productPriceRepository.Update(ctx, current); err != nil {
+			return nil, err
+		}
+		scheduled = current
+	case err == nil:
+		// The current range is split, the new price runs until the current range would have ended
+		scheduled.EffectiveTo = current.EffectiveTo
+		current.EffectiveTo = &from
+		if err := s.productPriceRepository.Update(ctx, current); err != nil {
+			return nil, err
+		}
+		if err := s.productPriceRepository.Create(ctx, scheduled); err != nil {
+			return nil, err
+		}
+	case errors.Is(err, v1.ErrNotFound):
+		// Before the first range, the new price runs until it
+		next, err := s.productPriceRepository.GetNextAfter(ctx, product.ID, from)
+		if err != nil && !errors.Is(err, v1.ErrNotFound) {
+			return nil, err
+		}
+		if next != nil {
+			scheduled.EffectiveTo = &next.EffectiveFrom
+		}
+		if err := s.productPriceRepository.Create(ctx, scheduled); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	if scheduled.Covers(time.Now()) {
+		product.Price = price
+	}
+	return scheduled, nil
+}
+
+// priceAt return the price of product in effect at the given time
+func (s *productService) priceAt(ctx context.Context, product *model.Product, at time.Time) (float64, error) {
+	price, err := s.productPriceRepository.GetAt(ctx, product.ID, at)
+	if err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			return product.Price, nil
+		}
+		return 0, err
+	}
+	return price.Price, nil
+}
+
+func (s *productService) GetProductPrices(ctx context.Context, productRef string, req *v1.GetProductPricesRequest) (*v1.GetProductPricesResponse, error) {
+	var result []v1.GetProductPriceData
+
+	product, err := s.productRepository.GetProductByPref(ctx, productRef)
+	if err != nil {
+		return nil, err
+	}
+
+	prices, total, err := s.productPriceRepository.Search(ctx, product.ID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range *prices {
+		result = append(result, productPriceData(&(*prices)[i]))
+	}
+
+	// Calculate total pages
+	totalPages := int(total / req.Size)
+	if total%req.Size > 0 {
+		totalPages++
+	}
+
+	return &v1.GetProductPricesResponse{
+		Pagination: v1.Pagination{
+			Page:       req.Page,
+			TotalRows:  total,
+			TotalPages: totalPages,
+		},
+		Response: v1.Response{
+			Data: result,
+		},
+	}, nil
+}
+
+func (s *productService) ScheduleProductPrice(ctx context.Context, userId string, productRef string, req *v1.ScheduleProductPriceRequest) (*v1.GetProductPriceData, error) {
+	from, err := time.Parse(time.RFC3339, req.EffectiveFrom)
+	if err != nil {
+		return nil, v1.ErrBadRequest
+	}
+	// History is not rewritten, a price starts now at the earliest
+	if from.Before(time.Now()) {
+		return nil, v1.ErrBadRequest
+	}
+
+	var scheduled *model.ProductPrice
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		product, err := s.productRepository.GetProductByPrefForUpdate(ctx, productRef)
+		if err != nil {
+			return err
+		}
+
+		scheduled, err = s.schedulePrice(ctx, userId, product, req.Price, from)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := productPriceData(scheduled)
+	return &data, nil
+}
+
+// CancelProductPrice removes a price that is not in effect yet, the previous range is extended over it
+func (s *productService) CancelProductPrice(ctx context.Context, productRef string, priceId uint64) error {
+	return s.tm.Transaction(ctx, func(ctx context.Context) error {
+		product, err := s.productRepository.GetProductByPrefForUpdate(ctx, productRef)
+		if err != nil {
+			return err
+		}
+
+		price, err := s.productPriceRepository.GetProductPriceById(ctx, product.ID, priceId)
+		if err != nil {
+			return err
+		}
+		if !price.EffectiveFrom.After(time.Now()) {
+			return v1.ErrPriceAlreadyEffective
+		}
+
+		previous, err := s.productPriceRepository.GetEndingAt(ctx, product.ID, price.EffectiveFrom)
+		switch {
+		case err == nil:
+			previous.EffectiveTo = price.EffectiveTo
+			if err := s.productPriceRepository.Update(ctx, previous); err != nil {
+				return err
+			}
+		case !errors.Is(err, v1.ErrNotFound):
+			return err
+		}
+
+		return s.productPriceRepository.Delete(ctx, price.ID)
+	})
+}
+
+func productPriceData(price *model.ProductPrice) v1.GetProductPriceData {
+	return v1.GetProductPriceData{
+		ID:            price.ID,
+		Price:         price.Price,
+		EffectiveFrom: formatTime(&price.EffectiveFrom),
+		EffectiveTo:   formatTime(price.EffectiveTo),
+		AuthorID:      price.AuthorID,
+		Scheduled:     price.EffectiveFrom.After(time.Now()),
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+)
+
+// setReference gives the product the next reference of the configured pattern when it has none, or checks the one
+// it was given is free. It must be called inside a Transaction.
+func (s *productService) setReference(ctx context.Context, product *model.Product) error {
+	if product.Reference != "" {
+		exists, err := s.productRepository.ReferenceExists(ctx, product.Reference)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("reference %s is taken: %w", product.Reference, v1.ErrBadRequest)
+		}
+		return nil
+	}
+
+	var categoryPrefix string
+	if s.referenceFormat.HasCategory() {
+		category, err := s.categoryRepository.GetCategoryById(ctx, product.CategoryID)
+		if err != nil {
+			return err
+		}
+		categoryPrefix = category.ReferencePrefix
+	}
+
+	// The sequence of a scope only moves on, a number is skipped when its reference was given by hand
+	// or generated before the counter. There are finitely many of those.
+	now := time.Now()
+	scope := s.referenceFormat.Scope(now, categoryPrefix)
+	for {
+		n, err := s.referenceCounterRepository.Next(ctx, scope)
+		if err != nil {
+			return err
+		}
+		reference := s.referenceFormat.Reference(now, categoryPrefix, n)
+		exists, err := s.productRepository.ReferenceExists(ctx, reference)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			product.Reference = reference
+			return nil
+		}
+	}
+}
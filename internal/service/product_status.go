@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+)
+
+// TransitionProduct moves the product to another status of its lifecycle, the reason is kept in its status history
+func (s *productService) TransitionProduct(ctx context.Context, userId string, productRef string, req *v1.TransitionProductRequest, ifMatch v1.IfMatch) (*v1.PatchProductData, error) {
+	product, changes, err := s.updateProduct(ctx, userId, productRef, req.Reason, ifMatch, func(ctx context.Context, product *model.Product) error {
+		if !product.CanBecome(req.Status) {
+			return fmt.Errorf("product %s can't move from %s to %s: %w", product.Reference, product.Status, req.Status, v1.ErrProductTransition)
+		}
+		product.Status = req.Status
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.PatchProductData{
+		Version: product.Version,
+		Changes: changes,
+	}, nil
+}
+
+func (s *productService) GetProductStatusHistory(ctx context.Context, productRef string, req *v1.GetProductStatusHistoryRequest) (*v1.GetProductStatusHistoryResponse, error) {
+	var result []v1.ProductStatusChangeData
+
+	product, err := s.productRepository.GetProductByPref(ctx, productRef)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, total, err := s.productStatusChangeRepository.Search(ctx, product.ID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, change := range *changes {
+		result = append(result, v1.ProductStatusChangeData{
+			FromStatus: change.FromStatus,
+			ToStatus:   change.ToStatus,
+			Reason:     change.Reason,
+			ActorID:    change.ActorID,
+			CreatedAt:  change.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	// Calculate total pages
+	totalPages := int(total / req.Size)
+	if total%req.Size > 0 {
+		totalPages++
+	}
+
+	return &v1.GetProductStatusHistoryResponse{
+		Pagination: v1.Pagination{
+			Page:       req.Page,
+			TotalRows:  total,
+			TotalPages: totalPages,
+		},
+		Response: v1.Response{
+			Data: result,
+		},
+	}, nil
+}
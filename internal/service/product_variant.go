@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+)
+
+// CreateVariant adds a variant to a product, a variant can't have variants itself
+func (s *productService) CreateVariant(ctx context.Context, userId string, productRef string, req *v1.CreateVariantRequest) (*v1.GetProductDetailData, error) {
+	var variant *model.Product
+
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		// The lock serializes the checks of the options of concurrent variants
+		parent, err := s.productRepository.GetProductByPrefForUpdate(ctx, productRef)
+		if err != nil {
+			return err
+		}
+		if parent.IsVariant() {
+			return fmt.Errorf("product %s is a variant: %w", parent.Reference, v1.ErrBadRequest)
+		}
+
+		variant = &model.Product{
+			Reference:  req.Reference,
+			Name:       req.ProductName,
+			CategoryID: parent.CategoryID,
+			SupplierID: parent.SupplierID,
+			StockCity:  req.StockLocation,
+			Price:      req.Price,
+			Status:     req.Status,
+			DateAdded:  time.Now(),
+			Quantity:   req.Quantity,
+			ParentID:   &parent.ID,
+			Options:    req.Options,
+			GTIN:       newGTIN(req.GTIN),
+		}
+		if variant.Name == "" {
+			variant.Name = parent.Name
+		}
+		if variant.StockCity == "" {
+			variant.StockCity = parent.StockCity
+		}
+		for _, attribute := range parent.Attributes {
+			variant.Attributes = append(variant.Attributes, model.ProductAttribute{
+				Name:  attribute.Name,
+				Type:  attribute.Type,
+				Value: attribute.Value,
+			})
+		}
+
+		if err := s.checkVariant(ctx, variant, variant); err != nil {
+			return err
+		}
+		return s.createProduct(ctx, userId, variant)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetProduct(ctx, variant.Reference)
+}
+
+// checkVariant checks product as changed from before: a variant keeps the category and supplier of its parent
+// and its options are set and unique among the variants of its parent, a product that is not a variant has no options
+func (s *productService) checkVariant(ctx context.Context, before *model.Product, product *model.Product) error {
+	if !product.IsVariant() {
+		if len(product.Options) > 0 {
+			return fmt.Errorf("product %s is not a variant, it has no options: %w", product.Reference, v1.ErrBadRequest)
+		}
+		return nil
+	}
+
+	if product.CategoryID != before.CategoryID || product.SupplierID != before.SupplierID {
+		return fmt.Errorf("variant %s has the category and supplier of its parent: %w", product.Reference, v1.ErrBadRequest)
+	}
+	if len(product.Options) == 0 {
+		return fmt.Errorf("variant %s has no options: %w", product.Reference, v1.ErrBadRequest)
+	}
+
+	siblings, err := s.productRepository.GetVariantsForUpdate(ctx, *product.ParentID)
+	if err != nil {
+		return err
+	}
+	for i := range siblings {
+		if siblings[i].ID != product.ID && siblings[i].SameOptions(product) {
+			return fmt.Errorf("variant %s has the options of %s: %w", product.Reference, siblings[i].Reference, v1.ErrBadRequest)
+		}
+	}
+	return nil
+}
+
+// moveVariants gives a new category or supplier of product to its variants, they get a new version too
+func (s *productService) moveVariants(ctx context.Context, userId string, before *model.Product, product *model.Product) error {
+	if product.IsVariant() || (product.CategoryID == before.CategoryID && product.SupplierID == before.SupplierID) {
+		return nil
+	}
+
+	variants, err := s.productRepository.GetVariantsForUpdate(ctx, product.ID)
+	if err != nil {
+		return err
+	}
+	if len(variants) == 0 {
+		return nil
+	}
+	if err := s.productRepository.MoveVariants(ctx, product); err != nil {
+		return err
+	}
+	return recordMovedProducts(ctx, s.productVersionRepository, s.outboxRepository, userId, variants, func(variant *model.Product) {
+		variant.CategoryID = product.CategoryID
+		variant.SupplierID = product.SupplierID
+	})
+}
+
+// productDetailData return the detail of product, with the variants it has loaded
+func productDetailData(product *model.Product) *v1.GetProductDetailData {
+	data := &v1.GetProductDetailData{
+		Reference:     product.Reference,
+		GTIN:          gtinValue(product.GTIN),
+		ProductName:   product.Name,
+		Category:      product.Category.Name,
+		Price:         product.Price,
+		Status:        product.Status,
+		StockLocation: product.StockCity,
+		DateAdded:     product.DateAdded.Format("2006-01-02"),
+		Quantity:      product.Quantity,
+		Supplier:      product.Supplier.Name,
+		Version:       product.Version,
+		Attributes:    product.AttributeValues(),
+		Options:       product.Options,
+	}
+	if product.Parent != nil {
+		data.ParentReference = product.Parent.Reference
+	}
+	if len(product.Images) > 0 {
+		data.Images = productImagesData(product.Images)
+	}
+
+	if len(product.Variants) > 0 {
+		totalQuantity := product.Quantity
+		for _, variant := range product.Variants {
+			data.Variants = append(data.Variants, v1.ProductVariantData{
+				Reference:   variant.Reference,
+				GTIN:        gtinValue(variant.GTIN),
+				ProductName: variant.Name,
+				Price:       variant.Price,
+				Status:      variant.Status,
+				Quantity:    variant.Quantity,
+				Options:     variant.Options,
+			})
+			totalQuantity += variant.Quantity
+		}
+		data.VariantCount = len(product.Variants)
+		data.TotalQuantity = &totalQuantity
+	}
+
+	// A bundle has no stock of its own
+	if product.IsBundle() {
+		data.Quantity = product.BuildableQuantity()
+		for i := range product.Components {
+			data.Components = append(data.Components, bundleComponentData(&product.Components[i]))
+		}
+	}
+	return data
+}
+
+// formatOptions lists the options by name, e.g. colour: red, size: M
+func formatOptions(options map[string]string) string {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := make([]string, 0, len(names))
+	for _, name := range names {
+		values = append(values, fmt.Sprintf("%s: %s", name, options[name]))
+	}
+	return strings.Join(values, ", ")
+}
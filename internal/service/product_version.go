@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+)
+
+func (s *productService) GetProductVersions(ctx context.Context, productRef string, req *v1.GetProductVersionsRequest) (*v1.GetProductVersionsResponse, error) {
+	var result []v1.GetProductVersionData
+
+	product, err := s.productRepository.GetProductByPref(ctx, productRef)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, total, err := s.productVersionRepository.Search(ctx, product.ID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range *versions {
+		result = append(result, s.productVersionData(ctx, &(*versions)[i]))
+	}
+
+	// Calculate total pages
+	totalPages := int(total / req.Size)
+	if total%req.Size > 0 {
+		totalPages++
+	}
+
+	return &v1.GetProductVersionsResponse{
+		Pagination: v1.Pagination{
+			Page:       req.Page,
+			TotalRows:  total,
+			TotalPages: totalPages,
+		},
+		Response: v1.Response{
+			Data: result,
+		},
+	}, nil
+}
+
+func (s *productService) GetProductVersion(ctx context.Context, productRef string, version int) (*v1.GetProductVersionData, error) {
+	product, err := s.productRepository.GetProductByPref(ctx, productRef)
+	if err != nil {
+		return nil, err
+	}
+
+	productVersion, err := s.productVersionRepository.GetByVersion(ctx, product.ID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	data := s.productVersionData(ctx, productVersion)
+	return &data, nil
+}
+
+func (s *productService) GetProductAsOf(ctx context.Context, productRef string, req *v1.GetProductAsOfRequest) (*v1.GetProductVersionData, error) {
+	at, err := time.Parse(time.RFC3339, req.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.productRepository.GetProductByPref(ctx, productRef)
+	if err != nil {
+		return nil, err
+	}
+
+	productVersion, err := s.productVersionRepository.GetAsOf(ctx, product.ID, at)
+	if err != nil {
+		return nil, err
+	}
+
+	data := s.productVersionData(ctx, productVersion)
+	return &data, nil
+}
+
+func (s *productService) DiffProductVersions(ctx context.Context, productRef string, req *v1.DiffProductVersionsRequest) (*v1.DiffProductVersionsData, error) {
+	from, err := s.GetProductVersion(ctx, productRef, req.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.GetProductVersion(ctx, productRef, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compare the detail fields by their JSON name
+	changes := make(map[string]v1.ProductFieldChange)
+	fromValue, toValue := reflect.ValueOf(from.Product), reflect.ValueOf(to.Product)
+	for i := 0; i < fromValue.NumField(); i++ {
+		oldValue, newValue := fromValue.Field(i).Interface(), toValue.Field(i).Interface()
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		name := strings.Split(fromValue.Type().Field(i).Tag.Get("json"), ",")[0]
+		changes[name] = v1.ProductFieldChange{Old: oldValue, New: newValue}
+	}
+
+	return &v1.DiffProductVersionsData{
+		From:    req.From,
+		To:      req.To,
+		Changes: changes,
+	}, nil
+}
+
+// RestoreProductVersion applies the descriptive fields of a version as a new update.
+// Quantity and status follow the stock, they are kept as they are now. A variant keeps the category
+// and supplier of its parent.
+func (s *productService) RestoreProductVersion(ctx context.Context, userId string, productRef string, version int) (*v1.GetProductVersionData, error) {
+	var restored *model.ProductVersion
+
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		product, err := s.productRepository.GetProductByPrefForUpdate(ctx, productRef)
+		if err != nil {
+			return err
+		}
+		before := *product
+
+		productVersion, err := s.productVersionRepository.GetByVersion(ctx, product.ID, version)
+		if err != nil {
+			return err
+		}
+
+		if _, err := s.categoryRepository.GetCategoryById(ctx, productVersion.CategoryID); err != nil {
+			return fmt.Errorf("category %s not found", productVersion.CategoryID)
+		}
+		if _, err := s.supplierRepository.GetSupplierById(ctx, productVersion.SupplierID); err != nil {
+			return fmt.Errorf("supplier %s not found", productVersion.SupplierID)
+		}
+
+		product.Name = productVersion.Name
+		product.CategoryID = productVersion.CategoryID
+		product.SupplierID = productVersion.SupplierID
+		product.StockCity = productVersion.StockCity
+		product.DateAdded = productVersion.DateAdded
+		product.Options = productVersion.Options
+		product.GTIN = productVersion.GTIN
+		if err := s.checkGTIN(ctx, product); err != nil {
+			return err
+		}
+		if product.IsVariant() {
+			product.CategoryID, product.SupplierID = before.CategoryID, before.SupplierID
+		}
+		if err := s.checkVariant(ctx, &before, product); err != nil {
+			return err
+		}
+		if product.Attributes, err = productAttributes(ctx, s.categoryRepository, productVersion.CategoryID, productVersion.Attributes); err != nil {
+			return err
+		}
+
+		if productVersion.Price != before.Price {
+			if _, err := s.schedulePrice(ctx, userId, product, productVersion.Price, time.Now()); err != nil {
+				return err
+			}
+		}
+
+		if err := s.productRepository.Update(ctx, product); err != nil {
+			return err
+		}
+		if err := s.productRepository.ReplaceAttributes(ctx, product); err != nil {
+			return err
+		}
+		if err := s.moveVariants(ctx, userId, &before, product); err != nil {
+			return err
+		}
+		if restored, err = s.productVersionRepository.Append(ctx, &before, product, userId); err != nil {
+			return err
+		}
+		return s.addProductEvent(ctx, model.ProductUpdatedEvent, product)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := s.productVersionData(ctx, restored)
+	return &data, nil
+}
+
+// productVersionData resolves the category and supplier names, they are empty if those were deleted since
+func (s *productService) productVersionData(ctx context.Context, version *model.ProductVersion) v1.GetProductVersionData {
+	data := v1.GetProductVersionData{
+		Version:   version.Version,
+		AuthorID:  version.AuthorID,
+		CreatedAt: formatTime(&version.CreatedAt),
+		Product: v1.GetProductDetailData{
+			Reference:     version.Reference,
+			GTIN:          gtinValue(version.GTIN),
+			ProductName:   version.Name,
+			Price:         version.Price,
+			Status:        version.Status,
+			StockLocation: version.StockCity,
+			DateAdded:     version.DateAdded.Format("2006-01-02"),
+			Quantity:      version.Quantity,
+			Attributes:    version.Attributes,
+			Options:       version.Options,
+		},
+	}
+
+	if category, err := s.categoryRepository.GetCategoryById(ctx, version.CategoryID); err == nil {
+		data.Product.Category = category.Name
+	}
+	if supplier, err := s.supplierRepository.GetSupplierById(ctx, version.SupplierID); err == nil {
+		data.Product.Supplier = supplier.Name
+	}
+
+	return data
+}
+
+// recordMovedProducts stores a version and an update event for each product moved by a bulk update,
+// products are as read before the update and move applies it to one of them
+func recordMovedProducts(
+	ctx context.Context,
+	productVersionRepository repository.ProductVersionRepository,
+	outboxRepository repository.OutboxRepository,
+	userId string,
+	products []model.Product,
+	move func(product *model.Product),
+) error {
+	for i := range products {
+		before := products[i]
+		move(&products[i])
+		products[i].Version++
+		if _, err := productVersionRepository.Append(ctx, &before, &products[i], userId); err != nil {
+			return err
+		}
+		event, err := model.NewProductEvent(model.ProductUpdatedEvent, &products[i])
+		if err != nil {
+			return err
+		}
+		if err := outboxRepository.Create(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,488 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jung-kurt/gofpdf"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+)
+
+type PurchaseOrderService interface {
+	GetPurchaseOrders(ctx context.Context, req *v1.SearchPurchaseOrderRequest) (*v1.SearchPurchaseOrderResponse, error)
+	GetPurchaseOrder(ctx context.Context, id string) (*v1.GetPurchaseOrderDetailData, error)
+	CreatePurchaseOrder(ctx context.Context, userId string, req *v1.CreatePurchaseOrderRequest) (*v1.GetPurchaseOrderDetailData, error)
+	// UpdatePurchaseOrder only applies to draft orders
+	UpdatePurchaseOrder(ctx context.Context, id string, req *v1.UpdatePurchaseOrderRequest, ifMatch v1.IfMatch) (*v1.GetPurchaseOrderDetailData, error)
+	SendPurchaseOrder(ctx context.Context, id string, ifMatch v1.IfMatch) (*v1.GetPurchaseOrderDetailData, error)
+	CancelPurchaseOrder(ctx context.Context, id string, ifMatch v1.IfMatch) (*v1.GetPurchaseOrderDetailData, error)
+	// ReceivePurchaseOrder adds the received quantities to the stock of the products in one transaction
+	ReceivePurchaseOrder(ctx context.Context, userId string, id string, req *v1.ReceivePurchaseOrderRequest) (*v1.GetPurchaseOrderDetailData, error)
+	ExportPurchaseOrderToPDF(ctx context.Context, id string) (*v1.ExportPurchaseOrderData, error)
+}
+
+func NewPurchaseOrderService(
+	service *Service,
+	purchaseOrderRepository repository.PurchaseOrderRepository,
+	supplierRepository repository.SupplierRepository,
+	productRepository repository.ProductRepository,
+	stockMovementRepository repository.StockMovementRepository,
+	documentRepository repository.DocumentsRepository,
+	productStatusChangeRepository repository.ProductStatusChangeRepository,
+	bundleComponentRepository repository.BundleComponentRepository,
+) PurchaseOrderService {
+	return &purchaseOrderService{
+		Service:                 service,
+		purchaseOrderRepository: purchaseOrderRepository,
+		supplierRepository:      supplierRepository,
+		productRepository:       productRepository,
+		stockMovementRepository: stockMovementRepository,
+		documentRepository:      documentRepository,
+
+		productStatusChangeRepository: productStatusChangeRepository,
+		bundleComponentRepository:     bundleComponentRepository,
+	}
+}
+
+type purchaseOrderService struct {
+	*Service
+	purchaseOrderRepository repository.PurchaseOrderRepository
+	supplierRepository      repository.SupplierRepository
+	productRepository       repository.ProductRepository
+	stockMovementRepository repository.StockMovementRepository
+	documentRepository      repository.DocumentsRepository
+
+	productStatusChangeRepository repository.ProductStatusChangeRepository
+	bundleComponentRepository     repository.BundleComponentRepository
+}
+
+func (s *purchaseOrderService) GetPurchaseOrders(ctx context.Context, req *v1.SearchPurchaseOrderRequest) (*v1.SearchPurchaseOrderResponse, error) {
+	var result []v1.GetPurchaseOrderDetailData
+
+	orders, total, err := s.purchaseOrderRepository.Search(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range *orders {
+		data := purchaseOrderDetailData(&(*orders)[i])
+		// The lines are only listed in the detail
+		data.Lines = nil
+		result = append(result, data)
+	}
+
+	// Calculate total pages
+	totalPages := int(total / req.Size)
+	if total%req.Size > 0 {
+		totalPages++
+	}
+
+	return &v1.SearchPurchaseOrderResponse{
+		Pagination: v1.Pagination{
+			Page:       req.Page,
+			TotalRows:  total,
+			TotalPages: totalPages,
+		},
+		Response: v1.Response{
+			Data: result,
+		},
+	}, nil
+}
+
+func (s *purchaseOrderService) GetPurchaseOrder(ctx context.Context, id string) (*v1.GetPurchaseOrderDetailData, error) {
+	orderUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, v1.ErrBadRequest
+	}
+
+	order, err := s.purchaseOrderRepository.GetPurchaseOrderById(ctx, orderUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := purchaseOrderDetailData(order)
+	return &data, nil
+}
+
+func (s *purchaseOrderService) CreatePurchaseOrder(ctx context.Context, userId string, req *v1.CreatePurchaseOrderRequest) (*v1.GetPurchaseOrderDetailData, error) {
+	supplierUUID, _ := uuid.Parse(req.SupplierId)
+	number, err := s.sid.GenString()
+	if err != nil {
+		return nil, err
+	}
+
+	order := &model.PurchaseOrder{
+		ID:        uuid.New(),
+		Number:    "PO-" + number,
+		Status:    model.PurchaseOrderDraft,
+		Note:      req.Note,
+		CreatedBy: userId,
+		Version:   1,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		supplier, err := s.supplierRepository.GetSupplierById(ctx, supplierUUID)
+		if err != nil {
+			return fmt.Errorf("supplier %s not found: %w", req.SupplierId, v1.ErrBadRequest)
+		}
+		if supplier.Status != "active" {
+			return fmt.Errorf("supplier %s is not active: %w", req.SupplierId, v1.ErrBadRequest)
+		}
+		order.SupplierID, order.SupplierName, order.Currency = &supplier.ID, supplier.Name, supplier.Currency
+
+		if order.Lines, err = s.purchaseOrderLines(ctx, req.Lines); err != nil {
+			return err
+		}
+		return s.purchaseOrderRepository.Create(ctx, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := purchaseOrderDetailData(order)
+	return &data, nil
+}
+
+func (s *purchaseOrderService) UpdatePurchaseOrder(ctx context.Context, id string, req *v1.UpdatePurchaseOrderRequest, ifMatch v1.IfMatch) (*v1.GetPurchaseOrderDetailData, error) {
+	return s.updatePurchaseOrder(ctx, id, ifMatch, func(ctx context.Context, order *model.PurchaseOrder) error {
+		if order.Status != model.PurchaseOrderDraft {
+			return v1.ErrPurchaseOrderStatus
+		}
+		lines, err := s.purchaseOrderLines(ctx, req.Lines)
+		if err != nil {
+			return err
+		}
+
+		order.Note, order.Lines = req.Note, lines
+		return s.purchaseOrderRepository.ReplaceLines(ctx, order)
+	})
+}
+
+func (s *purchaseOrderService) SendPurchaseOrder(ctx context.Context, id string, ifMatch v1.IfMatch) (*v1.GetPurchaseOrderDetailData, error) {
+	return s.updatePurchaseOrder(ctx, id, ifMatch, func(ctx context.Context, order *model.PurchaseOrder) error {
+		if !order.CanBecome(model.PurchaseOrderSent) {
+			return v1.ErrPurchaseOrderStatus
+		}
+
+		now := time.Now()
+		order.Status, order.SentAt = model.PurchaseOrderSent, &now
+		// The supplier may have been purged since the order was drafted
+		if order.SupplierID != nil {
+			if supplier, err := s.supplierRepository.GetSupplierById(ctx, *order.SupplierID); err == nil {
+				expectedAt := now.AddDate(0, 0, supplier.LeadTimeDays)
+				order.ExpectedAt = &expectedAt
+			}
+		}
+		return nil
+	})
+}
+
+func (s *purchaseOrderService) CancelPurchaseOrder(ctx context.Context, id string, ifMatch v1.IfMatch) (*v1.GetPurchaseOrderDetailData, error) {
+	return s.updatePurchaseOrder(ctx, id, ifMatch, func(ctx context.Context, order *model.PurchaseOrder) error {
+		if !order.CanBecome(model.PurchaseOrderCancelled) {
+			return v1.ErrPurchaseOrderStatus
+		}
+
+		now := time.Now()
+		order.Status, order.CancelledAt = model.PurchaseOrderCancelled, &now
+		return nil
+	})
+}
+
+func (s *purchaseOrderService) ReceivePurchaseOrder(ctx context.Context, userId string, id string, req *v1.ReceivePurchaseOrderRequest) (*v1.GetPurchaseOrderDetailData, error) {
+	return s.updatePurchaseOrder(ctx, id, v1.IfMatch{}, func(ctx context.Context, order *model.PurchaseOrder) error {
+		if !order.CanBecome(model.PurchaseOrderReceived) {
+			return v1.ErrPurchaseOrderStatus
+		}
+
+		quantities := make(map[string]int, len(req.Lines))
+		for _, line := range req.Lines {
+			quantities[line.Reference] += line.Quantity
+		}
+		var received []model.PurchaseOrderLine
+		for i := range order.Lines {
+			line := &order.Lines[i]
+			quantity, ok := quantities[line.Reference]
+			if len(req.Lines) == 0 {
+				quantity, ok = line.Remaining(), line.Remaining() > 0
+			}
+			if !ok {
+				continue
+			}
+			delete(quantities, line.Reference)
+			if quantity > line.Remaining() {
+				return fmt.Errorf("only %d of %s are still expected: %w", line.Remaining(), line.Reference, v1.ErrBadRequest)
+			}
+			// Lines are in their stored order, so every receipt of the order locks the products in the same order
+			if err := s.receiveLine(ctx, userId, order, line, quantity); err != nil {
+				return err
+			}
+			received = append(received, *line)
+		}
+		for reference := range quantities {
+			return fmt.Errorf("%s is not in the order: %w", reference, v1.ErrBadRequest)
+		}
+		if len(received) == 0 {
+			return fmt.Errorf("nothing to receive: %w", v1.ErrBadRequest)
+		}
+		if err := s.purchaseOrderRepository.UpdateReceived(ctx, received); err != nil {
+			return err
+		}
+
+		order.Status = model.PurchaseOrderPartiallyReceived
+		if order.FullyReceived() {
+			now := time.Now()
+			order.Status, order.ReceivedAt = model.PurchaseOrderReceived, &now
+		}
+		return nil
+	})
+}
+
+// receiveLine adds quantity to the stock of the product of line and to its received quantity
+func (s *purchaseOrderService) receiveLine(ctx context.Context, userId string, order *model.PurchaseOrder, line *model.PurchaseOrderLine, quantity int) error {
+	if line.ProductID == nil {
+		return fmt.Errorf("product %s no longer exists: %w", line.Reference, v1.ErrBadRequest)
+	}
+	product, err := s.productRepository.GetProductByIdForUpdate(ctx, *line.ProductID)
+	if err != nil {
+		return fmt.Errorf("product %s not found: %w", line.Reference, v1.ErrBadRequest)
+	}
+	// The product became a bundle since it was ordered
+	if err := checkBundleStock(ctx, s.bundleComponentRepository, product); err != nil {
+		return err
+	}
+
+	status := product.Status
+	line.ReceivedQuantity += quantity
+	product.Restock(quantity)
+	if err := s.productRepository.UpdateStock(ctx, product); err != nil {
+		return err
+	}
+	if product.Status != status {
+		change := model.NewProductStatusChange(status, product, model.StockMovementPurchaseReceipt, userId)
+		if err := s.productStatusChangeRepository.Create(ctx, change); err != nil {
+			return err
+		}
+	}
+
+	return s.stockMovementRepository.Create(ctx, &model.StockMovement{
+		ProductID:     product.ID,
+		Delta:         quantity,
+		QuantityAfter: product.Quantity,
+		Reason:        model.StockMovementPurchaseReceipt,
+		SourceID:      order.Number,
+		ActorID:       userId,
+	})
+}
+
+func (s *purchaseOrderService) ExportPurchaseOrderToPDF(ctx context.Context, id string) (*v1.ExportPurchaseOrderData, error) {
+	orderUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, v1.ErrBadRequest
+	}
+
+	order, err := s.purchaseOrderRepository.GetPurchaseOrderById(ctx, orderUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	fileName, filePath, err := exportPurchaseOrderPdf(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store file information in the database //
+	document := model.Documents{
+		ID:              uuid.New(),
+		Filename:        fileName,
+		Path:            filePath,
+		Kind:            model.DocumentKindPurchaseOrderPDF,
+		PurchaseOrderID: &order.ID,
+		UploadedAt:      time.Now(),
+	}
+
+	if err := s.documentRepository.Create(ctx, &document); err != nil {
+		return nil, err
+	}
+
+	return &v1.ExportPurchaseOrderData{
+		DocumentID: document.ID.String(),
+		Filename:   document.Filename,
+	}, nil
+}
+
+// updatePurchaseOrder applies a change to the order id and saves it, ifMatch is checked against the locked order
+func (s *purchaseOrderService) updatePurchaseOrder(
+	ctx context.Context,
+	id string,
+	ifMatch v1.IfMatch,
+	apply func(ctx context.Context, order *model.PurchaseOrder) error,
+) (*v1.GetPurchaseOrderDetailData, error) {
+	orderUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, v1.ErrBadRequest
+	}
+
+	var order *model.PurchaseOrder
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		order, err = s.purchaseOrderRepository.GetPurchaseOrderByIdForUpdate(ctx, orderUUID)
+		if err != nil {
+			return err
+		}
+		if !ifMatch.Matches(order.Version) {
+			return v1.ErrVersionMismatch
+		}
+
+		if err := apply(ctx, order); err != nil {
+			return err
+		}
+		order.UpdatedAt = time.Now()
+		return s.purchaseOrderRepository.Update(ctx, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := purchaseOrderDetailData(order)
+	return &data, nil
+}
+
+// purchaseOrderLines return the order lines of the live products with the requested references
+func (s *purchaseOrderService) purchaseOrderLines(ctx context.Context, requests []v1.PurchaseOrderLineRequest) ([]model.PurchaseOrderLine, error) {
+	lines := make([]model.PurchaseOrderLine, 0, len(requests))
+	seen := make(map[string]bool, len(requests))
+	for _, line := range requests {
+		if seen[line.Reference] {
+			return nil, fmt.Errorf("product %s is ordered twice: %w", line.Reference, v1.ErrBadRequest)
+		}
+		seen[line.Reference] = true
+
+		product, err := s.productRepository.GetProductByPref(ctx, line.Reference)
+		if err != nil {
+			return nil, fmt.Errorf("product %s not found: %w", line.Reference, v1.ErrBadRequest)
+		}
+		// A bundle has no stock of its own to receive, its components are ordered instead
+		if product.IsBundle() {
+			return nil, fmt.Errorf("product %s is a bundle: %w", line.Reference, v1.ErrBadRequest)
+		}
+		lines = append(lines, model.PurchaseOrderLine{
+			ProductID: &product.ID,
+			Reference: product.Reference,
+			Name:      product.Name,
+			Quantity:  line.Quantity,
+			UnitCost:  line.UnitCost,
+		})
+	}
+	return lines, nil
+}
+
+func exportPurchaseOrderPdf(ctx context.Context, order *model.PurchaseOrder) (string, string, error) {
+	storagePath := "./storage/pdf"
+	err := os.MkdirAll(storagePath, os.ModePerm)
+	if err != nil {
+		return "", "", err
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Arial", "B", 16)
+
+	// Heading //
+	pdf.Cell(40, 10, fmt.Sprintf("Purchase Order %s", order.Number))
+	pdf.Ln(12)
+
+	// Order Details //
+	pdf.SetFont("Arial", "", 12)
+	pdf.Cell(40, 10, fmt.Sprintf("Supplier Name: %s", order.SupplierName))
+	pdf.Ln(8)
+	pdf.Cell(40, 10, fmt.Sprintf("Status: %s", order.Status))
+	pdf.Ln(8)
+	pdf.Cell(40, 10, fmt.Sprintf("Date: %s", order.CreatedAt.Format("2006-01-02")))
+	pdf.Ln(8)
+	if order.ExpectedAt != nil {
+		pdf.Cell(40, 10, fmt.Sprintf("Expected Delivery: %s", order.ExpectedAt.Format("2006-01-02")))
+		pdf.Ln(8)
+	}
+	if order.Note != "" {
+		pdf.MultiCell(0, 8, fmt.Sprintf("Note: %s", order.Note), "", "L", false)
+	}
+	pdf.Ln(4)
+
+	// Order Lines //
+	widths := []float64{45, 65, 20, 30, 30}
+	pdf.SetFont("Arial", "B", 11)
+	for i, header := range []string{"Reference", "Name", "Qty", "Unit Cost", "Amount"} {
+		pdf.CellFormat(widths[i], 8, header, "1", 0, "C", false, 0, "")
+	}
+	pdf.Ln(-1)
+	pdf.SetFont("Arial", "", 10)
+	for _, line := range order.Lines {
+		pdf.CellFormat(widths[0], 8, line.Reference, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 8, line.Name, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[2], 8, fmt.Sprintf("%d", line.Quantity), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 8, fmt.Sprintf("%.2f", line.UnitCost), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[4], 8, fmt.Sprintf("%.2f", float64(line.Quantity)*line.UnitCost), "1", 0, "R", false, 0, "")
+		pdf.Ln(-1)
+	}
+	pdf.SetFont("Arial", "B", 11)
+	pdf.CellFormat(widths[0]+widths[1]+widths[2]+widths[3], 8, fmt.Sprintf("Total (%s)", order.Currency), "1", 0, "R", false, 0, "")
+	pdf.CellFormat(widths[4], 8, fmt.Sprintf("%.2f", order.Total()), "1", 0, "R", false, 0, "")
+	pdf.Ln(-1)
+
+	// Unique file name //
+	fileName := fmt.Sprintf("purchase_order_%s_%s.pdf", order.Number, time.Now().Format("20060102_150405"))
+	filePath := filepath.Join(storagePath, fileName)
+
+	// Save the PDF file //
+	if err := pdf.OutputFileAndClose(filePath); err != nil {
+		return "", "", err
+	}
+
+	return fileName, filePath, nil
+}
+
+func purchaseOrderDetailData(order *model.PurchaseOrder) v1.GetPurchaseOrderDetailData {
+	data := v1.GetPurchaseOrderDetailData{
+		ID:           order.ID.String(),
+		Number:       order.Number,
+		SupplierName: order.SupplierName,
+		Status:       order.Status,
+		Currency:     order.Currency,
+		Note:         order.Note,
+		Total:        order.Total(),
+		CreatedAt:    order.CreatedAt.Format(time.RFC3339),
+		Version:      order.Version,
+	}
+	if order.SupplierID != nil {
+		data.SupplierID = order.SupplierID.String()
+	}
+	if order.ExpectedAt != nil {
+		data.ExpectedAt = order.ExpectedAt.Format("2006-01-02")
+	}
+	if order.SentAt != nil {
+		data.SentAt = order.SentAt.Format(time.RFC3339)
+	}
+	if order.ReceivedAt != nil {
+		data.ReceivedAt = order.ReceivedAt.Format(time.RFC3339)
+	}
+	if order.CancelledAt != nil {
+		data.CancelledAt = order.CancelledAt.Format(time.RFC3339)
+	}
+	for _, line := range order.Lines {
+		data.Lines = append(data.Lines, v1.PurchaseOrderLineData{
+			Reference:        line.Reference,
+			Name:             line.Name,
+			Quantity:         line.Quantity,
+			ReceivedQuantity: line.ReceivedQuantity,
+			UnitCost:         line.UnitCost,
+		})
+	}
+	return data
+}
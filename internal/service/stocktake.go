@@ -0,0 +1,655 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jung-kurt/gofpdf"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/spf13/viper"
+)
+
+type StocktakeService interface {
+	GetStocktakes(ctx context.Context, req *v1.SearchStocktakeRequest) (*v1.SearchStocktakeResponse, error)
+	GetStocktake(ctx context.Context, id string) (*v1.GetStocktakeDetailData, error)
+	// CreateStocktake opens a stocktake of the products in scope with their stock as it is now
+	CreateStocktake(ctx context.Context, userId string, req *v1.CreateStocktakeRequest) (*v1.GetStocktakeDetailData, error)
+	SubmitStocktakeCounts(ctx context.Context, userId string, id string, req *v1.SubmitStocktakeCountsRequest) (*v1.GetStocktakeDetailData, error)
+	GetStocktakeVariance(ctx context.Context, id string) (*v1.GetStocktakeVarianceData, error)
+	// ApproveStocktake sets the stock of the counted products to their counted quantity in one transaction,
+	// only a supervisor can approve
+	ApproveStocktake(ctx context.Context, userId string, id string, req *v1.ApproveStocktakeRequest, ifMatch v1.IfMatch) (*v1.GetStocktakeDetailData, error)
+	CancelStocktake(ctx context.Context, id string, ifMatch v1.IfMatch) (*v1.GetStocktakeDetailData, error)
+	ExportStocktakeSheetToPDF(ctx context.Context, id string) (*v1.ExportStocktakeData, error)
+	ExportStocktakeVarianceToPDF(ctx context.Context, id string) (*v1.ExportStocktakeData, error)
+}
+
+func NewStocktakeService(
+	service *Service,
+	conf *viper.Viper,
+	stocktakeRepository repository.StocktakeRepository,
+	productRepository repository.ProductRepository,
+	categoryRepository repository.CategoryRepository,
+	productLotRepository repository.ProductLotRepository,
+	stockMovementRepository repository.StockMovementRepository,
+	productStatusChangeRepository repository.ProductStatusChangeRepository,
+	documentRepository repository.DocumentsRepository,
+) StocktakeService {
+	s := &stocktakeService{
+		Service:                 service,
+		stocktakeRepository:     stocktakeRepository,
+		productRepository:       productRepository,
+		categoryRepository:      categoryRepository,
+		productLotRepository:    productLotRepository,
+		stockMovementRepository: stockMovementRepository,
+		documentRepository:      documentRepository,
+
+		productStatusChangeRepository: productStatusChangeRepository,
+		supervisors:                   make(map[string]bool),
+	}
+	for _, userId := range conf.GetStringSlice("stocktake.supervisors") {
+		s.supervisors[userId] = true
+	}
+	return s
+}
+
+type stocktakeService struct {
+	*Service
+	stocktakeRepository     repository.StocktakeRepository
+	productRepository       repository.ProductRepository
+	categoryRepository      repository.CategoryRepository
+	productLotRepository    repository.ProductLotRepository
+	stockMovementRepository repository.StockMovementRepository
+	documentRepository      repository.DocumentsRepository
+
+	productStatusChangeRepository repository.ProductStatusChangeRepository
+	// supervisors are the users who can approve a stocktake, anyone can when there are none
+	supervisors map[string]bool
+}
+
+func (s *stocktakeService) GetStocktakes(ctx context.Context, req *v1.SearchStocktakeRequest) (*v1.SearchStocktakeResponse, error) {
+	var result []v1.GetStocktakeDetailData
+
+	stocktakes, total, err := s.stocktakeRepository.Search(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range *stocktakes {
+		data := stocktakeDetailData(&(*stocktakes)[i])
+		// The lines are only listed in the detail
+		data.Lines = nil
+		result = append(result, data)
+	}
+
+	// Calculate total pages
+	totalPages := int(total / req.Size)
+	if total%req.Size > 0 {
+		totalPages++
+	}
+
+	return &v1.SearchStocktakeResponse{
+		Pagination: v1.Pagination{
+			Page:       req.Page,
+			TotalRows:  total,
+			TotalPages: totalPages,
+		},
+		Response: v1.Response{
+			Data: result,
+		},
+	}, nil
+}
+
+func (s *stocktakeService) GetStocktake(ctx context.Context, id string) (*v1.GetStocktakeDetailData, error) {
+	stocktake, err := s.getStocktake(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	data := stocktakeDetailData(stocktake)
+	return &data, nil
+}
+
+func (s *stocktakeService) CreateStocktake(ctx context.Context, userId string, req *v1.CreateStocktakeRequest) (*v1.GetStocktakeDetailData, error) {
+	number, err := s.sid.GenString()
+	if err != nil {
+		return nil, err
+	}
+
+	stocktake := &model.Stocktake{
+		ID:                   uuid.New(),
+		Number:               "ST-" + number,
+		Status:               model.StocktakeOpen,
+		StockCity:            req.StockLocation,
+		IncludeSubcategories: req.IncludeSubcategories,
+		Note:                 req.Note,
+		CreatedBy:            userId,
+		Version:              1,
+		CreatedAt:            time.Now(),
+		UpdatedAt:            time.Now(),
+	}
+	if req.CategoryId != "" {
+		categoryUUID, _ := uuid.Parse(req.CategoryId)
+		if _, err := s.categoryRepository.GetCategoryById(ctx, categoryUUID); err != nil {
+			return nil, fmt.Errorf("category %s not found: %w", req.CategoryId, v1.ErrBadRequest)
+		}
+		stocktake.CategoryID = &categoryUUID
+	}
+
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		products, err := s.stocktakeRepository.GetProductsInScope(ctx, stocktake.StockCity, stocktake.CategoryID, stocktake.IncludeSubcategories)
+		if err != nil {
+			return err
+		}
+		if len(products) == 0 {
+			return fmt.Errorf("no product to count: %w", v1.ErrBadRequest)
+		}
+		for _, product := range products {
+			stocktake.Lines = append(stocktake.Lines, model.StocktakeLine{
+				ProductID:      product.ID,
+				Reference:      product.Reference,
+				Name:           product.Name,
+				StockCity:      product.StockCity,
+				SystemQuantity: product.Quantity,
+			})
+		}
+		return s.stocktakeRepository.Create(ctx, stocktake)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := stocktakeDetailData(stocktake)
+	return &data, nil
+}
+
+func (s *stocktakeService) SubmitStocktakeCounts(ctx context.Context, userId string, id string, req *v1.SubmitStocktakeCountsRequest) (*v1.GetStocktakeDetailData, error) {
+	_, err := s.updateStocktake(ctx, id, v1.IfMatch{}, func(ctx context.Context, stocktake *model.Stocktake) error {
+		if stocktake.Status != model.StocktakeOpen {
+			return v1.ErrStocktakeStatus
+		}
+
+		lines := make(map[string]uint64, len(stocktake.Lines))
+		for _, line := range stocktake.Lines {
+			lines[line.Reference] = line.ID
+		}
+		now := time.Now()
+		counts := make([]model.StocktakeCount, 0, len(req.Counts))
+		seen := make(map[string]bool, len(req.Counts))
+		for _, count := range req.Counts {
+			if seen[count.Reference] {
+				return fmt.Errorf("%s is counted twice: %w", count.Reference, v1.ErrBadRequest)
+			}
+			seen[count.Reference] = true
+
+			lineID, ok := lines[count.Reference]
+			if !ok {
+				return fmt.Errorf("%s is not counted by stocktake %s: %w", count.Reference, stocktake.Number, v1.ErrBadRequest)
+			}
+			counts = append(counts, model.StocktakeCount{
+				LineID:    lineID,
+				DeviceID:  req.DeviceID,
+				Quantity:  count.Quantity,
+				CountedBy: userId,
+				CountedAt: now,
+			})
+		}
+		return s.stocktakeRepository.SaveCounts(ctx, counts)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The counted quantities are summed by the database
+	return s.GetStocktake(ctx, id)
+}
+
+func (s *stocktakeService) GetStocktakeVariance(ctx context.Context, id string) (*v1.GetStocktakeVarianceData, error) {
+	stocktake, err := s.getStocktake(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return stocktakeVarianceData(stocktake), nil
+}
+
+func (s *stocktakeService) ApproveStocktake(ctx context.Context, userId string, id string, req *v1.ApproveStocktakeRequest, ifMatch v1.IfMatch) (*v1.GetStocktakeDetailData, error) {
+	if len(s.supervisors) > 0 && !s.supervisors[userId] {
+		return nil, fmt.Errorf("user %s can't approve a stocktake: %w", userId, v1.ErrForbidden)
+	}
+
+	_, err := s.updateStocktake(ctx, id, ifMatch, func(ctx context.Context, stocktake *model.Stocktake) error {
+		if stocktake.Status != model.StocktakeOpen {
+			return v1.ErrStocktakeStatus
+		}
+
+		// Lines are in their stored order, so the products are locked in the order of their reference
+		for i := range stocktake.Lines {
+			if err := s.adjustStock(ctx, userId, stocktake, &stocktake.Lines[i], req.ZeroUncounted); err != nil {
+				return err
+			}
+		}
+		if err := s.stocktakeRepository.UpdateLines(ctx, stocktake.Lines); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		stocktake.Status, stocktake.ApprovedBy, stocktake.ApprovedAt = model.StocktakeApproved, userId, &now
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetStocktake(ctx, id)
+}
+
+// adjustStock sets the stock of the product of line to its counted quantity, a decrease is taken from the lots
+// first-expired-first-out, expired lots first. Products that are no longer live are left out.
+func (s *stocktakeService) adjustStock(ctx context.Context, userId string, stocktake *model.Stocktake, line *model.StocktakeLine, zeroUncounted bool) error {
+	product, err := s.productRepository.GetProductByIdForUpdate(ctx, line.ProductID)
+	if err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	line.SystemQuantity = product.Quantity
+	if line.CountedQuantity == nil {
+		if !zeroUncounted {
+			return nil
+		}
+		zero := 0
+		line.CountedQuantity = &zero
+	}
+	delta := *line.CountedQuantity - product.Quantity
+	line.Adjustment = &delta
+	if delta == 0 {
+		return nil
+	}
+
+	before, status := product.Quantity, product.Status
+	product.Quantity = *line.CountedQuantity
+	product.SyncStockStatus()
+	if err := s.productRepository.UpdateStock(ctx, product); err != nil {
+		return err
+	}
+	if product.Status != status {
+		change := model.NewProductStatusChange(status, product, model.StockMovementStocktake, userId)
+		if err := s.productStatusChangeRepository.Create(ctx, change); err != nil {
+			return err
+		}
+	}
+
+	movements := []model.StockMovement{{ProductID: product.ID, Delta: delta, QuantityAfter: product.Quantity}}
+	if delta < 0 {
+		lots, err := s.productLotRepository.GetByProduct(ctx, product.ID, false)
+		if err != nil {
+			return err
+		}
+		consumed := model.ConsumeFEFO(lots, -delta)
+		updated := make([]model.ProductLot, 0, len(consumed))
+		for _, consumption := range consumed {
+			updated = append(updated, *consumption.Lot)
+		}
+		if err := s.productLotRepository.UpdateQuantities(ctx, updated); err != nil {
+			return err
+		}
+		movements = model.LotMovements(product, before, consumed)
+	}
+	for _, movement := range movements {
+		movement.Reason, movement.SourceID, movement.ActorID = model.StockMovementStocktake, stocktake.Number, userId
+		if err := s.stockMovementRepository.Create(ctx, &movement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *stocktakeService) CancelStocktake(ctx context.Context, id string, ifMatch v1.IfMatch) (*v1.GetStocktakeDetailData, error) {
+	stocktake, err := s.updateStocktake(ctx, id, ifMatch, func(ctx context.Context, stocktake *model.Stocktake) error {
+		if stocktake.Status != model.StocktakeOpen {
+			return v1.ErrStocktakeStatus
+		}
+
+		now := time.Now()
+		stocktake.Status, stocktake.CancelledAt = model.StocktakeCancelled, &now
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := stocktakeDetailData(stocktake)
+	return &data, nil
+}
+
+// ExportStocktakeSheetToPDF prints the products to count with an empty column for the counted quantity,
+// the stock of the system is left out so it doesn't sway the counters
+func (s *stocktakeService) ExportStocktakeSheetToPDF(ctx context.Context, id string) (*v1.ExportStocktakeData, error) {
+	stocktake, err := s.getStocktake(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	fileName, filePath, err := exportStocktakeSheetPdf(ctx, stocktake)
+	if err != nil {
+		return nil, err
+	}
+	return s.createStocktakeDocument(ctx, fileName, filePath, model.DocumentKindStocktakeSheetPDF)
+}
+
+func (s *stocktakeService) ExportStocktakeVarianceToPDF(ctx context.Context, id string) (*v1.ExportStocktakeData, error) {
+	stocktake, err := s.getStocktake(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	fileName, filePath, err := exportStocktakeVariancePdf(ctx, stocktake, stocktakeVarianceData(stocktake))
+	if err != nil {
+		return nil, err
+	}
+	return s.createStocktakeDocument(ctx, fileName, filePath, model.DocumentKindStocktakeVariancePDF)
+}
+
+func (s *stocktakeService) createStocktakeDocument(ctx context.Context, fileName string, filePath string, kind string) (*v1.ExportStocktakeData, error) {
+	// Store file information in the database //
+	document := model.Documents{
+		ID:         uuid.New(),
+		Filename:   fileName,
+		Path:       filePath,
+		Kind:       kind,
+		UploadedAt: time.Now(),
+	}
+	if err := s.documentRepository.Create(ctx, &document); err != nil {
+		return nil, err
+	}
+
+	return &v1.ExportStocktakeData{
+		DocumentID: document.ID.String(),
+		Filename:   document.Filename,
+	}, nil
+}
+
+// getStocktake return the stocktake id, the system quantities of an open stocktake are the stock of the products now
+func (s *stocktakeService) getStocktake(ctx context.Context, id string) (*model.Stocktake, error) {
+	stocktakeUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, v1.ErrBadRequest
+	}
+
+	stocktake, err := s.stocktakeRepository.GetStocktakeById(ctx, stocktakeUUID)
+	if err != nil {
+		return nil, err
+	}
+	if stocktake.Status == model.StocktakeOpen {
+		for i := range stocktake.Lines {
+			if product := stocktake.Lines[i].Product; product != nil {
+				stocktake.Lines[i].SystemQuantity = product.Quantity
+			}
+		}
+	}
+	return stocktake, nil
+}
+
+// updateStocktake applies a change to the stocktake id and saves it, ifMatch is checked against the locked stocktake
+func (s *stocktakeService) updateStocktake(
+	ctx context.Context,
+	id string,
+	ifMatch v1.IfMatch,
+	apply func(ctx context.Context, stocktake *model.Stocktake) error,
+) (*model.Stocktake, error) {
+	stocktakeUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, v1.ErrBadRequest
+	}
+
+	var stocktake *model.Stocktake
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		stocktake, err = s.stocktakeRepository.GetStocktakeByIdForUpdate(ctx, stocktakeUUID)
+		if err != nil {
+			return err
+		}
+		if !ifMatch.Matches(stocktake.Version) {
+			return v1.ErrVersionMismatch
+		}
+
+		if err := apply(ctx, stocktake); err != nil {
+			return err
+		}
+		stocktake.UpdatedAt = time.Now()
+		return s.stocktakeRepository.Update(ctx, stocktake)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stocktake, nil
+}
+
+func exportStocktakeSheetPdf(ctx context.Context, stocktake *model.Stocktake) (string, string, error) {
+	storagePath := "./storage/pdf"
+	err := os.MkdirAll(storagePath, os.ModePerm)
+	if err != nil {
+		return "", "", err
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	writeStocktakeHeading(pdf, "Count Sheet", stocktake)
+
+	// Lines //
+	widths := []float64{45, 65, 40, 40}
+	pdf.SetFont("Arial", "B", 11)
+	for i, header := range []string{"Reference", "Name", "Location", "Counted"} {
+		pdf.CellFormat(widths[i], 8, header, "1", 0, "C", false, 0, "")
+	}
+	pdf.Ln(-1)
+	pdf.SetFont("Arial", "", 10)
+	for _, line := range stocktake.Lines {
+		pdf.CellFormat(widths[0], 10, line.Reference, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 10, fitText(pdf, line.Name, widths[1]-2), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[2], 10, fitText(pdf, line.StockCity, widths[2]-2), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[3], 10, "", "1", 0, "R", false, 0, "")
+		pdf.Ln(-1)
+	}
+	pdf.Ln(8)
+	pdf.Cell(40, 10, "Counted by: ______________________    Device: ____________    Date: ____________")
+
+	// Unique file name //
+	fileName := fmt.Sprintf("stocktake_sheet_%s_%s.pdf", stocktake.Number, time.Now().Format("20060102_150405"))
+	filePath := filepath.Join(storagePath, fileName)
+
+	// Save the PDF file //
+	if err := pdf.OutputFileAndClose(filePath); err != nil {
+		return "", "", err
+	}
+
+	return fileName, filePath, nil
+}
+
+func exportStocktakeVariancePdf(ctx context.Context, stocktake *model.Stocktake, variance *v1.GetStocktakeVarianceData) (string, string, error) {
+	storagePath := "./storage/pdf"
+	err := os.MkdirAll(storagePath, os.ModePerm)
+	if err != nil {
+		return "", "", err
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	writeStocktakeHeading(pdf, "Variance Report", stocktake)
+
+	// Lines //
+	widths := []float64{40, 55, 20, 20, 20, 35}
+	pdf.SetFont("Arial", "B", 11)
+	for i, header := range []string{"Reference", "Name", "System", "Counted", "Variance", "Value"} {
+		pdf.CellFormat(widths[i], 8, header, "1", 0, "C", false, 0, "")
+	}
+	pdf.Ln(-1)
+	pdf.SetFont("Arial", "", 10)
+	for _, line := range variance.Lines {
+		pdf.CellFormat(widths[0], 8, line.Reference, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 8, fitText(pdf, line.ProductName, widths[1]-2), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[2], 8, fmt.Sprintf("%d", line.SystemQuantity), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 8, fmt.Sprintf("%d", *line.CountedQuantity), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[4], 8, fmt.Sprintf("%+d", *line.Variance), "1", 0, "R", false, 0, "")
+		value := ""
+		if line.VarianceValue != nil {
+			value = fmt.Sprintf("%.2f", *line.VarianceValue)
+		}
+		pdf.CellFormat(widths[5], 8, value, "1", 0, "R", false, 0, "")
+		pdf.Ln(-1)
+	}
+	pdf.SetFont("Arial", "B", 11)
+	for _, total := range []struct {
+		label    string
+		quantity int
+		value    float64
+	}{
+		{"Shrinkage", variance.Shrinkage, variance.ShrinkageValue},
+		{"Surplus", variance.Surplus, variance.SurplusValue},
+	} {
+		pdf.CellFormat(widths[0]+widths[1]+widths[2]+widths[3], 8, total.label, "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[4], 8, fmt.Sprintf("%+d", total.quantity), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[5], 8, fmt.Sprintf("%.2f", total.value), "1", 0, "R", false, 0, "")
+		pdf.Ln(-1)
+	}
+
+	// Uncounted //
+	if len(variance.Uncounted) > 0 {
+		pdf.Ln(6)
+		pdf.SetFont("Arial", "B", 12)
+		pdf.Cell(40, 10, fmt.Sprintf("Not Counted (%d)", len(variance.Uncounted)))
+		pdf.Ln(10)
+		pdf.SetFont("Arial", "", 10)
+		for _, line := range variance.Uncounted {
+			pdf.CellFormat(widths[0], 8, line.Reference, "1", 0, "L", false, 0, "")
+			pdf.CellFormat(widths[1], 8, fitText(pdf, line.ProductName, widths[1]-2), "1", 0, "L", false, 0, "")
+			pdf.CellFormat(widths[2], 8, fmt.Sprintf("%d", line.SystemQuantity), "1", 0, "R", false, 0, "")
+			pdf.Ln(-1)
+		}
+	}
+
+	// Unique file name //
+	fileName := fmt.Sprintf("stocktake_variance_%s_%s.pdf", stocktake.Number, time.Now().Format("20060102_150405"))
+	filePath := filepath.Join(storagePath, fileName)
+
+	// Save the PDF file //
+	if err := pdf.OutputFileAndClose(filePath); err != nil {
+		return "", "", err
+	}
+
+	return fileName, filePath, nil
+}
+
+func writeStocktakeHeading(pdf *gofpdf.Fpdf, title string, stocktake *model.Stocktake) {
+	pdf.SetFont("Arial", "B", 16)
+	pdf.Cell(40, 10, fmt.Sprintf("%s %s", title, stocktake.Number))
+	pdf.Ln(12)
+
+	pdf.SetFont("Arial", "", 12)
+	if stocktake.StockCity != "" {
+		pdf.Cell(40, 10, fmt.Sprintf("Stock Location: %s", stocktake.StockCity))
+		pdf.Ln(8)
+	}
+	if stocktake.Category != nil {
+		pdf.Cell(40, 10, fmt.Sprintf("Category: %s", stocktake.Category.Name))
+		pdf.Ln(8)
+	}
+	pdf.Cell(40, 10, fmt.Sprintf("Status: %s", stocktake.Status))
+	pdf.Ln(8)
+	pdf.Cell(40, 10, fmt.Sprintf("Opened: %s", stocktake.CreatedAt.Format("2006-01-02")))
+	pdf.Ln(8)
+	if stocktake.ApprovedAt != nil {
+		pdf.Cell(40, 10, fmt.Sprintf("Approved: %s by %s", stocktake.ApprovedAt.Format("2006-01-02"), stocktake.ApprovedBy))
+		pdf.Ln(8)
+	}
+	pdf.Ln(4)
+}
+
+func stocktakeVarianceData(stocktake *model.Stocktake) *v1.GetStocktakeVarianceData {
+	data := &v1.GetStocktakeVarianceData{
+		Number:    stocktake.Number,
+		Status:    stocktake.Status,
+		Lines:     []v1.StocktakeLineData{},
+		Uncounted: []v1.StocktakeLineData{},
+	}
+	for i := range stocktake.Lines {
+		line := stocktakeLineData(&stocktake.Lines[i])
+		switch {
+		case line.Variance == nil:
+			data.Uncounted = append(data.Uncounted, line)
+		case *line.Variance != 0:
+			data.Lines = append(data.Lines, line)
+			value := 0.0
+			if line.VarianceValue != nil {
+				value = *line.VarianceValue
+			}
+			if *line.Variance < 0 {
+				data.Shrinkage += *line.Variance
+				data.ShrinkageValue += value
+			} else {
+				data.Surplus += *line.Variance
+				data.SurplusValue += value
+			}
+		}
+	}
+	return data
+}
+
+func stocktakeDetailData(stocktake *model.Stocktake) v1.GetStocktakeDetailData {
+	data := v1.GetStocktakeDetailData{
+		ID:                   stocktake.ID.String(),
+		Number:               stocktake.Number,
+		Status:               stocktake.Status,
+		StockLocation:        stocktake.StockCity,
+		IncludeSubcategories: stocktake.IncludeSubcategories,
+		Note:                 stocktake.Note,
+		CreatedBy:            stocktake.CreatedBy,
+		ApprovedBy:           stocktake.ApprovedBy,
+		CreatedAt:            stocktake.CreatedAt.Format(time.RFC3339),
+		Products:             len(stocktake.Lines),
+		Version:              stocktake.Version,
+	}
+	if stocktake.CategoryID != nil {
+		data.CategoryID = stocktake.CategoryID.String()
+	}
+	if stocktake.ApprovedAt != nil {
+		data.ApprovedAt = stocktake.ApprovedAt.Format(time.RFC3339)
+	}
+	if stocktake.CancelledAt != nil {
+		data.CancelledAt = stocktake.CancelledAt.Format(time.RFC3339)
+	}
+	for i := range stocktake.Lines {
+		if stocktake.Lines[i].CountedQuantity != nil {
+			data.Counted++
+		}
+		data.Lines = append(data.Lines, stocktakeLineData(&stocktake.Lines[i]))
+	}
+	return data
+}
+
+func stocktakeLineData(line *model.StocktakeLine) v1.StocktakeLineData {
+	data := v1.StocktakeLineData{
+		Reference:       line.Reference,
+		ProductName:     line.Name,
+		StockLocation:   line.StockCity,
+		SystemQuantity:  line.SystemQuantity,
+		CountedQuantity: line.CountedQuantity,
+		Variance:        line.Variance(),
+		Adjustment:      line.Adjustment,
+	}
+	if data.Variance != nil && line.Product != nil {
+		value := float64(*data.Variance) * line.Product.Price
+		data.VarianceValue = &value
+	}
+	return data
+}
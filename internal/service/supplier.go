@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	v1 "github.com/quydmfl/niveau-test/api/v1"
@@ -13,21 +15,36 @@ type SupplierService interface {
 	GetSuppliers(ctx context.Context, req *v1.SearchSupplierRequest) (*v1.SearchSupplierResponse, error)
 	GetSupplier(ctx context.Context, id string) (*v1.GetSupplierDetailData, error)
 	CreateSupplier(ctx context.Context, req *v1.CreateSupplierRequest) error
+	UpdateSupplier(ctx context.Context, id string, req *v1.UpdateSupplierRequest, ifMatch v1.IfMatch) (*v1.GetSupplierDetailData, error)
+	MergeSupplier(ctx context.Context, userId string, id string, req *v1.MergeSupplierRequest) (*v1.MergeSupplierData, error)
+	DeleteSupplier(ctx context.Context, id string, ifMatch v1.IfMatch) error
+	GetDeletedSuppliers(ctx context.Context, req *v1.GetTrashRequest) (*v1.GetTrashResponse, error)
+	RestoreSupplier(ctx context.Context, id string) error
+	PurgeSupplier(ctx context.Context, id string) error
 }
 
 func NewSupplierService(
 	service *Service,
 	supplierRepository repository.SupplierRepository,
+	productRepository repository.ProductRepository,
+	productVersionRepository repository.ProductVersionRepository,
+	outboxRepository repository.OutboxRepository,
 ) SupplierService {
 	return &supplierService{
-		Service:            service,
-		supplierRepository: supplierRepository,
+		Service:                  service,
+		supplierRepository:       supplierRepository,
+		productRepository:        productRepository,
+		productVersionRepository: productVersionRepository,
+		outboxRepository:         outboxRepository,
 	}
 }
 
 type supplierService struct {
 	*Service
-	supplierRepository repository.SupplierRepository
+	supplierRepository       repository.SupplierRepository
+	productRepository        repository.ProductRepository
+	productVersionRepository repository.ProductVersionRepository
+	outboxRepository         repository.OutboxRepository
 }
 
 func (s *supplierService) GetSupplier(ctx context.Context, id string) (*v1.GetSupplierDetailData, error) {
@@ -36,15 +53,17 @@ func (s *supplierService) GetSupplier(ctx context.Context, id string) (*v1.GetSu
 		return nil, err
 	}
 
-	supplier, err := s.supplierRepository.GetSupplierById(ctx, supplierUUID)
+	supplier, err := s.supplierRepository.GetSupplierDetailById(ctx, supplierUUID)
 	if err != nil {
 		return nil, err
 	}
 
-	return &v1.GetSupplierDetailData{
-		ID:   supplier.ID.String(),
-		Name: supplier.Name,
-	}, nil
+	data := supplierDetailData(supplier)
+	if data.Stock, err = s.productRepository.StatsSupplierStock(ctx, supplier.ID); err != nil {
+		return nil, err
+	}
+
+	return &data, nil
 }
 
 func (s *supplierService) GetSuppliers(ctx context.Context, req *v1.SearchSupplierRequest) (*v1.SearchSupplierResponse, error) {
@@ -55,11 +74,8 @@ func (s *supplierService) GetSuppliers(ctx context.Context, req *v1.SearchSuppli
 		return nil, err
 	}
 
-	for _, supplier := range *suppliers {
-		result = append(result, v1.GetSupplierDetailData{
-			ID:   supplier.ID.String(),
-			Name: supplier.Name,
-		})
+	for i := range *suppliers {
+		result = append(result, supplierDetailData(&(*suppliers)[i]))
 	}
 
 	// Calculate total pages
@@ -82,7 +98,15 @@ func (s *supplierService) GetSuppliers(ctx context.Context, req *v1.SearchSuppli
 
 func (s *supplierService) CreateSupplier(ctx context.Context, req *v1.CreateSupplierRequest) error {
 	supplier := &model.Supplier{
-		Name: req.Name,
+		Name:         req.Name,
+		Email:        req.Email,
+		Phone:        req.Phone,
+		Currency:     req.Currency,
+		PaymentTerms: req.PaymentTerms,
+		LeadTimeDays: req.LeadTimeDays,
+		Status:       req.Status,
+		Contacts:     supplierContacts(req.Contacts),
+		Addresses:    supplierAddresses(req.Addresses),
 	}
 
 	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
@@ -94,3 +118,192 @@ func (s *supplierService) CreateSupplier(ctx context.Context, req *v1.CreateSupp
 
 	return err
 }
+
+func (s *supplierService) UpdateSupplier(ctx context.Context, id string, req *v1.UpdateSupplierRequest, ifMatch v1.IfMatch) (*v1.GetSupplierDetailData, error) {
+	supplierUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, v1.ErrBadRequest
+	}
+
+	var supplier *model.Supplier
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		supplier, err = s.supplierRepository.GetSupplierByIdForUpdate(ctx, supplierUUID)
+		if err != nil {
+			return err
+		}
+		if !ifMatch.Matches(supplier.Version) {
+			return v1.ErrVersionMismatch
+		}
+
+		supplier.Name = req.Name
+		supplier.Email = req.Email
+		supplier.Phone = req.Phone
+		supplier.Currency = req.Currency
+		supplier.PaymentTerms = req.PaymentTerms
+		supplier.LeadTimeDays = req.LeadTimeDays
+		supplier.Status = req.Status
+		supplier.UpdatedAt = time.Now()
+		if err := s.supplierRepository.Update(ctx, supplier); err != nil {
+			return err
+		}
+
+		supplier.Contacts = supplierContacts(req.Contacts)
+		if err := s.supplierRepository.ReplaceContacts(ctx, supplier); err != nil {
+			return err
+		}
+		supplier.Addresses = supplierAddresses(req.Addresses)
+		return s.supplierRepository.ReplaceAddresses(ctx, supplier)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := supplierDetailData(supplier)
+	return &data, nil
+}
+
+// MergeSupplier moves the products of a duplicated supplier to the target with the contacts and addresses
+// the target doesn't have yet, then puts it in the trash.
+// The moved products get a new version and a product update event like any other change.
+func (s *supplierService) MergeSupplier(ctx context.Context, userId string, id string, req *v1.MergeSupplierRequest) (*v1.MergeSupplierData, error) {
+	sourceUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, v1.ErrBadRequest
+	}
+	targetUUID, _ := uuid.Parse(req.TargetId)
+	if sourceUUID == targetUUID {
+		return nil, fmt.Errorf("supplier %s can't merge into itself: %w", id, v1.ErrBadRequest)
+	}
+
+	data := &v1.MergeSupplierData{}
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		source, err := s.supplierRepository.GetSupplierByIdForUpdate(ctx, sourceUUID)
+		if err != nil {
+			return err
+		}
+		target, err := s.supplierRepository.GetSupplierByIdForUpdate(ctx, targetUUID)
+		if err != nil {
+			return fmt.Errorf("supplier %s not found: %w", req.TargetId, v1.ErrBadRequest)
+		}
+
+		products, err := s.productRepository.GetBySupplierForUpdate(ctx, source.ID)
+		if err != nil {
+			return err
+		}
+		if data.MovedProducts, err = s.productRepository.MoveSupplier(ctx, source.ID, target.ID); err != nil {
+			return err
+		}
+		err = recordMovedProducts(ctx, s.productVersionRepository, s.outboxRepository, userId, products, func(product *model.Product) {
+			product.SupplierID = target.ID
+		})
+		if err != nil {
+			return err
+		}
+
+		// The duplicated contacts and addresses stay with the source in the trash
+		var contactIds, addressIds []uint64
+		for i := range source.Contacts {
+			if !hasContact(target.Contacts, &source.Contacts[i]) {
+				contactIds = append(contactIds, source.Contacts[i].ID)
+			}
+		}
+		for i := range source.Addresses {
+			if !hasAddress(target.Addresses, &source.Addresses[i]) {
+				addressIds = append(addressIds, source.Addresses[i].ID)
+			}
+		}
+		if err := s.supplierRepository.MoveContacts(ctx, contactIds, target.ID); err != nil {
+			return err
+		}
+		if err := s.supplierRepository.MoveAddresses(ctx, addressIds, target.ID); err != nil {
+			return err
+		}
+		data.MovedContacts, data.MovedAddresses = len(contactIds), len(addressIds)
+
+		return s.supplierRepository.Delete(ctx, source)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func hasContact(contacts []model.SupplierContact, contact *model.SupplierContact) bool {
+	for i := range contacts {
+		if contacts[i].SameAs(contact) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAddress(addresses []model.SupplierAddress, address *model.SupplierAddress) bool {
+	for i := range addresses {
+		if addresses[i].SameAs(address) {
+			return true
+		}
+	}
+	return false
+}
+
+func supplierContacts(contacts []v1.SupplierContactRequest) []model.SupplierContact {
+	result := make([]model.SupplierContact, 0, len(contacts))
+	for _, contact := range contacts {
+		result = append(result, model.SupplierContact{
+			Name:  contact.Name,
+			Role:  contact.Role,
+			Email: contact.Email,
+			Phone: contact.Phone,
+		})
+	}
+	return result
+}
+
+func supplierAddresses(addresses []v1.SupplierAddressRequest) []model.SupplierAddress {
+	result := make([]model.SupplierAddress, 0, len(addresses))
+	for _, address := range addresses {
+		result = append(result, model.SupplierAddress{
+			Kind:       address.Kind,
+			Line1:      address.Line1,
+			Line2:      address.Line2,
+			City:       address.City,
+			PostalCode: address.PostalCode,
+			Country:    address.Country,
+		})
+	}
+	return result
+}
+
+func supplierDetailData(supplier *model.Supplier) v1.GetSupplierDetailData {
+	data := v1.GetSupplierDetailData{
+		ID:           supplier.ID.String(),
+		Name:         supplier.Name,
+		Email:        supplier.Email,
+		Phone:        supplier.Phone,
+		Currency:     supplier.Currency,
+		PaymentTerms: supplier.PaymentTerms,
+		LeadTimeDays: supplier.LeadTimeDays,
+		Status:       supplier.Status,
+		Version:      supplier.Version,
+	}
+	for _, contact := range supplier.Contacts {
+		data.Contacts = append(data.Contacts, v1.SupplierContactData{
+			Name:  contact.Name,
+			Role:  contact.Role,
+			Email: contact.Email,
+			Phone: contact.Phone,
+		})
+	}
+	for _, address := range supplier.Addresses {
+		data.Addresses = append(data.Addresses, v1.SupplierAddressData{
+			Kind:       address.Kind,
+			Line1:      address.Line1,
+			Line2:      address.Line2,
+			City:       address.City,
+			PostalCode: address.PostalCode,
+			Country:    address.Country,
+		})
+	}
+	return data
+}
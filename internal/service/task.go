@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/quydmfl/niveau-test/internal/task"
+)
+
+type TaskService interface {
+	GetTasks(ctx context.Context) []v1.GetTaskData
+	GetTaskRuns(ctx context.Context, req *v1.SearchTaskRunRequest) (*v1.SearchTaskRunResponse, error)
+	GetTaskRun(ctx context.Context, id uint64) (*v1.GetTaskRunData, error)
+	TriggerTask(ctx context.Context, userId string, name string, req *v1.TriggerTaskRequest) (*v1.GetTaskRunData, error)
+}
+
+func NewTaskService(
+	service *Service,
+	registry *task.Registry,
+	taskRunRepository repository.TaskRunRepository,
+) TaskService {
+	return &taskService{
+		Service:           service,
+		registry:          registry,
+		taskRunRepository: taskRunRepository,
+	}
+}
+
+type taskService struct {
+	*Service
+	registry          *task.Registry
+	taskRunRepository repository.TaskRunRepository
+}
+
+func (s *taskService) GetTasks(ctx context.Context) []v1.GetTaskData {
+	var result []v1.GetTaskData
+	for _, def := range s.registry.Definitions() {
+		result = append(result, v1.GetTaskData{
+			Name:    def.Name,
+			Cron:    def.Cron,
+			Timeout: def.Timeout.String(),
+			Overlap: string(def.Overlap),
+			DryRun:  def.DryRun,
+		})
+	}
+	return result
+}
+
+func (s *taskService) GetTaskRuns(ctx context.Context, req *v1.SearchTaskRunRequest) (*v1.SearchTaskRunResponse, error) {
+	var result []v1.GetTaskRunData
+
+	runs, total, err := s.taskRunRepository.Search(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range *runs {
+		result = append(result, taskRunData(&(*runs)[i]))
+	}
+
+	// Calculate total pages
+	totalPages := int(total / req.Size)
+	if total%req.Size > 0 {
+		totalPages++
+	}
+
+	return &v1.SearchTaskRunResponse{
+		Pagination: v1.Pagination{
+			Page:       req.Page,
+			TotalRows:  total,
+			TotalPages: totalPages,
+		},
+		Response: v1.Response{
+			Data: result,
+		},
+	}, nil
+}
+
+func (s *taskService) GetTaskRun(ctx context.Context, id uint64) (*v1.GetTaskRunData, error) {
+	run, err := s.taskRunRepository.GetTaskRunById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	data := taskRunData(run)
+	return &data, nil
+}
+
+func (s *taskService) TriggerTask(ctx context.Context, userId string, name string, req *v1.TriggerTaskRequest) (*v1.GetTaskRunData, error) {
+	run, err := s.registry.Trigger(ctx, name, userId, req.DryRun)
+	if err != nil {
+		if err == task.ErrTaskNotFound {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+
+	data := taskRunData(run)
+	return &data, nil
+}
+
+func taskRunData(run *model.TaskRun) v1.GetTaskRunData {
+	data := v1.GetTaskRunData{
+		ID:          run.ID,
+		TaskName:    run.TaskName,
+		Trigger:     run.Trigger,
+		TriggeredBy: run.TriggeredBy,
+		DryRun:      run.DryRun,
+		Status:      run.Status,
+		Error:       run.Error,
+		Host:        run.Host,
+		ScheduledAt: formatTime(run.ScheduledAt),
+		StartedAt:   formatTime(run.StartedAt),
+		FinishedAt:  formatTime(run.FinishedAt),
+	}
+	if run.Result != "" {
+		data.Result = json.RawMessage(run.Result)
+	}
+	return data
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"gorm.io/gorm"
+)
+
+func (s *productService) GetDeletedProducts(ctx context.Context, req *v1.GetTrashRequest) (*v1.GetTrashResponse, error) {
+	products, total, err := s.productRepository.SearchDeleted(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []v1.TrashItemData
+	for _, product := range *products {
+		result = append(result, trashItemData(product.Reference, product.Name, product.DeletedAt))
+	}
+	return trashResponse(req, result, total), nil
+}
+
+func (s *productService) RestoreProduct(ctx context.Context, productRef string) error {
+	return s.tm.Transaction(ctx, func(ctx context.Context) error {
+		product, err := s.productRepository.GetDeletedProductByPref(ctx, productRef)
+		if err != nil {
+			return err
+		}
+
+		// The parent is restored first
+		if product.IsVariant() {
+			if _, err := s.productRepository.GetProductByIdForUpdate(ctx, *product.ParentID); err != nil {
+				return fmt.Errorf("parent product %s is not live: %w", product.ParentID, v1.ErrBadRequest)
+			}
+		}
+
+		if err := s.productRepository.Restore(ctx, product); err != nil {
+			return err
+		}
+		return s.addProductEvent(ctx, model.ProductRestoredEvent, product)
+	})
+}
+
+// PurgeProduct deletes a product in the trash for good, with its price and version history and its variants in the trash
+func (s *productService) PurgeProduct(ctx context.Context, productRef string) error {
+	var images []model.ProductImage
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		product, err := s.productRepository.GetDeletedProductByPref(ctx, productRef)
+		if err != nil {
+			return err
+		}
+		// The rows of the images go with the product, their files once it is committed
+		images, err = s.productImageRepository.GetByProductWithVariants(ctx, product.ID)
+		if err != nil {
+			return err
+		}
+		return s.productRepository.Purge(ctx, product)
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range images {
+		s.removeImageFiles(ctx, &images[i])
+	}
+	return nil
+}
+
+// DeleteCategory puts the category in the trash.
+// It is refused while the category has live products or subcategories.
+func (s *categoryService) DeleteCategory(ctx context.Context, id string, ifMatch v1.IfMatch) error {
+	categoryUUID, err := uuid.Parse(id)
+	if err != nil {
+		return v1.ErrBadRequest
+	}
+
+	return s.tm.Transaction(ctx, func(ctx context.Context) error {
+		// The lock keeps products from being added to the category until it is in the trash
+		category, err := s.categoryRepository.GetCategoryByIdForUpdate(ctx, categoryUUID)
+		if err != nil {
+			return err
+		}
+		if !ifMatch.Matches(category.Version) {
+			return v1.ErrVersionMismatch
+		}
+
+		count, err := s.productRepository.CountByCategory(ctx, category.ID, false)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return v1.ErrCategoryHasProducts
+		}
+
+		if count, err = s.categoryRepository.CountChildren(ctx, category.ID, false); err != nil {
+			return err
+		}
+		if count > 0 {
+			return v1.ErrCategoryHasChildren
+		}
+
+		return s.categoryRepository.Delete(ctx, category)
+	})
+}
+
+func (s *categoryService) GetDeletedCategories(ctx context.Context, req *v1.GetTrashRequest) (*v1.GetTrashResponse, error) {
+	categories, total, err := s.categoryRepository.SearchDeleted(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []v1.TrashItemData
+	for _, category := range *categories {
+		result = append(result, trashItemData(category.ID.String(), category.Name, category.DeletedAt))
+	}
+	return trashResponse(req, result, total), nil
+}
+
+func (s *categoryService) RestoreCategory(ctx context.Context, id string) error {
+	categoryUUID, err := uuid.Parse(id)
+	if err != nil {
+		return v1.ErrBadRequest
+	}
+
+	return s.tm.Transaction(ctx, func(ctx context.Context) error {
+		category, err := s.categoryRepository.GetDeletedCategoryById(ctx, categoryUUID)
+		if err != nil {
+			return err
+		}
+
+		// The parent is restored first
+		if category.ParentID != nil {
+			if _, err := s.categoryRepository.GetCategoryById(ctx, *category.ParentID); err != nil {
+				return fmt.Errorf("parent category %s is not live: %w", category.ParentID, v1.ErrBadRequest)
+			}
+		}
+
+		return s.categoryRepository.Restore(ctx, category)
+	})
+}
+
+// PurgeCategory deletes a category in the trash for good with its products in the trash,
+// it is refused while the category has live products or subcategories, even in the trash
+func (s *categoryService) PurgeCategory(ctx context.Context, id string) error {
+	categoryUUID, err := uuid.Parse(id)
+	if err != nil {
+		return v1.ErrBadRequest
+	}
+
+	return s.tm.Transaction(ctx, func(ctx context.Context) error {
+		category, err := s.categoryRepository.GetDeletedCategoryById(ctx, categoryUUID)
+		if err != nil {
+			return err
+		}
+
+		count, err := s.productRepository.CountByCategory(ctx, category.ID, false)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return v1.ErrCategoryHasProducts
+		}
+
+		if count, err = s.categoryRepository.CountChildren(ctx, category.ID, true); err != nil {
+			return err
+		}
+		if count > 0 {
+			return v1.ErrCategoryHasChildren
+		}
+
+		if err := s.productRepository.PurgeByCategory(ctx, category.ID); err != nil {
+			return err
+		}
+		return s.categoryRepository.Purge(ctx, category)
+	})
+}
+
+func (s *supplierService) DeleteSupplier(ctx context.Context, id string, ifMatch v1.IfMatch) error {
+	supplierUUID, err := uuid.Parse(id)
+	if err != nil {
+		return v1.ErrBadRequest
+	}
+
+	supplier, err := s.supplierRepository.GetSupplierById(ctx, supplierUUID)
+	if err != nil {
+		return err
+	}
+	if !ifMatch.Matches(supplier.Version) {
+		return v1.ErrVersionMismatch
+	}
+
+	return s.supplierRepository.Delete(ctx, supplier)
+}
+
+func (s *supplierService) GetDeletedSuppliers(ctx context.Context, req *v1.GetTrashRequest) (*v1.GetTrashResponse, error) {
+	suppliers, total, err := s.supplierRepository.SearchDeleted(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []v1.TrashItemData
+	for _, supplier := range *suppliers {
+		result = append(result, trashItemData(supplier.ID.String(), supplier.Name, supplier.DeletedAt))
+	}
+	return trashResponse(req, result, total), nil
+}
+
+func (s *supplierService) RestoreSupplier(ctx context.Context, id string) error {
+	supplierUUID, err := uuid.Parse(id)
+	if err != nil {
+		return v1.ErrBadRequest
+	}
+
+	return s.tm.Transaction(ctx, func(ctx context.Context) error {
+		supplier, err := s.supplierRepository.GetDeletedSupplierById(ctx, supplierUUID)
+		if err != nil {
+			return err
+		}
+		return s.supplierRepository.Restore(ctx, supplier)
+	})
+}
+
+// PurgeSupplier deletes a supplier in the trash for good, its products are left without supplier
+func (s *supplierService) PurgeSupplier(ctx context.Context, id string) error {
+	supplierUUID, err := uuid.Parse(id)
+	if err != nil {
+		return v1.ErrBadRequest
+	}
+
+	return s.tm.Transaction(ctx, func(ctx context.Context) error {
+		supplier, err := s.supplierRepository.GetDeletedSupplierById(ctx, supplierUUID)
+		if err != nil {
+			return err
+		}
+		return s.supplierRepository.Purge(ctx, supplier)
+	})
+}
+
+func trashItemData(id string, name string, deletedAt gorm.DeletedAt) v1.TrashItemData {
+	return v1.TrashItemData{
+		ID:        id,
+		Name:      name,
+		DeletedAt: formatTime(&deletedAt.Time),
+	}
+}
+
+func trashResponse(req *v1.GetTrashRequest, items []v1.TrashItemData, total int) *v1.GetTrashResponse {
+	// Calculate total pages
+	totalPages := int(total / req.Size)
+	if total%req.Size > 0 {
+		totalPages++
+	}
+
+	return &v1.GetTrashResponse{
+		Pagination: v1.Pagination{
+			Page:       req.Page,
+			TotalRows:  total,
+			TotalPages: totalPages,
+		},
+		Response: v1.Response{
+			Data: items,
+		},
+	}
+}
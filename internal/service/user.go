@@ -55,6 +55,7 @@ func (s *userService) Register(ctx context.Context, req *v1.RegisterRequest) err
 		UserId:   userId,
 		Email:    req.Email,
 		Password: string(hashedPassword),
+		Role:     model.UserRoleUser,
 	}
 	// Transaction demo
 	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
@@ -78,7 +79,7 @@ func (s *userService) Login(ctx context.Context, req *v1.LoginRequest) (string,
 	if err != nil {
 		return "", err
 	}
-	token, err := s.jwt.GenToken(user.UserId, time.Now().Add(time.Hour*24*90))
+	token, err := s.jwt.GenToken(user.UserId, user.Role, time.Now().Add(time.Hour*24*90))
 	if err != nil {
 		return "", err
 	}
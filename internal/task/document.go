@@ -0,0 +1,230 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type DocumentTask interface {
+	TaskProvider
+	CleanupDocuments(ctx context.Context) (*DocumentCleanupReport, error)
+}
+
+// DocumentCleanupReport lists what a cleanup run deleted or flagged, or would have in dry run
+type DocumentCleanupReport struct {
+	DryRun bool `json:"dry_run"`
+	// ExpiredDocuments are past the retention of their kind
+	ExpiredDocuments []string `json:"expired_documents"`
+	// DetachedDocuments belonged to a deleted product
+	DetachedDocuments []string `json:"detached_documents"`
+	// OrphanFiles are in storage without a documents row
+	OrphanFiles []string `json:"orphan_files"`
+	// MissingFiles are referenced by a documents row but no longer in storage
+	MissingFiles []string `json:"missing_files"`
+	// RestoredFiles were flagged missing and are back in storage
+	RestoredFiles []string `json:"restored_files"`
+	Errors        []string `json:"errors,omitempty"`
+}
+
+func NewDocumentTask(
+	task *Task,
+	conf *viper.Viper,
+	documentRepo repository.DocumentsRepository,
+) DocumentTask {
+	t := &documentTask{
+		Task:              task,
+		documentRepo:      documentRepo,
+		storagePath:       conf.GetString("task.document_cleanup.storage_path"),
+		retention:         make(map[string]time.Duration),
+		detachedRetention: conf.GetDuration("task.document_cleanup.detached_retention"),
+		orphanGrace:       conf.GetDuration("task.document_cleanup.orphan_grace"),
+	}
+
+	// Default values
+	if t.storagePath == "" {
+		t.storagePath = "./storage/pdf"
+	}
+	if t.orphanGrace <= 0 {
+		t.orphanGrace = time.Hour
+	}
+
+	// Kinds without a retention are kept forever
+	for kind := range conf.GetStringMap("task.document_cleanup.retention") {
+		if retention := conf.GetDuration("task.document_cleanup.retention." + kind); retention > 0 {
+			t.retention[kind] = retention
+		}
+	}
+
+	return t
+}
+
+type documentTask struct {
+	*Task
+	documentRepo      repository.DocumentsRepository
+	storagePath       string
+	retention         map[string]time.Duration
+	detachedRetention time.Duration
+	// orphanGrace protects files whose row is not committed yet
+	orphanGrace time.Duration
+}
+
+func (t *documentTask) Definition(conf *viper.Viper) Definition {
+	return newDefinition(conf, "document_cleanup", func(ctx context.Context) (interface{}, error) {
+		return t.CleanupDocuments(ctx)
+	})
+}
+
+func (t *documentTask) CleanupDocuments(ctx context.Context) (*DocumentCleanupReport, error) {
+	now := time.Now()
+	report := &DocumentCleanupReport{DryRun: IsDryRun(ctx)}
+
+	// Expired and detached documents //
+	expired := make(map[uuid.UUID]model.Documents)
+	for kind, retention := range t.retention {
+		documents, err := t.documentRepo.GetExpired(ctx, kind, now.Add(-retention))
+		if err != nil {
+			return nil, err
+		}
+		for _, document := range documents {
+			expired[document.ID] = document
+			report.ExpiredDocuments = append(report.ExpiredDocuments, document.Path)
+		}
+	}
+	if t.detachedRetention > 0 {
+		documents, err := t.documentRepo.GetDetached(ctx, now.Add(-t.detachedRetention))
+		if err != nil {
+			return nil, err
+		}
+		for _, document := range documents {
+			if _, ok := expired[document.ID]; ok {
+				continue
+			}
+			expired[document.ID] = document
+			report.DetachedDocuments = append(report.DetachedDocuments, document.Path)
+		}
+	}
+
+	if !report.DryRun && len(expired) > 0 {
+		ids := make([]uuid.UUID, 0, len(expired))
+		for id := range expired {
+			ids = append(ids, id)
+		}
+		// Rows go first, a file left behind by a failed remove is collected as an orphan next run
+		if err := t.documentRepo.DeleteByIds(ctx, ids); err != nil {
+			return nil, err
+		}
+		for _, document := range expired {
+			if err := os.Remove(document.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				report.Errors = append(report.Errors, err.Error())
+			}
+		}
+	}
+
+	// Missing files //
+	documents, err := t.documentRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(documents)+len(expired))
+	for _, document := range expired {
+		known[absPath(document.Path)] = true
+	}
+
+	var missing, restored []uuid.UUID
+	for _, document := range documents {
+		known[absPath(document.Path)] = true
+		if _, ok := expired[document.ID]; ok {
+			continue
+		}
+
+		_, err := os.Stat(document.Path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			if document.MissingAt == nil {
+				missing = append(missing, document.ID)
+				report.MissingFiles = append(report.MissingFiles, document.Path)
+			}
+		case err != nil:
+			report.Errors = append(report.Errors, err.Error())
+		case document.MissingAt != nil:
+			restored = append(restored, document.ID)
+			report.RestoredFiles = append(report.RestoredFiles, document.Path)
+		}
+	}
+
+	if !report.DryRun {
+		if err := t.documentRepo.MarkMissing(ctx, missing, now); err != nil {
+			return nil, err
+		}
+		if err := t.documentRepo.ClearMissing(ctx, restored); err != nil {
+			return nil, err
+		}
+	}
+
+	// Orphan files //
+	err = filepath.WalkDir(t.storagePath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !entry.Type().IsRegular() || known[absPath(path)] {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if now.Sub(info.ModTime()) < t.orphanGrace {
+			return nil
+		}
+
+		report.OrphanFiles = append(report.OrphanFiles, path)
+		if !report.DryRun {
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				report.Errors = append(report.Errors, err.Error())
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	sort.Strings(report.ExpiredDocuments)
+	sort.Strings(report.DetachedDocuments)
+
+	t.logger.Info("CleanupDocuments",
+		zap.Bool("dry_run", report.DryRun),
+		zap.Int("expired", len(report.ExpiredDocuments)),
+		zap.Int("detached", len(report.DetachedDocuments)),
+		zap.Int("orphans", len(report.OrphanFiles)),
+		zap.Int("missing", len(report.MissingFiles)),
+		zap.Int("errors", len(report.Errors)),
+	)
+
+	return report, nil
+}
+
+// absPath normalizes a stored path so rows and walked files compare equal
+func absPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	return abs
+}
@@ -0,0 +1,239 @@
+package task
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quydmfl/niveau-test/internal/helper"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type ForecastTask interface {
+	TaskProvider
+	ComputeForecasts(ctx context.Context) (*ForecastReport, error)
+}
+
+// ForecastReport lists the products with a reorder to place, the forecasts are only stored when it is not a dry run
+type ForecastReport struct {
+	DryRun   bool             `json:"dry_run"`
+	Since    string           `json:"since"`
+	Method   string           `json:"method"`
+	Products int              `json:"products"`
+	Reorder  []ForecastResult `json:"reorder"`
+}
+
+type ForecastResult struct {
+	Reference       string  `json:"reference"`
+	DailyDemand     float64 `json:"daily_demand"`
+	Quantity        int     `json:"quantity"`
+	OnOrder         int     `json:"on_order"`
+	ReorderPoint    int     `json:"reorder_point"`
+	ReorderQuantity int     `json:"reorder_quantity"`
+}
+
+func NewForecastTask(
+	task *Task,
+	conf *viper.Viper,
+	productForecastRepo repository.ProductForecastRepository,
+	stockMovementRepo repository.StockMovementRepository,
+	purchaseOrderRepo repository.PurchaseOrderRepository,
+) ForecastTask {
+	t := &forecastTask{
+		Task:                task,
+		productForecastRepo: productForecastRepo,
+		stockMovementRepo:   stockMovementRepo,
+		purchaseOrderRepo:   purchaseOrderRepo,
+		method:              conf.GetString("task.demand_forecast.method"),
+		periodDays:          conf.GetInt("task.demand_forecast.period_days"),
+		periods:             conf.GetInt("task.demand_forecast.periods"),
+		window:              conf.GetInt("task.demand_forecast.window"),
+		alpha:               conf.GetFloat64("task.demand_forecast.alpha"),
+		safetyFactor:        conf.GetFloat64("task.demand_forecast.safety_factor"),
+		reviewDays:          conf.GetInt("task.demand_forecast.review_days"),
+	}
+
+	// Default values
+	if t.method != model.ForecastMovingAverage {
+		t.method = model.ForecastExponentialSmoothing
+	}
+	if t.periodDays <= 0 {
+		t.periodDays = 7
+	}
+	if t.periods <= 0 {
+		t.periods = 12
+	}
+	if t.window <= 0 {
+		t.window = 4
+	}
+	if t.alpha <= 0 || t.alpha > 1 {
+		t.alpha = 0.3
+	}
+	// 0 is no safety stock
+	if !conf.IsSet("task.demand_forecast.safety_factor") || t.safetyFactor < 0 {
+		t.safetyFactor = 1.65
+	}
+	if t.reviewDays <= 0 {
+		t.reviewDays = 14
+	}
+
+	return t
+}
+
+type forecastTask struct {
+	*Task
+	productForecastRepo repository.ProductForecastRepository
+	stockMovementRepo   repository.StockMovementRepository
+	purchaseOrderRepo   repository.PurchaseOrderRepository
+
+	method string
+	// The history is periods of periodDays days up to now, the moving average is over the last window of them
+	periodDays int
+	periods    int
+	window     int
+	alpha      float64
+	// safetyFactor is the number of standard deviations of the demand over the lead time kept as safety stock
+	safetyFactor float64
+	// reviewDays is the demand a reorder covers after its delivery, until the next one
+	reviewDays int
+}
+
+func (t *forecastTask) Definition(conf *viper.Viper) Definition {
+	return newDefinition(conf, "demand_forecast", func(ctx context.Context) (interface{}, error) {
+		return t.ComputeForecasts(ctx)
+	})
+}
+
+// ComputeForecasts forecasts the daily demand of every product from its stock decreases and suggests a reorder
+// once the stock and what is on order fall to the demand over the lead time of its supplier plus the safety stock.
+// The forecasts of the previous run are replaced.
+func (t *forecastTask) ComputeForecasts(ctx context.Context) (*ForecastReport, error) {
+	now := time.Now()
+	period := time.Duration(t.periodDays) * 24 * time.Hour
+	since := now.Add(-time.Duration(t.periods) * period)
+	report := &ForecastReport{DryRun: IsDryRun(ctx), Since: since.Format(time.RFC3339), Method: t.method}
+
+	products, err := t.productForecastRepo.GetProductsToForecast(ctx)
+	if err != nil {
+		return nil, err
+	}
+	movements, err := t.stockMovementRepo.GetDemand(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	onOrder, err := t.purchaseOrderRepo.SumOnOrder(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	demand := make(map[uuid.UUID][]float64, len(products))
+	for _, movement := range movements {
+		index := int(movement.CreatedAt.Sub(since) / period)
+		if index < 0 || index >= t.periods {
+			continue
+		}
+		if demand[movement.ProductID] == nil {
+			demand[movement.ProductID] = make([]float64, t.periods)
+		}
+		demand[movement.ProductID][index] -= float64(movement.Delta)
+	}
+
+	forecasts := make([]model.ProductForecast, 0, len(products))
+	for i := range products {
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+
+		product := &products[i]
+		history := demand[product.ID]
+		if history == nil {
+			history = make([]float64, t.periods)
+		}
+		// The periods before the product was added are no demand of it
+		if first := int(product.DateAdded.Sub(since) / period); first > 0 {
+			if first >= t.periods {
+				first = t.periods - 1
+			}
+			history = history[first:]
+		}
+
+		forecast := t.forecast(product, history, onOrder[product.ID], now)
+		forecasts = append(forecasts, forecast)
+		if forecast.ReorderQuantity > 0 {
+			report.Reorder = append(report.Reorder, ForecastResult{
+				Reference:       forecast.Reference,
+				DailyDemand:     forecast.DailyDemand,
+				Quantity:        forecast.Quantity,
+				OnOrder:         forecast.OnOrder,
+				ReorderPoint:    forecast.ReorderPoint,
+				ReorderQuantity: forecast.ReorderQuantity,
+			})
+		}
+	}
+	report.Products = len(forecasts)
+
+	if !report.DryRun {
+		if err := t.productForecastRepo.Replace(ctx, forecasts); err != nil {
+			return nil, err
+		}
+	}
+
+	t.logger.Info("ComputeForecasts",
+		zap.Bool("dry_run", report.DryRun),
+		zap.String("method", report.Method),
+		zap.Int("products", report.Products),
+		zap.Int("reorder", len(report.Reorder)),
+	)
+
+	return report, nil
+}
+
+// forecast computes the demand of product from its demand per period and the reorder it suggests
+func (t *forecastTask) forecast(product *model.Product, history []float64, onOrder int, now time.Time) model.ProductForecast {
+	days := float64(t.periodDays)
+	forecast := model.ProductForecast{
+		ProductID:            product.ID,
+		Reference:            product.Reference,
+		Name:                 product.Name,
+		SupplierID:           product.SupplierID,
+		SupplierName:         product.Supplier.Name,
+		Method:               t.method,
+		PeriodDays:           t.periodDays,
+		Periods:              len(history),
+		MovingAverage:        roundDemand(helper.MovingAverage(history, t.window) / days),
+		ExponentialSmoothing: roundDemand(helper.ExponentialSmoothing(history, t.alpha) / days),
+		// The deviation of a period spreads over its days as the square root
+		DemandStdDev: roundDemand(helper.StandardDeviation(history) / math.Sqrt(days)),
+		LeadTimeDays: product.Supplier.LeadTimeDays,
+		Quantity:     product.Quantity,
+		OnOrder:      onOrder,
+		ComputedAt:   now,
+	}
+	forecast.DailyDemand = forecast.ExponentialSmoothing
+	if t.method == model.ForecastMovingAverage {
+		forecast.DailyDemand = forecast.MovingAverage
+	}
+
+	leadTime := float64(forecast.LeadTimeDays)
+	forecast.SafetyStock = int(math.Ceil(t.safetyFactor * forecast.DemandStdDev * math.Sqrt(leadTime)))
+	forecast.ReorderPoint = int(math.Ceil(forecast.DailyDemand*leadTime)) + forecast.SafetyStock
+
+	// The reorder brings the stock back to the demand until the next one can be delivered
+	position := forecast.Quantity + forecast.OnOrder
+	if forecast.DailyDemand > 0 && position <= forecast.ReorderPoint {
+		target := int(math.Ceil(forecast.DailyDemand*(leadTime+float64(t.reviewDays)))) + forecast.SafetyStock
+		if target > position {
+			forecast.ReorderQuantity = target - position
+		}
+	}
+	return forecast
+}
+
+// roundDemand keeps the 4 decimals that are stored
+func roundDemand(value float64) float64 {
+	return math.Round(value*10000) / 10000
+}
@@ -0,0 +1,126 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/quydmfl/niveau-test/internal/helper"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type LotTask interface {
+	TaskProvider
+	FlagExpiringLots(ctx context.Context) (*LotExpiryReport, error)
+}
+
+// LotExpiryReport lists the lots flagged as nearing expiry, or that would have been in dry run
+type LotExpiryReport struct {
+	DryRun  bool              `json:"dry_run"`
+	Before  string            `json:"before"`
+	Flagged []LotExpiryResult `json:"flagged"`
+	Errors  []string          `json:"errors,omitempty"`
+}
+
+type LotExpiryResult struct {
+	Reference string `json:"reference"`
+	LotNumber string `json:"lot_number"`
+	Quantity  int    `json:"quantity"`
+	ExpiresAt string `json:"expires_at"`
+	DaysLeft  int    `json:"days_left"`
+}
+
+func NewLotTask(
+	task *Task,
+	conf *viper.Viper,
+	productLotRepo repository.ProductLotRepository,
+	outboxRepo repository.OutboxRepository,
+) LotTask {
+	t := &lotTask{
+		Task:           task,
+		productLotRepo: productLotRepo,
+		outboxRepo:     outboxRepo,
+		warning:        conf.GetDuration("task.lot_expiry.warning"),
+	}
+
+	// Default values
+	if t.warning <= 0 {
+		t.warning = 14 * 24 * time.Hour
+	}
+
+	return t
+}
+
+type lotTask struct {
+	*Task
+	productLotRepo repository.ProductLotRepository
+	outboxRepo     repository.OutboxRepository
+	warning        time.Duration
+}
+
+func (t *lotTask) Definition(conf *viper.Viper) Definition {
+	return newDefinition(conf, "lot_expiry", func(ctx context.Context) (interface{}, error) {
+		return t.FlagExpiringLots(ctx)
+	})
+}
+
+// FlagExpiringLots flags the lots with stock left that expire within the warning window, expired ones included.
+// A lot is flagged once, with a ProductLotExpiringEvent so that the stock can be sold off or written off in time.
+func (t *lotTask) FlagExpiringLots(ctx context.Context) (*LotExpiryReport, error) {
+	now := time.Now()
+	before := helper.Date(now).Add(t.warning)
+	report := &LotExpiryReport{DryRun: IsDryRun(ctx), Before: before.Format("2006-01-02")}
+
+	lots, err := t.productLotRepo.GetToFlag(ctx, before)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range lots {
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+
+		lot := &lots[i]
+		if !report.DryRun {
+			if err := t.flag(ctx, lot, now); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("lot %s of %s: %v", lot.LotNumber, lot.Product.Reference, err))
+				continue
+			}
+		}
+		report.Flagged = append(report.Flagged, LotExpiryResult{
+			Reference: lot.Product.Reference,
+			LotNumber: lot.LotNumber,
+			Quantity:  lot.Quantity,
+			ExpiresAt: lot.ExpiresAt.Format("2006-01-02"),
+			DaysLeft:  lot.DaysLeft(now),
+		})
+	}
+
+	t.logger.Info("FlagExpiringLots",
+		zap.Bool("dry_run", report.DryRun),
+		zap.String("before", report.Before),
+		zap.Int("flagged", len(report.Flagged)),
+		zap.Int("errors", len(report.Errors)),
+	)
+
+	return report, nil
+}
+
+// flag marks the lot and stores its event in the same Transaction
+func (t *lotTask) flag(ctx context.Context, lot *model.ProductLot, now time.Time) error {
+	return t.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := t.productLotRepo.MarkFlagged(ctx, lot, now); err != nil {
+			return err
+		}
+
+		event, err := model.NewProductLotEvent(model.ProductLotExpiringEvent, lot.Product, lot)
+		if err != nil {
+			return err
+		}
+		return t.outboxRepo.Create(ctx, event)
+	})
+}
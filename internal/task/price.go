@@ -0,0 +1,144 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/audit"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type PriceTask interface {
+	TaskProvider
+	ActivatePrices(ctx context.Context) (*PriceActivationReport, error)
+}
+
+// PriceActivationReport lists the products whose price was switched to the scheduled one
+type PriceActivationReport struct {
+	DryRun    bool                    `json:"dry_run"`
+	Activated []PriceActivationResult `json:"activated"`
+	Errors    []string                `json:"errors,omitempty"`
+}
+
+type PriceActivationResult struct {
+	Reference string  `json:"reference"`
+	OldPrice  float64 `json:"old_price"`
+	NewPrice  float64 `json:"new_price"`
+}
+
+func NewPriceTask(
+	task *Task,
+	productRepo repository.ProductRepository,
+	productPriceRepo repository.ProductPriceRepository,
+	productVersionRepo repository.ProductVersionRepository,
+	outboxRepo repository.OutboxRepository,
+) PriceTask {
+	return &priceTask{
+		Task:               task,
+		productRepo:        productRepo,
+		productPriceRepo:   productPriceRepo,
+		productVersionRepo: productVersionRepo,
+		outboxRepo:         outboxRepo,
+	}
+}
+
+type priceTask struct {
+	*Task
+	productRepo        repository.ProductRepository
+	productPriceRepo   repository.ProductPriceRepository
+	productVersionRepo repository.ProductVersionRepository
+	outboxRepo         repository.OutboxRepository
+}
+
+func (t *priceTask) Definition(conf *viper.Viper) Definition {
+	return newDefinition(conf, "price_activation", func(ctx context.Context) (interface{}, error) {
+		return t.ActivatePrices(ctx)
+	})
+}
+
+func (t *priceTask) ActivatePrices(ctx context.Context) (*PriceActivationReport, error) {
+	now := time.Now()
+	report := &PriceActivationReport{DryRun: IsDryRun(ctx)}
+
+	due, err := t.productPriceRepo.GetDueActivations(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, price := range due {
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+
+		result, err := t.activate(ctx, price, now, report.DryRun)
+		if err != nil {
+			report.Errors = append(report.Errors, err.Error())
+			continue
+		}
+		if result != nil {
+			report.Activated = append(report.Activated, *result)
+		}
+	}
+
+	t.logger.Info("ActivatePrices",
+		zap.Bool("dry_run", report.DryRun),
+		zap.Int("activated", len(report.Activated)),
+		zap.Int("errors", len(report.Errors)),
+	)
+
+	return report, nil
+}
+
+// activate switches the product to price in its own Transaction, like a product update
+func (t *priceTask) activate(ctx context.Context, price model.ProductPrice, now time.Time, dryRun bool) (*PriceActivationResult, error) {
+	var result *PriceActivationResult
+
+	err := t.tm.Transaction(ctx, func(ctx context.Context) error {
+		product, err := t.productRepo.GetProductByIdForUpdate(ctx, price.ProductID)
+		if err != nil {
+			return err
+		}
+
+		// The schedule may have changed since the due prices were listed
+		current, err := t.productPriceRepo.GetAt(ctx, product.ID, now)
+		if err != nil {
+			if errors.Is(err, v1.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+		if current.Price == product.Price {
+			return nil
+		}
+
+		result = &PriceActivationResult{Reference: product.Reference, OldPrice: product.Price, NewPrice: current.Price}
+		if dryRun {
+			return nil
+		}
+
+		before := *product
+		product.Price = current.Price
+		if err := t.productRepo.UpdatePrice(ctx, product); err != nil {
+			return err
+		}
+		if _, err := t.productVersionRepo.Append(ctx, &before, product, audit.ActorFromContext(ctx).UserID); err != nil {
+			return err
+		}
+
+		event, err := model.NewProductEvent(model.ProductUpdatedEvent, product)
+		if err != nil {
+			return err
+		}
+		return t.outboxRepo.Create(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,345 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/quydmfl/niveau-test/internal/audit"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/quydmfl/niveau-test/pkg/lock"
+	"github.com/quydmfl/niveau-test/pkg/log"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var ErrTaskNotFound = errors.New("task not found")
+
+// OverlapPolicy decides what happens when a run starts while another run of the same task holds the lock
+type OverlapPolicy string
+
+const (
+	// OverlapSkip records the new run as skipped
+	OverlapSkip OverlapPolicy = "skip"
+	// OverlapWait waits for the lock until the task timeout
+	OverlapWait OverlapPolicy = "wait"
+)
+
+// Func is the body of a task, result is stored as JSON in the run history
+type Func func(ctx context.Context) (result interface{}, err error)
+
+const ctxDryRunKey = "TaskDryRun"
+
+// IsDryRun reports whether the task must only report the changes it would make
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(ctxDryRunKey).(bool)
+	return dryRun
+}
+
+type Definition struct {
+	Name string
+	// Cron expression with seconds, e.g. "0 */5 * * * *"
+	Cron    string
+	Timeout time.Duration
+	Overlap OverlapPolicy
+	// DryRun makes the scheduled runs report only, manual runs choose on trigger
+	DryRun bool
+	Run    Func
+}
+
+type Registry struct {
+	logger      *log.Logger
+	locker      lock.Locker
+	taskRunRepo repository.TaskRunRepository
+	host        string
+
+	definitions map[string]Definition
+	scheduler   *gocron.Scheduler
+
+	// runs are detached from the caller ctx, they are cancelled on Stop
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// TaskProvider is a task of the Registry, it supplies its Definition with the schedule read from conf
+type TaskProvider interface {
+	Definition(conf *viper.Viper) Definition
+}
+
+// NewTaskProviders lists the tasks of the Registry
+func NewTaskProviders(
+	userTask UserTask,
+	documentTask DocumentTask,
+	priceTask PriceTask,
+	trashTask TrashTask,
+	lotTask LotTask,
+	forecastTask ForecastTask,
+) []TaskProvider {
+	return []TaskProvider{userTask, documentTask, priceTask, trashTask, lotTask, forecastTask}
+}
+
+func NewRegistry(
+	logger *log.Logger,
+	conf *viper.Viper,
+	locker lock.Locker,
+	taskRunRepo repository.TaskRunRepository,
+	providers []TaskProvider,
+) *Registry {
+	host, _ := os.Hostname()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	r := &Registry{
+		logger:      logger,
+		locker:      locker,
+		taskRunRepo: taskRunRepo,
+		host:        host,
+		definitions: make(map[string]Definition),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+
+	for _, provider := range providers {
+		r.Register(provider.Definition(conf))
+	}
+
+	return r
+}
+
+// newDefinition reads the schedule of name from the config
+func newDefinition(conf *viper.Viper, name string, run Func) Definition {
+	def := Definition{
+		Name:    name,
+		Cron:    conf.GetString("task.schedules." + name + ".cron"),
+		Timeout: conf.GetDuration("task.schedules." + name + ".timeout"),
+		Overlap: OverlapPolicy(conf.GetString("task.schedules." + name + ".overlap")),
+		DryRun:  conf.GetBool("task.schedules." + name + ".dry_run"),
+		Run:     run,
+	}
+
+	// Default values
+	if def.Timeout <= 0 {
+		def.Timeout = 10 * time.Minute
+	}
+	if def.Overlap != OverlapWait {
+		def.Overlap = OverlapSkip
+	}
+
+	return def
+}
+
+func (r *Registry) Register(def Definition) {
+	r.definitions[def.Name] = def
+}
+
+// Definitions return the registered tasks sorted by name
+func (r *Registry) Definitions() []Definition {
+	defs := make([]Definition, 0, len(r.definitions))
+	for _, def := range r.definitions {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
+
+// Start schedules every task with a cron expression and blocks until Stop
+func (r *Registry) Start() error {
+	gocron.SetPanicHandler(func(jobName string, recoverData interface{}) {
+		r.logger.Error("TaskRegistry Panic", zap.String("job", jobName), zap.Any("recover", recoverData))
+	})
+
+	r.scheduler = gocron.NewScheduler(time.UTC)
+	for _, def := range r.Definitions() {
+		if def.Cron == "" {
+			continue
+		}
+
+		name, dryRun := def.Name, def.DryRun
+		_, err := r.scheduler.CronWithSeconds(def.Cron).Tag(name).Do(func() {
+			// The ticks are on whole seconds and a replica fires just after one on its own clock,
+			// truncating gives every replica the same ScheduledAt however late it fires within the second
+			tick := time.Now().UTC().Truncate(time.Second)
+			if _, err := r.Run(r.ctx, name, model.TaskTriggerSchedule, "", &tick, dryRun); err != nil {
+				r.logger.Error("TaskRegistry run error", zap.String("task", name), zap.Error(err))
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("schedule task %s: %w", name, err)
+		}
+		r.logger.Info("TaskRegistry scheduled", zap.String("task", name), zap.String("cron", def.Cron))
+	}
+
+	r.scheduler.StartBlocking()
+	return nil
+}
+
+// Stop cancels the running tasks and waits for them
+func (r *Registry) Stop() {
+	if r.scheduler != nil {
+		r.scheduler.Stop()
+	}
+	r.cancel()
+	r.wg.Wait()
+}
+
+// Trigger starts a manual run in background and return it
+func (r *Registry) Trigger(ctx context.Context, name string, triggeredBy string, dryRun bool) (*model.TaskRun, error) {
+	def, ok := r.definitions[name]
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+
+	run := r.newRun(def, model.TaskTriggerManual, triggeredBy, nil, dryRun)
+	if _, err := r.taskRunRepo.Claim(ctx, run); err != nil {
+		return nil, err
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.execute(r.ctx, def, *run)
+	}()
+
+	return run, nil
+}
+
+// Run executes name synchronously and return its run, nil if another replica claimed the scheduled tick
+func (r *Registry) Run(ctx context.Context, name string, trigger string, triggeredBy string, scheduledAt *time.Time, dryRun bool) (*model.TaskRun, error) {
+	def, ok := r.definitions[name]
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+
+	run := r.newRun(def, trigger, triggeredBy, scheduledAt, dryRun)
+	claimed, err := r.taskRunRepo.Claim(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, nil
+	}
+
+	r.wg.Add(1)
+	defer r.wg.Done()
+	result := r.execute(ctx, def, *run)
+	return &result, nil
+}
+
+func (r *Registry) newRun(def Definition, trigger string, triggeredBy string, scheduledAt *time.Time, dryRun bool) *model.TaskRun {
+	return &model.TaskRun{
+		TaskName:    def.Name,
+		ScheduledAt: scheduledAt,
+		Trigger:     trigger,
+		TriggeredBy: triggeredBy,
+		DryRun:      dryRun,
+		Status:      model.TaskRunPending,
+		Host:        r.host,
+		CreatedAt:   time.Now(),
+	}
+}
+
+func (r *Registry) execute(ctx context.Context, def Definition, run model.TaskRun) model.TaskRun {
+	logger := r.logger.With(zap.String("task", def.Name), zap.Uint64("run", run.ID))
+
+	ctx, cancel := context.WithTimeout(ctx, def.Timeout)
+	defer cancel()
+
+	l, err := r.acquire(ctx, def)
+	if err != nil || l == nil {
+		run.Status = model.TaskRunSkipped
+		run.Error = "another run of the task is in progress"
+		if err != nil {
+			run.Error = err.Error()
+		}
+		r.finish(logger, &run)
+		return run
+	}
+	defer func() {
+		if err := l.Unlock(ctx); err != nil {
+			logger.Error("TaskRegistry unlock error", zap.Error(err))
+		}
+	}()
+
+	now := time.Now()
+	run.Status = model.TaskRunRunning
+	run.StartedAt = &now
+	if err := r.taskRunRepo.Update(ctx, &run); err != nil {
+		logger.Error("TaskRegistry update run error", zap.Error(err))
+	}
+
+	// Writes of the task are audited under the user who triggered it, or the task itself
+	actor := audit.Actor{UserID: run.TriggeredBy, TraceID: fmt.Sprintf("task_run:%d", run.ID)}
+	if actor.UserID == "" {
+		actor.UserID = "task:" + def.Name
+	}
+	taskCtx := audit.WithActor(context.WithValue(ctx, ctxDryRunKey, run.DryRun), actor)
+
+	result, err := r.call(taskCtx, def)
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		run.Status = model.TaskRunTimeout
+		run.Error = fmt.Sprintf("task exceeded its timeout of %s", def.Timeout)
+	case err != nil:
+		run.Status = model.TaskRunFailed
+		run.Error = err.Error()
+	default:
+		run.Status = model.TaskRunSuccess
+	}
+	if result != nil {
+		if data, err := json.Marshal(result); err == nil {
+			run.Result = string(data)
+		}
+	}
+
+	r.finish(logger, &run)
+	return run
+}
+
+// acquire takes the task lock according to the overlap policy, a nil lock means the run must be skipped
+func (r *Registry) acquire(ctx context.Context, def Definition) (lock.Lock, error) {
+	// The lock must outlive the run on lockers without crash detection
+	ttl := def.Timeout + time.Minute
+
+	for {
+		l, acquired, err := r.locker.TryLock(ctx, "task:"+def.Name, ttl)
+		if err != nil || acquired || def.Overlap != OverlapWait {
+			return l, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// call runs the task body, a panic fails the run instead of the process
+func (r *Registry) call(ctx context.Context, def Definition) (result interface{}, err error) {
+	defer func() {
+		if recoverData := recover(); recoverData != nil {
+			err = fmt.Errorf("task panic: %v", recoverData)
+		}
+	}()
+	return def.Run(ctx)
+}
+
+func (r *Registry) finish(logger *zap.Logger, run *model.TaskRun) {
+	now := time.Now()
+	run.FinishedAt = &now
+
+	// The run ctx may be expired, the history must still be written
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.taskRunRepo.Update(ctx, run); err != nil {
+		logger.Error("TaskRegistry update run error", zap.Error(err))
+	}
+	logger.Info("TaskRegistry run finished", zap.String("status", run.Status), zap.String("error", run.Error))
+}
@@ -0,0 +1,196 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type TrashTask interface {
+	TaskProvider
+	PurgeTrash(ctx context.Context) (*TrashPurgeReport, error)
+}
+
+// TrashPurgeReport lists what a purge run deleted for good, or would have in dry run
+type TrashPurgeReport struct {
+	DryRun     bool     `json:"dry_run"`
+	Products   []string `json:"products"`
+	Suppliers  []string `json:"suppliers"`
+	Categories []string `json:"categories"`
+	// SkippedCategories still have products or subcategories, live or in the trash
+	SkippedCategories []string `json:"skipped_categories"`
+	Errors            []string `json:"errors,omitempty"`
+}
+
+func NewTrashTask(
+	task *Task,
+	conf *viper.Viper,
+	productRepo repository.ProductRepository,
+	categoryRepo repository.CategoryRepository,
+	supplierRepo repository.SupplierRepository,
+	productImageRepo repository.ProductImageRepository,
+) TrashTask {
+	t := &trashTask{
+		Task:             task,
+		productRepo:      productRepo,
+		categoryRepo:     categoryRepo,
+		supplierRepo:     supplierRepo,
+		productImageRepo: productImageRepo,
+		retention:        conf.GetDuration("task.trash_purge.retention"),
+	}
+
+	// Default values
+	if t.retention <= 0 {
+		t.retention = 30 * 24 * time.Hour
+	}
+
+	return t
+}
+
+type trashTask struct {
+	*Task
+	productRepo      repository.ProductRepository
+	categoryRepo     repository.CategoryRepository
+	supplierRepo     repository.SupplierRepository
+	productImageRepo repository.ProductImageRepository
+	retention        time.Duration
+}
+
+func (t *trashTask) Definition(conf *viper.Viper) Definition {
+	return newDefinition(conf, "trash_purge", func(ctx context.Context) (interface{}, error) {
+		return t.PurgeTrash(ctx)
+	})
+}
+
+// PurgeTrash deletes for good what has been in the trash longer than the retention.
+// Products go first so that the categories they emptied can be purged in the same run.
+func (t *trashTask) PurgeTrash(ctx context.Context) (*TrashPurgeReport, error) {
+	before := time.Now().Add(-t.retention)
+	report := &TrashPurgeReport{DryRun: IsDryRun(ctx)}
+
+	products, err := t.productRepo.GetDeletedBefore(ctx, before)
+	if err != nil {
+		return nil, err
+	}
+	for i := range products {
+		if !report.DryRun {
+			if err := t.purgeProduct(ctx, &products[i]); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("product %s: %v", products[i].Reference, err))
+				continue
+			}
+		}
+		report.Products = append(report.Products, products[i].Reference)
+	}
+
+	suppliers, err := t.supplierRepo.GetDeletedBefore(ctx, before)
+	if err != nil {
+		return nil, err
+	}
+	for i := range suppliers {
+		if !report.DryRun {
+			if err := t.supplierRepo.Purge(ctx, &suppliers[i]); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("supplier %s: %v", suppliers[i].ID, err))
+				continue
+			}
+		}
+		report.Suppliers = append(report.Suppliers, suppliers[i].ID.String())
+	}
+
+	categories, err := t.categoryRepo.GetDeletedBefore(ctx, before)
+	if err != nil {
+		return nil, err
+	}
+	for i := range categories {
+		count, err := t.productRepo.CountByCategory(ctx, categories[i].ID, true)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("category %s: %v", categories[i].ID, err))
+			continue
+		}
+		// In dry run the products reported above are still there
+		if report.DryRun {
+			for _, product := range products {
+				if product.CategoryID == categories[i].ID {
+					count--
+				}
+			}
+		}
+		if count > 0 {
+			report.SkippedCategories = append(report.SkippedCategories, categories[i].ID.String())
+			continue
+		}
+
+		// Subcategories are deleted before their parent, those purged are earlier in this run
+		children, err := t.categoryRepo.CountChildren(ctx, categories[i].ID, true)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("category %s: %v", categories[i].ID, err))
+			continue
+		}
+		if report.DryRun {
+			for j := 0; j < i; j++ {
+				if categories[j].ParentID != nil && *categories[j].ParentID == categories[i].ID && purgedCategory(report, categories[j].ID.String()) {
+					children--
+				}
+			}
+		}
+		if children > 0 {
+			report.SkippedCategories = append(report.SkippedCategories, categories[i].ID.String())
+			continue
+		}
+
+		if !report.DryRun {
+			if err := t.categoryRepo.Purge(ctx, &categories[i]); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("category %s: %v", categories[i].ID, err))
+				continue
+			}
+		}
+		report.Categories = append(report.Categories, categories[i].ID.String())
+	}
+
+	t.logger.Info("PurgeTrash",
+		zap.Bool("dry_run", report.DryRun),
+		zap.Int("products", len(report.Products)),
+		zap.Int("suppliers", len(report.Suppliers)),
+		zap.Int("categories", len(report.Categories)),
+		zap.Int("skipped_categories", len(report.SkippedCategories)),
+		zap.Int("errors", len(report.Errors)),
+	)
+
+	return report, nil
+}
+
+// purgeProduct deletes the product for good, then the files of its images and of those of its variants
+func (t *trashTask) purgeProduct(ctx context.Context, product *model.Product) error {
+	images, err := t.productImageRepo.GetByProductWithVariants(ctx, product.ID)
+	if err != nil {
+		return err
+	}
+	if err := t.productRepo.Purge(ctx, product); err != nil {
+		return err
+	}
+
+	for i := range images {
+		for _, path := range images[i].Files() {
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				t.logger.Warn("remove product image", zap.String("path", path), zap.Error(err))
+			}
+		}
+	}
+	return nil
+}
+
+func purgedCategory(report *TrashPurgeReport, id string) bool {
+	for _, purged := range report.Categories {
+		if purged == id {
+			return true
+		}
+	}
+	return false
+}
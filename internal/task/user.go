@@ -4,9 +4,11 @@ import (
 	"context"
 
 	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/spf13/viper"
 )
 
 type UserTask interface {
+	TaskProvider
 	CheckUser(ctx context.Context) error
 }
 
@@ -25,6 +27,12 @@ type userTask struct {
 	*Task
 }
 
+func (t userTask) Definition(conf *viper.Viper) Definition {
+	return newDefinition(conf, "check_user", func(ctx context.Context) (interface{}, error) {
+		return nil, t.CheckUser(ctx)
+	})
+}
+
 func (t userTask) CheckUser(ctx context.Context) error {
 	// do something
 	t.logger.Info("CheckUser")
@@ -0,0 +1,70 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// Message is the unit exchanged with a broker.
+// Key is used by brokers that partition topics (e.g. kafka) so that messages
+// sharing a key are delivered in order.
+type Message struct {
+	ID      string
+	Topic   string
+	Key     string
+	Payload []byte
+	Headers map[string]string
+
+	// position of the message in a partitioned log, set by subscriptions
+	partition int
+	offset    int64
+}
+
+type Broker interface {
+	Publish(ctx context.Context, msg *Message) error
+	// Subscribe joins the consumer group on topic, each message is delivered to one member of the group
+	Subscribe(ctx context.Context, topic string, group string) (Subscription, error)
+	Close() error
+}
+
+// Subscription delivers messages at least once: a message that is fetched but never committed is delivered again.
+type Subscription interface {
+	// Fetch blocks until a message is available or ctx is done
+	Fetch(ctx context.Context) (*Message, error)
+	// Commit acknowledges msg. Messages may be committed in any order.
+	Commit(ctx context.Context, msg *Message) error
+	Close() error
+}
+
+// NewBroker build the broker configured by data.broker.driver.
+// The memory broker only delivers inside the process, it is refused outside the local and test envs.
+func NewBroker(conf *viper.Viper) (Broker, func()) {
+	var (
+		b   Broker
+		err error
+	)
+
+	driver := conf.GetString("data.broker.driver")
+	switch driver {
+	case "kafka":
+		b, err = NewKafkaBroker(conf.GetStringSlice("data.broker.kafka.brokers"))
+	case "nats":
+		b, err = NewNatsBroker(conf.GetString("data.broker.nats.url"))
+	case "memory", "":
+		if env := conf.GetString("env"); env != "local" && env != "test" {
+			panic(fmt.Sprintf("the memory broker loses the events outside the process, configure kafka or nats for env %q", env))
+		}
+		b = NewMemoryBroker()
+	default:
+		panic(fmt.Sprintf("unknown broker driver: %s", driver))
+	}
+	if err != nil {
+		panic(fmt.Sprintf("broker error: %s", err.Error()))
+	}
+
+	return b, func() {
+		_ = b.Close()
+	}
+}
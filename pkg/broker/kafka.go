@@ -0,0 +1,139 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type KafkaBroker struct {
+	brokers []string
+	writer  *kafka.Writer
+}
+
+func NewKafkaBroker(brokers []string) (*KafkaBroker, error) {
+	if len(brokers) == 0 {
+		return nil, errors.New("kafka brokers is empty")
+	}
+
+	return &KafkaBroker{
+		brokers: brokers,
+		writer: &kafka.Writer{
+			Addr: kafka.TCP(brokers...),
+			// Messages with the same key always land on the same partition, which keeps them ordered
+			Balancer:               &kafka.Hash{},
+			RequiredAcks:           kafka.RequireAll,
+			AllowAutoTopicCreation: true,
+		},
+	}, nil
+}
+
+func (b *KafkaBroker) Publish(ctx context.Context, msg *Message) error {
+	headers := make([]kafka.Header, 0, len(msg.Headers)+1)
+	headers = append(headers, kafka.Header{Key: "message_id", Value: []byte(msg.ID)})
+	for k, v := range msg.Headers {
+		headers = append(headers, kafka.Header{Key: k, Value: []byte(v)})
+	}
+
+	return b.writer.WriteMessages(ctx, kafka.Message{
+		Topic:   msg.Topic,
+		Key:     []byte(msg.Key),
+		Value:   msg.Payload,
+		Headers: headers,
+	})
+}
+
+func (b *KafkaBroker) Close() error {
+	return b.writer.Close()
+}
+
+func (b *KafkaBroker) Subscribe(ctx context.Context, topic string, group string) (Subscription, error) {
+	return &kafkaSubscription{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers:     b.brokers,
+			GroupID:     group,
+			Topic:       topic,
+			StartOffset: kafka.FirstOffset,
+		}),
+		partitions: make(map[int]*kafkaPartition),
+	}, nil
+}
+
+type kafkaSubscription struct {
+	reader *kafka.Reader
+
+	mu         sync.Mutex
+	partitions map[int]*kafkaPartition
+}
+
+// kafkaPartition tracks the fetched offsets of a partition.
+// Kafka commits are positional, so an offset is only committed once every offset fetched before it is done.
+type kafkaPartition struct {
+	fetched []int64
+	done    map[int64]bool
+}
+
+func (s *kafkaSubscription) Fetch(ctx context.Context) (*Message, error) {
+	m, err := s.reader.FetchMessage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &Message{
+		ID:        fmt.Sprintf("%s-%d-%d", m.Topic, m.Partition, m.Offset),
+		Topic:     m.Topic,
+		Key:       string(m.Key),
+		Payload:   m.Value,
+		Headers:   make(map[string]string, len(m.Headers)),
+		partition: m.Partition,
+		offset:    m.Offset,
+	}
+	for _, h := range m.Headers {
+		if h.Key == "message_id" {
+			msg.ID = string(h.Value)
+			continue
+		}
+		msg.Headers[h.Key] = string(h.Value)
+	}
+
+	s.mu.Lock()
+	p, ok := s.partitions[m.Partition]
+	if !ok {
+		p = &kafkaPartition{done: make(map[int64]bool)}
+		s.partitions[m.Partition] = p
+	}
+	p.fetched = append(p.fetched, m.Offset)
+	s.mu.Unlock()
+
+	return msg, nil
+}
+
+func (s *kafkaSubscription) Commit(ctx context.Context, msg *Message) error {
+	s.mu.Lock()
+	p, ok := s.partitions[msg.partition]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("partition %d was not fetched", msg.partition)
+	}
+
+	p.done[msg.offset] = true
+	commit := int64(-1)
+	for len(p.fetched) > 0 && p.done[p.fetched[0]] {
+		commit = p.fetched[0]
+		delete(p.done, commit)
+		p.fetched = p.fetched[1:]
+	}
+	s.mu.Unlock()
+
+	if commit < 0 {
+		return nil
+	}
+	return s.reader.CommitMessages(ctx, kafka.Message{Topic: msg.Topic, Partition: msg.partition, Offset: commit})
+}
+
+func (s *kafkaSubscription) Close() error {
+	return s.reader.Close()
+}
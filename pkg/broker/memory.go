@@ -0,0 +1,150 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var ErrSubscriptionClosed = errors.New("subscription closed")
+
+// MemoryBroker keeps published messages in memory.
+// It is meant for local development and tests.
+type MemoryBroker struct {
+	mu       sync.RWMutex
+	messages map[string][]*Message
+	groups   map[string]*memoryGroup
+	// closed and replaced on every publish to wake up waiting subscriptions
+	notify chan struct{}
+}
+
+// memoryGroup is the position of a consumer group in a topic
+type memoryGroup struct {
+	next      int64
+	redeliver []int64
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		messages: make(map[string][]*Message),
+		groups:   make(map[string]*memoryGroup),
+		notify:   make(chan struct{}),
+	}
+}
+
+func (b *MemoryBroker) Publish(ctx context.Context, msg *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.messages[msg.Topic] = append(b.messages[msg.Topic], msg)
+	close(b.notify)
+	b.notify = make(chan struct{})
+	return nil
+}
+
+// Messages return a copy of the messages published on topic
+func (b *MemoryBroker) Messages(topic string) []*Message {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	result := make([]*Message, len(b.messages[topic]))
+	copy(result, b.messages[topic])
+	return result
+}
+
+func (b *MemoryBroker) Subscribe(ctx context.Context, topic string, group string) (Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := topic + "/" + group
+	if _, ok := b.groups[key]; !ok {
+		b.groups[key] = &memoryGroup{}
+	}
+
+	return &memorySubscription{
+		broker: b,
+		topic:  topic,
+		group:  b.groups[key],
+		owned:  make(map[int64]bool),
+	}, nil
+}
+
+func (b *MemoryBroker) Close() error {
+	return nil
+}
+
+type memorySubscription struct {
+	broker *MemoryBroker
+	topic  string
+	group  *memoryGroup
+	// offsets fetched by this subscription and not committed yet
+	owned  map[int64]bool
+	closed bool
+}
+
+func (s *memorySubscription) Fetch(ctx context.Context) (*Message, error) {
+	for {
+		s.broker.mu.Lock()
+		if s.closed {
+			s.broker.mu.Unlock()
+			return nil, ErrSubscriptionClosed
+		}
+
+		offset := int64(-1)
+		if len(s.group.redeliver) > 0 {
+			offset, s.group.redeliver = s.group.redeliver[0], s.group.redeliver[1:]
+		} else if s.group.next < int64(len(s.broker.messages[s.topic])) {
+			offset = s.group.next
+			s.group.next++
+		}
+
+		if offset >= 0 {
+			s.owned[offset] = true
+			msg := *s.broker.messages[s.topic][offset]
+			msg.offset = offset
+			s.broker.mu.Unlock()
+			return &msg, nil
+		}
+
+		notify := s.broker.notify
+		s.broker.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-notify:
+		}
+	}
+}
+
+func (s *memorySubscription) Commit(ctx context.Context, msg *Message) error {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	delete(s.owned, msg.offset)
+	return nil
+}
+
+// Close hands the uncommitted messages back to the group
+func (s *memorySubscription) Close() error {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	for offset := range s.owned {
+		s.group.redeliver = append(s.group.redeliver, offset)
+	}
+	s.owned = nil
+
+	// wake up the other members of the group
+	close(s.broker.notify)
+	s.broker.notify = make(chan struct{})
+	return nil
+}
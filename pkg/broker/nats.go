@@ -0,0 +1,138 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+const natsPollInterval = 5 * time.Second
+
+// NatsBroker publishes through JetStream so that every publish is acknowledged by the server.
+// The streams covering the published subjects must exist beforehand.
+type NatsBroker struct {
+	conn *nats.Conn
+	js   nats.JetStreamContext
+}
+
+func NewNatsBroker(url string) (*NatsBroker, error) {
+	conn, err := nats.Connect(url)
+	if err != nil {
+		return nil, err
+	}
+
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &NatsBroker{
+		conn: conn,
+		js:   js,
+	}, nil
+}
+
+func (b *NatsBroker) Publish(ctx context.Context, msg *Message) error {
+	m := nats.NewMsg(msg.Topic)
+	m.Data = msg.Payload
+	m.Header.Set("key", msg.Key)
+	for k, v := range msg.Headers {
+		m.Header.Set(k, v)
+	}
+
+	// MsgId enables the server side de-duplication window
+	_, err := b.js.PublishMsg(m, nats.Context(ctx), nats.MsgId(msg.ID))
+	return err
+}
+
+func (b *NatsBroker) Close() error {
+	return b.conn.Drain()
+}
+
+// Subscribe creates a durable pull consumer named after group
+func (b *NatsBroker) Subscribe(ctx context.Context, topic string, group string) (Subscription, error) {
+	sub, err := b.js.PullSubscribe(topic, group, nats.ManualAck(), nats.Context(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return &natsSubscription{
+		sub:     sub,
+		pending: make(map[*Message]*nats.Msg),
+	}, nil
+}
+
+type natsSubscription struct {
+	sub *nats.Subscription
+
+	mu      sync.Mutex
+	pending map[*Message]*nats.Msg
+}
+
+func (s *natsSubscription) Fetch(ctx context.Context) (*Message, error) {
+	for {
+		// pull requests need a deadline, poll in short windows until ctx is done
+		pollCtx, cancel := context.WithTimeout(ctx, natsPollInterval)
+		msgs, err := s.sub.Fetch(1, nats.Context(pollCtx))
+		cancel()
+		if (errors.Is(err, context.DeadlineExceeded) || errors.Is(err, nats.ErrTimeout)) && ctx.Err() == nil {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(msgs) == 0 {
+			continue
+		}
+
+		m := msgs[0]
+		msg := NewNatsMessage(m)
+
+		s.mu.Lock()
+		s.pending[msg] = m
+		s.mu.Unlock()
+		return msg, nil
+	}
+}
+
+// NewNatsMessage converts a message delivered by JetStream.
+// Its ID is the Nats-Msg-Id set on publish, or the stream and sequence of the message when it was published without one.
+func NewNatsMessage(m *nats.Msg) *Message {
+	msg := &Message{
+		ID:      m.Header.Get(nats.MsgIdHdr),
+		Topic:   m.Subject,
+		Key:     m.Header.Get("key"),
+		Payload: m.Data,
+		Headers: make(map[string]string, len(m.Header)),
+	}
+	if msg.ID == "" {
+		if meta, err := m.Metadata(); err == nil {
+			msg.ID = fmt.Sprintf("%s-%d", meta.Stream, meta.Sequence.Stream)
+		}
+	}
+	for k := range m.Header {
+		msg.Headers[k] = m.Header.Get(k)
+	}
+	return msg
+}
+
+func (s *natsSubscription) Commit(ctx context.Context, msg *Message) error {
+	s.mu.Lock()
+	m, ok := s.pending[msg]
+	delete(s.pending, msg)
+	s.mu.Unlock()
+
+	if !ok {
+		return errors.New("message was not fetched by this subscription")
+	}
+	return m.AckSync(nats.Context(ctx))
+}
+
+func (s *natsSubscription) Close() error {
+	return s.sub.Unsubscribe()
+}
@@ -0,0 +1,79 @@
+package lock
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DatabaseLocker uses session level locks: pg_try_advisory_lock on postgres, GET_LOCK on mysql.
+// The lock is bound to a dedicated connection, it is released by the server if the holder dies.
+type DatabaseLocker struct {
+	db *gorm.DB
+}
+
+func NewDatabaseLocker(db *gorm.DB) Locker {
+	// sqlite is single process, there is nothing to coordinate
+	if db.Dialector.Name() == "sqlite" {
+		return NewLocalLocker()
+	}
+	return &DatabaseLocker{db: db}
+}
+
+func (l *DatabaseLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (Lock, bool, error) {
+	sqlDB, err := l.db.DB()
+	if err != nil {
+		return nil, false, err
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var (
+		acquired bool
+		unlock   string
+		arg      interface{}
+	)
+	switch l.db.Dialector.Name() {
+	case "mysql":
+		// GET_LOCK names are limited to 64 characters
+		arg = key
+		if len(key) > 64 {
+			arg = key[:64]
+		}
+		var result sql.NullInt64
+		err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 0)", arg).Scan(&result)
+		acquired = result.Valid && result.Int64 == 1
+		unlock = "SELECT RELEASE_LOCK(?)"
+	default:
+		arg = hashKey(key)
+		err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", arg).Scan(&acquired)
+		unlock = "SELECT pg_advisory_unlock($1)"
+	}
+	if err != nil || !acquired {
+		_ = conn.Close()
+		return nil, false, err
+	}
+
+	return &databaseLock{conn: conn, unlock: unlock, arg: arg}, true, nil
+}
+
+type databaseLock struct {
+	conn   *sql.Conn
+	unlock string
+	arg    interface{}
+}
+
+func (l *databaseLock) Unlock(ctx context.Context) error {
+	defer l.conn.Close()
+
+	// Unlock even if the holder ctx is already done
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := l.conn.ExecContext(ctx, l.unlock, l.arg)
+	return err
+}
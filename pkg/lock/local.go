@@ -0,0 +1,43 @@
+package lock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LocalLocker only coordinates the goroutines of the current process
+type LocalLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+func NewLocalLocker() Locker {
+	return &LocalLocker{
+		held: make(map[string]bool),
+	}
+}
+
+func (l *LocalLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (Lock, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.held[key] {
+		return nil, false, nil
+	}
+	l.held[key] = true
+	return &localLock{locker: l, key: key}, true, nil
+}
+
+type localLock struct {
+	locker *LocalLocker
+	key    string
+}
+
+func (l *localLock) Unlock(ctx context.Context) error {
+	l.locker.mu.Lock()
+	defer l.locker.mu.Unlock()
+
+	delete(l.locker.held, l.key)
+	return nil
+}
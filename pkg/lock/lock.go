@@ -0,0 +1,24 @@
+package lock
+
+import (
+	"context"
+	"hash/fnv"
+	"time"
+)
+
+type Locker interface {
+	// TryLock acquires key without waiting, acquired is false when another holder owns it.
+	// ttl bounds how long the lock survives a crashed holder on lockers that can not detect it.
+	TryLock(ctx context.Context, key string, ttl time.Duration) (lock Lock, acquired bool, err error)
+}
+
+type Lock interface {
+	Unlock(ctx context.Context) error
+}
+
+// hashKey maps key to the int64 space used by database advisory locks
+func hashKey(key string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,51 @@
+package lock
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// releaseScript deletes the key only if it still holds our token
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+type RedisLocker struct {
+	rdb    *redis.Client
+	prefix string
+}
+
+func NewRedisLocker(rdb *redis.Client) Locker {
+	return &RedisLocker{
+		rdb:    rdb,
+		prefix: "lock:",
+	}
+}
+
+func (l *RedisLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (Lock, bool, error) {
+	token := uuid.NewString()
+	acquired, err := l.rdb.SetNX(ctx, l.prefix+key, token, ttl).Result()
+	if err != nil || !acquired {
+		return nil, false, err
+	}
+
+	return &redisLock{rdb: l.rdb, key: l.prefix + key, token: token}, true, nil
+}
+
+type redisLock struct {
+	rdb   *redis.Client
+	key   string
+	token string
+}
+
+func (l *redisLock) Unlock(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return releaseScript.Run(ctx, l.rdb, []string{l.key}, l.token).Err()
+}
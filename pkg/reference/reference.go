@@ -0,0 +1,205 @@
+package reference
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultPattern is the format of the references generated before the pattern was configurable
+const DefaultPattern = "PROD-{YYYY}-{MM}-{SEQ:4}"
+
+// DefaultCategoryPrefix replaces {CAT} for the categories without a reference prefix
+const DefaultCategoryPrefix = "GEN"
+
+var (
+	tokenRegexp   = regexp.MustCompile(`\{[^}]*\}`)
+	literalRegexp = regexp.MustCompile(`^[A-Z0-9-]*$`)
+	// AnyRegexp is what any stored reference is made of, whatever pattern generated it
+	AnyRegexp = regexp.MustCompile(`^[A-Z0-9-]+$`)
+	// CategoryPrefixRegexp is what a category prefix can be made of, it is part of the references
+	CategoryPrefixRegexp = regexp.MustCompile(`^[A-Z0-9]{1,10}$`)
+)
+
+type segmentKind int
+
+const (
+	literal segmentKind = iota
+	year
+	shortYear
+	month
+	day
+	category
+	sequence
+)
+
+type segment struct {
+	kind  segmentKind
+	text  string
+	width int
+}
+
+// Format is a parsed reference pattern. The pattern is made of uppercase letters, digits and hyphens with the tokens
+// {YYYY}, {YY}, {MM} and {DD} for the date parts, {CAT} for the prefix of the category and {SEQ:n} for the sequence
+// zero-padded to n digits, e.g. "{CAT}-{YY}{MM}-{SEQ:5}". The sequence is required, it grows past n digits.
+type Format struct {
+	pattern        string
+	segments       []segment
+	categoryPrefix string
+	regexp         *regexp.Regexp
+}
+
+// NewFormat reads the pattern of the references from product_reference.pattern, it panics on an invalid pattern
+func NewFormat(conf *viper.Viper) *Format {
+	pattern := conf.GetString("product_reference.pattern")
+	categoryPrefix := conf.GetString("product_reference.category_prefix")
+
+	// Default values
+	if pattern == "" {
+		pattern = DefaultPattern
+	}
+	if categoryPrefix == "" {
+		categoryPrefix = DefaultCategoryPrefix
+	}
+
+	format, err := Parse(pattern)
+	if err != nil {
+		panic(err)
+	}
+	if !CategoryPrefixRegexp.MatchString(categoryPrefix) {
+		panic(fmt.Sprintf("invalid product_reference.category_prefix %q", categoryPrefix))
+	}
+	format.categoryPrefix = categoryPrefix
+	return format
+}
+
+// Parse checks the pattern, see Format
+func Parse(pattern string) (*Format, error) {
+	f := &Format{pattern: pattern, categoryPrefix: DefaultCategoryPrefix}
+	expr := strings.Builder{}
+	expr.WriteString("^")
+
+	sequences := 0
+	rest := pattern
+	for rest != "" {
+		loc := tokenRegexp.FindStringIndex(rest)
+		text := rest
+		if loc != nil {
+			text = rest[:loc[0]]
+		}
+		if text != "" {
+			if !literalRegexp.MatchString(text) {
+				return nil, fmt.Errorf("reference pattern %q: only uppercase letters, digits and hyphens are allowed around the tokens", pattern)
+			}
+			f.segments = append(f.segments, segment{kind: literal, text: text})
+			expr.WriteString(regexp.QuoteMeta(text))
+		}
+		if loc == nil {
+			break
+		}
+
+		token := rest[loc[0]:loc[1]]
+		rest = rest[loc[1]:]
+		switch {
+		case token == "{YYYY}":
+			f.segments = append(f.segments, segment{kind: year})
+			expr.WriteString(`\d{4}`)
+		case token == "{YY}":
+			f.segments = append(f.segments, segment{kind: shortYear})
+			expr.WriteString(`\d{2}`)
+		case token == "{MM}":
+			f.segments = append(f.segments, segment{kind: month})
+			expr.WriteString(`(0[1-9]|1[0-2])`)
+		case token == "{DD}":
+			f.segments = append(f.segments, segment{kind: day})
+			expr.WriteString(`(0[1-9]|[12]\d|3[01])`)
+		case token == "{CAT}":
+			f.segments = append(f.segments, segment{kind: category})
+			expr.WriteString(`[A-Z0-9]{1,10}`)
+		case strings.HasPrefix(token, "{SEQ:"):
+			width, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(token, "{SEQ:"), "}"))
+			if err != nil || width < 1 || width > 12 {
+				return nil, fmt.Errorf("reference pattern %q: the sequence is {SEQ:n} with n from 1 to 12", pattern)
+			}
+			f.segments = append(f.segments, segment{kind: sequence, width: width})
+			expr.WriteString(fmt.Sprintf(`\d{%d,}`, width))
+			sequences++
+		default:
+			return nil, fmt.Errorf("reference pattern %q: unknown token %s", pattern, token)
+		}
+	}
+	if sequences != 1 {
+		return nil, fmt.Errorf("reference pattern %q: it needs one {SEQ:n}", pattern)
+	}
+
+	// Variants may add suffixes to the reference of their parent, e.g. -M
+	expr.WriteString(`(-[A-Z0-9]+)*$`)
+	f.regexp = regexp.MustCompile(expr.String())
+	return f, nil
+}
+
+func (f *Format) Pattern() string {
+	return f.pattern
+}
+
+// HasCategory tells if the references depend on the category of the product
+func (f *Format) HasCategory() bool {
+	for _, s := range f.segments {
+		if s.kind == category {
+			return true
+		}
+	}
+	return false
+}
+
+// Scope is the reference without its sequence number, e.g. "PROD-2024-01-{SEQ}".
+// Each scope has its own sequence, it starts again with a new period or for another category.
+func (f *Format) Scope(t time.Time, categoryPrefix string) string {
+	return f.render(t, categoryPrefix, "{SEQ}")
+}
+
+// Reference is the reference of number n of the scope of t and categoryPrefix
+func (f *Format) Reference(t time.Time, categoryPrefix string, n int64) string {
+	for _, s := range f.segments {
+		if s.kind == sequence {
+			return f.render(t, categoryPrefix, fmt.Sprintf("%0*d", s.width, n))
+		}
+	}
+	return f.render(t, categoryPrefix, "")
+}
+
+// Match tells if reference follows the pattern, a variant may add suffixes to the reference of its parent
+func (f *Format) Match(reference string) bool {
+	return f.regexp.MatchString(reference)
+}
+
+func (f *Format) render(t time.Time, categoryPrefix string, seq string) string {
+	if categoryPrefix == "" {
+		categoryPrefix = f.categoryPrefix
+	}
+
+	b := strings.Builder{}
+	for _, s := range f.segments {
+		switch s.kind {
+		case literal:
+			b.WriteString(s.text)
+		case year:
+			b.WriteString(t.Format("2006"))
+		case shortYear:
+			b.WriteString(t.Format("06"))
+		case month:
+			b.WriteString(t.Format("01"))
+		case day:
+			b.WriteString(t.Format("02"))
+		case category:
+			b.WriteString(categoryPrefix)
+		case sequence:
+			b.WriteString(seq)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,21 @@
+package validators
+
+import (
+	"regexp"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	// Attribute names are used in the filters of the search, name:value
+	attributeNameRegexp   = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+	attributeFilterRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}:.{1,255}$`)
+)
+
+func AttributeNameValidator(fl validator.FieldLevel) bool {
+	return attributeNameRegexp.MatchString(fl.Field().String())
+}
+
+func AttributeFilterValidator(fl validator.FieldLevel) bool {
+	return attributeFilterRegexp.MatchString(fl.Field().String())
+}
@@ -0,0 +1,34 @@
+package validators
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidGTIN checks a GTIN-8, GTIN-12 (UPC-A), GTIN-13 (EAN-13) or GTIN-14 with its GS1 check digit
+func ValidGTIN(code string) bool {
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return false
+	}
+
+	// From the right, leaving out the check digit, the digits are weighted 3, 1, 3, ...
+	sum := 0
+	for i := len(code) - 2; i >= 0; i-- {
+		digit := int(code[i] - '0')
+		if digit < 0 || digit > 9 {
+			return false
+		}
+		if (len(code)-2-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	check := int(code[len(code)-1] - '0')
+	return check >= 0 && check <= 9 && (10-sum%10)%10 == check
+}
+
+func GTINValidator(fl validator.FieldLevel) bool {
+	return ValidGTIN(fl.Field().String())
+}
@@ -1,20 +1,38 @@
 package validators
 
 import (
-	"regexp"
-
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
+	"github.com/quydmfl/niveau-test/pkg/reference"
+	"github.com/spf13/viper"
 )
 
-func ProductReferenceValidator(fl validator.FieldLevel) bool {
-	re := regexp.MustCompile(`^[A-Z0-9-]+$`) // Only uppercase letters, numbers, and hyphens
-	return re.MatchString(fl.Field().String())
+// ProductReferenceValidator checks the references of new products against the configured pattern, see reference.Format
+func ProductReferenceValidator(format *reference.Format) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		return format.Match(fl.Field().String())
+	}
+}
+
+// ProductReferenceLookupValidator checks the references used to find products, a product created before the
+// pattern changed keeps a reference of the previous one
+func ProductReferenceLookupValidator(fl validator.FieldLevel) bool {
+	return reference.AnyRegexp.MatchString(fl.Field().String())
+}
+
+// CategoryPrefixValidator checks the prefix a category gives to the references of its products
+func CategoryPrefixValidator(fl validator.FieldLevel) bool {
+	return reference.CategoryPrefixRegexp.MatchString(fl.Field().String())
 }
 
-func RegisterCustomValidators() {
+func RegisterCustomValidators(conf *viper.Viper) {
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		// Register all custom validators in here //
-		_ = v.RegisterValidation("product_ref", ProductReferenceValidator)
+		_ = v.RegisterValidation("product_ref", ProductReferenceValidator(reference.NewFormat(conf)))
+		_ = v.RegisterValidation("product_ref_lookup", ProductReferenceLookupValidator)
+		_ = v.RegisterValidation("category_prefix", CategoryPrefixValidator)
+		_ = v.RegisterValidation("gtin", GTINValidator)
+		_ = v.RegisterValidation("attribute_name", AttributeNameValidator)
+		_ = v.RegisterValidation("attribute_filter", AttributeFilterValidator)
 	}
 }
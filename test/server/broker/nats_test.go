@@ -0,0 +1,34 @@
+package broker
+
+import (
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/quydmfl/niveau-test/pkg/broker"
+	"github.com/stretchr/testify/assert"
+)
+
+// jetStreamMsg builds a message as delivered by a pull consumer, the reply subject carries its metadata
+func jetStreamMsg(streamSeq string, header nats.Header) *nats.Msg {
+	return &nats.Msg{
+		Subject: "stock.updates",
+		Reply:   "$JS.ACK.STOCK.stock-consumer.1." + streamSeq + ".1.1700000000000000000.0",
+		Header:  header,
+		Data:    []byte(`{"reference":"PROD-202401-001"}`),
+		Sub:     &nats.Subscription{},
+	}
+}
+
+func TestNewNatsMessage_ID(t *testing.T) {
+	msg := broker.NewNatsMessage(jetStreamMsg("7", nats.Header{nats.MsgIdHdr: []string{"event-1"}, "key": []string{"PROD-202401-001"}}))
+	assert.Equal(t, "event-1", msg.ID)
+	assert.Equal(t, "PROD-202401-001", msg.Key)
+	assert.Equal(t, "stock.updates", msg.Topic)
+
+	// Published without Nats-Msg-Id, every message is still told apart by its position in the stream
+	first := broker.NewNatsMessage(jetStreamMsg("8", nil))
+	second := broker.NewNatsMessage(jetStreamMsg("9", nats.Header{}))
+	assert.Equal(t, "STOCK-8", first.ID)
+	assert.Equal(t, "STOCK-9", second.ID)
+	assert.Equal(t, []byte(`{"reference":"PROD-202401-001"}`), first.Payload)
+}
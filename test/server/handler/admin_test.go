@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/middleware"
+	"github.com/quydmfl/niveau-test/internal/model"
+)
+
+func TestAdminAuth(t *testing.T) {
+	r := gin.New()
+	r.Group("/admin/tasks").Use(middleware.StrictAuth(jwt, logger), middleware.AdminAuth(logger)).
+		GET("/", func(ctx *gin.Context) { v1.HandleSuccess(ctx, nil) })
+
+	adminToken, err := jwt.GenToken(userId, model.UserRoleAdmin, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := newHttpExcept(t, r)
+	e.GET("/admin/tasks/").Expect().Status(http.StatusUnauthorized)
+	e.GET("/admin/tasks/").WithHeader("Authorization", "Bearer "+genToken(t)).
+		Expect().
+		Status(http.StatusForbidden)
+	e.GET("/admin/tasks/").WithHeader("Authorization", "Bearer "+adminToken).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		Value("code").IsEqual(0)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/quydmfl/niveau-test/internal/handler"
 	"github.com/quydmfl/niveau-test/internal/middleware"
+	"github.com/quydmfl/niveau-test/internal/model"
 	"github.com/quydmfl/niveau-test/pkg/config"
 	jwt2 "github.com/quydmfl/niveau-test/pkg/jwt"
 	"github.com/quydmfl/niveau-test/pkg/log"
@@ -69,7 +70,7 @@ func performRequest(r http.Handler, method, path string, body *bytes.Buffer) *ht
 }
 
 func genToken(t *testing.T) string {
-	token, err := jwt.GenToken(userId, time.Now().Add(time.Hour*24*90))
+	token, err := jwt.GenToken(userId, model.UserRoleUser, time.Now().Add(time.Hour*24*90))
 	if err != nil {
 		t.Error(err)
 		return token
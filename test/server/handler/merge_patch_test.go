@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindMergePatch(t *testing.T) {
+	var (
+		req    v1.PatchProductRequest
+		fields map[string]bool
+	)
+	router.PATCH("/merge-patch", func(ctx *gin.Context) {
+		req = v1.PatchProductRequest{}
+		var err error
+		if fields, err = handler.BindMergePatch(ctx, &req.UpdateProductRequest); err != nil {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+		ctx.Status(http.StatusOK)
+	})
+
+	// Only the fields present are validated, the required ones that are missing are fine
+	resp := performRequest(router, http.MethodPatch, "/merge-patch", bytes.NewBufferString(`{"price": 145000, "stock_location": "Brookstone"}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, map[string]bool{"price": true, "stock_location": true}, fields)
+	assert.Equal(t, 145000.0, req.Price)
+	assert.Equal(t, "Brookstone", req.StockLocation)
+	assert.Empty(t, req.ProductName)
+
+	for _, body := range []string{
+		`{"price": 0}`,
+		`{"status": "Lost"}`,
+		`{"added_date": "28/01/2024"}`,
+		`{"product_name": null}`,
+		`{"color": "red"}`,
+		`[{"op": "replace", "path": "/price", "value": 1}]`,
+	} {
+		resp := performRequest(router, http.MethodPatch, "/merge-patch", bytes.NewBufferString(body))
+		assert.Equal(t, http.StatusBadRequest, resp.Code, body)
+	}
+}
@@ -0,0 +1,52 @@
+package helper
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/quydmfl/niveau-test/internal/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeBarcode(t *testing.T) {
+	code, err := helper.EncodeBarcode(helper.BarcodeCode128, "PROD-202401-001")
+	assert.NoError(t, err)
+	assert.False(t, helper.IsTwoDimensional(code))
+
+	code, err = helper.EncodeBarcode(helper.BarcodeQR, "PROD-202401-001")
+	assert.NoError(t, err)
+	assert.True(t, helper.IsTwoDimensional(code))
+
+	// EAN-13 takes digits only, with a valid check digit when there are 13
+	_, err = helper.EncodeBarcode(helper.BarcodeEAN13, "PROD-202401-001")
+	assert.Error(t, err)
+	_, err = helper.EncodeBarcode(helper.BarcodeEAN13, "4006381333932")
+	assert.Error(t, err)
+	code, err = helper.EncodeBarcode(helper.BarcodeEAN13, "400638133393")
+	assert.NoError(t, err)
+	assert.Equal(t, "4006381333931", code.Content())
+}
+
+func TestWriteBarcode(t *testing.T) {
+	code, err := helper.EncodeBarcode(helper.BarcodeCode128, "PROD-202401-001")
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, helper.WriteBarcodePNG(&buf, code, 300, 100))
+	img, err := png.Decode(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 300, img.Bounds().Dx())
+	assert.Equal(t, 100, img.Bounds().Dy())
+
+	// The modules don't fit in fewer pixels
+	assert.Error(t, helper.WriteBarcodePNG(&buf, code, code.Bounds().Dx()-1, 100))
+
+	buf.Reset()
+	assert.NoError(t, helper.WriteBarcodeSVG(&buf, code, 300, 100))
+	svg := buf.String()
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="300" height="100"`))
+	assert.True(t, strings.HasSuffix(svg, `</g></svg>`))
+	assert.Contains(t, svg, `<rect x="0" y="0" width="2" height="1"/>`)
+}
@@ -0,0 +1,32 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/quydmfl/niveau-test/internal/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMovingAverage(t *testing.T) {
+	values := []float64{10, 20, 30, 40}
+	assert.Equal(t, 35.0, helper.MovingAverage(values, 2))
+	assert.Equal(t, 25.0, helper.MovingAverage(values, 10))
+	assert.Equal(t, 25.0, helper.MovingAverage(values, 0))
+	assert.Zero(t, helper.MovingAverage(nil, 4))
+}
+
+func TestExponentialSmoothing(t *testing.T) {
+	// 10, then 0.5*20+0.5*10 = 15, then 0.5*40+0.5*15 = 27.5
+	assert.InDelta(t, 27.5, helper.ExponentialSmoothing([]float64{10, 20, 40}, 0.5), 1e-9)
+	assert.Equal(t, 10.0, helper.ExponentialSmoothing([]float64{10}, 0.3))
+	assert.Zero(t, helper.ExponentialSmoothing(nil, 0.3))
+
+	// alpha 1 is the last value
+	assert.Equal(t, 40.0, helper.ExponentialSmoothing([]float64{10, 20, 40}, 1))
+}
+
+func TestStandardDeviation(t *testing.T) {
+	assert.InDelta(t, 2.138, helper.StandardDeviation([]float64{2, 4, 4, 4, 5, 5, 7, 9}), 1e-3)
+	assert.Zero(t, helper.StandardDeviation([]float64{5}))
+	assert.Zero(t, helper.StandardDeviation([]float64{3, 3, 3}))
+}
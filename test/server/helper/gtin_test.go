@@ -0,0 +1,30 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/quydmfl/niveau-test/internal/helper"
+	"github.com/quydmfl/niveau-test/pkg/validators"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidGTIN(t *testing.T) {
+	for _, gtin := range []string{"96385074", "036000291452", "4006381333931", "10614141000415"} {
+		assert.True(t, validators.ValidGTIN(gtin), gtin)
+	}
+	for _, gtin := range []string{"", "4006381333932", "400638133393", "400638133393A", "12345", "PROD-202401-001"} {
+		assert.False(t, validators.ValidGTIN(gtin), gtin)
+	}
+}
+
+func TestGTINForms(t *testing.T) {
+	assert.Equal(t, []string{"036000291452", "0036000291452", "00036000291452"}, helper.GTINForms("036000291452"))
+	assert.Equal(t, []string{"036000291452", "0036000291452", "00036000291452"}, helper.GTINForms("0036000291452"))
+	assert.Equal(t, []string{"10614141000415"}, helper.GTINForms("10614141000415"))
+
+	ean, err := helper.GTINToEAN13("036000291452")
+	assert.NoError(t, err)
+	assert.Equal(t, "0036000291452", ean)
+	_, err = helper.GTINToEAN13("10614141000415")
+	assert.Error(t, err)
+}
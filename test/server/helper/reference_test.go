@@ -0,0 +1,45 @@
+package helper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quydmfl/niveau-test/pkg/reference"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReferenceFormat(t *testing.T) {
+	at := time.Date(2024, time.January, 28, 10, 0, 0, 0, time.UTC)
+
+	format := reference.NewFormat(viper.New())
+	assert.Equal(t, reference.DefaultPattern, format.Pattern())
+	assert.False(t, format.HasCategory())
+	assert.Equal(t, "PROD-2024-01-{SEQ}", format.Scope(at, ""))
+	assert.Equal(t, "PROD-2024-01-0042", format.Reference(at, "", 42))
+	// The sequence grows past its width
+	assert.Equal(t, "PROD-2024-01-12345", format.Reference(at, "", 12345))
+
+	format, err := reference.Parse("{CAT}-{YY}{MM}{DD}-{SEQ:5}")
+	assert.NoError(t, err)
+	assert.True(t, format.HasCategory())
+	assert.Equal(t, "FOOD-240128-{SEQ}", format.Scope(at, "FOOD"))
+	assert.Equal(t, "GEN-240128-00007", format.Reference(at, "", 7))
+
+	for _, ref := range []string{"FOOD-240128-00007", "GEN-241231-123456", "FOOD-240128-00007-M", "FOOD-240128-00007-XL-RED"} {
+		assert.True(t, format.Match(ref), ref)
+	}
+	for _, ref := range []string{"", "FOOD-240128-0007", "FOOD-241328-00007", "food-240128-00007", "FOOD-240128-00007-", "PROD-2024-01-0001"} {
+		assert.False(t, format.Match(ref), ref)
+	}
+	// The lookups accept the references of the previous patterns
+	assert.True(t, reference.AnyRegexp.MatchString("PROD-2024-01-0001"))
+	for _, ref := range []string{"", "food-240128-00007", "PROD_2024"} {
+		assert.False(t, reference.AnyRegexp.MatchString(ref), ref)
+	}
+
+	for _, pattern := range []string{"PROD-{YYYY}", "{SEQ:4}-{SEQ:4}", "prod-{SEQ:4}", "PROD-{SEQ:0}", "PROD-{HH}-{SEQ:4}", "PROD_{SEQ:4}"} {
+		_, err := reference.Parse(pattern)
+		assert.Error(t, err, pattern)
+	}
+}
@@ -0,0 +1,153 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/quydmfl/niveau-test/internal/job"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/quydmfl/niveau-test/pkg/broker"
+	"github.com/stretchr/testify/assert"
+)
+
+// runConsumer runs c until cond is true or the timeout expires, then shuts it down
+func runConsumer(t *testing.T, c job.Consumer, cond func() bool) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- c.Run(ctx)
+	}()
+
+	assert.Eventually(t, cond, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestConsumer_Idempotent(t *testing.T) {
+	repo, j := setupRepository(t, &model.ProcessedMessage{})
+	b := broker.NewMemoryBroker()
+	c := job.NewConsumer(j, conf, b, repository.NewProcessedMessageRepository(repo))
+
+	var handled int32
+	c.Register("stock.updates", func(ctx context.Context, msg *broker.Message) error {
+		atomic.AddInt32(&handled, 1)
+		return nil
+	}, job.WithConcurrency(2))
+
+	ctx := context.Background()
+	// The second message is a redelivery of the first one
+	assert.NoError(t, b.Publish(ctx, &broker.Message{ID: "m1", Topic: "stock.updates", Payload: []byte("{}")}))
+	assert.NoError(t, b.Publish(ctx, &broker.Message{ID: "m1", Topic: "stock.updates", Payload: []byte("{}")}))
+	assert.NoError(t, b.Publish(ctx, &broker.Message{ID: "m2", Topic: "stock.updates", Payload: []byte("{}")}))
+
+	runConsumer(t, c, func() bool {
+		var count int64
+		repo.DB(ctx).Model(&model.ProcessedMessage{}).Count(&count)
+		return count == 2
+	})
+	assert.Equal(t, int32(2), atomic.LoadInt32(&handled))
+}
+
+func TestConsumer_DeadLetter(t *testing.T) {
+	repo, j := setupRepository(t, &model.ProcessedMessage{})
+	b := broker.NewMemoryBroker()
+	c := job.NewConsumer(j, conf, b, repository.NewProcessedMessageRepository(repo))
+
+	var attempts int32
+	c.Register("stock.updates", func(ctx context.Context, msg *broker.Message) error {
+		atomic.AddInt32(&attempts, 1)
+		if msg.ID == "invalid" {
+			return job.Permanent(errors.New("invalid payload"))
+		}
+		return errors.New("database unavailable")
+	}, job.WithRetry(2, time.Millisecond))
+
+	ctx := context.Background()
+	assert.NoError(t, b.Publish(ctx, &broker.Message{ID: "retried", Topic: "stock.updates"}))
+	assert.NoError(t, b.Publish(ctx, &broker.Message{ID: "invalid", Topic: "stock.updates"}))
+
+	runConsumer(t, c, func() bool {
+		return len(b.Messages("stock.updates.dlq")) == 2
+	})
+
+	// 3 attempts for the retried message, 1 for the permanent failure
+	assert.Equal(t, int32(4), atomic.LoadInt32(&attempts))
+	dlq := b.Messages("stock.updates.dlq")
+	assert.Equal(t, "retried", dlq[0].ID)
+	assert.Equal(t, "database unavailable", dlq[0].Headers["dlq_error"])
+	assert.Equal(t, "invalid", dlq[1].ID)
+}
+
+// concurrentTransaction runs the handlers without a database, the sqlite connection would run them one at a time
+type concurrentTransaction struct{}
+
+func (concurrentTransaction) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type memoryProcessedMessages struct {
+	mu        sync.Mutex
+	processed map[string]bool
+}
+
+func (r *memoryProcessedMessages) Exists(ctx context.Context, consumer string, messageID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.processed[consumer+messageID], nil
+}
+
+func (r *memoryProcessedMessages) Create(ctx context.Context, message *model.ProcessedMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.processed[message.Consumer+message.MessageID] = true
+	return nil
+}
+
+func (r *memoryProcessedMessages) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.processed)
+}
+
+func TestConsumer_OrderedByKey(t *testing.T) {
+	processed := &memoryProcessedMessages{processed: make(map[string]bool)}
+	b := broker.NewMemoryBroker()
+	c := job.NewConsumer(job.NewJob(concurrentTransaction{}, logger, nil), conf, b, processed)
+
+	var (
+		mu    sync.Mutex
+		order = make(map[string][]string)
+	)
+	c.Register("stock.updates", func(ctx context.Context, msg *broker.Message) error {
+		// The first message is slow, a worker picked per message would handle the next ones of its key first
+		if msg.ID == "a0" {
+			time.Sleep(100 * time.Millisecond)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		order[msg.Key] = append(order[msg.Key], msg.ID)
+		return nil
+	}, job.WithConcurrency(4))
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		for _, key := range []string{"a", "b", "c"} {
+			assert.NoError(t, b.Publish(ctx, &broker.Message{ID: fmt.Sprintf("%s%d", key, i), Topic: "stock.updates", Key: key}))
+		}
+	}
+
+	runConsumer(t, c, func() bool {
+		return processed.count() == 9
+	})
+	mu.Lock()
+	defer mu.Unlock()
+	for _, key := range []string{"a", "b", "c"} {
+		assert.Equal(t, []string{key + "0", key + "1", key + "2"}, order[key])
+	}
+}
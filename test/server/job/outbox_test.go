@@ -0,0 +1,161 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/quydmfl/niveau-test/internal/job"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/quydmfl/niveau-test/pkg/broker"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyBroker fails the first publish of the messages keyed by failKey
+type flakyBroker struct {
+	*broker.MemoryBroker
+	failKey string
+	failed  bool
+}
+
+func (b *flakyBroker) Publish(ctx context.Context, msg *broker.Message) error {
+	if msg.Key == b.failKey && !b.failed {
+		b.failed = true
+		return errors.New("broker unavailable")
+	}
+	return b.MemoryBroker.Publish(ctx, msg)
+}
+
+func TestOutboxJob_Dispatch(t *testing.T) {
+	repo, j := setupRepository(t, &model.OutboxEvent{})
+	outboxRepo := repository.NewOutboxRepository(repo)
+	b := &flakyBroker{MemoryBroker: broker.NewMemoryBroker(), failKey: "a"}
+	outboxJob := job.NewOutboxJob(j, conf, outboxRepo, b)
+
+	ctx := context.Background()
+	for _, e := range []struct{ aggregate, eventType string }{
+		{"a", "product.created"},
+		{"b", "product.created"},
+		{"a", "product.updated"},
+		{"b", "product.updated"},
+	} {
+		event, err := model.NewOutboxEvent("products", e.aggregate, e.eventType, map[string]string{"id": e.aggregate})
+		assert.NoError(t, err)
+		assert.NoError(t, outboxRepo.Create(ctx, event))
+	}
+
+	// The first event of "a" fails, so the second one must wait for it
+	published, err := outboxJob.Dispatch(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, published)
+
+	published, err = outboxJob.Dispatch(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, published)
+
+	var order []string
+	for _, msg := range b.Messages("products") {
+		order = append(order, msg.Key+":"+msg.Headers["event_type"])
+	}
+	assert.Equal(t, []string{
+		"b:product.created",
+		"b:product.updated",
+		"a:product.created",
+		"a:product.updated",
+	}, order)
+
+	pending, err := outboxRepo.FetchPending(ctx, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+// downBroker fails every publish of the messages keyed by failKey
+type downBroker struct {
+	*broker.MemoryBroker
+	failKey string
+}
+
+func (b *downBroker) Publish(ctx context.Context, msg *broker.Message) error {
+	if msg.Key == b.failKey {
+		return errors.New("broker unavailable")
+	}
+	return b.MemoryBroker.Publish(ctx, msg)
+}
+
+func TestOutboxJob_DispatchBlockedAggregate(t *testing.T) {
+	repo, j := setupRepository(t, &model.OutboxEvent{})
+	outboxRepo := repository.NewOutboxRepository(repo)
+	b := &downBroker{MemoryBroker: broker.NewMemoryBroker(), failKey: "a"}
+	c := viper.New()
+	c.Set("job.outbox.batch_size", 5)
+	outboxJob := job.NewOutboxJob(j, c, outboxRepo, b)
+
+	ctx := context.Background()
+	// More events of the blocked aggregate than a batch, before the ones of "b"
+	for i := 0; i < 8; i++ {
+		event, err := model.NewOutboxEvent("products", "a", "product.updated", map[string]int{"i": i})
+		assert.NoError(t, err)
+		assert.NoError(t, outboxRepo.Create(ctx, event))
+	}
+	for i := 0; i < 2; i++ {
+		event, err := model.NewOutboxEvent("products", "b", "product.updated", map[string]int{"i": i})
+		assert.NoError(t, err)
+		assert.NoError(t, outboxRepo.Create(ctx, event))
+	}
+
+	// The first batch is all "a", its head fails
+	published, err := outboxJob.Dispatch(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, published)
+
+	// The events of "a" are fetched again after those of "b"
+	pending, err := outboxRepo.FetchPending(ctx, 5)
+	assert.NoError(t, err)
+	var keys []string
+	for _, event := range pending {
+		keys = append(keys, event.AggregateID)
+	}
+	assert.Equal(t, []string{"b", "b", "a", "a", "a"}, keys)
+
+	published, err = outboxJob.Dispatch(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Len(t, b.Messages("products"), 2)
+
+	// "a" keeps its order once the broker is back
+	b.failKey = ""
+	for _, expected := range []int{5, 3} {
+		published, err = outboxJob.Dispatch(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, published)
+	}
+
+	var order []string
+	for _, msg := range b.Messages("products")[2:] {
+		order = append(order, msg.Key+":"+string(msg.Payload))
+	}
+	assert.Equal(t, []string{
+		`a:{"i":0}`, `a:{"i":1}`, `a:{"i":2}`, `a:{"i":3}`, `a:{"i":4}`, `a:{"i":5}`, `a:{"i":6}`, `a:{"i":7}`,
+	}, order)
+}
+
+func TestOutboxJob_Cleanup(t *testing.T) {
+	repo, j := setupRepository(t, &model.OutboxEvent{})
+	outboxRepo := repository.NewOutboxRepository(repo)
+	outboxJob := job.NewOutboxJob(j, conf, outboxRepo, broker.NewMemoryBroker())
+
+	ctx := context.Background()
+	old, _ := model.NewOutboxEvent("products", "a", "product.created", nil)
+	recent, _ := model.NewOutboxEvent("products", "b", "product.created", nil)
+	assert.NoError(t, outboxRepo.Create(ctx, old))
+	assert.NoError(t, outboxRepo.Create(ctx, recent))
+	assert.NoError(t, outboxRepo.MarkPublished(ctx, []uint64{old.ID}, time.Now().Add(-30*24*time.Hour)))
+	assert.NoError(t, outboxRepo.MarkPublished(ctx, []uint64{recent.ID}, time.Now()))
+
+	deleted, err := outboxJob.Cleanup(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/audit"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func setupAuditRepository(t *testing.T) (*repository.Repository, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	if err := repository.RegisterAuditCallbacks(db); err != nil {
+		t.Fatalf("failed to register audit callbacks: %v", err)
+	}
+	if err := db.AutoMigrate(&model.User{}, &model.AuditLog{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	return repository.NewRepository(logger, db), db
+}
+
+func TestAuditCallbacks(t *testing.T) {
+	repo, db := setupAuditRepository(t)
+	userRepo := repository.NewUserRepository(repo)
+	auditRepo := repository.NewAuditLogRepository(repo)
+
+	ctx := audit.WithActor(context.Background(), audit.Actor{UserID: "admin", TraceID: "trace-1", IP: "203.0.113.7"})
+
+	user := &model.User{UserId: "u1", Nickname: "Alice", Password: "secret", Email: "alice@example.com"}
+	assert.NoError(t, userRepo.Create(ctx, user))
+
+	user.Nickname = "Alicia"
+	assert.NoError(t, repo.Transaction(ctx, func(ctx context.Context) error {
+		return userRepo.Update(ctx, user)
+	}))
+
+	assert.NoError(t, db.WithContext(ctx).Where("user_id = ?", "u1").Delete(&model.User{}).Error)
+
+	logs, total, err := auditRepo.Search(ctx, &v1.SearchAuditLogRequest{EntityType: "users", ActorID: "admin"})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+
+	// Latest first
+	actions := []string{model.AuditActionDelete, model.AuditActionUpdate, model.AuditActionCreate}
+	for i, entry := range *logs {
+		assert.Equal(t, actions[i], entry.Action)
+		assert.Equal(t, fmt.Sprint(user.Id), entry.EntityID)
+		assert.Equal(t, "trace-1", entry.TraceID)
+		assert.Equal(t, "203.0.113.7", entry.IP)
+		assert.NotContains(t, entry.Changes, "secret")
+	}
+
+	var changes map[string]struct {
+		Old interface{} `json:"old"`
+		New interface{} `json:"new"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte((*logs)[1].Changes), &changes))
+	assert.Equal(t, "Alice", changes["nickname"].Old)
+	assert.Equal(t, "Alicia", changes["nickname"].New)
+	assert.NotContains(t, changes, "email")
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBundleComponentRepository_BuildableQuantity(t *testing.T) {
+	repo, component := setupProductDB(t)
+	productRepo := repository.NewProductRepository(repo)
+	bundleRepo := repository.NewBundleComponentRepository(repo)
+	ctx := context.Background()
+
+	// 10 of the first component and 7 of the second
+	other := &model.Product{
+		ID:         uuid.New(),
+		Reference:  "PROD-202401-002",
+		Name:       "Choco Crisp",
+		DateAdded:  time.Now(),
+		Status:     "Available",
+		CategoryID: component.CategoryID,
+		SupplierID: component.SupplierID,
+		Price:      50000,
+		Quantity:   7,
+		Version:    1,
+	}
+	bundle := &model.Product{
+		ID:         uuid.New(),
+		Reference:  "PROD-202401-003",
+		Name:       "Gift Box",
+		DateAdded:  time.Now(),
+		Status:     "Available",
+		CategoryID: component.CategoryID,
+		SupplierID: component.SupplierID,
+		Price:      400000,
+		Version:    1,
+	}
+	assert.NoError(t, productRepo.Create(ctx, other))
+	assert.NoError(t, productRepo.Create(ctx, bundle))
+
+	assert.NoError(t, bundleRepo.Replace(ctx, bundle.ID, []model.BundleComponent{
+		{ComponentID: component.ID, Quantity: 2},
+		{ComponentID: other.ID, Quantity: 3},
+	}))
+
+	components, err := bundleRepo.GetByBundle(ctx, bundle.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, components, 2) {
+		assert.Equal(t, component.Reference, components[0].Component.Reference)
+		assert.Equal(t, 5, components[0].Buildable())
+		assert.Equal(t, 2, components[1].Buildable())
+	}
+
+	// The second component is short
+	saved, err := productRepo.GetProductByPref(ctx, bundle.Reference)
+	assert.NoError(t, err)
+	assert.True(t, saved.IsBundle())
+	assert.Equal(t, 2, saved.BuildableQuantity())
+
+	count, err := bundleRepo.CountBundles(ctx, other.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// No components make it an ordinary product again
+	assert.NoError(t, bundleRepo.Replace(ctx, bundle.ID, nil))
+	saved, err = productRepo.GetProductByPref(ctx, bundle.Reference)
+	assert.NoError(t, err)
+	assert.False(t, saved.IsBundle())
+	count, err = bundleRepo.CountBundles(ctx, other.ID)
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func setupCategoryRepository(t *testing.T) (repository.CategoryRepository, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	// The postgres defaults of the model can't be migrated on sqlite
+	if err := db.Exec(`CREATE TABLE product_categories (
+		id TEXT PRIMARY KEY, name TEXT, status TEXT, parent_id TEXT, path TEXT NOT NULL DEFAULT '', reference_prefix TEXT,
+		version INT NOT NULL DEFAULT 1, deleted_at DATETIME, created_at DATETIME, updated_at DATETIME)`).Error; err != nil {
+		t.Fatalf("failed to create product_categories: %v", err)
+	}
+
+	return repository.NewCategoryRepository(repository.NewRepository(logger, db)), db
+}
+
+func createCategory(t *testing.T, categoryRepo repository.CategoryRepository, name string, parent *model.Category) *model.Category {
+	category := &model.Category{ID: uuid.New(), Name: name, Status: "active", Version: 1}
+	category.Path = model.CategoryPath("", category.ID)
+	if parent != nil {
+		category.ParentID, category.Path = &parent.ID, model.CategoryPath(parent.Path, category.ID)
+	}
+	if err := categoryRepo.Create(context.Background(), category); err != nil {
+		t.Fatalf("failed to create category: %v", err)
+	}
+	return category
+}
+
+func TestCategoryRepository_MoveSubtree(t *testing.T) {
+	categoryRepo, _ := setupCategoryRepository(t)
+	ctx := context.Background()
+
+	food := createCategory(t, categoryRepo, "Food", nil)
+	drinks := createCategory(t, categoryRepo, "Drinks", nil)
+	snacks := createCategory(t, categoryRepo, "Snacks", food)
+	chips := createCategory(t, categoryRepo, "Chips", snacks)
+
+	assert.True(t, food.IsAncestorOf(chips))
+	assert.True(t, snacks.IsAncestorOf(snacks))
+	assert.False(t, chips.IsAncestorOf(snacks))
+
+	// Snacks moves under Drinks, Chips follows
+	oldPath := snacks.Path
+	snacks.ParentID, snacks.Path = &drinks.ID, model.CategoryPath(drinks.Path, snacks.ID)
+	assert.NoError(t, categoryRepo.Update(ctx, snacks))
+	assert.NoError(t, categoryRepo.UpdatePaths(ctx, oldPath, snacks.Path))
+
+	moved, err := categoryRepo.GetCategoryById(ctx, chips.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "/"+drinks.ID.String()+"/"+snacks.ID.String()+"/"+chips.ID.String()+"/", moved.Path)
+	assert.Equal(t, 2, moved.Version)
+
+	count, err := categoryRepo.CountChildren(ctx, food.ID, true)
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+	children, err := categoryRepo.GetChildren(ctx, drinks.ID)
+	assert.NoError(t, err)
+	assert.Len(t, children, 1)
+
+	// The version read before the move is stale
+	snacks.Version = 1
+	assert.ErrorIs(t, categoryRepo.Update(ctx, snacks), v1.ErrVersionMismatch)
+}
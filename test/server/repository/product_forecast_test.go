@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupForecastRepository(t *testing.T) (*repository.Repository, *model.Product) {
+	repo, product := setupProductDB(t)
+
+	// The postgres defaults of the model can't be migrated on sqlite
+	for _, table := range []string{
+		`ALTER TABLE suppliers ADD COLUMN lead_time_days INT NOT NULL DEFAULT 0`,
+		`CREATE TABLE stock_movements (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, product_id TEXT, delta INT, quantity_after INT, reason TEXT,
+			source_id TEXT, actor_id TEXT, lot_id TEXT, created_at DATETIME)`,
+		`CREATE TABLE product_forecasts (
+			product_id TEXT PRIMARY KEY, reference TEXT, name TEXT, supplier_id TEXT, supplier_name TEXT, method TEXT,
+			period_days INT, periods INT, moving_average REAL, exponential_smoothing REAL, daily_demand REAL,
+			demand_std_dev REAL, lead_time_days INT, safety_stock INT, quantity INT, on_order INT, reorder_point INT,
+			reorder_quantity INT, computed_at DATETIME)`,
+	} {
+		if err := repo.DB(context.Background()).Exec(table).Error; err != nil {
+			t.Fatalf("failed to create table: %v", err)
+		}
+	}
+
+	return repo, product
+}
+
+func TestStockMovementRepository_GetDemand(t *testing.T) {
+	repo, product := setupForecastRepository(t)
+	movementRepo := repository.NewStockMovementRepository(repo)
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, movement := range []model.StockMovement{
+		{Delta: -3, Reason: model.StockMovementConsumption, CreatedAt: now.Add(-48 * time.Hour)},
+		{Delta: -2, Reason: model.StockMovementExternalSync, CreatedAt: now.Add(-24 * time.Hour)},
+		// Received, corrected by a stocktake, or too old
+		{Delta: 10, Reason: model.StockMovementPurchaseReceipt, CreatedAt: now.Add(-24 * time.Hour)},
+		{Delta: -4, Reason: model.StockMovementStocktake, CreatedAt: now.Add(-24 * time.Hour)},
+		{Delta: -5, Reason: model.StockMovementConsumption, CreatedAt: now.Add(-30 * 24 * time.Hour)},
+	} {
+		movement.ProductID = product.ID
+		assert.NoError(t, movementRepo.Create(ctx, &movement))
+	}
+
+	movements, err := movementRepo.GetDemand(ctx, now.Add(-7*24*time.Hour))
+	assert.NoError(t, err)
+	if assert.Len(t, movements, 2) {
+		assert.Equal(t, -3, movements[0].Delta)
+		assert.Equal(t, -2, movements[1].Delta)
+		assert.Equal(t, product.ID, movements[1].ProductID)
+	}
+}
+
+func TestProductForecastRepository_Replace(t *testing.T) {
+	repo, product := setupForecastRepository(t)
+	forecastRepo := repository.NewProductForecastRepository(repo)
+	ctx := context.Background()
+
+	assert.NoError(t, repo.DB(ctx).Exec(`INSERT INTO suppliers (id, name, lead_time_days) VALUES (?, ?, ?)`,
+		product.SupplierID, "Snack Co", 7).Error)
+
+	products, err := forecastRepo.GetProductsToForecast(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, products, 1) {
+		assert.Equal(t, 7, products[0].Supplier.LeadTimeDays)
+	}
+
+	other := uuid.New()
+	assert.NoError(t, forecastRepo.Replace(ctx, []model.ProductForecast{
+		{ProductID: other, Reference: "PROD-202401-002", SupplierID: uuid.New(), ComputedAt: time.Now()},
+	}))
+	assert.NoError(t, forecastRepo.Replace(ctx, []model.ProductForecast{
+		{ProductID: product.ID, Reference: product.Reference, SupplierID: product.SupplierID, ReorderQuantity: 12, ComputedAt: time.Now()},
+		{ProductID: uuid.New(), Reference: "PROD-202401-003", SupplierID: product.SupplierID, ComputedAt: time.Now()},
+	}))
+
+	// The previous run is gone
+	_, err = forecastRepo.GetByProduct(ctx, other)
+	assert.ErrorIs(t, err, v1.ErrNotFound)
+	forecast, err := forecastRepo.GetByProduct(ctx, product.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, forecast.ReorderQuantity)
+
+	forecasts, total, err := forecastRepo.Search(ctx, &v1.SearchForecastRequest{ForecastFilter: v1.ForecastFilter{SupplierId: product.SupplierID.String()}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, product.Reference, (*forecasts)[0].Reference)
+
+	forecasts, total, err = forecastRepo.Search(ctx, &v1.SearchForecastRequest{ForecastFilter: v1.ForecastFilter{ReorderOnly: true}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Len(t, *forecasts, 1)
+}
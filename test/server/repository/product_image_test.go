@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductImageRepository_Positions(t *testing.T) {
+	repo, product := setupProductDB(t)
+	productRepo := repository.NewProductRepository(repo)
+	imageRepo := repository.NewProductImageRepository(repo)
+	ctx := context.Background()
+
+	var images []model.ProductImage
+	for position, name := range []string{"front.jpg", "back.jpg", "side.jpg"} {
+		image := model.ProductImage{
+			ID:          uuid.New(),
+			ProductID:   product.ID,
+			Filename:    name,
+			Path:        "storage/images/" + name,
+			ContentType: "image/jpeg",
+			Position:    position,
+			Thumbnails:  map[int]string{64: "thumb_64_" + name},
+			UploadedAt:  time.Now(),
+		}
+		assert.NoError(t, imageRepo.Create(ctx, &image))
+		images = append(images, image)
+	}
+
+	// side, front, back
+	images[0].Position, images[1].Position, images[2].Position = 1, 2, 0
+	assert.NoError(t, imageRepo.UpdatePositions(ctx, images))
+
+	saved, err := imageRepo.GetByProduct(ctx, product.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, saved, 3) {
+		assert.Equal(t, "side.jpg", saved[0].Filename)
+		assert.Equal(t, "front.jpg", saved[1].Filename)
+		assert.Equal(t, "back.jpg", saved[2].Filename)
+		assert.Equal(t, map[int]string{64: "thumb_64_side.jpg"}, saved[0].Thumbnails)
+	}
+
+	detail, err := productRepo.GetProductByPref(ctx, product.Reference)
+	assert.NoError(t, err)
+	if assert.Len(t, detail.Images, 3) {
+		assert.Equal(t, "side.jpg", detail.Images[0].Filename)
+	}
+
+	_, err = imageRepo.GetById(ctx, uuid.New(), images[0].ID)
+	assert.ErrorIs(t, err, v1.ErrNotFound)
+	assert.NoError(t, imageRepo.Delete(ctx, &images[2]))
+	saved, err = imageRepo.GetByProduct(ctx, product.ID)
+	assert.NoError(t, err)
+	assert.Len(t, saved, 2)
+}
+
+func TestProductImageRepository_GetByProductWithVariants(t *testing.T) {
+	repo, product := setupProductDB(t)
+	productRepo := repository.NewProductRepository(repo)
+	imageRepo := repository.NewProductImageRepository(repo)
+	ctx := context.Background()
+
+	variant := &model.Product{
+		ID:         uuid.New(),
+		Reference:  product.Reference + "-M",
+		Name:       product.Name + " M",
+		DateAdded:  time.Now(),
+		Status:     model.ProductAvailable,
+		CategoryID: product.CategoryID,
+		SupplierID: product.SupplierID,
+		ParentID:   &product.ID,
+		Options:    map[string]string{"size": "M"},
+		Version:    1,
+	}
+	assert.NoError(t, productRepo.Create(ctx, variant))
+
+	for _, productID := range []uuid.UUID{product.ID, variant.ID, uuid.New()} {
+		assert.NoError(t, imageRepo.Create(ctx, &model.ProductImage{
+			ID:          uuid.New(),
+			ProductID:   productID,
+			Filename:    "front.jpg",
+			Path:        "storage/images/front.jpg",
+			ContentType: "image/jpeg",
+			UploadedAt:  time.Now(),
+		}))
+	}
+
+	images, err := imageRepo.GetByProductWithVariants(ctx, product.ID)
+	assert.NoError(t, err)
+	assert.Len(t, images, 2)
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/helper"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// createLots adds a lot of quantity per number expiring the given days from today
+func createLots(t *testing.T, lotRepo repository.ProductLotRepository, product *model.Product, quantity int, days map[string]int) {
+	today := helper.Date(time.Now())
+	for number, day := range days {
+		lot := model.ProductLot{
+			ID:               uuid.New(),
+			ProductID:        product.ID,
+			LotNumber:        number,
+			Quantity:         quantity,
+			ReceivedQuantity: quantity,
+			ExpiresAt:        today.AddDate(0, 0, day),
+			ReceivedAt:       time.Now(),
+		}
+		assert.NoError(t, lotRepo.Create(context.Background(), &lot))
+	}
+}
+
+func TestProductLotRepository_ConsumeFEFO(t *testing.T) {
+	repo, product := setupProductDB(t)
+	lotRepo := repository.NewProductLotRepository(repo)
+	ctx := context.Background()
+
+	// 10 in stock, 8 of them in lots
+	createLots(t, lotRepo, product, 4, map[string]int{"B": 20, "A": 5})
+
+	lots, err := lotRepo.GetByProduct(ctx, product.ID, false)
+	assert.NoError(t, err)
+	if assert.Len(t, lots, 2) {
+		assert.Equal(t, "A", lots[0].LotNumber)
+	}
+
+	consumed := model.ConsumeFEFO(lots, 5)
+	if assert.Len(t, consumed, 2) {
+		assert.Equal(t, 4, consumed[0].Quantity)
+		assert.Equal(t, "B", consumed[1].Lot.LotNumber)
+		assert.Equal(t, 1, consumed[1].Quantity)
+	}
+	assert.NoError(t, lotRepo.UpdateQuantities(ctx, lots))
+
+	inLots, err := lotRepo.SumQuantity(ctx, product.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, inLots)
+
+	lots, err = lotRepo.GetByProduct(ctx, product.ID, false)
+	assert.NoError(t, err)
+	assert.Len(t, lots, 1)
+	lots, err = lotRepo.GetByProduct(ctx, product.ID, true)
+	assert.NoError(t, err)
+	assert.Len(t, lots, 2)
+
+	// What the lots don't cover is taken from the stock without lot
+	before := product.Quantity
+	product.Quantity = 0
+	consumed = model.ConsumeFEFO(lots, before)
+	movements := model.LotMovements(product, before, consumed)
+	if assert.Len(t, movements, 2) {
+		assert.NotNil(t, movements[0].LotID)
+		assert.Equal(t, -3, movements[0].Delta)
+		assert.Equal(t, 7, movements[0].QuantityAfter)
+		assert.Nil(t, movements[1].LotID)
+		assert.Equal(t, -7, movements[1].Delta)
+		assert.Equal(t, 0, movements[1].QuantityAfter)
+	}
+
+	_, err = lotRepo.GetByNumber(ctx, product.ID, "C")
+	assert.ErrorIs(t, err, v1.ErrNotFound)
+}
+
+func TestProductLotRepository_Expiring(t *testing.T) {
+	repo, product := setupProductDB(t)
+	productRepo := repository.NewProductRepository(repo)
+	lotRepo := repository.NewProductLotRepository(repo)
+	ctx := context.Background()
+
+	createLots(t, lotRepo, product, 5, map[string]int{"EXPIRED": -2, "SOON": 3, "LATER": 40})
+	today := helper.Date(time.Now())
+
+	lots, total, err := lotRepo.GetExpiring(ctx, today, today.AddDate(0, 0, 30), "", &v1.Page{Page: 1, Size: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	if assert.Len(t, lots, 1) {
+		assert.Equal(t, "SOON", lots[0].LotNumber)
+		assert.Equal(t, product.Reference, lots[0].Product.Reference)
+		assert.Equal(t, 3, lots[0].DaysLeft(time.Now()))
+	}
+	_, total, err = lotRepo.GetExpiring(ctx, today.AddDate(0, 0, -7), today.AddDate(0, 0, 40), "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+
+	// Expired lots are flagged too, each lot once
+	toFlag, err := lotRepo.GetToFlag(ctx, today.AddDate(0, 0, 14))
+	assert.NoError(t, err)
+	if assert.Len(t, toFlag, 2) {
+		assert.Equal(t, "EXPIRED", toFlag[0].LotNumber)
+		assert.True(t, toFlag[0].Expired(time.Now()))
+		assert.NoError(t, lotRepo.MarkFlagged(ctx, &toFlag[0], time.Now()))
+	}
+	toFlag, err = lotRepo.GetToFlag(ctx, today.AddDate(0, 0, 14))
+	assert.NoError(t, err)
+	assert.Len(t, toFlag, 1)
+
+	// The lots of a product in the trash are not listed
+	assert.NoError(t, productRepo.Delete(ctx, product))
+	_, total, err = lotRepo.GetExpiring(ctx, today.AddDate(0, 0, -7), today.AddDate(0, 0, 40), "", nil)
+	assert.NoError(t, err)
+	assert.Zero(t, total)
+}
@@ -0,0 +1,378 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func setupProductRepository(t *testing.T) (repository.ProductRepository, *model.Product) {
+	repo, product := setupProductDB(t)
+	return repository.NewProductRepository(repo), product
+}
+
+// setupProductDB creates the product tables on sqlite with one product
+func setupProductDB(t *testing.T) (*repository.Repository, *model.Product) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	// The postgres defaults of the model can't be migrated on sqlite
+	if err := db.Exec(`CREATE TABLE products (
+		id TEXT PRIMARY KEY, reference TEXT, name TEXT, added_date DATE, status TEXT, category_id TEXT,
+		price NUMERIC, stock_city TEXT, supplier_id TEXT, quantity INT, parent_id TEXT, options TEXT, gtin TEXT UNIQUE,
+		version INT NOT NULL DEFAULT 1, deleted_at DATETIME)`).Error; err != nil {
+		t.Fatalf("failed to create products: %v", err)
+	}
+	// The search preloads them
+	if err := db.Exec(`CREATE TABLE product_categories (id TEXT PRIMARY KEY, name TEXT, deleted_at DATETIME)`).Error; err != nil {
+		t.Fatalf("failed to create product_categories: %v", err)
+	}
+	if err := db.Exec(`CREATE TABLE suppliers (id TEXT PRIMARY KEY, name TEXT, deleted_at DATETIME)`).Error; err != nil {
+		t.Fatalf("failed to create suppliers: %v", err)
+	}
+	if err := db.Exec(`CREATE TABLE product_attributes (
+		id INTEGER PRIMARY KEY AUTOINCREMENT, product_id TEXT, name TEXT, type TEXT, value TEXT)`).Error; err != nil {
+		t.Fatalf("failed to create product_attributes: %v", err)
+	}
+	if err := db.Exec(`CREATE TABLE product_images (
+		id TEXT PRIMARY KEY, product_id TEXT, filename TEXT, path TEXT, content_type TEXT, size INT,
+		width INT, height INT, position INT NOT NULL DEFAULT 0, thumbnails TEXT, uploaded_at DATETIME)`).Error; err != nil {
+		t.Fatalf("failed to create product_images: %v", err)
+	}
+	if err := db.Exec(`CREATE TABLE product_lots (
+		id TEXT PRIMARY KEY, product_id TEXT, lot_number TEXT, quantity INT NOT NULL DEFAULT 0, received_quantity INT NOT NULL DEFAULT 0,
+		manufactured_at DATE, expires_at DATE, stock_city TEXT, expiry_flagged_at DATETIME, received_at DATETIME,
+		UNIQUE (product_id, lot_number))`).Error; err != nil {
+		t.Fatalf("failed to create product_lots: %v", err)
+	}
+	if err := db.Exec(`CREATE TABLE product_bundle_components (
+		id INTEGER PRIMARY KEY AUTOINCREMENT, bundle_id TEXT, component_id TEXT, quantity INT NOT NULL,
+		UNIQUE (bundle_id, component_id))`).Error; err != nil {
+		t.Fatalf("failed to create product_bundle_components: %v", err)
+	}
+	if err := db.Exec(`CREATE TABLE reference_counters (scope TEXT PRIMARY KEY, value INT NOT NULL DEFAULT 0, updated_at DATETIME)`).Error; err != nil {
+		t.Fatalf("failed to create reference_counters: %v", err)
+	}
+
+	product := &model.Product{
+		ID:         uuid.New(),
+		Reference:  "PROD-202401-001",
+		Name:       "Crunchy Munch",
+		DateAdded:  time.Now(),
+		Status:     "Available",
+		CategoryID: uuid.New(),
+		SupplierID: uuid.New(),
+		Price:      150000,
+		Quantity:   10,
+		Version:    1,
+	}
+	if err := db.Create(product).Error; err != nil {
+		t.Fatalf("failed to create product: %v", err)
+	}
+
+	return repository.NewRepository(logger, db), product
+}
+
+func TestProductRepository_UpdateChecksVersion(t *testing.T) {
+	productRepo, product := setupProductRepository(t)
+	ctx := context.Background()
+
+	stale := *product
+
+	product.Name = "Crunchy Munch XL"
+	assert.NoError(t, productRepo.Update(ctx, product))
+	assert.Equal(t, 2, product.Version)
+
+	stale.Name = "Crunchy Munch Mini"
+	assert.ErrorIs(t, productRepo.Update(ctx, &stale), v1.ErrVersionMismatch)
+	assert.Equal(t, 1, stale.Version)
+	assert.ErrorIs(t, productRepo.Delete(ctx, &stale), v1.ErrVersionMismatch)
+
+	saved, err := productRepo.GetProductByPrefForUpdate(ctx, product.Reference)
+	assert.NoError(t, err)
+	assert.Equal(t, "Crunchy Munch XL", saved.Name)
+	assert.Equal(t, 2, saved.Version)
+}
+
+func TestProductRepository_UpdateStockIncrementsVersion(t *testing.T) {
+	productRepo, product := setupProductRepository(t)
+	ctx := context.Background()
+
+	product.Quantity = 0
+	product.Status = "Out of Stock"
+	assert.NoError(t, productRepo.UpdateStock(ctx, product))
+	assert.Equal(t, 2, product.Version)
+
+	saved, err := productRepo.GetProductByPrefForUpdate(ctx, product.Reference)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, saved.Quantity)
+	assert.Equal(t, 2, saved.Version)
+
+}
+
+func TestProductRepository_Trash(t *testing.T) {
+	productRepo, product := setupProductRepository(t)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, productRepo.Purge(ctx, product), v1.ErrNotFound)
+	assert.NoError(t, productRepo.Delete(ctx, product))
+	_, err := productRepo.GetProductByPrefForUpdate(ctx, product.Reference)
+	assert.ErrorIs(t, err, v1.ErrNotFound)
+
+	trash, total, err := productRepo.SearchDeleted(ctx, &v1.GetTrashRequest{Page: v1.Page{Page: 1, Size: 10}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, product.Reference, (*trash)[0].Reference)
+
+	deleted, err := productRepo.GetDeletedProductByPref(ctx, product.Reference)
+	assert.NoError(t, err)
+	assert.NoError(t, productRepo.Restore(ctx, deleted))
+	assert.Equal(t, 2, deleted.Version)
+
+	restored, err := productRepo.GetProductByPrefForUpdate(ctx, product.Reference)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, restored.Version)
+
+	assert.NoError(t, productRepo.Delete(ctx, restored))
+	count, err := productRepo.CountByCategory(ctx, product.CategoryID, false)
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+
+	deleted, err = productRepo.GetDeletedProductByPref(ctx, product.Reference)
+	assert.NoError(t, err)
+	assert.NoError(t, productRepo.Purge(ctx, deleted))
+	count, err = productRepo.CountByCategory(ctx, product.CategoryID, true)
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestProductRepository_SearchHidesArchived(t *testing.T) {
+	productRepo, product := setupProductRepository(t)
+	ctx := context.Background()
+
+	search := func(req v1.SearchProductRequest) int {
+		req.Page = v1.Page{Page: 1, Size: 10}
+		req.Sorting.SetDefault()
+		_, total, err := productRepo.Search(ctx, &req)
+		assert.NoError(t, err)
+		return total
+	}
+
+	assert.False(t, product.CanBecome(model.ProductArchived))
+	assert.True(t, product.CanBecome(model.ProductDiscontinued))
+	product.Status = model.ProductDiscontinued
+	assert.True(t, product.CanBecome(model.ProductArchived))
+	product.Status = model.ProductArchived
+	assert.NoError(t, productRepo.Update(ctx, product))
+
+	assert.Equal(t, 0, search(v1.SearchProductRequest{}))
+	assert.Equal(t, 1, search(v1.SearchProductRequest{IncludeArchived: true}))
+	assert.Equal(t, 1, search(v1.SearchProductRequest{Status: model.ProductArchived}))
+}
+
+func TestProductRepository_SearchFiltersAttributes(t *testing.T) {
+	productRepo, product := setupProductRepository(t)
+	ctx := context.Background()
+
+	schema := []model.CategoryAttribute{
+		{Name: "color", Type: model.AttributeEnum, Required: true, AllowedValues: []string{"red", "green"}},
+		{Name: "weight", Type: model.AttributeNumber},
+		{Name: "organic", Type: model.AttributeBool},
+	}
+	_, err := model.NewProductAttributes(schema, map[string]interface{}{"weight": 1.5})
+	assert.Error(t, err, "color is required")
+	_, err = model.NewProductAttributes(schema, map[string]interface{}{"color": "blue"})
+	assert.Error(t, err, "blue is not allowed")
+	_, err = model.NewProductAttributes(schema, map[string]interface{}{"color": "red", "size": "XL"})
+	assert.Error(t, err, "size is not in the schema")
+
+	product.Attributes, err = model.NewProductAttributes(schema, map[string]interface{}{"color": "red", "weight": 1.5, "organic": true})
+	assert.NoError(t, err)
+	assert.NoError(t, productRepo.ReplaceAttributes(ctx, product))
+
+	stored, err := productRepo.GetProductByPref(ctx, product.Reference)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"color": "red", "weight": 1.5, "organic": true}, stored.AttributeValues())
+
+	search := func(filters ...string) int {
+		req := v1.SearchProductRequest{Page: v1.Page{Page: 1, Size: 10}, Attributes: filters}
+		req.Sorting.SetDefault()
+		_, total, err := productRepo.Search(ctx, &req)
+		assert.NoError(t, err)
+		return total
+	}
+
+	assert.Equal(t, 1, search("color:red"))
+	assert.Equal(t, 0, search("color:green"))
+	assert.Equal(t, 1, search("color:red", "weight:1.5", "organic:true"))
+	assert.Equal(t, 0, search("color:red", "organic:false"))
+}
+
+func TestProductRepository_SearchCollapsesVariants(t *testing.T) {
+	productRepo, product := setupProductRepository(t)
+	ctx := context.Background()
+
+	for size, quantity := range map[string]int{"M": 5, "L": 7} {
+		variant := &model.Product{
+			ID:         uuid.New(),
+			Reference:  product.Reference + "-" + size,
+			Name:       product.Name + " " + size,
+			DateAdded:  time.Now(),
+			Status:     model.ProductAvailable,
+			CategoryID: product.CategoryID,
+			SupplierID: product.SupplierID,
+			Price:      product.Price,
+			Quantity:   quantity,
+			ParentID:   &product.ID,
+			Options:    map[string]string{"size": size},
+			Version:    1,
+		}
+		assert.NoError(t, productRepo.Create(ctx, variant))
+	}
+
+	search := func(req v1.SearchProductRequest) []model.Product {
+		req.Page = v1.Page{Page: 1, Size: 10}
+		req.Sorting.SetDefault()
+		products, _, err := productRepo.Search(ctx, &req)
+		assert.NoError(t, err)
+		return *products
+	}
+
+	assert.Len(t, search(v1.SearchProductRequest{}), 3)
+
+	collapsed := search(v1.SearchProductRequest{CollapseVariants: true})
+	if assert.Len(t, collapsed, 1) {
+		assert.Equal(t, product.ID, collapsed[0].ID)
+	}
+	// The parent is listed when one of its variants matches
+	collapsed = search(v1.SearchProductRequest{CollapseVariants: true, Reference: product.Reference + "-L"})
+	if assert.Len(t, collapsed, 1) {
+		assert.Equal(t, product.ID, collapsed[0].ID)
+	}
+
+	stocks, err := productRepo.SumVariantQuantities(ctx, []uuid.UUID{product.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, repository.VariantStock{Count: 2, Quantity: 12}, stocks[product.ID])
+
+	count, err := productRepo.CountVariants(ctx, product.ID, false)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+
+	variant, err := productRepo.GetProductByPref(ctx, product.Reference+"-M")
+	assert.NoError(t, err)
+	assert.True(t, variant.IsVariant())
+	assert.Equal(t, map[string]string{"size": "M"}, variant.Options)
+	if assert.NotNil(t, variant.Parent) {
+		assert.Equal(t, product.Reference, variant.Parent.Reference)
+	}
+
+	parent, err := productRepo.GetProductByPref(ctx, product.Reference)
+	assert.NoError(t, err)
+	assert.Len(t, parent.Variants, 2)
+}
+
+func TestProductRepository_GetProductByGTIN(t *testing.T) {
+	productRepo, product := setupProductRepository(t)
+	ctx := context.Background()
+
+	// A UPC-A stored as 12 digits, scanned as the 13 digits of an EAN-13
+	gtin := "036000291452"
+	product.GTIN = &gtin
+	assert.NoError(t, productRepo.Update(ctx, product))
+
+	found, err := productRepo.GetProductByGTIN(ctx, "0036000291452", false)
+	assert.NoError(t, err)
+	assert.Equal(t, product.ID, found.ID)
+
+	req := v1.SearchProductRequest{Page: v1.Page{Page: 1, Size: 10}, GTIN: "00036000291452"}
+	req.Sorting.SetDefault()
+	_, total, err := productRepo.Search(ctx, &req)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+
+	// Products in the trash keep their GTIN
+	assert.NoError(t, productRepo.Delete(ctx, product))
+	_, err = productRepo.GetProductByGTIN(ctx, gtin, false)
+	assert.ErrorIs(t, err, v1.ErrNotFound)
+	found, err = productRepo.GetProductByGTIN(ctx, gtin, true)
+	assert.NoError(t, err)
+	assert.Equal(t, product.ID, found.ID)
+}
+
+func TestProductRepository_GetLiveReferences(t *testing.T) {
+	productRepo, product := setupProductRepository(t)
+	ctx := context.Background()
+
+	live, err := productRepo.GetLiveReferences(ctx, []string{product.Reference, "PROD-202401-999"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{product.Reference}, live)
+
+	assert.NoError(t, productRepo.Delete(ctx, product))
+	live, err = productRepo.GetLiveReferences(ctx, []string{product.Reference})
+	assert.NoError(t, err)
+	assert.Empty(t, live)
+}
+
+func TestRepository_NestedTransaction(t *testing.T) {
+	repo, product := setupProductDB(t)
+	productRepo := repository.NewProductRepository(repo)
+	tm := repository.NewTransaction(repo)
+	ctx := context.Background()
+
+	// The nested Transaction that fails is rolled back alone
+	err := tm.Transaction(ctx, func(ctx context.Context) error {
+		product.Name = "Crunchy Munch XL"
+		if err := productRepo.Update(ctx, product); err != nil {
+			return err
+		}
+		nestedErr := tm.Transaction(ctx, func(ctx context.Context) error {
+			product.Quantity = 0
+			if err := productRepo.UpdateStock(ctx, product); err != nil {
+				return err
+			}
+			return v1.ErrBadRequest
+		})
+		assert.ErrorIs(t, nestedErr, v1.ErrBadRequest)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	saved, err := productRepo.GetProductByPref(ctx, product.Reference)
+	assert.NoError(t, err)
+	assert.Equal(t, "Crunchy Munch XL", saved.Name)
+	assert.Equal(t, 10, saved.Quantity)
+
+	// The outer Transaction that fails rolls back the nested ones
+	err = tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := tm.Transaction(ctx, func(ctx context.Context) error {
+			saved.Name = "Crunchy Munch Mini"
+			return productRepo.Update(ctx, saved)
+		}); err != nil {
+			return err
+		}
+		return v1.ErrBadRequest
+	})
+	assert.ErrorIs(t, err, v1.ErrBadRequest)
+
+	saved, err = productRepo.GetProductByPref(ctx, product.Reference)
+	assert.NoError(t, err)
+	assert.Equal(t, "Crunchy Munch XL", saved.Name)
+}
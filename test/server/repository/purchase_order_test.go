@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func setupPurchaseOrderRepository(t *testing.T) repository.PurchaseOrderRepository {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	// The postgres defaults of the model can't be migrated on sqlite
+	for _, table := range []string{
+		`CREATE TABLE purchase_orders (
+			id TEXT PRIMARY KEY, number TEXT UNIQUE, supplier_id TEXT, supplier_name TEXT, status TEXT, currency TEXT,
+			note TEXT, created_by TEXT, expected_at DATETIME, sent_at DATETIME, received_at DATETIME,
+			cancelled_at DATETIME, version INT NOT NULL DEFAULT 1, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE purchase_order_lines (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, purchase_order_id TEXT, product_id TEXT, reference TEXT,
+			name TEXT, quantity INT, received_quantity INT NOT NULL DEFAULT 0, unit_cost REAL)`,
+	} {
+		if err := db.Exec(table).Error; err != nil {
+			t.Fatalf("failed to create table: %v", err)
+		}
+	}
+
+	return repository.NewPurchaseOrderRepository(repository.NewRepository(logger, db))
+}
+
+func TestPurchaseOrderRepository_Receive(t *testing.T) {
+	orderRepo := setupPurchaseOrderRepository(t)
+	ctx := context.Background()
+
+	order := &model.PurchaseOrder{ID: uuid.New(), Number: "PO-1", SupplierName: "Crunchy Munch", Status: model.PurchaseOrderDraft, Currency: "VND", Version: 1,
+		Lines: []model.PurchaseOrderLine{
+			{Reference: "PROD-2024-01-0001", Name: "Chips", Quantity: 10, UnitCost: 2.5},
+			{Reference: "PROD-2024-01-0002", Name: "Soda", Quantity: 4, UnitCost: 1},
+		}}
+	assert.NoError(t, orderRepo.Create(ctx, order))
+	assert.Equal(t, 29.0, order.Total())
+	assert.False(t, order.CanBecome(model.PurchaseOrderReceived))
+
+	order.Status = model.PurchaseOrderSent
+	assert.NoError(t, orderRepo.Update(ctx, order))
+	stale := *order
+	stale.Version = 1
+	assert.ErrorIs(t, orderRepo.Update(ctx, &stale), v1.ErrVersionMismatch)
+
+	locked, err := orderRepo.GetPurchaseOrderByIdForUpdate(ctx, order.ID)
+	assert.NoError(t, err)
+	assert.True(t, locked.CanBecome(model.PurchaseOrderReceived))
+	assert.Len(t, locked.Lines, 2)
+	locked.Lines[0].ReceivedQuantity = 10
+	assert.NoError(t, orderRepo.UpdateReceived(ctx, locked.Lines[:1]))
+
+	received, err := orderRepo.GetPurchaseOrderById(ctx, order.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, received.Lines[0].Remaining())
+	assert.Equal(t, 4, received.Lines[1].Remaining())
+	assert.False(t, received.FullyReceived())
+
+	orders, total, err := orderRepo.Search(ctx, &v1.SearchPurchaseOrderRequest{Page: v1.Page{Page: 1, Size: 10}, Status: model.PurchaseOrderSent})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "PO-1", (*orders)[0].Number)
+}
+
+func TestPurchaseOrderRepository_SumOnOrder(t *testing.T) {
+	orderRepo := setupPurchaseOrderRepository(t)
+	ctx := context.Background()
+
+	chips, soda := uuid.New(), uuid.New()
+	for _, order := range []*model.PurchaseOrder{
+		{ID: uuid.New(), Number: "PO-1", Status: model.PurchaseOrderSent, Version: 1,
+			Lines: []model.PurchaseOrderLine{{ProductID: &chips, Reference: "PROD-2024-01-0001", Name: "Chips", Quantity: 10}}},
+		{ID: uuid.New(), Number: "PO-2", Status: model.PurchaseOrderPartiallyReceived, Version: 1,
+			Lines: []model.PurchaseOrderLine{
+				{ProductID: &chips, Reference: "PROD-2024-01-0001", Name: "Chips", Quantity: 6, ReceivedQuantity: 4},
+				{ProductID: &soda, Reference: "PROD-2024-01-0002", Name: "Soda", Quantity: 3, ReceivedQuantity: 3},
+			}},
+		// Not ordered yet, or not any more
+		{ID: uuid.New(), Number: "PO-3", Status: model.PurchaseOrderDraft, Version: 1,
+			Lines: []model.PurchaseOrderLine{{ProductID: &soda, Reference: "PROD-2024-01-0002", Name: "Soda", Quantity: 8}}},
+		{ID: uuid.New(), Number: "PO-4", Status: model.PurchaseOrderCancelled, Version: 1,
+			Lines: []model.PurchaseOrderLine{{ProductID: &chips, Reference: "PROD-2024-01-0001", Name: "Chips", Quantity: 5}}},
+	} {
+		assert.NoError(t, orderRepo.Create(ctx, order))
+	}
+
+	onOrder, err := orderRepo.SumOnOrder(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, onOrder[chips])
+	assert.Zero(t, onOrder[soda])
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReferenceCounterRepository_Next(t *testing.T) {
+	repo, product := setupProductDB(t)
+	productRepo := repository.NewProductRepository(repo)
+	counterRepo := repository.NewReferenceCounterRepository(repo)
+	ctx := context.Background()
+
+	var values []int64
+	for _, scope := range []string{"PROD-2024-01-{SEQ}", "PROD-2024-01-{SEQ}", "PROD-2024-02-{SEQ}", "PROD-2024-01-{SEQ}"} {
+		assert.NoError(t, repo.Transaction(ctx, func(ctx context.Context) error {
+			value, err := counterRepo.Next(ctx, scope)
+			values = append(values, value)
+			return err
+		}))
+	}
+	assert.Equal(t, []int64{1, 2, 1, 3}, values)
+
+	// A product in the trash keeps its reference
+	assert.NoError(t, productRepo.Delete(ctx, product))
+	exists, err := productRepo.ReferenceExists(ctx, product.Reference)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = productRepo.ReferenceExists(ctx, "PROD-2024-01-0001")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
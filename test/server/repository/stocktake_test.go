@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupStocktakeRepository(t *testing.T) (*repository.Repository, *model.Product) {
+	repo, product := setupProductDB(t)
+
+	// The postgres defaults of the model can't be migrated on sqlite
+	for _, table := range []string{
+		`ALTER TABLE product_categories ADD COLUMN path TEXT NOT NULL DEFAULT ''`,
+		`CREATE TABLE stocktakes (
+			id TEXT PRIMARY KEY, number TEXT UNIQUE, status TEXT, stock_city TEXT, category_id TEXT,
+			include_subcategories BOOLEAN NOT NULL DEFAULT false, note TEXT, created_by TEXT, approved_by TEXT,
+			approved_at DATETIME, cancelled_at DATETIME, version INT NOT NULL DEFAULT 1, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE stocktake_lines (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, stocktake_id TEXT, product_id TEXT, reference TEXT, name TEXT,
+			stock_city TEXT, system_quantity INT NOT NULL DEFAULT 0, counted_quantity INT, adjustment INT)`,
+		`CREATE TABLE stocktake_counts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, line_id INTEGER NOT NULL, device_id TEXT NOT NULL, quantity INT NOT NULL,
+			counted_by TEXT, counted_at DATETIME, UNIQUE (line_id, device_id))`,
+	} {
+		if err := repo.DB(context.Background()).Exec(table).Error; err != nil {
+			t.Fatalf("failed to create table: %v", err)
+		}
+	}
+
+	return repo, product
+}
+
+func TestStocktakeRepository_SaveCounts(t *testing.T) {
+	repo, product := setupStocktakeRepository(t)
+	stocktakeRepo := repository.NewStocktakeRepository(repo)
+	ctx := context.Background()
+
+	stocktake := &model.Stocktake{ID: uuid.New(), Number: "ST-1", Status: model.StocktakeOpen, StockCity: "Ha Noi", Version: 1,
+		CreatedAt: time.Now(), UpdatedAt: time.Now(),
+		Lines: []model.StocktakeLine{
+			{ProductID: product.ID, Reference: product.Reference, Name: product.Name, SystemQuantity: product.Quantity},
+		}}
+	assert.NoError(t, stocktakeRepo.Create(ctx, stocktake))
+	lineID := stocktake.Lines[0].ID
+
+	// Two devices count separate shelves, the second count of a device replaces its first
+	assert.NoError(t, stocktakeRepo.SaveCounts(ctx, []model.StocktakeCount{{LineID: lineID, DeviceID: "scanner-01", Quantity: 4, CountedAt: time.Now()}}))
+	assert.NoError(t, stocktakeRepo.SaveCounts(ctx, []model.StocktakeCount{{LineID: lineID, DeviceID: "scanner-02", Quantity: 3, CountedAt: time.Now()}}))
+	assert.NoError(t, stocktakeRepo.SaveCounts(ctx, []model.StocktakeCount{{LineID: lineID, DeviceID: "scanner-01", Quantity: 5, CountedAt: time.Now()}}))
+
+	saved, err := stocktakeRepo.GetStocktakeById(ctx, stocktake.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, saved.Lines, 1) && assert.NotNil(t, saved.Lines[0].CountedQuantity) {
+		assert.Equal(t, 8, *saved.Lines[0].CountedQuantity)
+		assert.Equal(t, -2, *saved.Lines[0].Variance())
+		assert.Equal(t, product.Reference, saved.Lines[0].Product.Reference)
+	}
+
+	// Another writer bumped the version
+	saved.Status = model.StocktakeCancelled
+	stale := *saved
+	assert.NoError(t, stocktakeRepo.Update(ctx, saved))
+	assert.Equal(t, 2, saved.Version)
+	assert.Error(t, stocktakeRepo.Update(ctx, &stale))
+}
+
+func TestStocktakeRepository_GetProductsInScope(t *testing.T) {
+	repo, product := setupStocktakeRepository(t)
+	stocktakeRepo := repository.NewStocktakeRepository(repo)
+	productRepo := repository.NewProductRepository(repo)
+	bundleRepo := repository.NewBundleComponentRepository(repo)
+	ctx := context.Background()
+
+	parentID, childID := uuid.New(), uuid.New()
+	parentPath := model.CategoryPath("", parentID)
+	assert.NoError(t, repo.DB(ctx).Exec(`INSERT INTO product_categories (id, name, path) VALUES (?, ?, ?), (?, ?, ?)`,
+		parentID, "Snacks", parentPath, childID, "Chips", model.CategoryPath(parentPath, childID)).Error)
+
+	newProduct := func(reference string, categoryID uuid.UUID, status string, city string) *model.Product {
+		p := &model.Product{
+			ID:         uuid.New(),
+			Reference:  reference,
+			Name:       reference,
+			DateAdded:  time.Now(),
+			Status:     status,
+			CategoryID: categoryID,
+			SupplierID: product.SupplierID,
+			StockCity:  city,
+			Price:      1000,
+			Quantity:   5,
+			Version:    1,
+		}
+		assert.NoError(t, productRepo.Create(ctx, p))
+		return p
+	}
+	newProduct("PROD-202401-012", parentID, "Available", "Ha Noi")
+	newProduct("PROD-202401-011", childID, "Available", "Ha Noi")
+	newProduct("PROD-202401-013", childID, "Available", "Da Nang")
+	newProduct("PROD-202401-014", childID, model.ProductArchived, "Ha Noi")
+	bundle := newProduct("PROD-202401-015", parentID, "Available", "Ha Noi")
+	assert.NoError(t, bundleRepo.Replace(ctx, bundle.ID, []model.BundleComponent{{ComponentID: product.ID, Quantity: 1}}))
+
+	references := func(products []model.Product) []string {
+		var result []string
+		for _, p := range products {
+			result = append(result, p.Reference)
+		}
+		return result
+	}
+
+	products, err := stocktakeRepo.GetProductsInScope(ctx, "", &parentID, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"PROD-202401-012"}, references(products))
+
+	products, err = stocktakeRepo.GetProductsInScope(ctx, "", &parentID, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"PROD-202401-011", "PROD-202401-012", "PROD-202401-013"}, references(products))
+
+	products, err = stocktakeRepo.GetProductsInScope(ctx, "Ha Noi", &parentID, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"PROD-202401-011", "PROD-202401-012"}, references(products))
+}
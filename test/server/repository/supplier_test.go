@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func setupSupplierRepository(t *testing.T) (repository.SupplierRepository, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	// The postgres defaults of the model can't be migrated on sqlite
+	for _, table := range []string{
+		`CREATE TABLE suppliers (
+			id TEXT PRIMARY KEY, name TEXT, email TEXT, phone TEXT, currency TEXT, payment_terms TEXT,
+			lead_time_days INT, status TEXT, version INT NOT NULL DEFAULT 1, deleted_at DATETIME,
+			created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE supplier_contacts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, supplier_id TEXT, name TEXT, role TEXT, email TEXT, phone TEXT)`,
+		`CREATE TABLE supplier_addresses (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, supplier_id TEXT, kind TEXT, line1 TEXT, line2 TEXT,
+			city TEXT, postal_code TEXT, country TEXT)`,
+	} {
+		if err := db.Exec(table).Error; err != nil {
+			t.Fatalf("failed to create table: %v", err)
+		}
+	}
+
+	return repository.NewSupplierRepository(repository.NewRepository(logger, db)), db
+}
+
+func TestSupplierRepository_UpdateAndMoveContacts(t *testing.T) {
+	supplierRepo, _ := setupSupplierRepository(t)
+	ctx := context.Background()
+
+	source := &model.Supplier{ID: uuid.New(), Name: "Crunchy Munch", Version: 1, Contacts: []model.SupplierContact{
+		{Name: "Lan", Email: "lan@crunchymunch.com"},
+		{Name: "Minh", Email: "minh@crunchymunch.com"},
+	}}
+	target := &model.Supplier{ID: uuid.New(), Name: "Crunchy Munch Co", Version: 1, Contacts: []model.SupplierContact{
+		{Name: "Lan Nguyen", Email: "LAN@crunchymunch.com"},
+	}}
+	assert.NoError(t, supplierRepo.Create(ctx, source))
+	assert.NoError(t, supplierRepo.Create(ctx, target))
+
+	created, err := supplierRepo.GetSupplierDetailById(ctx, source.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "VND", created.Currency)
+	assert.Equal(t, "active", created.Status)
+	assert.Len(t, created.Contacts, 2)
+	assert.True(t, created.Contacts[0].SameAs(&target.Contacts[0]))
+	assert.False(t, created.Contacts[1].SameAs(&target.Contacts[0]))
+
+	// A stale version is refused
+	stale := *created
+	created.LeadTimeDays = 10
+	assert.NoError(t, supplierRepo.Update(ctx, created))
+	assert.Equal(t, 2, created.Version)
+	assert.ErrorIs(t, supplierRepo.Update(ctx, &stale), v1.ErrVersionMismatch)
+	updated, err := supplierRepo.GetSupplierById(ctx, source.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, updated.LeadTimeDays)
+
+	assert.NoError(t, supplierRepo.MoveContacts(ctx, []uint64{created.Contacts[1].ID}, target.ID))
+	merged, err := supplierRepo.GetSupplierByIdForUpdate(ctx, target.ID)
+	assert.NoError(t, err)
+	assert.Len(t, merged.Contacts, 2)
+
+	merged.Contacts = []model.SupplierContact{{Name: "Hoa"}}
+	assert.NoError(t, supplierRepo.ReplaceContacts(ctx, merged))
+	replaced, err := supplierRepo.GetSupplierDetailById(ctx, target.ID)
+	assert.NoError(t, err)
+	assert.Len(t, replaced.Contacts, 1)
+	assert.Equal(t, "Hoa", replaced.Contacts[0].Name)
+}
@@ -49,13 +49,14 @@ func TestUserRepository_Create(t *testing.T) {
 		Nickname:  "Test",
 		Password:  "password",
 		Email:     "test@example.com",
+		Role:      model.UserRoleUser,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
 	mock.ExpectBegin()
 	mock.ExpectExec("INSERT INTO `users`").
-		WithArgs(user.UserId, user.Nickname, user.Password, user.Email, user.CreatedAt, user.UpdatedAt, user.DeletedAt, user.Id).
+		WithArgs(user.UserId, user.Nickname, user.Password, user.Email, user.Role, user.CreatedAt, user.UpdatedAt, user.DeletedAt, user.Id).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 
@@ -0,0 +1,88 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/quydmfl/niveau-test/internal/service"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// setupCategoryService builds the category service on the database of setupProductService, the product is in a root category
+func setupCategoryService(t *testing.T) (service.CategoryService, *gorm.DB, *model.Product) {
+	_, db, product := setupProductService(t)
+	for _, column := range []string{
+		`ALTER TABLE product_categories ADD COLUMN status TEXT`,
+		`ALTER TABLE product_categories ADD COLUMN parent_id TEXT`,
+		`ALTER TABLE product_categories ADD COLUMN path TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE product_categories ADD COLUMN reference_prefix TEXT`,
+		`ALTER TABLE product_categories ADD COLUMN version INT NOT NULL DEFAULT 1`,
+		`ALTER TABLE product_categories ADD COLUMN created_at DATETIME`,
+		`ALTER TABLE product_categories ADD COLUMN updated_at DATETIME`,
+	} {
+		assert.NoError(t, db.Exec(column).Error)
+	}
+	assert.NoError(t, db.Exec(`INSERT INTO product_categories (id, name, status, path) VALUES (?, ?, ?, ?)`,
+		product.CategoryID, "Snacks", "Active", "/"+product.CategoryID.String()+"/").Error)
+
+	repo := repository.NewRepository(logger, db)
+	categoryService := service.NewCategoryService(service.NewService(repository.NewTransaction(repo), logger, sf, j),
+		repository.NewCategoryRepository(repo),
+		repository.NewProductRepository(repo),
+		repository.NewProductVersionRepository(repo),
+		repository.NewOutboxRepository(repo),
+	)
+	return categoryService, db, product
+}
+
+func TestCategoryService_MergeChecksAttributes(t *testing.T) {
+	categoryService, db, product := setupCategoryService(t)
+	ctx := context.Background()
+	targetID := uuid.New()
+	assert.NoError(t, db.Exec(`INSERT INTO product_categories (id, name, status, path) VALUES (?, ?, ?, ?)`,
+		targetID, "Food", "Active", "/"+targetID.String()+"/").Error)
+	// Every product of the target has a flavor
+	assert.NoError(t, db.Create(&model.CategoryAttribute{CategoryID: targetID, Name: "flavor", Type: model.AttributeString, Required: true}).Error)
+	req := &v1.MergeCategoryRequest{TargetId: targetID.String()}
+
+	// The product has no flavor, nothing is moved
+	_, err := categoryService.MergeCategory(ctx, "editor", product.CategoryID.String(), req)
+	assert.ErrorIs(t, err, v1.ErrBadRequest)
+	var stored model.Product
+	assert.NoError(t, db.First(&stored, "id = ?", product.ID).Error)
+	assert.Equal(t, product.CategoryID, stored.CategoryID)
+	assert.Equal(t, 1, stored.Version)
+
+	assert.NoError(t, db.Create(&model.ProductAttribute{ProductID: product.ID, Name: "flavor", Type: model.AttributeString, Value: "Cheese"}).Error)
+	data, err := categoryService.MergeCategory(ctx, "editor", product.CategoryID.String(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), data.MovedProducts)
+
+	stored = model.Product{}
+	assert.NoError(t, db.Preload("Attributes").First(&stored, "id = ?", product.ID).Error)
+	assert.Equal(t, targetID, stored.CategoryID)
+	assert.Equal(t, 2, stored.Version)
+	assert.Equal(t, map[string]interface{}{"flavor": "Cheese"}, stored.AttributeValues())
+}
+
+func TestCategoryService_DeleteRefusedWithProducts(t *testing.T) {
+	categoryService, db, product := setupCategoryService(t)
+	ctx := context.Background()
+
+	err := categoryService.DeleteCategory(ctx, product.CategoryID.String(), v1.IfMatch{})
+	assert.ErrorIs(t, err, v1.ErrCategoryHasProducts)
+	var count int64
+	assert.NoError(t, db.Model(&model.Category{}).Where("id = ?", product.CategoryID).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	// A product in the trash stays in the category
+	assert.NoError(t, db.Delete(product).Error)
+	assert.NoError(t, categoryService.DeleteCategory(ctx, product.CategoryID.String(), v1.IfMatch{}))
+	assert.NoError(t, db.Model(&model.Category{}).Where("id = ?", product.CategoryID).Count(&count).Error)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,59 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductService_StatusNeedsTransition(t *testing.T) {
+	productService, db, product := setupProductService(t)
+	ctx := context.Background()
+	assert.NoError(t, db.Exec("INSERT INTO product_categories (id, name) VALUES (?, ?)", product.CategoryID, "Food").Error)
+	assert.NoError(t, db.Exec("INSERT INTO suppliers (id, name) VALUES (?, ?)", product.SupplierID, "Snack Co").Error)
+
+	_, err := productService.PatchProduct(ctx, "editor", product.Reference, &v1.PatchProductRequest{
+		UpdateProductRequest: v1.UpdateProductRequest{Status: model.ProductDiscontinued},
+		Fields:               map[string]bool{"status": true},
+	}, v1.IfMatch{})
+	assert.ErrorIs(t, err, v1.ErrStatusNeedsTransition)
+
+	req := &v1.UpdateProductRequest{
+		ProductName:   "Crunchy Munch XL",
+		CategoryId:    product.CategoryID.String(),
+		Price:         product.Price,
+		Status:        model.ProductDiscontinued,
+		StockLocation: product.StockCity,
+		DateAdded:     "2024-01-28",
+		SupplierId:    product.SupplierID.String(),
+		Quantity:      product.Quantity,
+	}
+	assert.ErrorIs(t, productService.UpdateProduct(ctx, "editor", product.Reference, req, v1.IfMatch{}), v1.ErrStatusNeedsTransition)
+
+	// The current status is kept, the other fields change
+	req.Status = model.ProductAvailable
+	err = productService.UpdateProduct(ctx, "editor", product.Reference, req, v1.IfMatch{})
+	assert.NoError(t, err)
+	var stored model.Product
+	assert.NoError(t, db.First(&stored, "id = ?", product.ID).Error)
+	assert.Equal(t, "Crunchy Munch XL", stored.Name)
+	assert.Equal(t, model.ProductAvailable, stored.Status)
+}
+
+func TestProductService_BulkSetStatusNeedsReason(t *testing.T) {
+	productService, db, product := setupProductService(t)
+
+	_, err := productService.BulkUpdateProducts(context.Background(), "editor", &v1.BulkUpdateProductsRequest{
+		BulkProductTarget: v1.BulkProductTarget{References: []string{product.Reference}},
+		Action:            v1.BulkSetStatus,
+		Status:            model.ProductDiscontinued,
+	})
+	assert.ErrorIs(t, err, v1.ErrBadRequest)
+
+	var stored model.Product
+	assert.NoError(t, db.First(&stored, "id = ?", product.ID).Error)
+	assert.Equal(t, model.ProductAvailable, stored.Status)
+}
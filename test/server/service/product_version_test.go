@@ -0,0 +1,203 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/quydmfl/niveau-test/internal/service"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// setupProductService builds the product service on an in-memory sqlite database holding one product at version 1
+func setupProductService(t *testing.T) (service.ProductService, *gorm.DB, *model.Product) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+
+	// A single connection keeps every query on the same in-memory database
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	// The postgres defaults of the product models can't be migrated on sqlite
+	for _, table := range []string{
+		`CREATE TABLE products (
+			id TEXT PRIMARY KEY, reference TEXT, name TEXT, added_date DATE, status TEXT, category_id TEXT,
+			price NUMERIC, stock_city TEXT, supplier_id TEXT, quantity INT, parent_id TEXT, options TEXT, gtin TEXT UNIQUE,
+			version INT NOT NULL DEFAULT 1, deleted_at DATETIME)`,
+		`CREATE TABLE product_categories (id TEXT PRIMARY KEY, name TEXT, deleted_at DATETIME)`,
+		`CREATE TABLE category_attributes (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, category_id TEXT, name TEXT, type TEXT, required BOOLEAN NOT NULL DEFAULT false,
+			allowed_values TEXT, UNIQUE (category_id, name))`,
+		`CREATE TABLE suppliers (id TEXT PRIMARY KEY, name TEXT, deleted_at DATETIME)`,
+		`CREATE TABLE product_attributes (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, product_id TEXT, name TEXT, type TEXT, value TEXT)`,
+		`CREATE TABLE product_images (
+			id TEXT PRIMARY KEY, product_id TEXT, filename TEXT, path TEXT, content_type TEXT, size INT,
+			width INT, height INT, position INT NOT NULL DEFAULT 0, thumbnails TEXT, uploaded_at DATETIME)`,
+		`CREATE TABLE product_bundle_components (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, bundle_id TEXT, component_id TEXT, quantity INT NOT NULL,
+			UNIQUE (bundle_id, component_id))`,
+		`CREATE TABLE product_versions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, product_id TEXT, version INT, author_id TEXT, reference TEXT, gtin TEXT,
+			name TEXT, added_date DATE, status TEXT, category_id TEXT, price NUMERIC, stock_city TEXT, supplier_id TEXT,
+			quantity INT, attributes TEXT, options TEXT, created_at DATETIME, UNIQUE (product_id, version))`,
+	} {
+		if err := db.Exec(table).Error; err != nil {
+			t.Fatalf("failed to create table: %v", err)
+		}
+	}
+	if err := db.AutoMigrate(&model.OutboxEvent{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	repo := repository.NewRepository(logger, db)
+	product := &model.Product{
+		ID:         uuid.New(),
+		Reference:  "PROD-202401-001",
+		Name:       "Crunchy Munch",
+		DateAdded:  time.Date(2024, time.January, 28, 0, 0, 0, 0, time.UTC),
+		Status:     model.ProductAvailable,
+		CategoryID: uuid.New(),
+		SupplierID: uuid.New(),
+		StockCity:  "Brookstone",
+		Price:      150000,
+		Quantity:   10,
+		Version:    1,
+	}
+	if err := db.Create(product).Error; err != nil {
+		t.Fatalf("failed to create product: %v", err)
+	}
+	if _, err := repository.NewProductVersionRepository(repo).Append(context.Background(), nil, product, "creator"); err != nil {
+		t.Fatalf("failed to create version: %v", err)
+	}
+
+	srv := service.NewService(repository.NewTransaction(repo), logger, sf, j)
+	productService := service.NewProductService(srv, viper.New(),
+		repository.NewProductRepository(repo),
+		repository.NewCategoryRepository(repo),
+		repository.NewDocumentsRepository(repo),
+		repository.NewSupplierRepository(repo),
+		repository.NewOutboxRepository(repo),
+		repository.NewProductVersionRepository(repo),
+		repository.NewProductPriceRepository(repo),
+		repository.NewProductStatusChangeRepository(repo),
+		repository.NewProductImageRepository(repo),
+		repository.NewProductLotRepository(repo),
+		repository.NewStockMovementRepository(repo),
+		repository.NewReferenceCounterRepository(repo),
+		repository.NewBundleComponentRepository(repo),
+	)
+	return productService, db, product
+}
+
+func TestProductService_PatchAppendsVersion(t *testing.T) {
+	productService, _, product := setupProductService(t)
+	ctx := context.Background()
+
+	patched, err := productService.PatchProduct(ctx, "editor", product.Reference, &v1.PatchProductRequest{
+		UpdateProductRequest: v1.UpdateProductRequest{ProductName: "Crunchy Munch XL", StockLocation: "Ha Noi"},
+		Fields:               map[string]bool{"product_name": true, "stock_location": true},
+	}, v1.IfMatch{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, patched.Version)
+
+	versions, err := productService.GetProductVersions(ctx, product.Reference, &v1.GetProductVersionsRequest{Page: v1.Page{Page: 1, Size: 10}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, versions.TotalRows)
+	data := versions.Data.([]v1.GetProductVersionData)
+	assert.Equal(t, 2, data[0].Version)
+	assert.Equal(t, "editor", data[0].AuthorID)
+	assert.Equal(t, "Crunchy Munch XL", data[0].Product.ProductName)
+	assert.Equal(t, 1, data[1].Version)
+	assert.Equal(t, "Crunchy Munch", data[1].Product.ProductName)
+
+	// Nothing changes, no version is appended
+	_, err = productService.PatchProduct(ctx, "editor", product.Reference, &v1.PatchProductRequest{
+		UpdateProductRequest: v1.UpdateProductRequest{ProductName: "Crunchy Munch XL"},
+		Fields:               map[string]bool{"product_name": true},
+	}, v1.IfMatch{})
+	assert.NoError(t, err)
+	_, err = productService.GetProductVersion(ctx, product.Reference, 3)
+	assert.ErrorIs(t, err, v1.ErrNotFound)
+}
+
+func TestProductService_GetProductAsOf(t *testing.T) {
+	productService, db, product := setupProductService(t)
+	ctx := context.Background()
+
+	_, err := productService.PatchProduct(ctx, "editor", product.Reference, &v1.PatchProductRequest{
+		UpdateProductRequest: v1.UpdateProductRequest{ProductName: "Crunchy Munch XL"},
+		Fields:               map[string]bool{"product_name": true},
+	}, v1.IfMatch{})
+	assert.NoError(t, err)
+	// Version 1 was made an hour ago
+	now := time.Now()
+	assert.NoError(t, db.Model(&model.ProductVersion{}).Where("version = 1").
+		Update("created_at", now.Add(-time.Hour)).Error)
+
+	for _, c := range []struct {
+		at      time.Time
+		version int
+		name    string
+	}{
+		{now.Add(-30 * time.Minute), 1, "Crunchy Munch"},
+		{now.Add(time.Minute), 2, "Crunchy Munch XL"},
+	} {
+		version, err := productService.GetProductAsOf(ctx, product.Reference, &v1.GetProductAsOfRequest{Timestamp: c.at.Format(time.RFC3339)})
+		assert.NoError(t, err)
+		assert.Equal(t, c.version, version.Version)
+		assert.Equal(t, c.name, version.Product.ProductName)
+	}
+
+	// The product did not exist yet
+	_, err = productService.GetProductAsOf(ctx, product.Reference, &v1.GetProductAsOfRequest{Timestamp: now.Add(-2 * time.Hour).Format(time.RFC3339)})
+	assert.ErrorIs(t, err, v1.ErrNotFound)
+}
+
+func TestProductService_DiffProductVersions(t *testing.T) {
+	productService, _, product := setupProductService(t)
+	ctx := context.Background()
+
+	for _, req := range []*v1.PatchProductRequest{{
+		UpdateProductRequest: v1.UpdateProductRequest{ProductName: "Crunchy Munch XL"},
+		Fields:               map[string]bool{"product_name": true},
+	}, {
+		UpdateProductRequest: v1.UpdateProductRequest{StockLocation: "Ha Noi"},
+		Fields:               map[string]bool{"stock_location": true},
+	}} {
+		_, err := productService.PatchProduct(ctx, "editor", product.Reference, req, v1.IfMatch{})
+		assert.NoError(t, err)
+	}
+
+	diff, err := productService.DiffProductVersions(ctx, product.Reference, &v1.DiffProductVersionsRequest{From: 1, To: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, diff.From)
+	assert.Equal(t, 3, diff.To)
+	assert.Equal(t, map[string]v1.ProductFieldChange{
+		"product_name":   {Old: "Crunchy Munch", New: "Crunchy Munch XL"},
+		"stock_location": {Old: "Brookstone", New: "Ha Noi"},
+	}, diff.Changes)
+
+	diff, err = productService.DiffProductVersions(ctx, product.Reference, &v1.DiffProductVersionsRequest{From: 2, To: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]v1.ProductFieldChange{
+		"stock_location": {Old: "Brookstone", New: "Ha Noi"},
+	}, diff.Changes)
+
+	_, err = productService.DiffProductVersions(ctx, product.Reference, &v1.DiffProductVersionsRequest{From: 1, To: 4})
+	assert.ErrorIs(t, err, v1.ErrNotFound)
+}
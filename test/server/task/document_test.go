@@ -0,0 +1,150 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/quydmfl/niveau-test/internal/task"
+	"github.com/quydmfl/niveau-test/pkg/lock"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// documentFixture is the storage of the cleanup tests, every file but missing.pdf exists
+type documentFixture struct {
+	db  *gorm.DB
+	dir string
+	// expired is past the 30 days of product PDFs, kept is recent, missing has no file
+	expired, kept, missing model.Documents
+	// orphan has no row since 2 hours, recent since a minute which is within the grace period
+	orphan, recent string
+}
+
+// setupDocumentTask builds a registry running the document cleanup task on a temporary storage
+func setupDocumentTask(t *testing.T) (*task.Registry, *documentFixture) {
+	// The postgres defaults of the document model can't be migrated on sqlite
+	db := setupDB(t, []string{
+		`CREATE TABLE documents (
+			id TEXT PRIMARY KEY, filename TEXT, path TEXT, kind TEXT, product_id TEXT, purchase_order_id TEXT,
+			uploaded_at DATETIME, missing_at DATETIME)`,
+	}, &model.TaskRun{})
+
+	dir := t.TempDir()
+	productID := uuid.New()
+	document := func(name string, uploadedAt time.Time) model.Documents {
+		d := model.Documents{
+			ID:         uuid.New(),
+			Filename:   name,
+			Path:       filepath.Join(dir, name),
+			Kind:       model.DocumentKindProductPDF,
+			ProductID:  &productID,
+			UploadedAt: uploadedAt,
+		}
+		assert.NoError(t, db.Create(&d).Error)
+		return d
+	}
+	f := &documentFixture{
+		db:      db,
+		dir:     dir,
+		expired: document("expired.pdf", time.Now().Add(-60*24*time.Hour)),
+		kept:    document("kept.pdf", time.Now()),
+		missing: document("missing.pdf", time.Now()),
+		orphan:  filepath.Join(dir, "orphan.pdf"),
+		recent:  filepath.Join(dir, "recent.pdf"),
+	}
+	for path, modTime := range map[string]time.Time{
+		f.expired.Path: time.Now(),
+		f.kept.Path:    time.Now(),
+		f.orphan:       time.Now().Add(-2 * time.Hour),
+		f.recent:       time.Now().Add(-time.Minute),
+	} {
+		assert.NoError(t, os.WriteFile(path, []byte("%PDF-1.3"), 0o644))
+		assert.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	c := viper.New()
+	c.Set("task.document_cleanup.storage_path", dir)
+	c.Set("task.document_cleanup.retention.product_pdf", "720h")
+	c.Set("task.document_cleanup.orphan_grace", "1h")
+
+	repo := repository.NewRepository(logger, db)
+	documentTask := task.NewDocumentTask(task.NewTask(repository.NewTransaction(repo), logger, nil), c,
+		repository.NewDocumentsRepository(repo))
+	registry := task.NewRegistry(logger, viper.New(), lock.NewLocalLocker(), repository.NewTaskRunRepository(repo),
+		[]task.TaskProvider{documentTask})
+	t.Cleanup(registry.Stop)
+	return registry, f
+}
+
+// runDocumentCleanup runs the cleanup task and return its report
+func runDocumentCleanup(t *testing.T, registry *task.Registry, dryRun bool) task.DocumentCleanupReport {
+	run, err := registry.Run(context.Background(), "document_cleanup", model.TaskTriggerManual, "", nil, dryRun)
+	assert.NoError(t, err)
+	var report task.DocumentCleanupReport
+	if assert.NotNil(t, run) {
+		assert.Equal(t, model.TaskRunSuccess, run.Status)
+		assert.NoError(t, json.Unmarshal([]byte(run.Result), &report))
+	}
+	return report
+}
+
+func (f *documentFixture) documentIDs(t *testing.T) []uuid.UUID {
+	var ids []uuid.UUID
+	assert.NoError(t, f.db.Model(&model.Documents{}).Order("filename").Pluck("id", &ids).Error)
+	return ids
+}
+
+func (f *documentFixture) missingAt(t *testing.T) *time.Time {
+	var document model.Documents
+	assert.NoError(t, f.db.First(&document, "id = ?", f.missing.ID).Error)
+	return document.MissingAt
+}
+
+func TestDocumentTask_Cleanup(t *testing.T) {
+	registry, f := setupDocumentTask(t)
+
+	report := runDocumentCleanup(t, registry, false)
+	assert.False(t, report.DryRun)
+	assert.Equal(t, []string{f.expired.Path}, report.ExpiredDocuments)
+	assert.Equal(t, []string{f.orphan}, report.OrphanFiles)
+	assert.Equal(t, []string{f.missing.Path}, report.MissingFiles)
+	assert.Empty(t, report.Errors)
+
+	// The expired document lost its row and file, the orphan its file
+	assert.Equal(t, []uuid.UUID{f.kept.ID, f.missing.ID}, f.documentIDs(t))
+	assert.NoFileExists(t, f.expired.Path)
+	assert.NoFileExists(t, f.orphan)
+	assert.FileExists(t, f.kept.Path)
+	assert.FileExists(t, f.recent)
+	assert.NotNil(t, f.missingAt(t))
+
+	// The missing file is only reported once
+	report = runDocumentCleanup(t, registry, false)
+	assert.Empty(t, report.ExpiredDocuments)
+	assert.Empty(t, report.OrphanFiles)
+	assert.Empty(t, report.MissingFiles)
+}
+
+func TestDocumentTask_CleanupDryRun(t *testing.T) {
+	registry, f := setupDocumentTask(t)
+
+	report := runDocumentCleanup(t, registry, true)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, []string{f.expired.Path}, report.ExpiredDocuments)
+	assert.Equal(t, []string{f.orphan}, report.OrphanFiles)
+	assert.Equal(t, []string{f.missing.Path}, report.MissingFiles)
+
+	// Every file and row is left in place
+	assert.Equal(t, []uuid.UUID{f.expired.ID, f.kept.ID, f.missing.ID}, f.documentIDs(t))
+	assert.FileExists(t, f.expired.Path)
+	assert.FileExists(t, f.orphan)
+	assert.Nil(t, f.missingAt(t))
+}
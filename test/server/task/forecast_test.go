@@ -0,0 +1,137 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/quydmfl/niveau-test/api/v1"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/quydmfl/niveau-test/internal/service"
+	"github.com/quydmfl/niveau-test/internal/task"
+	"github.com/quydmfl/niveau-test/pkg/lock"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForecastTask_CountsManualDecrease(t *testing.T) {
+	// The postgres defaults of the product models can't be migrated on sqlite
+	db := setupDB(t, []string{
+		`CREATE TABLE products (
+			id TEXT PRIMARY KEY, reference TEXT, name TEXT, added_date DATE, status TEXT, category_id TEXT,
+			price NUMERIC, stock_city TEXT, supplier_id TEXT, quantity INT, parent_id TEXT, options TEXT, gtin TEXT UNIQUE,
+			version INT NOT NULL DEFAULT 1, deleted_at DATETIME)`,
+		`CREATE TABLE suppliers (id TEXT PRIMARY KEY, name TEXT, lead_time_days INT, deleted_at DATETIME)`,
+		`CREATE TABLE product_attributes (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, product_id TEXT, name TEXT, type TEXT, value TEXT)`,
+		`CREATE TABLE product_lots (
+			id TEXT PRIMARY KEY, product_id TEXT, lot_number TEXT, quantity INT NOT NULL DEFAULT 0, received_quantity INT NOT NULL DEFAULT 0,
+			manufactured_at DATE, expires_at DATE, stock_city TEXT, expiry_flagged_at DATETIME, received_at DATETIME,
+			UNIQUE (product_id, lot_number))`,
+		`CREATE TABLE product_bundle_components (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, bundle_id TEXT, component_id TEXT, quantity INT NOT NULL,
+			UNIQUE (bundle_id, component_id))`,
+		`CREATE TABLE product_versions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, product_id TEXT, version INT, author_id TEXT, reference TEXT, gtin TEXT,
+			name TEXT, added_date DATE, status TEXT, category_id TEXT, price NUMERIC, stock_city TEXT, supplier_id TEXT,
+			quantity INT, attributes TEXT, options TEXT, created_at DATETIME, UNIQUE (product_id, version))`,
+		`CREATE TABLE stock_movements (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, product_id TEXT, delta INT, quantity_after INT, reason TEXT,
+			source_id TEXT, actor_id TEXT, lot_id TEXT, created_at DATETIME DEFAULT CURRENT_TIMESTAMP)`,
+		`CREATE TABLE purchase_orders (
+			id TEXT PRIMARY KEY, number TEXT UNIQUE, supplier_id TEXT, supplier_name TEXT, status TEXT, currency TEXT,
+			note TEXT, created_by TEXT, expected_at DATETIME, sent_at DATETIME, received_at DATETIME,
+			cancelled_at DATETIME, version INT NOT NULL DEFAULT 1, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE purchase_order_lines (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, purchase_order_id TEXT, product_id TEXT, reference TEXT,
+			name TEXT, quantity INT, received_quantity INT NOT NULL DEFAULT 0, unit_cost REAL)`,
+		`CREATE TABLE product_forecasts (
+			product_id TEXT PRIMARY KEY, reference TEXT, name TEXT, supplier_id TEXT, supplier_name TEXT, method TEXT,
+			period_days INT, periods INT, moving_average REAL, exponential_smoothing REAL, daily_demand REAL,
+			demand_std_dev REAL, lead_time_days INT, safety_stock INT, quantity INT, on_order INT, reorder_point INT,
+			reorder_quantity INT, computed_at DATETIME)`,
+	}, &model.OutboxEvent{}, &model.TaskRun{})
+
+	supplier := &model.Supplier{ID: uuid.New(), Name: "Snack Co", LeadTimeDays: 7}
+	assert.NoError(t, db.Exec("INSERT INTO suppliers (id, name, lead_time_days) VALUES (?, ?, ?)",
+		supplier.ID, supplier.Name, supplier.LeadTimeDays).Error)
+	product := &model.Product{
+		ID:         uuid.New(),
+		Reference:  "PROD-202401-001",
+		Name:       "Crunchy Munch",
+		DateAdded:  time.Now().Add(-365 * 24 * time.Hour),
+		Status:     model.ProductAvailable,
+		CategoryID: uuid.New(),
+		SupplierID: supplier.ID,
+		Price:      150000,
+		Quantity:   10,
+		Version:    1,
+	}
+	assert.NoError(t, db.Create(product).Error)
+
+	repo := repository.NewRepository(logger, db)
+	tm := repository.NewTransaction(repo)
+	productService := service.NewProductService(service.NewService(tm, logger, nil, nil), viper.New(),
+		repository.NewProductRepository(repo),
+		repository.NewCategoryRepository(repo),
+		repository.NewDocumentsRepository(repo),
+		repository.NewSupplierRepository(repo),
+		repository.NewOutboxRepository(repo),
+		repository.NewProductVersionRepository(repo),
+		repository.NewProductPriceRepository(repo),
+		repository.NewProductStatusChangeRepository(repo),
+		repository.NewProductImageRepository(repo),
+		repository.NewProductLotRepository(repo),
+		repository.NewStockMovementRepository(repo),
+		repository.NewReferenceCounterRepository(repo),
+		repository.NewBundleComponentRepository(repo),
+	)
+	forecastTask := task.NewForecastTask(task.NewTask(tm, logger, nil), viper.New(),
+		repository.NewProductForecastRepository(repo),
+		repository.NewStockMovementRepository(repo),
+		repository.NewPurchaseOrderRepository(repo),
+	)
+	registry := task.NewRegistry(logger, viper.New(), lock.NewLocalLocker(), repository.NewTaskRunRepository(repo),
+		[]task.TaskProvider{forecastTask})
+	t.Cleanup(registry.Stop)
+
+	forecast := func() task.ForecastReport {
+		run, err := registry.Run(context.Background(), "demand_forecast", model.TaskTriggerManual, "", nil, true)
+		assert.NoError(t, err)
+		var report task.ForecastReport
+		if assert.NotNil(t, run) {
+			assert.Equal(t, model.TaskRunSuccess, run.Status)
+			assert.NoError(t, json.Unmarshal([]byte(run.Result), &report))
+		}
+		return report
+	}
+
+	// Without any movement there is no demand to reorder for
+	report := forecast()
+	assert.Equal(t, 1, report.Products)
+	assert.Empty(t, report.Reorder)
+
+	_, err := productService.PatchProduct(context.Background(), "editor", product.Reference, &v1.PatchProductRequest{
+		UpdateProductRequest: v1.UpdateProductRequest{Quantity: 1},
+		Fields:               map[string]bool{"quantity": true},
+	}, v1.IfMatch{})
+	assert.NoError(t, err)
+
+	var movement model.StockMovement
+	assert.NoError(t, db.First(&movement, "product_id = ?", product.ID).Error)
+	assert.Equal(t, model.StockMovementManualAdjustment, movement.Reason)
+	assert.Equal(t, -9, movement.Delta)
+	assert.Equal(t, 1, movement.QuantityAfter)
+	assert.Equal(t, "editor", movement.ActorID)
+
+	// The decrease is demand, the stock left is below the demand over the lead time
+	report = forecast()
+	if assert.Len(t, report.Reorder, 1) {
+		assert.Equal(t, product.Reference, report.Reorder[0].Reference)
+		assert.Greater(t, report.Reorder[0].DailyDemand, 0.0)
+		assert.Equal(t, 1, report.Reorder[0].Quantity)
+	}
+}
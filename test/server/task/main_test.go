@@ -0,0 +1,82 @@
+package task
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/quydmfl/niveau-test/internal/task"
+	"github.com/quydmfl/niveau-test/pkg/config"
+	"github.com/quydmfl/niveau-test/pkg/lock"
+	"github.com/quydmfl/niveau-test/pkg/log"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+var (
+	logger *log.Logger
+	conf   *viper.Viper
+)
+
+func TestMain(m *testing.M) {
+	fmt.Println("begin")
+	err := os.Setenv("APP_CONF", "../../../config/local.yml")
+	if err != nil {
+		fmt.Println("Setenv error", err)
+	}
+	var envConf = flag.String("conf", "config/local.yml", "config path, eg: -conf ./config/local.yml")
+	flag.Parse()
+	conf = config.NewConfig(*envConf)
+
+	// modify log directory
+	logPath := filepath.Join("../../../", conf.GetString("log.log_file_name"))
+	conf.Set("log.log_file_name", logPath)
+
+	logger = log.NewLog(conf)
+
+	code := m.Run()
+	fmt.Println("test end")
+
+	os.Exit(code)
+}
+
+// setupDB open a private in-memory sqlite database with the tables created by the statements and migrated with models
+func setupDB(t *testing.T, tables []string, models ...interface{}) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+
+	// A single connection keeps every query on the same in-memory database
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	for _, table := range tables {
+		if err := db.Exec(table).Error; err != nil {
+			t.Fatalf("failed to create table: %v", err)
+		}
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return db
+}
+
+// setupRegistry open a private in-memory sqlite database and build a registry without tasks
+func setupRegistry(t *testing.T, locker lock.Locker) (*task.Registry, repository.TaskRunRepository) {
+	db := setupDB(t, nil, &model.TaskRun{})
+
+	taskRunRepo := repository.NewTaskRunRepository(repository.NewRepository(logger, db))
+	registry := task.NewRegistry(logger, viper.New(), locker, taskRunRepo, nil)
+	t.Cleanup(registry.Stop)
+	return registry, taskRunRepo
+}
@@ -0,0 +1,132 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/repository"
+	"github.com/quydmfl/niveau-test/internal/task"
+	"github.com/quydmfl/niveau-test/pkg/lock"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// setupPriceTask builds a registry running the price task on a product at 150000 with a price of 165000 scheduled at start
+func setupPriceTask(t *testing.T, start time.Time) (*task.Registry, *gorm.DB, *model.Product) {
+	// The postgres defaults of the product models can't be migrated on sqlite
+	db := setupDB(t, []string{
+		`CREATE TABLE products (
+			id TEXT PRIMARY KEY, reference TEXT, name TEXT, added_date DATE, status TEXT, category_id TEXT,
+			price NUMERIC, stock_city TEXT, supplier_id TEXT, quantity INT, parent_id TEXT, options TEXT, gtin TEXT UNIQUE,
+			version INT NOT NULL DEFAULT 1, deleted_at DATETIME)`,
+		`CREATE TABLE product_attributes (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, product_id TEXT, name TEXT, type TEXT, value TEXT)`,
+		`CREATE TABLE product_prices (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, product_id TEXT, price NUMERIC, effective_from DATETIME,
+			effective_to DATETIME, author_id TEXT, created_at DATETIME)`,
+		`CREATE TABLE product_versions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, product_id TEXT, version INT, author_id TEXT, reference TEXT, gtin TEXT,
+			name TEXT, added_date DATE, status TEXT, category_id TEXT, price NUMERIC, stock_city TEXT, supplier_id TEXT,
+			quantity INT, attributes TEXT, options TEXT, created_at DATETIME, UNIQUE (product_id, version))`,
+	}, &model.OutboxEvent{}, &model.TaskRun{})
+
+	product := &model.Product{
+		ID:         uuid.New(),
+		Reference:  "PROD-202401-001",
+		Name:       "Crunchy Munch",
+		DateAdded:  time.Now(),
+		Status:     model.ProductAvailable,
+		CategoryID: uuid.New(),
+		SupplierID: uuid.New(),
+		Price:      150000,
+		Quantity:   10,
+		Version:    1,
+	}
+	assert.NoError(t, db.Create(product).Error)
+	for _, price := range []model.ProductPrice{
+		{ProductID: product.ID, Price: 150000, EffectiveFrom: time.Now().Add(-24 * time.Hour), EffectiveTo: &start, CreatedAt: time.Now()},
+		{ProductID: product.ID, Price: 165000, EffectiveFrom: start, CreatedAt: time.Now()},
+	} {
+		assert.NoError(t, db.Create(&price).Error)
+	}
+
+	repo := repository.NewRepository(logger, db)
+	priceTask := task.NewPriceTask(task.NewTask(repository.NewTransaction(repo), logger, nil),
+		repository.NewProductRepository(repo),
+		repository.NewProductPriceRepository(repo),
+		repository.NewProductVersionRepository(repo),
+		repository.NewOutboxRepository(repo),
+	)
+	registry := task.NewRegistry(logger, viper.New(), lock.NewLocalLocker(), repository.NewTaskRunRepository(repo),
+		[]task.TaskProvider{priceTask})
+	t.Cleanup(registry.Stop)
+	return registry, db, product
+}
+
+// runPriceActivation runs the price task and return its report
+func runPriceActivation(t *testing.T, registry *task.Registry, dryRun bool) task.PriceActivationReport {
+	run, err := registry.Run(context.Background(), "price_activation", model.TaskTriggerManual, "", nil, dryRun)
+	assert.NoError(t, err)
+	var report task.PriceActivationReport
+	if assert.NotNil(t, run) {
+		assert.Equal(t, model.TaskRunSuccess, run.Status)
+		assert.NoError(t, json.Unmarshal([]byte(run.Result), &report))
+	}
+	return report
+}
+
+func productPrice(t *testing.T, db *gorm.DB, product *model.Product) float64 {
+	var stored model.Product
+	assert.NoError(t, db.First(&stored, "id = ?", product.ID).Error)
+	return stored.Price
+}
+
+func TestPriceTask_ActivatesAtEffectiveTime(t *testing.T) {
+	start := time.Now().Add(time.Second)
+	registry, db, product := setupPriceTask(t, start)
+
+	// The scheduled price stays inactive until it starts
+	report := runPriceActivation(t, registry, false)
+	assert.Empty(t, report.Activated)
+	assert.Equal(t, 150000.0, productPrice(t, db, product))
+
+	time.Sleep(time.Until(start))
+	report = runPriceActivation(t, registry, false)
+	assert.Equal(t, []task.PriceActivationResult{
+		{Reference: product.Reference, OldPrice: 150000, NewPrice: 165000},
+	}, report.Activated)
+	assert.Equal(t, 165000.0, productPrice(t, db, product))
+
+	var versions, events int64
+	assert.NoError(t, db.Model(&model.ProductVersion{}).Where("product_id = ?", product.ID).Count(&versions).Error)
+	assert.NoError(t, db.Model(&model.OutboxEvent{}).Where("aggregate_id = ?", product.ID.String()).Count(&events).Error)
+	assert.Equal(t, int64(2), versions)
+	assert.Equal(t, int64(1), events)
+
+	// Nothing is left to activate
+	report = runPriceActivation(t, registry, false)
+	assert.Empty(t, report.Activated)
+}
+
+func TestPriceTask_DryRun(t *testing.T) {
+	registry, db, product := setupPriceTask(t, time.Now().Add(-time.Hour))
+
+	report := runPriceActivation(t, registry, true)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, []task.PriceActivationResult{
+		{Reference: product.Reference, OldPrice: 150000, NewPrice: 165000},
+	}, report.Activated)
+
+	// Nothing was written
+	assert.Equal(t, 150000.0, productPrice(t, db, product))
+	var versions, events int64
+	assert.NoError(t, db.Model(&model.ProductVersion{}).Count(&versions).Error)
+	assert.NoError(t, db.Model(&model.OutboxEvent{}).Count(&events).Error)
+	assert.Zero(t, versions)
+	assert.Zero(t, events)
+}
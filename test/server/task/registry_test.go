@@ -0,0 +1,82 @@
+package task
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/quydmfl/niveau-test/internal/model"
+	"github.com/quydmfl/niveau-test/internal/task"
+	"github.com/quydmfl/niveau-test/pkg/lock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_RunClaimsTickOnce(t *testing.T) {
+	registry, _ := setupRegistry(t, lock.NewLocalLocker())
+
+	calls := 0
+	registry.Register(task.Definition{
+		Name:    "count",
+		Timeout: time.Second,
+		Overlap: task.OverlapSkip,
+		Run: func(ctx context.Context) (interface{}, error) {
+			calls++
+			return map[string]int{"calls": calls}, nil
+		},
+	})
+
+	ctx := context.Background()
+	tick := time.Now().UTC().Round(time.Second)
+
+	run, err := registry.Run(ctx, "count", model.TaskTriggerSchedule, "", &tick, false)
+	assert.NoError(t, err)
+	if assert.NotNil(t, run) {
+		assert.Equal(t, model.TaskRunSuccess, run.Status)
+		assert.JSONEq(t, `{"calls":1}`, run.Result)
+	}
+
+	// Another replica firing the same tick must not run the task again
+	run, err = registry.Run(ctx, "count", model.TaskTriggerSchedule, "", &tick, false)
+	assert.NoError(t, err)
+	assert.Nil(t, run)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRegistry_OverlapSkip(t *testing.T) {
+	locker := lock.NewLocalLocker()
+	registry, taskRunRepo := setupRegistry(t, locker)
+
+	registry.Register(task.Definition{
+		Name:    "busy",
+		Timeout: time.Second,
+		Overlap: task.OverlapSkip,
+		Run: func(ctx context.Context) (interface{}, error) {
+			t.Fatal("task must not run while its lock is held")
+			return nil, nil
+		},
+	})
+
+	ctx := context.Background()
+	held, acquired, err := locker.TryLock(ctx, "task:busy", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	defer held.Unlock(ctx)
+
+	run, err := registry.Run(ctx, "busy", model.TaskTriggerManual, "admin", nil, false)
+	assert.NoError(t, err)
+	if assert.NotNil(t, run) {
+		assert.Equal(t, model.TaskRunSkipped, run.Status)
+
+		stored, err := taskRunRepo.GetTaskRunById(ctx, run.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.TaskRunSkipped, stored.Status)
+		assert.NotNil(t, stored.FinishedAt)
+	}
+}
+
+func TestRegistry_UnknownTask(t *testing.T) {
+	registry, _ := setupRegistry(t, lock.NewLocalLocker())
+
+	_, err := registry.Trigger(context.Background(), "missing", "admin", false)
+	assert.ErrorIs(t, err, task.ErrTaskNotFound)
+}